@@ -1,14 +1,14 @@
 package main
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 
 	"fyne.io/fyne/v2"
 
+	"github.com/oukeidos/focst/internal/language"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/metadata"
-	"github.com/oukeidos/focst/internal/names"
+	"github.com/oukeidos/focst/internal/paths"
 	"github.com/oukeidos/focst/internal/pipeline"
 )
 
@@ -30,6 +30,7 @@ type AppConfig struct {
 	NoLangPreprocess    bool
 	NoLangPostprocess   bool
 	ExtractionMaxTokens int
+	NoTimingCorrection  bool
 }
 
 const (
@@ -44,8 +45,8 @@ func (a *focstApp) loadConfig() {
 	_ = p                          // dummy
 	prefs := fyne.CurrentApp().Preferences()
 
-	a.config.SourceLang = prefs.StringWithFallback("SourceLang", "ja")
-	a.config.TargetLang = prefs.StringWithFallback("TargetLang", "ko")
+	a.config.SourceLang = normalizeLanguageCode(prefs.StringWithFallback("SourceLang", "ja"), "ja")
+	a.config.TargetLang = normalizeLanguageCode(prefs.StringWithFallback("TargetLang", "ko"), "ko")
 	savedModel := prefs.StringWithFallback("Model", defaultGUIModel)
 	a.config.Model = normalizeGeminiModel(savedModel)
 	if a.config.Model != savedModel {
@@ -79,6 +80,7 @@ func (a *focstApp) loadConfig() {
 	a.config.NoPostprocess = prefs.BoolWithFallback("NoPostprocess", false)
 	a.config.NoLangPreprocess = prefs.BoolWithFallback("NoLangPreprocess", false)
 	a.config.NoLangPostprocess = prefs.BoolWithFallback("NoLangPostprocess", false)
+	a.config.NoTimingCorrection = prefs.BoolWithFallback("NoTimingCorrection", false)
 	a.config.ExtractionMaxTokens = prefs.IntWithFallback("ExtractionMaxTokens", 16384)
 	if a.config.ExtractionMaxTokens > maxExtractionTokens {
 		logger.Warn("Extraction max tokens clamped", "requested", a.config.ExtractionMaxTokens, "effective", maxExtractionTokens)
@@ -87,26 +89,36 @@ func (a *focstApp) loadConfig() {
 	}
 
 	if a.config.LastDict != "" && a.config.LastDict != "None (Empty)" {
-		home, _ := os.UserHomeDir()
-		path := filepath.Join(home, ".focst", "names", a.config.LastDict+".json")
-		sourceCode, targetCode := a.getDictionaryMeta(a.config.LastDict)
-		if sourceCode == "" {
-			sourceCode = a.config.SourceLang
-		}
-		if targetCode == "" {
-			targetCode = a.config.TargetLang
-		}
-		if mapping, err := names.LoadMappingFile(path, sourceCode, targetCode); err == nil {
+		if mapping, err := a.loadDictionary(a.config.LastDict); err == nil {
 			a.config.NamesMapping = mapping
-		} else {
-			logger.Error("Failed to load dictionary", "path", path, "source", sourceCode, "target", targetCode, "error", err)
 		}
 	}
 
 	// Ensure names directory exists
-	home, _ := os.UserHomeDir()
-	namesDir := filepath.Join(home, ".focst", "names")
-	os.MkdirAll(namesDir, 0700)
+	if _, err := paths.NamesDir(); err != nil {
+		logger.Warn("Dictionary directory unavailable", "error", err)
+	}
+}
+
+// pipelineOptions maps the GUI settings onto pipeline.Options. Settings the
+// GUI does not expose keep their pipeline defaults.
+func (c AppConfig) pipelineOptions() pipeline.Options {
+	opts := pipeline.DefaultOptions()
+	opts.Model = c.Model
+	opts.ChunkSize = c.ChunkSize
+	opts.ContextSize = c.ContextSize
+	opts.Concurrency = c.Concurrency
+	opts.RetryOnLongLines = c.RetryOnLongLines
+	opts.NoPromptCPL = c.NoPromptCPL
+	opts.NoPreprocess = c.NoPreprocess
+	opts.NoPostprocess = c.NoPostprocess
+	opts.NoLangPreprocess = c.NoLangPreprocess
+	opts.NoLangPostprocess = c.NoLangPostprocess
+	opts.NoTimingCorrection = c.NoTimingCorrection
+	opts.SourceLang = c.SourceLang
+	opts.TargetLang = c.TargetLang
+	opts.NamesMapping = c.NamesMapping
+	return opts
 }
 
 func normalizeGeminiModel(model string) string {
@@ -122,6 +134,28 @@ func normalizeGeminiModel(model string) string {
 	return defaultGUIModel
 }
 
+// normalizeLanguageCode resolves aliases such as "zh" to their canonical code
+// (zh-Hans) so the language dropdowns always show the explicit variant.
+func normalizeLanguageCode(code, fallback string) string {
+	lang, ok := language.GetLanguage(code)
+	if !ok {
+		logger.Warn("Unsupported language in preferences; fallback to default", "requested", code, "default", fallback)
+		return fallback
+	}
+	return lang.Code
+}
+
+// languageLabel is the dropdown label for a language: its DisplayName, which
+// is unique, with the code added to Chinese variants so Simplified (zh-Hans)
+// and Traditional (zh-Hant) are unambiguous.
+func languageLabel(e language.LanguageEntry) string {
+	name := e.DisplayName()
+	if name == e.Name && language.ChineseScript(e.Code) != "" {
+		return fmt.Sprintf("%s [%s]", e.Name, e.ID)
+	}
+	return name
+}
+
 func (a *focstApp) saveConfig() {
 	prefs := fyne.CurrentApp().Preferences()
 	prefs.SetString("SourceLang", a.config.SourceLang)
@@ -139,5 +173,6 @@ func (a *focstApp) saveConfig() {
 	prefs.SetBool("NoPostprocess", a.config.NoPostprocess)
 	prefs.SetBool("NoLangPreprocess", a.config.NoLangPreprocess)
 	prefs.SetBool("NoLangPostprocess", a.config.NoLangPostprocess)
+	prefs.SetBool("NoTimingCorrection", a.config.NoTimingCorrection)
 	prefs.SetInt("ExtractionMaxTokens", a.config.ExtractionMaxTokens)
 }
@@ -1,6 +1,12 @@
 package main
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/pipeline"
+)
 
 func TestNormalizeGeminiModel(t *testing.T) {
 	tests := []struct {
@@ -40,3 +46,77 @@ func TestNormalizeGeminiModel(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeLanguageCode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "zh", want: "zh-Hans"},
+		{input: "zh-Hant", want: "zh-Hant"},
+		{input: "ko", want: "ko"},
+		{input: "xx", want: "ja"},
+	}
+	for _, tc := range tests {
+		if got := normalizeLanguageCode(tc.input, "ja"); got != tc.want {
+			t.Fatalf("normalizeLanguageCode(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestLanguageLabel_ChineseVariantsExplicit(t *testing.T) {
+	entry := func(id string) language.LanguageEntry {
+		l, _ := language.GetLanguage(id)
+		return language.LanguageEntry{ID: id, Language: l}
+	}
+	if got := languageLabel(entry("zh-Hans")); got != "Chinese (Simplified) [zh-Hans]" {
+		t.Fatalf("languageLabel(zh-Hans) = %q", got)
+	}
+	if got := languageLabel(entry("zh-Hant")); got != "Chinese (Traditional) [zh-Hant]" {
+		t.Fatalf("languageLabel(zh-Hant) = %q", got)
+	}
+	if got := languageLabel(entry("ko")); got != "Korean" {
+		t.Fatalf("languageLabel(ko) = %q", got)
+	}
+}
+
+// TestAppConfigPipelineOptions mirrors the CLI's TestTranslatePipelineOptions:
+// the same toggles must land on the same pipeline.Options fields.
+func TestAppConfigPipelineOptions(t *testing.T) {
+	cfg := AppConfig{
+		SourceLang:         "en",
+		TargetLang:         "fr",
+		Model:              "gemini-3-flash-preview",
+		NamesMapping:       map[string]string{"a": "b"},
+		ChunkSize:          40,
+		ContextSize:        3,
+		Concurrency:        4,
+		RetryOnLongLines:   true,
+		NoPromptCPL:        true,
+		NoPreprocess:       true,
+		NoPostprocess:      true,
+		NoLangPreprocess:   true,
+		NoLangPostprocess:  true,
+		NoTimingCorrection: true,
+	}
+
+	got := cfg.pipelineOptions()
+	want := pipeline.DefaultOptions()
+	want.Model = "gemini-3-flash-preview"
+	want.ChunkSize = 40
+	want.ContextSize = 3
+	want.Concurrency = 4
+	want.RetryOnLongLines = true
+	want.NoPromptCPL = true
+	want.NoPreprocess = true
+	want.NoPostprocess = true
+	want.NoLangPreprocess = true
+	want.NoLangPostprocess = true
+	want.NoTimingCorrection = true
+	want.SourceLang = "en"
+	want.TargetLang = "fr"
+	want.NamesMapping = map[string]string{"a": "b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("pipelineOptions() =\n%+v\nwant\n%+v", got, want)
+	}
+}
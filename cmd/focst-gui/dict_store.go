@@ -13,6 +13,7 @@ import (
 	"github.com/oukeidos/focst/internal/files"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/names"
+	"github.com/oukeidos/focst/internal/paths"
 )
 
 const emptyDictionaryName = "None (Empty)"
@@ -33,6 +34,29 @@ func (d dictionaryEntry) label() string {
 	return fmt.Sprintf("%s [%s->%s]", d.Name, d.SourceLang, d.TargetLang)
 }
 
+// dictionaryPath returns the file path of the named dictionary.
+func dictionaryPath(name string) (string, error) {
+	namesDir, err := paths.NamesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(namesDir, name+".json"), nil
+}
+
+// suggestedDictionaryFile returns the file name offered when saving a new
+// dictionary: the work title made safe for the OS and not yet taken in
+// namesDir, or "names.json" without a title.
+func suggestedDictionaryFile(namesDir, title string) string {
+	if strings.TrimSpace(title) == "" {
+		return "names.json"
+	}
+	path, _, err := files.SafePath(filepath.Join(namesDir, files.SanitizeFilename(title)+".json"))
+	if err != nil {
+		return "names.json"
+	}
+	return filepath.Base(path)
+}
+
 func dictMetaSourceKey(name string) string { return "DictMeta." + name + ".source" }
 func dictMetaTargetKey(name string) string { return "DictMeta." + name + ".target" }
 
@@ -71,9 +95,8 @@ func (a *focstApp) clearDictionaryMeta(name string) {
 }
 
 func (a *focstApp) listDictionaryEntries(parent fyne.Window) []dictionaryEntry {
-	home, _ := os.UserHomeDir()
-	namesDir := filepath.Join(home, ".focst", "names")
-	if err := os.MkdirAll(namesDir, 0700); err != nil {
+	namesDir, err := paths.NamesDir()
+	if err != nil {
 		a.reportDictListError(parent, fmt.Errorf("failed to create dictionary directory: %w", err))
 		return []dictionaryEntry{{Name: emptyDictionaryName}}
 	}
@@ -139,8 +162,10 @@ func (a *focstApp) loadDictionary(name string) (map[string]string, error) {
 	if name == "" || name == emptyDictionaryName {
 		return make(map[string]string), nil
 	}
-	home, _ := os.UserHomeDir()
-	path := filepath.Join(home, ".focst", "names", name+".json")
+	path, err := dictionaryPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate dictionary %q: %w", name, err)
+	}
 	sourceCode, targetCode := a.getDictionaryMeta(name)
 	if sourceCode == "" {
 		sourceCode = a.config.SourceLang
@@ -188,7 +213,9 @@ func (a *focstApp) deleteDictionary(name string) error {
 	if name == "" || name == emptyDictionaryName {
 		return nil
 	}
-	home, _ := os.UserHomeDir()
-	path := filepath.Join(home, ".focst", "names", name+".json")
+	path, err := dictionaryPath(name)
+	if err != nil {
+		return err
+	}
 	return os.Remove(path)
 }
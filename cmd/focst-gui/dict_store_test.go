@@ -161,3 +161,20 @@ func TestLoadDictionary_ValidFile(t *testing.T) {
 		t.Fatalf("loaded mapping mismatch: %#v", got)
 	}
 }
+
+func TestSuggestedDictionaryFile(t *testing.T) {
+	dir := t.TempDir()
+	if got := suggestedDictionaryFile(dir, ""); got != "names.json" {
+		t.Fatalf("without a title = %q, want names.json", got)
+	}
+	got := suggestedDictionaryFile(dir, "Fate/Zero")
+	if got != "Fate_Zero.json" {
+		t.Fatalf("suggested %q, want Fate_Zero.json", got)
+	}
+	if err := os.WriteFile(filepath.Join(dir, got), []byte("{}"), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if again := suggestedDictionaryFile(dir, "Fate/Zero"); again == got || filepath.Dir(filepath.Join(dir, again)) != dir {
+		t.Fatalf("suggested %q for a taken name, want a unique file in the names dir", again)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/oukeidos/focst/internal/language"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/metadata"
+	"github.com/oukeidos/focst/internal/paths"
 	"github.com/oukeidos/focst/internal/pipeline"
 	"github.com/oukeidos/focst/internal/recovery"
 )
@@ -49,6 +50,10 @@ type AppState int
 const (
 	StateIdle AppState = iota
 	StateProcessing
+	// StateCanceling keeps the processing view, labeled "Canceling…", from a
+	// cancel request until the job's goroutine returns and sets its final
+	// state.
+	StateCanceling
 	StateSuccess
 	StateFailure
 	StatePartialSuccess
@@ -64,7 +69,10 @@ type focstApp struct {
 	// UI Components
 	idleView           fyne.CanvasObject
 	processingView     fyne.CanvasObject
+	spinner            *largeSpinner
+	processingStatus   *widget.Label
 	successView        fyne.CanvasObject
+	reviewButton       *widget.Button
 	failureView        fyne.CanvasObject
 	partialSuccessView fyne.CanvasObject
 	canceledView       fyne.CanvasObject
@@ -77,7 +85,10 @@ type focstApp struct {
 	lastInputPath       string
 	lastRecoveryLogPath string
 	lastWasRepair       bool
+	lastReview          *pipeline.Review
+	lastReviewConfig    pipeline.Config
 	currentConfirmWin   fyne.Window
+	currentReviewWin    fyne.Window
 	currentSettingsWin  fyne.Window
 	config              AppConfig
 	activeDictLabel     *widget.Label
@@ -170,8 +181,34 @@ func (a *focstApp) cancelActive(reason string) {
 	}
 }
 
+// requestCancel stops the running job at the user's request. The view shows
+// "Canceling…" at once, since in-flight API calls can take a while to return;
+// the job's goroutine then sets the final state.
+func (a *focstApp) requestCancel() {
+	if a.state != StateProcessing {
+		return
+	}
+	a.setState(StateCanceling)
+	a.cancelActive("canceled by user")
+}
+
+// busy reports whether a job is running or still unwinding after a cancel.
+func (a *focstApp) busy() bool {
+	return a.state == StateProcessing || a.state == StateCanceling
+}
+
+// setProcessingStatus updates the status line under the spinner. Progress
+// that arrives after a cancel request does not replace "Canceling…". Call it
+// on the UI goroutine.
+func (a *focstApp) setProcessingStatus(status string) {
+	if a.processingStatus == nil || a.state == StateCanceling || a.processingStatus.Text == status {
+		return
+	}
+	a.processingStatus.SetText(status)
+}
+
 func (a *focstApp) syncMainKeyState() {
-	if a.state == StateProcessing {
+	if a.busy() {
 		return
 	}
 	key, _ := auth.GetKey("gemini", false)
@@ -318,50 +355,106 @@ func (r *tappableIconRenderer) Objects() []fyne.CanvasObject {
 
 func (r *tappableIconRenderer) Destroy() {}
 
-// largeSpinner is a custom breathing ring widget.
+// largeSpinner is a custom breathing ring widget. It animates only between
+// Start and Stop, and while it has a renderer, so no goroutine runs while the
+// spinner is hidden or after it is destroyed.
 type largeSpinner struct {
 	widget.BaseWidget
+	onTapped func()
+
+	mu     sync.Mutex
+	active bool
+	circle *canvas.Circle
+	stop   chan struct{}
+	// done is closed when the running animation goroutine returns.
+	done chan struct{}
 }
 
-func newLargeSpinner() *largeSpinner {
-	s := &largeSpinner{}
+func newLargeSpinner(onTapped func()) *largeSpinner {
+	s := &largeSpinner{onTapped: onTapped}
 	s.ExtendBaseWidget(s)
 	return s
 }
 
+func (s *largeSpinner) Tapped(_ *fyne.PointEvent) {
+	if s.onTapped != nil {
+		s.onTapped()
+	}
+}
+
+// Start begins the animation, or resumes it once the renderer exists.
+func (s *largeSpinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = true
+	s.startLocked()
+}
+
+// Stop pauses the animation and lets its goroutine return.
+func (s *largeSpinner) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = false
+	s.stopLocked()
+}
+
+func (s *largeSpinner) startLocked() {
+	if !s.active || s.circle == nil || s.stop != nil {
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	s.stop, s.done = stop, done
+	circle := s.circle
+	safeGo("ui.spinner.animate", func() {
+		defer close(done)
+		animateSpinner(circle, stop)
+	})
+}
+
+func (s *largeSpinner) stopLocked() {
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// animateSpinner fades the ring's stroke in and out every 50ms until stop is
+// closed.
+func animateSpinner(c *canvas.Circle, stop <-chan struct{}) {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for frame := 0; ; frame = (frame + 1) % 42 {
+		step := frame
+		if step > 20 {
+			step = 41 - step
+		}
+		alpha := uint8(50 + 150*float32(step)/20)
+		baseColor := theme.Color(theme.ColorNamePrimary)
+		red, g, b, _ := baseColor.RGBA()
+		safeDo("ui.spinner.frame", func() {
+			c.StrokeColor = color.NRGBA{R: uint8(red >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: alpha}
+			canvas.Refresh(c)
+		})
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (s *largeSpinner) CreateRenderer() fyne.WidgetRenderer {
 	c := canvas.NewCircle(color.Transparent)
 	c.StrokeColor = theme.Color(theme.ColorNamePrimary)
 	c.StrokeWidth = 8 // Even thicker
 
-	r := &largeSpinnerRenderer{circle: c, s: s}
+	s.mu.Lock()
+	s.stopLocked()
+	s.circle = c
+	s.startLocked()
+	s.mu.Unlock()
 
-	safeGo("ui.spinner.animate", func() {
-		for {
-			for i := 0; i <= 20; i++ {
-				alpha := uint8(50 + 150*float32(i)/20)
-				baseColor := theme.Color(theme.ColorNamePrimary)
-				red, g, b, _ := baseColor.RGBA()
-				safeDo("ui.spinner.frame_in", func() {
-					c.StrokeColor = color.NRGBA{R: uint8(red >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: alpha}
-					r.Refresh()
-				})
-				time.Sleep(50 * time.Millisecond)
-			}
-			for i := 20; i >= 0; i-- {
-				alpha := uint8(50 + 150*float32(i)/20)
-				baseColor := theme.Color(theme.ColorNamePrimary)
-				red, g, b, _ := baseColor.RGBA()
-				safeDo("ui.spinner.frame_out", func() {
-					c.StrokeColor = color.NRGBA{R: uint8(red >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: alpha}
-					r.Refresh()
-				})
-				time.Sleep(50 * time.Millisecond)
-			}
-		}
-	})
-
-	return r
+	return &largeSpinnerRenderer{circle: c, s: s}
 }
 
 type largeSpinnerRenderer struct {
@@ -387,14 +480,36 @@ func (r *largeSpinnerRenderer) Objects() []fyne.CanvasObject {
 	return []fyne.CanvasObject{r.circle}
 }
 
-func (r *largeSpinnerRenderer) Destroy() {}
+// Destroy ends the animation of this renderer's circle. Start state is kept,
+// so a new renderer picks the animation up again.
+func (r *largeSpinnerRenderer) Destroy() {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.s.circle == r.circle {
+		r.s.stopLocked()
+		r.s.circle = nil
+	}
+}
 
 func (a *focstApp) setupUI() {
 	// Pre-build all views once
 	a.idleView = container.NewCenter(newDropZone(a.showFilePicker))
-	a.processingView = container.NewCenter(newLargeSpinner())
+	a.processingStatus = widget.NewLabel("")
+	a.processingStatus.Alignment = fyne.TextAlignCenter
+	a.spinner = newLargeSpinner(func() {
+		if a.state != StateProcessing {
+			return
+		}
+		a.confirmWindow("Cancel Process", "Stop the running job?", a.requestCancel, nil)
+	})
+	a.processingView = container.NewCenter(container.NewVBox(a.spinner, a.processingStatus))
 
-	a.successView = container.NewCenter(newColoredIcon(theme.ConfirmIcon(), theme.ColorNameSuccess, func() { a.setState(StateIdle) }))
+	a.reviewButton = widget.NewButton("Review segments", a.showReviewWindow)
+	a.reviewButton.Hide()
+	a.successView = container.NewCenter(container.NewVBox(
+		newColoredIcon(theme.ConfirmIcon(), theme.ColorNameSuccess, func() { a.setState(StateIdle) }),
+		a.reviewButton,
+	))
 	a.failureView = container.NewCenter(newColoredIcon(theme.CancelIcon(), theme.ColorNameError, func() {
 		a.showConfirmWindow("Retry Process", "The process failed. Would you like to retry?", func() {
 			if a.lastWasRepair {
@@ -536,12 +651,13 @@ func (a *focstApp) showSettingsWindow() {
 	codeToName := make(map[string]string)
 	nameToCode := make(map[string]string)
 	for _, l := range allLangs {
-		if l.ID == "zh" {
-			continue // Skip redundant Chinese Simplified root
+		if l.ID != l.Code {
+			continue // Skip aliases such as "zh"; the code they resolve to is listed itself
 		}
-		langNames = append(langNames, l.Name)
-		codeToName[l.Code] = l.Name
-		nameToCode[l.Name] = l.Code
+		label := languageLabel(l)
+		langNames = append(langNames, label)
+		codeToName[l.Code] = label
+		nameToCode[label] = l.Code
 	}
 	refreshDictionaryOptions := func() {}
 
@@ -558,6 +674,9 @@ func (a *focstApp) showSettingsWindow() {
 			dialog.ShowError(fmt.Errorf("source and target languages cannot be the same"), w)
 			return
 		}
+		if srcScript, tgtScript := language.ChineseScript(a.config.SourceLang), language.ChineseScript(selectedCode); srcScript != "" && tgtScript != "" {
+			dialog.ShowInformation("Chinese Script", fmt.Sprintf("Output will be converted from %s to %s Chinese.", srcScript, tgtScript), w)
+		}
 		a.config.TargetLang = selectedCode
 		a.saveConfig()
 		refreshDictionaryOptions()
@@ -769,8 +888,11 @@ func (a *focstApp) showSettingsWindow() {
 			dialog.ShowError(fmt.Errorf("no dictionary selected to overwrite"), w)
 			return
 		}
-		home, _ := os.UserHomeDir()
-		path := filepath.Join(home, ".focst", "names", selectedDictName+".json")
+		path, err := dictionaryPath(selectedDictName)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
 		dialog.ShowConfirm("Confirm Overwrite", "Overwrite the existing dictionary file?", func(ok bool) {
 			if ok {
 				saveAction(path)
@@ -779,8 +901,11 @@ func (a *focstApp) showSettingsWindow() {
 	}
 
 	saveAsBtn.OnTapped = func() {
-		home, _ := os.UserHomeDir()
-		namesDir := filepath.Join(home, ".focst", "names")
+		namesDir, err := paths.NamesDir()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
 		fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
 			if err != nil || writer == nil {
 				return
@@ -798,7 +923,7 @@ func (a *focstApp) showSettingsWindow() {
 			saveAction(savePath)
 		}, w)
 		fd.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
-		fd.SetFileName("names.json")
+		fd.SetFileName(suggestedDictionaryFile(namesDir, workTitle.Text))
 		if uri := storage.NewFileURI(namesDir); uri != nil {
 			if lister, err := storage.ListerForURI(uri); err == nil {
 				fd.SetLocation(lister)
@@ -947,6 +1072,12 @@ func (a *focstApp) showSettingsWindow() {
 	})
 	langPostprocessCheck.SetChecked(!a.config.NoLangPostprocess)
 
+	timingCorrectionCheck := widget.NewCheck("Timing Correction", func(b bool) {
+		a.config.NoTimingCorrection = !b
+		a.saveConfig()
+	})
+	timingCorrectionCheck.SetChecked(!a.config.NoTimingCorrection)
+
 	maxTokensEntry := newFixedWidthEntry(120)
 	maxTokensEntry.SetText(strconv.Itoa(a.config.ExtractionMaxTokens))
 	maxTokensEntry.OnChanged = func(s string) {
@@ -973,6 +1104,7 @@ func (a *focstApp) showSettingsWindow() {
 		a.config.NoPostprocess = false
 		a.config.NoLangPreprocess = false
 		a.config.NoLangPostprocess = false
+		a.config.NoTimingCorrection = false
 		a.config.ExtractionMaxTokens = 16384
 
 		// Update UI
@@ -985,6 +1117,7 @@ func (a *focstApp) showSettingsWindow() {
 		langPreprocessCheck.SetChecked(true)
 		postprocessCheck.SetChecked(true)
 		langPostprocessCheck.SetChecked(true)
+		timingCorrectionCheck.SetChecked(true)
 		maxTokensEntry.SetText("16384")
 
 		a.saveConfig()
@@ -1014,6 +1147,7 @@ func (a *focstApp) showSettingsWindow() {
 		langPreprocessCheck,
 		postprocessCheck,
 		langPostprocessCheck,
+		timingCorrectionCheck,
 	)
 
 	advancedGrid := container.NewGridWithColumns(2, leftCol, rightCol)
@@ -1236,6 +1370,7 @@ func (a *focstApp) setState(s AppState) {
 		a.state = s
 		a.idleView.Hide()
 		a.processingView.Hide()
+		a.spinner.Stop()
 		a.successView.Hide()
 		a.failureView.Hide()
 		a.partialSuccessView.Hide()
@@ -1246,10 +1381,25 @@ func (a *focstApp) setState(s AppState) {
 		case StateIdle:
 			a.idleView.Show()
 		case StateProcessing:
+			if a.processingStatus != nil {
+				a.processingStatus.SetText("")
+			}
+			a.spinner.Start()
+			a.processingView.Show()
+		case StateCanceling:
+			if a.processingStatus != nil {
+				a.processingStatus.SetText("Canceling…")
+			}
+			a.spinner.Start()
 			a.processingView.Show()
 		case StateNoKey:
 			a.apiKeyView.Show()
 		case StateSuccess:
+			if a.lastReview != nil {
+				a.reviewButton.Show()
+			} else {
+				a.reviewButton.Hide()
+			}
 			a.successView.Show()
 		case StatePartialSuccess:
 			a.partialSuccessView.Show()
@@ -1309,6 +1459,12 @@ func (a *focstApp) flashRed() {
 }
 
 func (a *focstApp) showConfirmWindow(title, message string, onYes func()) {
+	a.confirmWindow(title, message, onYes, func() { a.setState(StateIdle) })
+}
+
+// confirmWindow asks a yes/no question in its own window. onNo may be nil to
+// just close it.
+func (a *focstApp) confirmWindow(title, message string, onYes, onNo func()) {
 	if a.currentConfirmWin != nil {
 		a.currentConfirmWin.RequestFocus()
 		return
@@ -1333,7 +1489,9 @@ func (a *focstApp) showConfirmWindow(title, message string, onYes func()) {
 
 	noBtn := newHugeButton("NO", color.NRGBA{R: 200, G: 200, B: 200, A: 255}, func() {
 		confirmWin.Close()
-		a.setState(StateIdle)
+		if onNo != nil {
+			onNo()
+		}
 	})
 
 	btns := container.NewGridWithColumns(2, yesBtn, noBtn)
@@ -1350,7 +1508,7 @@ func (a *focstApp) showConfirmWindow(title, message string, onYes func()) {
 }
 
 func (a *focstApp) handleDropped(uri fyne.URI) {
-	if a.state == StateProcessing {
+	if a.busy() {
 		return
 	}
 
@@ -1542,6 +1700,7 @@ func main() {
 	w.CenterOnScreen()
 
 	fa := newFocstApp(w)
+	myApp.Lifecycle().SetOnStarted(fa.offerResume)
 	w.SetCloseIntercept(func() {
 		fa.cancelActive("window closed")
 		fa.sessionKey = ""
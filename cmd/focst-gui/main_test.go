@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+// spinnerDone returns the channel closed when the spinner's current animation
+// goroutine returns, or nil when none was started.
+func spinnerDone(s *largeSpinner) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+func waitSpinnerStopped(t *testing.T, done chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("spinner animation goroutine did not exit")
+	}
+}
+
+func TestLargeSpinner_AnimatesOnlyWhileStarted(t *testing.T) {
+	test.NewTempApp(t)
+	s := newLargeSpinner(nil)
+	r := s.CreateRenderer()
+	if spinnerDone(s) != nil {
+		t.Fatal("spinner animates before Start")
+	}
+
+	s.Start()
+	first := spinnerDone(s)
+	if first == nil {
+		t.Fatal("Start did not begin the animation")
+	}
+	s.Start()
+	if spinnerDone(s) != first {
+		t.Fatal("a second Start began another animation")
+	}
+	s.Stop()
+	waitSpinnerStopped(t, first)
+
+	s.Start()
+	second := spinnerDone(s)
+	if second == first {
+		t.Fatal("Start after Stop did not resume the animation")
+	}
+	r.Destroy()
+	waitSpinnerStopped(t, second)
+}
+
+func TestLargeSpinner_StartBeforeRenderer(t *testing.T) {
+	test.NewTempApp(t)
+	s := newLargeSpinner(nil)
+	s.Start()
+	if spinnerDone(s) != nil {
+		t.Fatal("spinner animates without a renderer")
+	}
+	r := s.CreateRenderer()
+	done := spinnerDone(s)
+	if done == nil {
+		t.Fatal("new renderer did not pick up the started animation")
+	}
+	r.Destroy()
+	waitSpinnerStopped(t, done)
+}
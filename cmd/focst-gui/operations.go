@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -16,6 +17,7 @@ import (
 	"github.com/oukeidos/focst/internal/names"
 	"github.com/oukeidos/focst/internal/openai"
 	"github.com/oukeidos/focst/internal/pipeline"
+	"github.com/oukeidos/focst/internal/recovery"
 	"github.com/oukeidos/focst/internal/srt"
 	"github.com/oukeidos/focst/internal/translator"
 )
@@ -23,8 +25,16 @@ import (
 var errOpenAIKeyMissing = errors.New("openai api key is required")
 
 func (a *focstApp) startTranslation(inputPath string) {
+	outputPath := srt.GenerateOutputPath(inputPath, language.Languages[a.config.TargetLang].Code)
+	a.runTranslation(inputPath, outputPath, a.config.pipelineOptions(), nil)
+}
+
+// runTranslation translates inputPath with opts. resume continues the
+// session of an earlier launch; when nil, a new resume token is started.
+func (a *focstApp) runTranslation(inputPath, outputPath string, opts pipeline.Options, resume *pipeline.ResumeToken) {
 	a.setState(StateProcessing)
 	a.lastRecoveryLogPath = ""
+	a.lastReview = nil
 
 	// Mock flow for debug files
 	if state, ok := debugStateForPath(inputPath); ok {
@@ -44,33 +54,24 @@ func (a *focstApp) startTranslation(inputPath string) {
 		return
 	}
 
-	// Prepare Config
-	// Note: We need to resolve languages here or inside pipeline? Pipeline expects codes/names.
-	// Config has SourceLang/TargetLang strings which pipeline resolves.
-	cfg := pipeline.Config{
-		InputPath:         inputPath,
-		OutputPath:        srt.GenerateOutputPath(inputPath, language.Languages[a.config.TargetLang].Code),
-		APIKey:            apiKey,
-		Model:             a.config.Model,
-		ChunkSize:         a.config.ChunkSize,
-		ContextSize:       a.config.ContextSize,
-		Concurrency:       a.config.Concurrency,
-		RetryOnLongLines:  a.config.RetryOnLongLines,
-		NoPromptCPL:       a.config.NoPromptCPL,
-		NoPreprocess:      a.config.NoPreprocess,
-		NoPostprocess:     a.config.NoPostprocess,
-		NoLangPreprocess:  a.config.NoLangPreprocess,
-		NoLangPostprocess: a.config.NoLangPostprocess,
-		SourceLang:        a.config.SourceLang,
-		TargetLang:        a.config.TargetLang,
-		NamesMapping:      a.config.NamesMapping,
-		OnProgress: func(p translator.TranslationProgress) {
-			// Update UI with progress?
-			// The original GUI didn't seem to show detailed chunk progress in the main view,
-			// just StateProcessing spinner/text.
-			// We can log it.
-			logger.Info("GUI Progress", "chunk", p.ChunkIndex, "status", p.State)
-		},
+	cfg, err := pipeline.NewConfig(inputPath, outputPath, apiKey, opts)
+	if err != nil {
+		logger.Error("Translation failed", "error", err)
+		a.setState(StateFailure)
+		return
+	}
+	if resume == nil {
+		resume = newResumeToken(inputPath, outputPath, opts)
+	}
+	cfg.Resume = resume
+	throttle := newRateLimitTracker()
+	cfg.OnProgress = func(p translator.TranslationProgress) {
+		// Update UI with progress?
+		// The original GUI didn't seem to show detailed chunk progress in the main view,
+		// just StateProcessing spinner/text.
+		// We can log it.
+		logger.Info("GUI Progress", "chunk", p.ChunkIndex, "status", p.State)
+		a.showThrottleStatus(throttle, p)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -78,6 +79,7 @@ func (a *focstApp) startTranslation(inputPath string) {
 	a.safeGo("ops.translate", func() {
 		defer a.clearActiveCancel(cancelID)
 		result, err := pipeline.RunTranslation(ctx, cfg)
+		finishResumeToken(resume, err)
 		if err != nil {
 			a.lastRecoveryLogPath = ""
 			if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
@@ -94,12 +96,17 @@ func (a *focstApp) startTranslation(inputPath string) {
 			return
 		}
 		a.lastRecoveryLogPath = result.RecoveryLogPath
+		if result.Review != nil {
+			a.lastReview = result.Review
+			a.lastReviewConfig = cfg
+		}
 		a.setState(stateForTranslationResult(result))
 	})
 }
 
 func (a *focstApp) startRepair(logPath string) {
 	a.setState(StateProcessing)
+	a.lastReview = nil
 
 	// Mock flow for debug files
 	if state, ok := debugStateForPath(logPath); ok {
@@ -122,13 +129,18 @@ func (a *focstApp) startRepair(logPath string) {
 	cfg := pipeline.Config{
 		LogPath:           logPath,
 		APIKey:            apiKey,
+		RampUp:            translator.DefaultRampUp,
 		RetryOnLongLines:  a.config.RetryOnLongLines,
 		NoPromptCPL:       a.config.NoPromptCPL,
 		NoPostprocess:     a.config.NoPostprocess,
 		NoLangPostprocess: a.config.NoLangPostprocess,
 		NamesMapping:      a.config.NamesMapping,
-		OnProgress: func(p translator.TranslationProgress) {
-			logger.Info("GUI Repair Progress", "chunk", p.ChunkIndex, "state", p.State)
+		OnRepairProgress: func(p recovery.RepairProgress) {
+			logger.Info("GUI Repair Progress", "chunk", p.ChunkIndex, "state", p.State, "repaired", p.Repaired, "targets", p.Targets)
+			status := repairStatusText(p)
+			a.safeDo("ops.repair.progress", func() {
+				a.setProcessingStatus(status)
+			})
 		},
 	}
 
@@ -155,6 +167,46 @@ func (a *focstApp) startRepair(logPath string) {
 	})
 }
 
+// startRetranslation re-translates the chunks holding the given segments of the
+// last successful run. Chunks that fail keep their previous translation.
+func (a *focstApp) startRetranslation(indices []int) {
+	review := a.lastReview
+	if review == nil {
+		return
+	}
+	a.setState(StateProcessing)
+
+	cfg := a.lastReviewConfig
+	throttle := newRateLimitTracker()
+	cfg.OnProgress = func(p translator.TranslationProgress) {
+		logger.Info("GUI Re-translation Progress", "chunk", p.ChunkIndex, "status", p.State)
+		a.showThrottleStatus(throttle, p)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelID := a.setActiveCancel(cancel)
+	a.safeGo("ops.retranslate", func() {
+		defer a.clearActiveCancel(cancelID)
+		result, err := pipeline.RunRetranslation(ctx, cfg, review, indices)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+				a.setState(StateCanceled)
+				return
+			}
+			logger.Error("Re-translation failed", "error", err)
+			a.setState(StateFailure)
+			return
+		}
+		if result.FailedChunks > 0 {
+			a.safeDo("ops.retranslate.failed_dialog", func() {
+				dialog.ShowInformation("Re-translation",
+					fmt.Sprintf("%d of %d chunks could not be re-translated and keep their previous translation.", result.FailedChunks, result.TotalChunks), a.window)
+			})
+		}
+		a.setState(StateSuccess)
+	})
+}
+
 func (a *focstApp) startNameExtraction(workType, title, year string, parent fyne.Window, onDone func(map[string]string, error)) {
 	key, _ := auth.GetKey("openai", false) // names.Extractor currently uses openai client
 	if key == "" {
@@ -173,7 +225,7 @@ func (a *focstApp) startNameExtraction(workType, title, year string, parent fyne
 	a.safeGo("ops.names.extract", func() {
 		defer a.clearActiveCancel(cancelID)
 		client := openai.NewClient(key, "gpt-5.2")
-		ex := names.NewExtractor(client)
+		ex := names.NewExtractor(names.NewOpenAIProvider(client))
 		src := a.config.SourceLang
 		tgt := a.config.TargetLang
 
@@ -242,3 +294,53 @@ func stateForTranslationResult(result pipeline.TranslationResult) AppState {
 		return StateFailure
 	}
 }
+
+func repairStatusText(p recovery.RepairProgress) string {
+	switch p.State {
+	case translator.StateInProgress:
+		if p.RateLimited {
+			return fmt.Sprintf("%s %d of %d repaired", rateLimitedStatus, p.Repaired, p.Targets)
+		}
+		return fmt.Sprintf("Retrying chunk %d (attempt %d)... %d of %d repaired", p.ChunkIndex+1, p.Attempt, p.Repaired, p.Targets)
+	default:
+		return fmt.Sprintf("Repaired %d of %d chunks", p.Repaired, p.Targets)
+	}
+}
+
+const rateLimitedStatus = "Rate limited, backing off…"
+
+// rateLimitTracker follows which chunks are retrying after a rate-limit error.
+// Progress events arrive from concurrent workers.
+type rateLimitTracker struct {
+	mu      sync.Mutex
+	waiting map[int]bool
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{waiting: make(map[int]bool)}
+}
+
+// update records p and returns the processing status text: the rate-limit
+// notice while any chunk is backing off after a 429, otherwise empty.
+func (r *rateLimitTracker) update(p translator.TranslationProgress) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case p.State == translator.StateInProgress && p.RateLimited:
+		r.waiting[p.ChunkIndex] = true
+	case p.State == translator.StateInProgress, p.State == translator.StateCompleted:
+		delete(r.waiting, p.ChunkIndex)
+	}
+	if len(r.waiting) > 0 {
+		return rateLimitedStatus
+	}
+	return ""
+}
+
+// showThrottleStatus shows or clears the rate-limit notice under the spinner.
+func (a *focstApp) showThrottleStatus(r *rateLimitTracker, p translator.TranslationProgress) {
+	status := r.update(p)
+	a.safeDo("ops.throttle_status", func() {
+		a.setProcessingStatus(status)
+	})
+}
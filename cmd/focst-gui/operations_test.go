@@ -4,8 +4,11 @@ import (
 	"path/filepath"
 	"testing"
 
+	"fyne.io/fyne/v2/storage"
+
 	"github.com/oukeidos/focst/internal/pipeline"
 	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/translator"
 )
 
 func TestIsModelNotFound(t *testing.T) {
@@ -91,3 +94,57 @@ func TestPartialSuccessRepairLogPath(t *testing.T) {
 type errString string
 
 func (e errString) Error() string { return string(e) }
+
+func TestRateLimitTracker(t *testing.T) {
+	r := newRateLimitTracker()
+	steps := []struct {
+		p    translator.TranslationProgress
+		want string
+	}{
+		{p: translator.TranslationProgress{ChunkIndex: 0, State: translator.StateStarted}, want: ""},
+		{p: translator.TranslationProgress{ChunkIndex: 0, State: translator.StateInProgress, RateLimited: true}, want: rateLimitedStatus},
+		{p: translator.TranslationProgress{ChunkIndex: 1, State: translator.StateInProgress, RateLimited: true}, want: rateLimitedStatus},
+		{p: translator.TranslationProgress{ChunkIndex: 0, State: translator.StateCompleted}, want: rateLimitedStatus},
+		{p: translator.TranslationProgress{ChunkIndex: 1, State: translator.StateInProgress}, want: ""},
+	}
+	for i, step := range steps {
+		if got := r.update(step.p); got != step.want {
+			t.Fatalf("step %d: update() = %q, want %q", i, got, step.want)
+		}
+	}
+}
+
+func TestRepairStatusText_RateLimited(t *testing.T) {
+	p := recovery.RepairProgress{
+		TranslationProgress: translator.TranslationProgress{ChunkIndex: 2, Attempt: 2, State: translator.StateInProgress, RateLimited: true},
+		Repaired:            1,
+		Targets:             3,
+	}
+	if got, want := repairStatusText(p), rateLimitedStatus+" 1 of 3 repaired"; got != want {
+		t.Fatalf("repairStatusText() = %q, want %q", got, want)
+	}
+	p.RateLimited = false
+	if got, want := repairStatusText(p), "Retrying chunk 3 (attempt 2)... 1 of 3 repaired"; got != want {
+		t.Fatalf("repairStatusText() = %q, want %q", got, want)
+	}
+}
+
+func TestHandleDropped_IgnoredWhileCanceling(t *testing.T) {
+	for _, state := range []AppState{StateProcessing, StateCanceling} {
+		app := &focstApp{state: state}
+		app.handleDropped(storage.NewFileURI("/tmp/next.srt"))
+		if app.lastInputPath != "" {
+			t.Fatalf("drop in state %d was handled (lastInputPath %q)", state, app.lastInputPath)
+		}
+	}
+}
+
+func TestRequestCancel_OnlyWhileProcessing(t *testing.T) {
+	canceled := false
+	app := &focstApp{state: StateCanceling}
+	app.setActiveCancel(func() { canceled = true })
+	app.requestCancel()
+	if canceled || app.state != StateCanceling {
+		t.Fatalf("requestCancel acted outside StateProcessing (canceled=%v, state=%d)", canceled, app.state)
+	}
+}
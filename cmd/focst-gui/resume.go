@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/paths"
+	"github.com/oukeidos/focst/internal/pipeline"
+)
+
+// newResumeToken starts the resume token of a translation at
+// paths.ResumeTokenPath, replacing any earlier one. The run goes ahead
+// without a token when it cannot be saved.
+func newResumeToken(inputPath, outputPath string, opts pipeline.Options) *pipeline.ResumeToken {
+	path, err := paths.ResumeTokenPath()
+	if err == nil {
+		var token *pipeline.ResumeToken
+		if token, err = pipeline.NewResumeToken(path, inputPath, outputPath, opts); err == nil {
+			return token
+		}
+	}
+	logger.Warn("Resume token disabled", "error", err)
+	return nil
+}
+
+// finishResumeToken removes the token once its run returned normally, since
+// the output or the session log now holds the work. After an error or a
+// cancel (including closing the window mid-run) the token is kept, so the
+// next launch offers to resume.
+func finishResumeToken(token *pipeline.ResumeToken, runErr error) {
+	if token == nil || runErr != nil {
+		return
+	}
+	if err := token.Remove(); err != nil {
+		logger.Warn("Failed to remove resume token", "error", err)
+	}
+}
+
+// pendingResumeToken returns the token of a translation an earlier launch
+// did not finish, or nil. A token whose input is gone or changed is removed.
+func pendingResumeToken(path string) *pipeline.ResumeToken {
+	token, err := pipeline.LoadResumeToken(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Discarding unreadable resume token", "path", path, "error", err)
+			_ = os.Remove(path)
+		}
+		return nil
+	}
+	if err := token.CheckInput(); err != nil {
+		logger.Warn("Discarding resume token", "input", token.InputPath, "error", err)
+		_ = token.Remove()
+		return nil
+	}
+	return token
+}
+
+// offerResume asks whether to resume the translation an earlier launch left
+// unfinished. Resuming runs it again with its original settings; completed
+// chunks come from the token and only the remaining ones are translated.
+func (a *focstApp) offerResume() {
+	path, err := paths.ResumeTokenPath()
+	if err != nil {
+		return
+	}
+	token := pendingResumeToken(path)
+	if token == nil {
+		return
+	}
+	logger.Info("Found unfinished translation", "input", token.InputPath, "completed_chunks", token.CompletedChunks())
+	title := fmt.Sprintf("Resume Last Session: %s", filepath.Base(token.InputPath))
+	a.confirmWindow(title, "Resume last session?", func() {
+		a.lastInputPath = token.InputPath
+		a.lastWasRepair = false
+		go a.runTranslation(token.InputPath, token.OutputPath, token.Options, token)
+	}, func() {
+		if err := token.Remove(); err != nil {
+			logger.Warn("Failed to remove resume token", "error", err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// showReviewWindow lists the segments of the last successful run so the user
+// can pick cues to re-translate.
+func (a *focstApp) showReviewWindow() {
+	if a.currentReviewWin != nil {
+		a.currentReviewWin.RequestFocus()
+		return
+	}
+	review := a.lastReview
+	if review == nil {
+		return
+	}
+
+	w := fyne.CurrentApp().NewWindow("Review Translation")
+	a.currentReviewWin = w
+	w.SetOnClosed(func() {
+		a.currentReviewWin = nil
+	})
+
+	checked := make(map[int]bool)
+	var retranslateBtn *widget.Button
+	updateButton := func() {
+		retranslateBtn.SetText(fmt.Sprintf("Re-translate selected (%d)", len(checked)))
+		if len(checked) == 0 {
+			retranslateBtn.Disable()
+		} else {
+			retranslateBtn.Enable()
+		}
+	}
+
+	list := widget.NewList(
+		func() int { return len(review.Source) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Wrapping = fyne.TextWrapWord
+			return container.NewBorder(nil, nil, widget.NewCheck("", nil), nil, label)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			check := row.Objects[1].(*widget.Check)
+			label.SetText(reviewRowText(review.Source[id], review.Translated[id]))
+			check.OnChanged = nil
+			check.SetChecked(checked[id])
+			check.OnChanged = func(on bool) {
+				if on {
+					checked[id] = true
+				} else {
+					delete(checked, id)
+				}
+				updateButton()
+			}
+		},
+	)
+
+	retranslateBtn = widget.NewButton("", func() {
+		indices := checkedIndices(checked)
+		w.Close()
+		go a.startRetranslation(indices)
+	})
+	retranslateBtn.Importance = widget.HighImportance
+	updateButton()
+
+	closeBtn := widget.NewButton("Close", func() { w.Close() })
+	footer := container.NewPadded(container.NewHBox(closeBtn, retranslateBtn))
+
+	w.SetContent(container.NewBorder(nil, container.NewCenter(footer), nil, nil, list))
+	w.Resize(fyne.NewSize(700, 600))
+	w.CenterOnScreen()
+	w.Show()
+}
+
+// reviewRowText renders one review row as "#ID source → translation".
+func reviewRowText(source, translated srt.Segment) string {
+	return fmt.Sprintf("#%d  %s\n→ %s", source.ID, strings.Join(source.Lines, " "), strings.Join(translated.Lines, " "))
+}
+
+// checkedIndices returns the checked segment indices in ascending order.
+func checkedIndices(checked map[int]bool) []int {
+	indices := make([]int, 0, len(checked))
+	for idx, on := range checked {
+		if on {
+			indices = append(indices, idx)
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestCheckedIndices(t *testing.T) {
+	got := checkedIndices(map[int]bool{7: true, 2: true, 4: false, 0: true})
+	if want := []int{0, 2, 7}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("checkedIndices = %v, want %v", got, want)
+	}
+}
+
+func TestReviewRowText(t *testing.T) {
+	got := reviewRowText(
+		srt.Segment{ID: 3, Lines: []string{"Hello", "there"}},
+		srt.Segment{ID: 3, Lines: []string{"안녕"}},
+	)
+	if want := "#3  Hello there\n→ 안녕"; got != want {
+		t.Fatalf("reviewRowText = %q, want %q", got, want)
+	}
+}
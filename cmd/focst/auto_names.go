@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/names"
+	"github.com/oukeidos/focst/internal/openai"
+)
+
+// namesModel is the OpenAI model used for name extraction.
+const namesModel = "gpt-5.2"
+
+// namesGeminiModel is the Gemini model used by "names --provider gemini".
+const namesGeminiModel = "gemini-3-flash-preview"
+
+// Name extraction providers for "names --provider".
+const (
+	namesProviderOpenAI = "openai"
+	namesProviderGemini = "gemini"
+)
+
+// defaultNamesMaxTokens is the output budget, reasoning included, for name
+// extraction.
+const defaultNamesMaxTokens = 16384
+
+// autoNamesResult is the glossary built by --auto-names and what it cost.
+type autoNamesResult struct {
+	mapping map[string]string
+	usage   names.Usage
+	model   string
+}
+
+// validateAutoNames checks the --auto-names flag combination.
+func (o *translateOptions) validateAutoNames() error {
+	if !o.autoNames {
+		return nil
+	}
+	if o.title == "" {
+		return fmt.Errorf("--auto-names requires --title")
+	}
+	if o.namesPath != "" {
+		return fmt.Errorf("--auto-names and --names cannot be used together")
+	}
+	return nil
+}
+
+// runAutoNames extracts a names mapping with OpenAI for --auto-names, kept in
+// memory for this run only. Without an OpenAI key, or when extraction fails,
+// it logs a warning and returns a nil result so translation goes ahead
+// without names. It only returns an error when ctx is canceled.
+func runAutoNames(ctx context.Context, opts *translateOptions) (*autoNamesResult, error) {
+	key, source, err := resolveAPIKey("openai", opts.allowEnv, opts.envOnly)
+	if err != nil {
+		logger.Warn("Skipping --auto-names: no OpenAI API key", "error", err)
+		return nil, nil
+	}
+	logger.Info("Using API Key", "service", "openai", "source", source)
+
+	client := openai.NewClient(key, namesModel)
+	client.SetRequestTimeout(opts.requestTimeout)
+	extractor := names.NewExtractor(names.NewOpenAIProvider(client))
+
+	logger.Info("Extracting character names", "title", opts.title, "type", opts.workType)
+	mappings, usage, err := extractor.Extract(ctx, opts.workType, opts.title, opts.year, defaultNamesMaxTokens, opts.sourceLangCode, opts.targetLangCode)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logger.Warn("Name extraction failed; translating without names", "error", err)
+		return &autoNamesResult{usage: usage, model: client.GetModelID()}, nil
+	}
+
+	mapping := make(map[string]string, len(mappings))
+	for _, m := range mappings {
+		mapping[m.Source] = m.Target
+	}
+	logger.Info("Extracted character names", "count", len(mapping))
+	return &autoNamesResult{mapping: mapping, usage: usage, model: client.GetModelID()}, nil
+}
+
+// printAutoNamesStats adds the name extraction usage to the execution stats,
+// followed by the combined cost of both APIs.
+func printAutoNamesStats(r *autoNamesResult, geminiCost float64) {
+	if r == nil {
+		return
+	}
+	cost := estimateNamesCost(namesProviderOpenAI, r.model, r.usage)
+	fmt.Printf("Names Model: %s\n", r.model)
+	fmt.Printf("Names Tokens: In=%d, Out=%d, Total=%d, Web=%d\n", r.usage.InputTokens, r.usage.OutputTokens, r.usage.TotalTokens, r.usage.WebSearchCalls)
+	fmt.Printf("Names Estimated Cost: $%.5f\n", cost)
+	fmt.Printf("Total Estimated Cost: $%.5f (Gemini + OpenAI)\n", geminiCost+cost)
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/signal"
 	"strings"
@@ -11,11 +13,11 @@ import (
 
 	"github.com/oukeidos/focst/internal/auth"
 	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/httpclient"
 	"github.com/oukeidos/focst/internal/language"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/metadata"
 	"github.com/oukeidos/focst/internal/names"
-	"github.com/oukeidos/focst/internal/openai"
 	"golang.org/x/term"
 )
 
@@ -27,7 +29,27 @@ var (
 	promptForKey = auth.PromptForAPIKey
 )
 
-// resolveAPIKey handles the logic for finding the API key.
+// initLogger sets up the global logger from the --no-color and
+// --log-unredacted flags. Disabled redaction is announced with a warning.
+func initLogger(level slog.Level, logFile io.Writer, noColor, unredacted bool) {
+	logger.InitWithOptions(level, logFile, logger.Options{DisableRedaction: unredacted, NoColor: noColor})
+	if unredacted {
+		logger.Warn("Log redaction disabled (--log-unredacted); API keys and subtitle text may appear in logs")
+	}
+}
+
+// configureTransport applies --insecure-skip-verify and --max-conns-per-host
+// to the shared HTTP transport before any request is made, with a warning
+// when TLS verification is disabled.
+func configureTransport(opts httpclient.TransportOptions) {
+	httpclient.SetTransportOptions(opts)
+	if opts.InsecureSkipVerify {
+		logger.Warn("TLS certificate verification disabled (--insecure-skip-verify); API keys and subtitle text can be read by anyone intercepting the connection. Use it only behind a trusted corporate proxy.")
+	}
+}
+
+// resolveAPIKey handles the logic for finding the API key. Its errors exit
+// with exitAuth.
 func resolveAPIKey(service string, allowEnv, envOnly bool) (string, string, error) {
 	if envOnly {
 		allowEnv = true
@@ -36,7 +58,7 @@ func resolveAPIKey(service string, allowEnv, envOnly bool) (string, string, erro
 		if key, ok := getEnvKey(service); ok {
 			return key, "Environment Variable", nil
 		}
-		return "", "", fmt.Errorf("env-only set but %s_API_KEY is not set", strings.ToUpper(service))
+		return "", "", withExitCode(exitAuth, fmt.Errorf("env-only set but %s_API_KEY is not set", strings.ToUpper(service)))
 	}
 
 	if key, source := getKey(service, false); key != "" {
@@ -56,7 +78,7 @@ func resolveAPIKey(service string, allowEnv, envOnly bool) (string, string, erro
 		}
 		key, err := promptForKey(fmt.Sprintf("%s API Key (press Enter to skip): ", svcName))
 		if err != nil {
-			return "", "", fmt.Errorf("error reading API key: %w", err)
+			return "", "", withExitCode(exitAuth, fmt.Errorf("error reading API key: %w", err))
 		}
 		if strings.TrimSpace(key) != "" {
 			return strings.TrimSpace(key), "Terminal Prompt", nil
@@ -64,12 +86,12 @@ func resolveAPIKey(service string, allowEnv, envOnly bool) (string, string, erro
 	}
 
 	if !isTerminal(int(os.Stdin.Fd())) {
-		return "", "", fmt.Errorf("no API key available (non-interactive shell); set keychain or use --allow-env")
+		return "", "", withExitCode(exitAuth, fmt.Errorf("no API key available (non-interactive shell); set keychain or use --allow-env"))
 	}
 	if allowEnv {
-		return "", "", fmt.Errorf("API key is required; not found in keychain or environment")
+		return "", "", withExitCode(exitAuth, fmt.Errorf("API key is required; not found in keychain or environment"))
 	}
-	return "", "", fmt.Errorf("API key is required; not found in keychain (environment disabled by default; use --allow-env)")
+	return "", "", withExitCode(exitAuth, fmt.Errorf("API key is required; not found in keychain (environment disabled by default; use --allow-env)"))
 }
 
 func resolveLanguageCode(input string) (string, error) {
@@ -85,7 +107,95 @@ func resolveLanguageCode(input string) (string, error) {
 			return entry.Code, nil
 		}
 	}
-	return "", fmt.Errorf("unsupported language: %s", input)
+	if suggestion := suggestLanguage(needle); suggestion != "" {
+		return "", fmt.Errorf("unsupported language: %s (did you mean %s? run \"focst langs\" for the full list)", input, suggestion)
+	}
+	return "", fmt.Errorf("unsupported language: %s (run \"focst langs\" for the full list)", input)
+}
+
+// languageCodeAliases maps common non-standard codes to supported IDs.
+var languageCodeAliases = map[string]string{
+	"cn": "zh-Hans",
+	"jp": "ja",
+	"kr": "ko",
+	"tw": "zh-Hant",
+	"he": "iw",
+}
+
+// suggestLanguage returns a human-readable suggestion such as `Korean [ko]`
+// for a near-miss input, or "" if nothing is close enough.
+func suggestLanguage(input string) string {
+	needle := strings.ToLower(strings.TrimSpace(input))
+	if needle == "" {
+		return ""
+	}
+	entries := language.GetSupportedLanguages()
+	format := func(e language.LanguageEntry) string {
+		return fmt.Sprintf("%q [%s]", e.Name, e.ID)
+	}
+	if id, ok := languageCodeAliases[needle]; ok {
+		for _, e := range entries {
+			if e.ID == id {
+				return format(e)
+			}
+		}
+	}
+
+	// Prefix matches on names ("Chinese" -> both Chinese variants).
+	var prefixed []string
+	for _, e := range entries {
+		if e.ID != e.Code {
+			continue // skip alias keys such as "zh"
+		}
+		if strings.HasPrefix(strings.ToLower(e.Name), needle) {
+			prefixed = append(prefixed, format(e))
+		}
+	}
+	if len(prefixed) > 0 && len(prefixed) <= 3 {
+		return strings.Join(prefixed, " or ")
+	}
+
+	best := -1
+	var bestEntry language.LanguageEntry
+	for _, e := range entries {
+		for _, candidate := range []string{strings.ToLower(e.Name), strings.ToLower(e.ID)} {
+			d := levenshtein(needle, candidate)
+			if best < 0 || d < best {
+				best = d
+				bestEntry = e
+			}
+		}
+	}
+	maxDistance := len([]rune(needle)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	if best < 0 || best > maxDistance {
+		return ""
+	}
+	return format(bestEntry)
+}
+
+// levenshtein returns the edit distance between a and b (by rune).
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
 }
 
 func loadNamesMapping(path, sourceCode, targetCode string) (map[string]string, error) {
@@ -93,7 +203,7 @@ func loadNamesMapping(path, sourceCode, targetCode string) (map[string]string, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to read names mapping file %s: %w", path, err)
 	}
-	mappings, err := names.DecodeMappings(data, sourceCode, targetCode)
+	mappings, err := names.DecodeMappingsAs(names.MappingFormatForPath(path), data, sourceCode, targetCode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse names mapping file %s: %w", path, err)
 	}
@@ -119,20 +229,39 @@ func printUsageStats(usage *gemini.UsageMetadata, duration time.Duration, model
 		if reasoningTokens < 0 {
 			reasoningTokens = 0
 		}
-		billableOutput := usage.CandidatesTokenCount + reasoningTokens
+		cost := estimateGeminiCost(model, *usage)
 
-		pricing, _ := metadata.GeminiPricing(model)
-		inRate := pricing.InputPerMillion
-		outRate := pricing.OutputPerMillion
+		fmt.Printf("Estimated Cost: $%.5f (Reasoning Tokens: %d)\n", cost, reasoningTokens)
+	}
+}
+
+// printPolishStats prints the polish pass of a run and the cost of both
+// passes, which it returns. translationCost is the first pass's cost.
+func printPolishStats(model string, usage gemini.UsageMetadata, translationCost float64) float64 {
+	cost := estimateGeminiCost(model, usage)
+	fmt.Printf("Polish Model: %s\n", model)
+	fmt.Printf("Polish Tokens: In=%d, Out=%d, Total=%d\n", usage.PromptTokenCount, usage.CandidatesTokenCount, usage.TotalTokenCount)
+	fmt.Printf("Polish Estimated Cost: $%.5f\n", cost)
+	fmt.Printf("Total Estimated Cost: $%.5f (translation + polish)\n", translationCost+cost)
+	return translationCost + cost
+}
 
-		inCost := (float64(usage.PromptTokenCount) / 1_000_000) * inRate
-		outCost := (float64(billableOutput) / 1_000_000) * outRate
+func estimateGeminiCost(model string, usage gemini.UsageMetadata) float64 {
+	return metadata.EstimateGeminiCost(model, usage.PromptTokenCount, usage.CandidatesTokenCount, usage.TotalTokenCount)
+}
 
-		fmt.Printf("Estimated Cost: $%.5f (Reasoning Tokens: %d)\n", inCost+outCost, reasoningTokens)
+// estimateNamesCost prices a name extraction run on provider.
+func estimateNamesCost(provider, model string, usage names.Usage) float64 {
+	if provider == namesProviderGemini {
+		// OutputTokens already includes reasoning, so nothing is left over
+		// for EstimateGeminiCost to add; each search query is billed.
+		tokenCost := metadata.EstimateGeminiCost(model, usage.InputTokens, usage.OutputTokens, usage.TotalTokens)
+		return tokenCost + float64(usage.WebSearchCalls)*metadata.GeminiSearchCostPerQuery
 	}
+	return estimateOpenAICost(model, usage)
 }
 
-func estimateOpenAICost(model string, usage openai.Usage) float64 {
+func estimateOpenAICost(model string, usage names.Usage) float64 {
 	pricing, _ := metadata.OpenAIPricing(model)
 	inRate := pricing.InputPerMillion
 	outRate := pricing.OutputPerMillion
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -164,3 +165,33 @@ func TestResolveAPIKey_PromptFallback(t *testing.T) {
 		t.Fatalf("expected keychain lookup before prompt")
 	}
 }
+
+func TestResolveLanguageCode_Suggestion(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantSub string
+	}{
+		{input: "Koraen", wantSub: `did you mean "Korean" [ko]?`},
+		{input: "Chinese", wantSub: `"Chinese (Simplified)" [zh-Hans] or "Chinese (Traditional)" [zh-Hant]`},
+		{input: "cn", wantSub: `did you mean "Chinese (Simplified)" [zh-Hans]?`},
+		{input: "Japanes", wantSub: `"Japanese" [ja]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := resolveLanguageCode(tt.input)
+			if err == nil {
+				t.Fatalf("expected error for %q", tt.input)
+			}
+			if !strings.Contains(err.Error(), tt.wantSub) {
+				t.Fatalf("error = %q, want substring %q", err.Error(), tt.wantSub)
+			}
+		})
+	}
+
+	if _, err := resolveLanguageCode("qqqqqqqq"); err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Fatalf("expected no suggestion for unrelated input, got %v", err)
+	}
+	if code, err := resolveLanguageCode("Korean "); err != nil || code != "ko" {
+		t.Fatalf("resolveLanguageCode(%q) = (%q, %v), want (ko, nil)", "Korean ", code, err)
+	}
+}
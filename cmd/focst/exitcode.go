@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/pipeline"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// Exit codes are part of the CLI contract for scripts (see README).
+const (
+	exitSuccess     = 0
+	exitFailure     = 1 // any failure not covered below
+	exitPartial     = 2 // finished, but some chunks failed
+	exitAuth        = 3 // missing API key or rejected credentials
+	exitBadInput    = 4 // invalid arguments, input files, or request
+	exitRateLimited = 5
+)
+
+// exitCodeError attaches an exit code to a command error.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: code, err: err}
+}
+
+// exitCode maps an error returned by a command to the process exit code:
+// an explicit code first, then pipeline input errors, then the error's
+// apperrors.Kind.
+func exitCode(err error) int {
+	if err == nil {
+		return exitSuccess
+	}
+	var coded *exitCodeError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	if pipeline.IsInputError(err) {
+		return exitBadInput
+	}
+	if kind, ok := apperrors.KindOf(err); ok {
+		return exitCodeForKind(kind)
+	}
+	return exitFailure
+}
+
+func exitCodeForKind(kind apperrors.Kind) int {
+	switch kind {
+	case apperrors.KindAuth:
+		return exitAuth
+	case apperrors.KindRateLimit:
+		return exitRateLimited
+	case apperrors.KindBadRequest:
+		return exitBadInput
+	default:
+		return exitFailure
+	}
+}
+
+// failedRunExitCode picks the exit code for a run in which every chunk
+// failed: the code for the failures' kind when they all share one.
+func failedRunExitCode(t translator.Throughput) int {
+	if t.Failed == 0 || len(t.FailedKinds) != 1 {
+		return exitFailure
+	}
+	for kind, n := range t.FailedKinds {
+		if n == t.Failed {
+			return exitCodeForKind(kind)
+		}
+	}
+	return exitFailure
+}
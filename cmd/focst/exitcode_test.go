@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/pipeline"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: exitSuccess},
+		{name: "plain", err: errors.New("disk full"), want: exitFailure},
+		{name: "partial", err: translationStatusError(pipeline.TranslationResult{Status: pipeline.TranslationStatusPartialSuccess}), want: exitPartial},
+		{name: "skipped", err: translationStatusError(pipeline.TranslationResult{Status: pipeline.TranslationStatusSkipped}), want: exitSuccess},
+		{
+			name: "failure_all_auth",
+			err: translationStatusError(pipeline.TranslationResult{
+				Status:     pipeline.TranslationStatusFailure,
+				Throughput: translator.Throughput{Failed: 3, FailedKinds: map[apperrors.Kind]int{apperrors.KindAuth: 3}},
+			}),
+			want: exitAuth,
+		},
+		{
+			name: "failure_all_rate_limited",
+			err: translationStatusError(pipeline.TranslationResult{
+				Status:     pipeline.TranslationStatusFailure,
+				Throughput: translator.Throughput{Failed: 2, FailedKinds: map[apperrors.Kind]int{apperrors.KindRateLimit: 2}},
+			}),
+			want: exitRateLimited,
+		},
+		{
+			name: "failure_mixed",
+			err: translationStatusError(pipeline.TranslationResult{
+				Status:     pipeline.TranslationStatusFailure,
+				Throughput: translator.Throughput{Failed: 2, FailedKinds: map[apperrors.Kind]int{apperrors.KindAuth: 1, apperrors.KindTransient: 1}},
+			}),
+			want: exitFailure,
+		},
+		{name: "fail_fast_auth", err: fmt.Errorf("fatal translation error: %w", apperrors.Auth(errors.New("401"))), want: exitAuth},
+		{name: "rate_limit", err: apperrors.RateLimit(errors.New("429")), want: exitRateLimited},
+		{name: "bad_request", err: apperrors.BadRequest(errors.New("400")), want: exitBadInput},
+		{name: "pipeline_input", err: &pipeline.InputError{Err: errors.New("invalid subtitle file")}, want: exitBadInput},
+		{name: "explicit_code_wins", err: withExitCode(exitPartial, apperrors.Auth(errors.New("401"))), want: exitPartial},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := exitCode(tc.err); got != tc.want {
+				t.Fatalf("exitCode(%v) = %d, want %d", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExitCode_CommandErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"translate", "--no-such-flag", "in.srt", "out.srt"},
+		{"translate", "in.srt"},
+		{"translate", "in.docx", "out.srt"},
+		{"repair"},
+	} {
+		_, err := executeCommand(t, args...)
+		if got := exitCode(err); got != exitBadInput {
+			t.Fatalf("%v: exitCode(%v) = %d, want %d", args, err, got, exitBadInput)
+		}
+	}
+}
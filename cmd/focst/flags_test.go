@@ -14,6 +14,7 @@ func TestOverwriteFlag_AcceptsYesAndShorthand(t *testing.T) {
 		{name: "root_long", args: []string{"--yes"}},
 		{name: "names_shorthand", args: []string{"names", "-y"}},
 		{name: "names_long", args: []string{"names", "--yes"}},
+		{name: "names_from_subs_shorthand", args: []string{"names", "from-subs", "-y"}},
 	}
 
 	for _, tc := range cases {
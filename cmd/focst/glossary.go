@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/names"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/spf13/cobra"
+)
+
+type applyGlossaryOptions struct {
+	sourceLangCode string
+	targetLangCode string
+	outputPath     string
+}
+
+func newApplyGlossaryCmd() *cobra.Command {
+	opts := applyGlossaryOptions{}
+	cmd := &cobra.Command{
+		Use:   "apply-glossary [options] <translated.srt> <names.json>",
+		Short: "Replace source names left in a translated file using a name mapping (no API calls)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				_ = cmd.Usage()
+				return fmt.Errorf("translated file and names mapping are required")
+			}
+			return runApplyGlossary(cmd, args, &opts)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	cmd.Flags().StringVar(&opts.sourceLangCode, "source", "ja", "Source language code of the mapping (default: ja)")
+	cmd.Flags().StringVar(&opts.targetLangCode, "target", "ko", "Target language code of the translated file (default: ko)")
+	cmd.Flags().StringVarP(&opts.outputPath, "output", "o", "", "Write the result here instead of updating the input file")
+	return cmd
+}
+
+func runApplyGlossary(cmd *cobra.Command, args []string, opts *applyGlossaryOptions) error {
+	inputPath, namesPath := args[0], args[1]
+	if err := validateSubtitleExtension("input", inputPath); err != nil {
+		return err
+	}
+	outputPath := inputPath
+	if opts.outputPath != "" {
+		outputPath = opts.outputPath
+		if err := validateSubtitleExtension("output", outputPath); err != nil {
+			return err
+		}
+	}
+	sourceCode, err := resolveLanguageCode(opts.sourceLangCode)
+	if err != nil {
+		return fmt.Errorf("invalid --source: %w", err)
+	}
+	targetCode, err := resolveLanguageCode(opts.targetLangCode)
+	if err != nil {
+		return fmt.Errorf("invalid --target: %w", err)
+	}
+
+	data, err := os.ReadFile(namesPath)
+	if err != nil {
+		return fmt.Errorf("failed to read names mapping file %s: %w", namesPath, err)
+	}
+	mappings, err := names.DecodeMappingsAs(names.MappingFormatForPath(namesPath), data, sourceCode, targetCode)
+	if err != nil {
+		return fmt.Errorf("failed to parse names mapping file %s: %w", namesPath, err)
+	}
+
+	segments, err := srt.Load(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load subtitle file: %w", err)
+	}
+	updated, count := names.NewGlossary(mappings, targetCode).ApplySegments(segments)
+	if count == 0 && outputPath == inputPath {
+		fmt.Fprintln(cmd.OutOrStdout(), "No names to replace; file left unchanged.")
+		return nil
+	}
+
+	if err := files.RejectSymlinkPath(outputPath); err != nil {
+		return err
+	}
+	if err := srt.SaveWithOptions(outputPath, updated, srt.SaveOptions{Verify: true, CueSettings: true}); err != nil {
+		return fmt.Errorf("failed to save subtitle file: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Replaced %d name occurrence(s) in %s\n", count, outputPath)
+	return nil
+}
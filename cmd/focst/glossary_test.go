@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyGlossaryCommand_Idempotent(t *testing.T) {
+	dir := t.TempDir()
+	subPath := filepath.Join(dir, "out.srt")
+	namesPath := filepath.Join(dir, "names.json")
+	sub := "1\n00:00:01,000 --> 00:00:02,000\nJon, wait.\n\n2\n00:00:03,000 --> 00:00:04,000\nJonathan is here.\n\n"
+	if err := os.WriteFile(subPath, []byte(sub), 0600); err != nil {
+		t.Fatalf("write subtitle: %v", err)
+	}
+	if err := os.WriteFile(namesPath, []byte(`[{"ja":"ジョン","en":"Jonathan"},{"ja":"Jon","en":"Jonathan"}]`), 0600); err != nil {
+		t.Fatalf("write names: %v", err)
+	}
+
+	out, err := executeCommand(t, "apply-glossary", subPath, namesPath, "--target", "en")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if !strings.Contains(out, "Replaced 1 name occurrence(s)") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	first, err := os.ReadFile(subPath)
+	if err != nil {
+		t.Fatalf("read result: %v", err)
+	}
+	if !strings.Contains(string(first), "Jonathan, wait.") || strings.Contains(string(first), "Jonathanathan") {
+		t.Fatalf("unexpected result:\n%s", first)
+	}
+
+	out, err = executeCommand(t, "apply-glossary", subPath, namesPath, "--target", "en")
+	if err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if !strings.Contains(out, "file left unchanged") {
+		t.Fatalf("second run should not change the file, got %q", out)
+	}
+	second, _ := os.ReadFile(subPath)
+	if string(second) != string(first) {
+		t.Fatalf("second run changed the file:\n%s", second)
+	}
+}
+
+func TestApplyGlossaryCommand_CSVMapping(t *testing.T) {
+	dir := t.TempDir()
+	subPath := filepath.Join(dir, "out.srt")
+	namesPath := filepath.Join(dir, "names.csv")
+	sub := "1\n00:00:01,000 --> 00:00:02,000\nJon, wait.\n\n"
+	if err := os.WriteFile(subPath, []byte(sub), 0600); err != nil {
+		t.Fatalf("write subtitle: %v", err)
+	}
+	if err := os.WriteFile(namesPath, []byte("ja,en\nJon,Jonathan\n"), 0600); err != nil {
+		t.Fatalf("write names: %v", err)
+	}
+
+	if _, err := executeCommand(t, "apply-glossary", subPath, namesPath, "--target", "en"); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	got, err := os.ReadFile(subPath)
+	if err != nil {
+		t.Fatalf("read result: %v", err)
+	}
+	if !strings.Contains(string(got), "Jonathan, wait.") {
+		t.Fatalf("unexpected result:\n%s", got)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/spf13/cobra"
+)
+
+type idmapOptions struct {
+	sourceLangCode   string
+	inputFormat      string
+	inputEncoding    string
+	noLangPreprocess bool
+	noBracketRemoval bool
+	noAngleStrip     bool
+	noMeaningless    bool
+	output           string
+}
+
+func newIDMapCmd() *cobra.Command {
+	opts := idmapOptions{}
+	cmd := &cobra.Command{
+		Use:   "idmap [options] <input.srt>",
+		Short: "Print how focst's internal segment IDs map to the input's cue numbers (no API calls)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Usage()
+				return withExitCode(exitBadInput, fmt.Errorf("exactly one subtitle file is required"))
+			}
+			return runIDMap(cmd, args[0], &opts)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	cmd.Flags().StringVar(&opts.sourceLangCode, "source", "ja", "Source language code (default: ja)")
+	cmd.Flags().StringVar(&opts.inputFormat, "input-format", "", "Parse the input as this format regardless of its extension: "+srt.FormatNamesLabel)
+	cmd.Flags().StringVar(&opts.inputEncoding, "input-encoding", "", "Read the input in this text encoding instead of detecting it: "+srt.EncodingNamesLabel)
+	cmd.Flags().BoolVar(&opts.noLangPreprocess, "no-lang-preprocess", false, "Disable language-specific preprocessing only")
+	cmd.Flags().BoolVar(&opts.noBracketRemoval, "no-bracket-removal", false, "Keep text in (), [], （）, and ［］ during Japanese preprocessing")
+	cmd.Flags().BoolVar(&opts.noAngleStrip, "no-angle-strip", false, "Keep < and > characters during Japanese preprocessing")
+	cmd.Flags().BoolVar(&opts.noMeaningless, "no-meaningless-filter", false, "Keep symbol-only segments during Japanese preprocessing")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write the mapping to this JSON file instead of standard output")
+	return cmd
+}
+
+// runIDMap preprocesses the input the way translate does and prints the
+// resulting ID map, so tools can convert between the input's cue numbers and
+// the IDs in recovery logs, reviews, and --dump-idmap files.
+func runIDMap(cmd *cobra.Command, inputPath string, opts *idmapOptions) error {
+	lang, ok := language.GetLanguage(opts.sourceLangCode)
+	if !ok {
+		return withExitCode(exitBadInput, fmt.Errorf("unsupported source language: %s", opts.sourceLangCode))
+	}
+	if opts.inputFormat == "" {
+		if err := validateSubtitleExtension("input", inputPath); err != nil {
+			return withExitCode(exitBadInput, err)
+		}
+	}
+	segments, err := srt.LoadWithOptions(inputPath, srt.LoadOptions{Format: opts.inputFormat, Encoding: opts.inputEncoding, Language: lang.Code})
+	if err != nil {
+		return withExitCode(exitBadInput, fmt.Errorf("failed to load subtitle file: %w", err))
+	}
+
+	_, mapping := srt.PreprocessSteps(segments, lang.Code, inputPath, srt.PreprocessOptions{
+		NoLangRules:         opts.noLangPreprocess,
+		NoBracketRemoval:    opts.noBracketRemoval,
+		NoAngleStrip:        opts.noAngleStrip,
+		NoMeaninglessFilter: opts.noMeaningless,
+	})
+	data, err := srt.MarshalIDMap(mapping)
+	if err != nil {
+		return err
+	}
+	if opts.output == "" {
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	if err := files.RejectSymlinkPath(opts.output); err != nil {
+		return err
+	}
+	if err := files.AtomicWrite(opts.output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write ID map: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%d of %d segments kept)\n", opts.output, len(mapping), len(segments))
+	return nil
+}
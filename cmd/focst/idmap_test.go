@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestIDMapCommand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "in.srt")
+	// Cue 2 is only a bracketed note and cue 4 only symbols; Japanese
+	// preprocessing drops both.
+	sub := "1\n00:00:01,000 --> 00:00:02,000\nこんにちは\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\n（笑）\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\n元気？\n\n" +
+		"4\n00:00:07,000 --> 00:00:08,000\n♪～\n\n" +
+		"5\n00:00:09,000 --> 00:00:10,000\nまたね\n"
+	if err := os.WriteFile(path, []byte(sub), 0600); err != nil {
+		t.Fatalf("write subtitle: %v", err)
+	}
+
+	out, err := executeCommand(t, "idmap", path, "--source", "ja")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	var doc struct {
+		Version int         `json:"version"`
+		Mapping []srt.IDMap `json:"mapping"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("parse output %q: %v", out, err)
+	}
+	want := []srt.IDMap{{InternalID: 1, OriginalID: 1}, {InternalID: 2, OriginalID: 3}, {InternalID: 3, OriginalID: 5}}
+	if doc.Version != srt.IDMapVersion || !reflect.DeepEqual(doc.Mapping, want) {
+		t.Fatalf("idmap = %+v, want version %d mapping %+v", doc, srt.IDMapVersion, want)
+	}
+
+	// The mapping must describe the segments preprocessing actually keeps.
+	segments, err := srt.Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cleaned, _ := srt.PreprocessSteps(segments, "ja", path, srt.PreprocessOptions{})
+	if len(cleaned) != len(doc.Mapping) {
+		t.Fatalf("mapping has %d entries, preprocessing kept %d segments", len(doc.Mapping), len(cleaned))
+	}
+	byID := make(map[int]srt.Segment)
+	for _, seg := range segments {
+		byID[seg.ID] = seg
+	}
+	for i, m := range doc.Mapping {
+		if cleaned[i].ID != m.InternalID || cleaned[i].StartTime != byID[m.OriginalID].StartTime {
+			t.Fatalf("entry %d = %+v does not match preprocessed segment %+v", i, m, cleaned[i])
+		}
+	}
+
+	// Without language rules nothing is dropped.
+	outPath := filepath.Join(dir, "map.json")
+	if _, err := executeCommand(t, "idmap", path, "--no-lang-preprocess", "-o", outPath); err != nil {
+		t.Fatalf("command with -o failed: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read map: %v", err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("parse map: %v", err)
+	}
+	if len(doc.Mapping) != 5 || doc.Mapping[3] != (srt.IDMap{InternalID: 4, OriginalID: 4}) {
+		t.Fatalf("unexpected mapping without language rules: %+v", doc.Mapping)
+	}
+
+	if _, err := executeCommand(t, "idmap", path, "--source", "xx"); exitCode(err) != exitBadInput {
+		t.Fatalf("expected bad input for unknown language, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/spf13/cobra"
+)
+
+type langEntryJSON struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+	CPL  int    `json:"cpl"`
+	CPS  int    `json:"cps"`
+}
+
+func newLangsCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "langs",
+		Short: "Show supported languages with codes and CPL/CPS profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			langs := language.GetSupportedLanguages()
+			out := cmd.OutOrStdout()
+			if asJSON {
+				entries := make([]langEntryJSON, 0, len(langs))
+				for _, l := range langs {
+					entries = append(entries, langEntryJSON{ID: l.ID, Code: l.Code, Name: l.Name, CPL: l.DefaultCPL, CPS: l.DefaultCPS})
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tCODE\tNAME\tCPL\tCPS")
+			for _, l := range langs {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", l.ID, l.Code, l.Name, l.DefaultCPL, l.DefaultCPS)
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print languages as JSON")
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	return cmd
+}
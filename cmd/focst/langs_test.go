@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/language"
+)
+
+func TestLangsCommand_Table(t *testing.T) {
+	out, err := executeCommand(t, "langs")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "ID") || !strings.Contains(out, "CPL") {
+		t.Fatalf("expected table header, got %q", out[:min(len(out), 80)])
+	}
+	if !strings.Contains(out, "zh-Hant") || !strings.Contains(out, "Korean") {
+		t.Fatalf("expected language rows in output")
+	}
+}
+
+func TestLangsCommand_JSON(t *testing.T) {
+	out, err := executeCommand(t, "langs", "--json")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	var entries []langEntryJSON
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(entries) != len(language.Languages) {
+		t.Fatalf("expected %d entries, got %d", len(language.Languages), len(entries))
+	}
+	for _, e := range entries {
+		if e.ID == "ko" && (e.Code != "ko" || e.CPL != 16 || e.CPS != 12) {
+			t.Fatalf("unexpected ko entry: %+v", e)
+		}
+	}
+}
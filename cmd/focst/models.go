@@ -0,0 +1,159 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/httpclient"
+	"github.com/oukeidos/focst/internal/metadata"
+	"github.com/oukeidos/focst/internal/paths"
+	"github.com/spf13/cobra"
+)
+
+// remoteModelsCacheTTL bounds how long a list-models response is reused.
+const remoteModelsCacheTTL = 10 * time.Minute
+
+var (
+	listRemoteModels    = gemini.ListModels
+	remoteModelsCacheFn = defaultRemoteModelsCachePath
+	remoteModelsNow     = time.Now
+)
+
+type modelsOptions struct {
+	remote         bool
+	geminiEndpoint string
+	requestTimeout time.Duration
+	allowEnv       bool
+	envOnly        bool
+}
+
+type remoteModelsCache struct {
+	KeyHash   string               `json:"key_hash"`
+	FetchedAt time.Time            `json:"fetched_at"`
+	Models    []gemini.RemoteModel `json:"models"`
+}
+
+func newModelsCmd() *cobra.Command {
+	opts := modelsOptions{}
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "List Gemini models (built-in list, or --remote to query the API)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.remote {
+				return printStaticModels(cmd)
+			}
+			return runRemoteModels(cmd, &opts)
+		},
+		SilenceUsage: true,
+	}
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	cmd.Flags().BoolVar(&opts.remote, "remote", false, "Query the Gemini API for models that support generateContent")
+	cmd.Flags().StringVar(&opts.geminiEndpoint, "gemini-endpoint", "", "Gemini API endpoint URL for --remote (e.g. a proxy or compatible gateway)")
+	cmd.Flags().DurationVar(&opts.requestTimeout, "request-timeout", httpclient.DefaultTimeout, "Timeout for the --remote list-models call")
+	cmd.Flags().BoolVar(&opts.allowEnv, "allow-env", false, "Allow reading API key from environment variables")
+	cmd.Flags().BoolVar(&opts.envOnly, "env-only", false, "Use only environment variables for API keys")
+	return cmd
+}
+
+func printStaticModels(cmd *cobra.Command) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME")
+	for _, m := range metadata.GeminiModels {
+		fmt.Fprintf(tw, "%s\t%s\n", m.ID, m.Label)
+	}
+	return tw.Flush()
+}
+
+func runRemoteModels(cmd *cobra.Command, opts *modelsOptions) error {
+	if opts.geminiEndpoint != "" {
+		if _, err := httpclient.ValidateBaseURL(opts.geminiEndpoint); err != nil {
+			return fmt.Errorf("invalid --gemini-endpoint: %w", err)
+		}
+	}
+	key, _, err := resolveAPIKey("gemini", opts.allowEnv, opts.envOnly)
+	if err != nil {
+		return err
+	}
+	keyHash := remoteModelsFingerprint(key, opts.geminiEndpoint)
+	cachePath, cacheErr := remoteModelsCacheFn()
+
+	models, ok := []gemini.RemoteModel(nil), false
+	if cacheErr == nil {
+		models, ok = loadRemoteModelsCache(cachePath, keyHash)
+	}
+	if !ok {
+		ctx, stop := signalContext()
+		defer stop()
+		models, err = listRemoteModels(ctx, key, gemini.ClientOptions{Endpoint: opts.geminiEndpoint, RequestTimeout: opts.requestTimeout})
+		if err != nil {
+			return fmt.Errorf("failed to list models: %w", err)
+		}
+		if cacheErr == nil {
+			_ = storeRemoteModelsCache(cachePath, keyHash, models)
+		}
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tPRICING")
+	for _, m := range models {
+		pricing := "unknown"
+		if _, known := metadata.GeminiPricing(m.ID); known {
+			pricing = "built-in"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", m.ID, m.DisplayName, pricing)
+	}
+	return tw.Flush()
+}
+
+func defaultRemoteModelsCachePath() (string, error) {
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gemini_models.json"), nil
+}
+
+// remoteModelsFingerprint identifies the key and endpoint a cached list belongs
+// to without storing the key.
+func remoteModelsFingerprint(key, endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint + "\x00" + key))
+	return hex.EncodeToString(sum[:8])
+}
+
+func loadRemoteModelsCache(path, keyHash string) ([]gemini.RemoteModel, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var cache remoteModelsCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, false
+	}
+	if cache.KeyHash != keyHash {
+		return nil, false
+	}
+	age := remoteModelsNow().Sub(cache.FetchedAt)
+	if age < 0 || age > remoteModelsCacheTTL {
+		return nil, false
+	}
+	return cache.Models, true
+}
+
+func storeRemoteModelsCache(path, keyHash string, models []gemini.RemoteModel) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(remoteModelsCache{KeyHash: keyHash, FetchedAt: remoteModelsNow(), Models: models})
+	if err != nil {
+		return err
+	}
+	return files.AtomicWrite(path, data, 0600)
+}
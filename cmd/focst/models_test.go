@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/gemini"
+)
+
+func withRemoteModelsStubs(t *testing.T, models []gemini.RemoteModel) *int {
+	t.Helper()
+	calls := 0
+	prevList := listRemoteModels
+	prevCache := remoteModelsCacheFn
+	prevNow := remoteModelsNow
+	cachePath := filepath.Join(t.TempDir(), "focst", "gemini_models.json")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	listRemoteModels = func(_ context.Context, _ string, _ gemini.ClientOptions) ([]gemini.RemoteModel, error) {
+		calls++
+		return models, nil
+	}
+	remoteModelsCacheFn = func() (string, error) { return cachePath, nil }
+	remoteModelsNow = func() time.Time { return now }
+	t.Cleanup(func() {
+		listRemoteModels = prevList
+		remoteModelsCacheFn = prevCache
+		remoteModelsNow = prevNow
+	})
+	return &calls
+}
+
+func TestModelsCommand_Static(t *testing.T) {
+	calls := withRemoteModelsStubs(t, nil)
+	out, err := executeCommand(t, "models")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if !strings.Contains(out, "gemini-3-flash-preview") {
+		t.Fatalf("expected built-in model in output, got %q", out)
+	}
+	if *calls != 0 {
+		t.Fatalf("static listing should not query the API")
+	}
+}
+
+func TestModelsCommand_RemoteUsesCache(t *testing.T) {
+	_, restore := withKeyStubs(t, false, "", "keychain-key", "")
+	defer restore()
+	calls := withRemoteModelsStubs(t, []gemini.RemoteModel{
+		{ID: "gemini-3-flash-preview", DisplayName: "Gemini 3 Flash"},
+		{ID: "gemini-9-experimental", DisplayName: "Gemini 9"},
+	})
+
+	for i := 0; i < 2; i++ {
+		out, err := executeCommand(t, "models", "--remote")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if !strings.Contains(out, "gemini-9-experimental") || !strings.Contains(out, "unknown") {
+			t.Fatalf("expected remote model rows, got %q", out)
+		}
+	}
+	if *calls != 1 {
+		t.Fatalf("expected one API call with cache reuse, got %d", *calls)
+	}
+
+	remoteModelsNow = func() time.Time { return time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC) }
+	if _, err := executeCommand(t, "models", "--remote"); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if *calls != 2 {
+		t.Fatalf("expected expired cache to refetch, got %d calls", *calls)
+	}
+}
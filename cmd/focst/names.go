@@ -3,34 +3,56 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/httpclient"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/names"
 	"github.com/oukeidos/focst/internal/openai"
 	"github.com/oukeidos/focst/internal/prompt"
+	"github.com/oukeidos/focst/internal/srt"
 	"github.com/spf13/cobra"
 )
 
+// namesClientOptions holds the flags shared by "names" and "names from-subs".
+type namesClientOptions struct {
+	provider           string
+	sourceName         string
+	targetName         string
+	maxTokens          int
+	autoExpandTokens   bool
+	baseURL            string
+	timeout            time.Duration
+	maxBody            int64
+	allowEnv           bool
+	envOnly            bool
+	yes                bool
+	debug              bool
+	logUnredacted      bool
+	noColor            bool
+	insecureSkipVerify bool
+}
+
 type namesOptions struct {
-	workType   string
-	title      string
-	year       string
-	sourceName string
-	targetName string
-	maxTokens  int
-	allowEnv   bool
-	envOnly    bool
-	yes        bool
-	debug      bool
+	namesClientOptions
+	workType string
+	title    string
+	year     string
+}
+
+type namesFromSubsOptions struct {
+	namesClientOptions
+	inputTokens int
 }
 
 func newNamesCmd() *cobra.Command {
 	opts := namesOptions{}
 	cmd := &cobra.Command{
-		Use:   "names [options] <output.json>",
-		Short: "Extract character name mappings using GPT-5.2",
+		Use:   "names [options] <output.json|output_dir>",
+		Short: "Extract character name mappings using GPT-5.2 or Gemini",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if opts.title == "" {
 				_ = cmd.Usage()
@@ -51,28 +73,171 @@ func newNamesCmd() *cobra.Command {
 	cmd.Flags().StringVar(&opts.year, "year", "", "Release year")
 	cmd.Flags().StringVar(&opts.sourceName, "source", "Japanese", "Source language name (e.g. Japanese)")
 	cmd.Flags().StringVar(&opts.targetName, "target", "Korean", "Target language name (e.g. Korean)")
-	cmd.Flags().IntVar(&opts.maxTokens, "max-tokens", 16384, "Max output tokens including reasoning")
+	addNamesClientFlags(cmd, &opts.namesClientOptions)
+	cmd.AddCommand(newNamesFromSubsCmd())
+	return cmd
+}
+
+func newNamesFromSubsCmd() *cobra.Command {
+	opts := namesFromSubsOptions{}
+	cmd := &cobra.Command{
+		Use:   "from-subs [options] <input.srt> <output.json>",
+		Short: "Extract name mappings from the subtitle text itself (no web search)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				_ = cmd.Usage()
+				return fmt.Errorf("input and output paths are required")
+			}
+			return runNamesFromSubs(cmd, args, &opts)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	cmd.Flags().StringVar(&opts.sourceName, "source", "ja", "Source language code or name")
+	cmd.Flags().StringVar(&opts.targetName, "target", "ko", "Target language code or name")
+	cmd.Flags().IntVar(&opts.inputTokens, "input-tokens", names.DefaultTextTokenBudget, "Approximate token budget for subtitle lines sent to the model (lines are sampled across the file)")
+	addNamesClientFlags(cmd, &opts.namesClientOptions)
+	return cmd
+}
+
+func addNamesClientFlags(cmd *cobra.Command, opts *namesClientOptions) {
+	cmd.Flags().StringVar(&opts.provider, "provider", namesProviderOpenAI, "Model provider for name extraction: openai or gemini")
+	cmd.Flags().IntVar(&opts.maxTokens, "max-tokens", defaultNamesMaxTokens, "Max output tokens including reasoning")
+	cmd.Flags().BoolVar(&opts.autoExpandTokens, "auto-expand-tokens", false, "When an OpenAI response stops at --max-tokens, retry once with four times the budget (up to 128000)")
+	cmd.Flags().StringVar(&opts.baseURL, "openai-base-url", openai.DefaultBaseURL, "OpenAI-compatible API base URL (e.g. a proxy or LiteLLM gateway)")
+	cmd.Flags().DurationVar(&opts.timeout, "request-timeout", httpclient.DefaultTimeout, "Timeout for the API call")
+	cmd.Flags().Int64Var(&opts.maxBody, "max-response-bytes", httpclient.MaxResponseBytes, "Largest API response body to accept, in bytes")
 	cmd.Flags().BoolVar(&opts.allowEnv, "allow-env", false, "Allow reading API key from environment variables")
 	cmd.Flags().BoolVar(&opts.envOnly, "env-only", false, "Use only environment variables for API keys")
 	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Overwrite output file without asking")
 	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug logging")
-	return cmd
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable colored log output (also set by the NO_COLOR environment variable)")
+	cmd.Flags().BoolVar(&opts.logUnredacted, "log-unredacted", false, "Debugging only: log attributes without redacting keys, prompts, or subtitle text")
+	cmd.Flags().BoolVar(&opts.insecureSkipVerify, "insecure-skip-verify", false, "Disable TLS certificate verification (only for trusted TLS-intercepting corporate proxies)")
 }
 
 func runNames(cmd *cobra.Command, args []string, opts *namesOptions) error {
 	startTime := time.Now()
-	outputPath := args[0]
+	outputPath, err := namesOutputPath(args[0], opts.title)
+	if err != nil {
+		return err
+	}
+	session, ok, err := startNamesSession(outputPath, &opts.namesClientOptions)
+	if err != nil || !ok {
+		return err
+	}
+
+	extractor := session.extractor()
+
+	logger.Info("Extracting character names", "title", opts.title, "type", opts.workType)
+	ctx, stop := signalContext()
+	defer stop()
+	mappings, usage, err := extractor.Extract(ctx, opts.workType, opts.title, opts.year, session.maxTokens, session.sourceCode, session.targetCode)
+	if err != nil {
+		if ctx.Err() != nil {
+			logger.Warn("Name extraction canceled", "error", err)
+			return nil
+		}
+		return err
+	}
+
+	return session.finish(startTime, mappings, usage)
+}
+
+func runNamesFromSubs(cmd *cobra.Command, args []string, opts *namesFromSubsOptions) error {
+	startTime := time.Now()
+	inputPath := args[0]
+	segments, err := srt.Load(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load subtitle file: %w", err)
+	}
+	var lines []string
+	for _, seg := range segments {
+		lines = append(lines, seg.Lines...)
+	}
+
+	session, ok, err := startNamesSession(args[1], &opts.namesClientOptions)
+	if err != nil || !ok {
+		return err
+	}
+
+	extractor := session.extractor()
+
+	logger.Info("Extracting names from subtitles", "path", inputPath, "lines", len(lines), "input_tokens", opts.inputTokens)
+	ctx, stop := signalContext()
+	defer stop()
+	mappings, usage, err := extractor.ExtractFromText(ctx, lines, opts.inputTokens, session.maxTokens, session.sourceCode, session.targetCode)
+	if err != nil {
+		if ctx.Err() != nil {
+			logger.Warn("Name extraction canceled", "error", err)
+			return nil
+		}
+		return err
+	}
+
+	return session.finish(startTime, mappings, usage)
+}
+
+// namesOutputPath returns arg, or when arg is an existing directory, a file
+// in it named after the title, e.g. "Fate_Zero.json" for "Fate/Zero". A
+// generated name never replaces an existing file.
+func namesOutputPath(arg, title string) (string, error) {
+	info, err := os.Stat(arg)
+	if err != nil || !info.IsDir() {
+		return arg, nil
+	}
+	path, _, err := files.SafePath(filepath.Join(arg, files.SanitizeFilename(title)+".json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve output path: %w", err)
+	}
+	return path, nil
+}
+
+// namesSession is the resolved output path, languages, and model provider
+// shared by the names commands.
+type namesSession struct {
+	outputPath string
+	sourceCode string
+	targetCode string
+	maxTokens  int
+	// expandLimit is the budget cap for --auto-expand-tokens, 0 when unset.
+	expandLimit  int
+	providerName string
+	provider     names.Provider
+}
+
+// extractor returns an Extractor on the session's provider.
+func (s *namesSession) extractor() *names.Extractor {
+	extractor := names.NewExtractor(s.provider)
+	extractor.SetAutoExpandTokens(s.expandLimit)
+	return extractor
+}
+
+// startNamesSession confirms the output path, initializes logging, and builds
+// the provider's client. ok is false when the user declined to overwrite.
+func startNamesSession(outputPath string, opts *namesClientOptions) (*namesSession, bool, error) {
+	if opts.provider != namesProviderOpenAI && opts.provider != namesProviderGemini {
+		return nil, false, fmt.Errorf("invalid --provider %q (use openai or gemini)", opts.provider)
+	}
+	baseURL, err := httpclient.ValidateBaseURL(opts.baseURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid --openai-base-url: %w", err)
+	}
+	if opts.provider == namesProviderGemini && opts.baseURL != openai.DefaultBaseURL {
+		return nil, false, fmt.Errorf("--openai-base-url applies only to --provider openai")
+	}
 
 	allowOverwrite := opts.yes
 	if !allowOverwrite {
 		if _, err := os.Stat(outputPath); err == nil {
 			confirmed, err := prompt.DefaultConfirmer().ConfirmOverwrite(outputPath, allowOverwrite)
 			if err != nil {
-				return err
+				return nil, false, err
 			}
 			if !confirmed {
 				fmt.Println("Aborted.")
-				return nil
+				return nil, false, nil
 			}
 			allowOverwrite = true
 		}
@@ -82,7 +247,7 @@ func runNames(cmd *cobra.Command, args []string, opts *namesOptions) error {
 	if !allowOverwrite {
 		safePath, changed, err := files.SafePath(outputPath)
 		if err != nil {
-			return fmt.Errorf("failed to resolve output path: %w", err)
+			return nil, false, fmt.Errorf("failed to resolve output path: %w", err)
 		}
 		if changed {
 			outputPath = safePath
@@ -90,65 +255,91 @@ func runNames(cmd *cobra.Command, args []string, opts *namesOptions) error {
 		}
 	}
 	if err := files.RejectSymlinkPath(outputPath); err != nil {
-		return err
+		return nil, false, err
 	}
 
 	logLevel := logger.LevelInfo
 	if opts.debug {
 		logLevel = logger.LevelDebug
 	}
-	logger.Init(logLevel, nil)
+	initLogger(logLevel, nil, opts.noColor, opts.logUnredacted)
+	configureTransport(httpclient.TransportOptions{InsecureSkipVerify: opts.insecureSkipVerify})
 	if pathChanged {
 		logger.Warn("Output path adjusted to avoid overwrite", "original", originalOutputPath, "effective", outputPath)
 	}
 
-	const openAIMaxTokens = 128000
+	providerMaxTokens := 128000
+	if opts.provider == namesProviderGemini {
+		providerMaxTokens = 65536
+	}
 	maxTokensVal := opts.maxTokens
-	if maxTokensVal > openAIMaxTokens {
-		logger.Warn("Max tokens clamped", "requested", maxTokensVal, "effective", openAIMaxTokens)
-		maxTokensVal = openAIMaxTokens
+	if maxTokensVal > providerMaxTokens {
+		logger.Warn("Max tokens clamped", "requested", maxTokensVal, "effective", providerMaxTokens)
+		maxTokensVal = providerMaxTokens
 	}
 
-	key, source, err := resolveAPIKey("openai", opts.allowEnv, opts.envOnly)
+	key, source, err := resolveAPIKey(opts.provider, opts.allowEnv, opts.envOnly)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
-	logger.Info("Using API Key", "service", "openai", "source", source)
+	logger.Info("Using API Key", "service", opts.provider, "source", source)
 
 	sourceCode, err := resolveLanguageCode(opts.sourceName)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	targetCode, err := resolveLanguageCode(opts.targetName)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
-	client := openai.NewClient(key, "gpt-5.2")
-	extractor := names.NewExtractor(client)
-
-	logger.Info("Extracting character names", "title", opts.title, "type", opts.workType)
-	ctx, stop := signalContext()
-	defer stop()
-	mappings, usage, err := extractor.Extract(ctx, opts.workType, opts.title, opts.year, maxTokensVal, sourceCode, targetCode)
-	if err != nil {
-		if ctx.Err() != nil {
-			logger.Warn("Name extraction canceled", "error", err)
-			return nil
+	var provider names.Provider
+	if opts.provider == namesProviderGemini {
+		client := gemini.NewGenerateClient(key, namesGeminiModel)
+		client.SetRequestTimeout(opts.timeout)
+		client.SetMaxResponseBytes(opts.maxBody)
+		provider = names.NewGeminiProvider(client)
+	} else {
+		client := openai.NewClient(key, namesModel)
+		if err := client.SetBaseURL(opts.baseURL); err != nil {
+			return nil, false, fmt.Errorf("invalid --openai-base-url: %w", err)
 		}
-		return err
+		client.SetRequestTimeout(opts.timeout)
+		client.SetMaxResponseBytes(opts.maxBody)
+		if client.BaseURL() != openai.DefaultBaseURL {
+			logger.Info("Using custom OpenAI base URL", "host", baseURL.Host)
+		}
+		provider = names.NewOpenAIProvider(client)
+	}
+
+	expandLimit := 0
+	if opts.autoExpandTokens {
+		expandLimit = providerMaxTokens
 	}
 
-	data, err := names.EncodeMappings(mappings, sourceCode, targetCode)
+	return &namesSession{
+		outputPath:   outputPath,
+		sourceCode:   sourceCode,
+		targetCode:   targetCode,
+		maxTokens:    maxTokensVal,
+		expandLimit:  expandLimit,
+		providerName: opts.provider,
+		provider:     provider,
+	}, true, nil
+}
+
+// finish writes the mappings and prints execution stats.
+func (s *namesSession) finish(startTime time.Time, mappings []names.CharacterMapping, usage names.Usage) error {
+	data, err := names.EncodeMappingsAs(names.MappingFormatForPath(s.outputPath), mappings, s.sourceCode, s.targetCode)
 	if err != nil {
 		return err
 	}
 
-	if err := files.AtomicWrite(outputPath, data, 0600); err != nil {
+	if err := files.AtomicWrite(s.outputPath, data, 0600); err != nil {
 		return err
 	}
 
-	logger.Info("Success", "count", len(mappings), "path", outputPath)
+	logger.Info("Success", "count", len(mappings), "path", s.outputPath)
 
 	fmt.Println("\n--- Execution Stats ---")
 	fmt.Printf("Time: %s\n", time.Since(startTime))
@@ -157,7 +348,7 @@ func runNames(cmd *cobra.Command, args []string, opts *namesOptions) error {
 		fmt.Printf("Web Search Calls: %d\n", usage.WebSearchCalls)
 	}
 
-	cost := estimateOpenAICost(client.GetModelID(), usage)
+	cost := estimateNamesCost(s.providerName, s.provider.GetModelID(), usage)
 	fmt.Printf("Estimated Cost: $%.5f\n", cost)
 	return nil
 }
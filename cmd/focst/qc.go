@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/spf13/cobra"
+)
+
+type qcOptions struct {
+	cps           float64
+	cpl           int
+	failThreshold bool
+}
+
+// qcListLimit caps how many segment IDs are printed per finding.
+const qcListLimit = 20
+
+func newQCCmd() *cobra.Command {
+	opts := qcOptions{}
+	cmd := &cobra.Command{
+		Use:   "qc [options] <input.srt>",
+		Short: "Report reading-speed, line-length, and timing statistics (no API calls)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Usage()
+				return withExitCode(exitBadInput, fmt.Errorf("exactly one subtitle file is required"))
+			}
+			return runQC(cmd, args[0], &opts)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	cmd.Flags().Float64Var(&opts.cps, "cps", 17, "Reading-speed limit in characters per second")
+	cmd.Flags().IntVar(&opts.cpl, "cpl", 42, "Line-length limit in characters")
+	cmd.Flags().BoolVar(&opts.failThreshold, "fail-threshold", false, "Exit with an error if any segment exceeds --cps or --cpl")
+	return cmd
+}
+
+func runQC(cmd *cobra.Command, inputPath string, opts *qcOptions) error {
+	if opts.cps <= 0 || opts.cpl <= 0 {
+		return withExitCode(exitBadInput, fmt.Errorf("--cps and --cpl must be positive"))
+	}
+	if err := validateSubtitleExtension("input", inputPath); err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	segments, err := srt.Load(inputPath)
+	if err != nil {
+		return withExitCode(exitBadInput, fmt.Errorf("failed to load subtitle file: %w", err))
+	}
+
+	report := srt.CheckQuality(segments, srt.QCOptions{MaxCPS: opts.cps, MaxCPL: opts.cpl})
+	if err := printQCReport(cmd, report, opts); err != nil {
+		return err
+	}
+	if opts.failThreshold && report.Violations() > 0 {
+		return fmt.Errorf("QC failed: %d segment(s) over %g CPS, %d segment(s) with lines over %d CPL",
+			len(report.OverCPS), opts.cps, len(report.LongLines), opts.cpl)
+	}
+	return nil
+}
+
+func printQCReport(cmd *cobra.Command, r srt.QCReport, opts *qcOptions) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Segments\t%d (%d with text and valid timing)\n", r.Segments, r.Measured)
+	fmt.Fprintf(tw, "CPS\tmin %.1f  mean %.1f  median %.1f  p95 %.1f  max %.1f\n",
+		r.CPS.Min, r.CPS.Mean, r.CPS.Median, r.CPS.P95, r.CPS.Max)
+	fmt.Fprintf(tw, "Over %g CPS\t%s\n", opts.cps, formatQCIDs(r.OverCPS))
+	fmt.Fprintf(tw, "Longest line\t%d chars\n", r.MaxLineLength)
+	fmt.Fprintf(tw, "Lines over %d CPL\t%s\n", opts.cpl, formatQCIDs(r.LongLines))
+	fmt.Fprintf(tw, "Duration\tmin %s  max %s\n", formatQCDuration(r.MinDuration), formatQCDuration(r.MaxDuration))
+	fmt.Fprintf(tw, "Min gap\t%s\n", formatQCDuration(r.MinGap))
+	fmt.Fprintf(tw, "Overlaps\t%s\n", formatQCIDs(r.Overlaps))
+	fmt.Fprintf(tw, "Invalid timing\t%s\n", formatQCIDs(r.InvalidTiming))
+	return tw.Flush()
+}
+
+// formatQCIDs renders a count followed by up to qcListLimit segment IDs.
+func formatQCIDs(ids []int) string {
+	if len(ids) == 0 {
+		return "0"
+	}
+	shown := ids
+	if len(shown) > qcListLimit {
+		shown = shown[:qcListLimit]
+	}
+	parts := make([]string, len(shown))
+	for i, id := range shown {
+		parts[i] = strconv.Itoa(id)
+	}
+	list := strings.Join(parts, ", ")
+	if len(ids) > len(shown) {
+		list += ", ..."
+	}
+	return fmt.Sprintf("%d (segments %s)", len(ids), list)
+}
+
+func formatQCDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
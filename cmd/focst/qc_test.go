@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQCCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "in.srt")
+	sub := "1\n00:00:01,000 --> 00:00:02,000\nHello you!\n\n2\n00:00:02,500 --> 00:00:03,000\nTwenty characters!!!\n\n"
+	if err := os.WriteFile(path, []byte(sub), 0600); err != nil {
+		t.Fatalf("write subtitle: %v", err)
+	}
+
+	out, err := executeCommand(t, "qc", path, "--cps", "17", "--cpl", "15")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	for _, want := range []string{"Over 17 CPS", "1 (segments 2)", "Lines over 15 CPL", "max 40.0", "min 0.500s"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	_, err = executeCommand(t, "qc", path, "--cps", "17", "--cpl", "15", "--fail-threshold")
+	if err == nil || !strings.Contains(err.Error(), "QC failed") || exitCode(err) != exitFailure {
+		t.Fatalf("expected QC failure, got %v", err)
+	}
+	if _, err := executeCommand(t, "qc", path, "--cps", "50", "--cpl", "42", "--fail-threshold"); err != nil {
+		t.Fatalf("expected pass under relaxed limits, got %v", err)
+	}
+	if _, err := executeCommand(t, "qc"); exitCode(err) != exitBadInput {
+		t.Fatalf("expected bad input for missing file, got %v", err)
+	}
+}
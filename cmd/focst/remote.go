@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/oukeidos/focst/internal/httpclient"
+	"github.com/oukeidos/focst/internal/logger"
+)
+
+// isRemoteInput reports whether arg names a URL rather than a local path.
+func isRemoteInput(arg string) bool {
+	i := strings.Index(arg, "://")
+	return i > 0 && !strings.ContainsAny(arg[:i], `/\`)
+}
+
+// parseRemoteInput reports whether arg is a URL rather than a local path and
+// parses it. Only http and https URLs are accepted. Errors name the URL by
+// remoteInputLabel, never by arg, whose query may carry an access token.
+func parseRemoteInput(arg string) (*url.URL, bool, error) {
+	if !isRemoteInput(arg) {
+		return nil, false, nil
+	}
+	u, err := url.Parse(arg)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // url.Error quotes the whole URL
+		}
+		return nil, true, fmt.Errorf("invalid input URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, true, fmt.Errorf("invalid input URL %s: scheme must be http or https", remoteInputLabel(u))
+	}
+	if u.Host == "" {
+		return nil, true, fmt.Errorf("invalid input URL %s: missing host", remoteInputLabel(u))
+	}
+	return u, true, nil
+}
+
+// remoteInputLabel renders u for logs and messages without its query string,
+// fragment, or credentials, which may carry access tokens.
+func remoteInputLabel(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.EscapedPath()
+}
+
+// remoteInputPath returns the URL path, whose extension selects the format.
+func remoteInputPath(u *url.URL) string {
+	return path.Base(u.Path)
+}
+
+// fetchRemoteInput downloads u into memory with the shared HTTP client and
+// its httpclient.MaxResponseBytes cap. A positive timeout bounds the download.
+func fetchRemoteInput(ctx context.Context, u *url.URL, timeout time.Duration) ([]byte, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	label := remoteInputLabel(u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", label, err)
+	}
+	body, resp, err := httpclient.DoAndRead(httpclient.GetDefaultClient(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download input %s: %w", label, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to download input %s: HTTP %s", label, resp.Status)
+	}
+	logger.Info("Downloaded input", "url", label, "bytes", len(body))
+	return body, nil
+}
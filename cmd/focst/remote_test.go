@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/httpclient"
+)
+
+func TestParseRemoteInput(t *testing.T) {
+	for _, arg := range []string{"input.srt", "dir/input.srt", `C:\subs\input.srt`, "./a://b.srt"} {
+		if _, remote, err := parseRemoteInput(arg); remote || err != nil {
+			t.Fatalf("parseRemoteInput(%q) = remote %v, err %v; want local path", arg, remote, err)
+		}
+	}
+
+	u, remote, err := parseRemoteInput("https://user:pw@example.com/subs/in.srt?token=secret")
+	if err != nil || !remote {
+		t.Fatalf("parseRemoteInput(https) = remote %v, err %v", remote, err)
+	}
+	if got := remoteInputLabel(u); got != "https://example.com/subs/in.srt" {
+		t.Fatalf("remoteInputLabel = %q", got)
+	}
+	if got := remoteInputPath(u); got != "in.srt" {
+		t.Fatalf("remoteInputPath = %q", got)
+	}
+
+	if _, remote, err := parseRemoteInput("ftp://example.com/in.srt"); !remote || err == nil || !strings.Contains(err.Error(), "scheme must be http or https") {
+		t.Fatalf("parseRemoteInput(ftp) = remote %v, err %v; want scheme error", remote, err)
+	}
+	for _, arg := range []string{"ftp://u:pw@example.com/in.srt?token=secret", "https:///in.srt?token=secret", "https://[::1/in.srt?token=secret"} {
+		_, _, err := parseRemoteInput(arg)
+		if err == nil || strings.Contains(err.Error(), "secret") || strings.Contains(err.Error(), "pw") {
+			t.Fatalf("parseRemoteInput(%q) error = %v, want an error without the query or credentials", arg, err)
+		}
+	}
+}
+
+func TestFetchRemoteInput(t *testing.T) {
+	const body = "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/in.srt":
+			_, _ = w.Write([]byte(body))
+		case "/huge.srt":
+			_, _ = w.Write([]byte(strings.Repeat("a", httpclient.MaxResponseBytes+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetch := func(path string) ([]byte, error) {
+		u, _, err := parseRemoteInput(server.URL + path)
+		if err != nil {
+			t.Fatalf("parseRemoteInput: %v", err)
+		}
+		return fetchRemoteInput(context.Background(), u, time.Minute)
+	}
+
+	data, err := fetch("/in.srt")
+	if err != nil || string(data) != body {
+		t.Fatalf("fetch = %q, %v", data, err)
+	}
+	if _, err := fetch("/missing.srt"); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Fatalf("expected HTTP 404 error, got %v", err)
+	}
+	if _, err := fetch("/huge.srt"); !errors.Is(err, httpclient.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
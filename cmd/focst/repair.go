@@ -5,8 +5,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/oukeidos/focst/internal/httpclient"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/pipeline"
+	"github.com/oukeidos/focst/internal/recovery"
 	"github.com/oukeidos/focst/internal/translator"
 	"github.com/spf13/cobra"
 )
@@ -17,10 +19,27 @@ var (
 )
 
 type repairOptions struct {
-	forceRepair bool
-	allowEnv    bool
-	envOnly     bool
-	debug       bool
+	forceRepair        bool
+	mergeOutput        bool
+	restoreCached      bool
+	backup             bool
+	dumpFailed         bool
+	maxAge             time.Duration
+	force              bool
+	geminiEndpoint     string
+	requestTimeout     time.Duration
+	rampUp             time.Duration
+	jitterMax          time.Duration
+	noJitter           bool
+	relaxSafety        bool
+	maxSegmentsPerMin  int
+	allowEnv           bool
+	envOnly            bool
+	debug              bool
+	logUnredacted      bool
+	noColor            bool
+	insecureSkipVerify bool
+	maxConnsPerHost    int
 }
 
 func newRepairCmd() *cobra.Command {
@@ -31,7 +50,7 @@ func newRepairCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				_ = cmd.Usage()
-				return fmt.Errorf("session_log.json is required")
+				return withExitCode(exitBadInput, fmt.Errorf("session_log.json is required"))
 			}
 			return runRepair(cmd, args, &opts)
 		},
@@ -40,9 +59,26 @@ func newRepairCmd() *cobra.Command {
 
 	cmd.SetUsageTemplate(subcommandUsageTemplate)
 	cmd.Flags().BoolVar(&opts.forceRepair, "force-repair", false, "Ignore existing output and re-translate all chunks")
+	cmd.Flags().BoolVar(&opts.mergeOutput, "merge-output", false, "Use the existing output as the base and overwrite only the failed chunks; fail if it does not match the source")
+	cmd.Flags().BoolVar(&opts.restoreCached, "restore-cached", false, "Take chunks that passed from the chunk cache instead of the existing output, discarding edits to them")
+	cmd.Flags().BoolVar(&opts.backup, "backup", false, "Copy an existing output file to <output>.bak before overwriting it")
+	cmd.Flags().BoolVar(&opts.dumpFailed, "dump-failed", false, "Write the source text of chunks still failed next to the session log")
+	cmd.Flags().DurationVar(&opts.maxAge, "max-age", 0, "Refuse a session log older than this, e.g. 168h for 7 days (0 = no limit)")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Repair a session log older than --max-age anyway, with a warning")
+	cmd.Flags().StringVar(&opts.geminiEndpoint, "gemini-endpoint", "", "Gemini API endpoint URL (e.g. a proxy or compatible gateway)")
+	cmd.Flags().DurationVar(&opts.requestTimeout, "request-timeout", httpclient.DefaultTimeout, "Timeout for each Gemini API call; timed-out calls are retried")
+	cmd.Flags().DurationVar(&opts.rampUp, "ramp-up", translator.DefaultRampUp, "Stagger concurrent worker starts over this window to avoid an initial burst (0 = start all at once)")
+	cmd.Flags().DurationVar(&opts.jitterMax, "jitter-max", translator.DefaultJitterMax, "Upper bound of the random delay added to each retry backoff")
+	cmd.Flags().BoolVar(&opts.noJitter, "no-jitter", false, "Retry after exactly the computed backoff, without random jitter")
+	cmd.Flags().BoolVar(&opts.relaxSafety, "relax-safety", false, "Resend a chunk blocked by Gemini's safety filters once with blocking turned off before splitting it")
+	cmd.Flags().IntVar(&opts.maxSegmentsPerMin, "max-segments-per-minute", 0, "Submit at most this many segments per minute, on top of the request rate limit (0 = no cap)")
 	cmd.Flags().BoolVar(&opts.allowEnv, "allow-env", false, "Allow reading API key from environment variables")
 	cmd.Flags().BoolVar(&opts.envOnly, "env-only", false, "Use only environment variables for API keys")
 	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug logging")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable colored log output (also set by the NO_COLOR environment variable)")
+	cmd.Flags().BoolVar(&opts.logUnredacted, "log-unredacted", false, "Debugging only: log attributes without redacting keys, prompts, or subtitle text")
+	cmd.Flags().BoolVar(&opts.insecureSkipVerify, "insecure-skip-verify", false, "Disable TLS certificate verification (only for trusted TLS-intercepting corporate proxies)")
+	cmd.Flags().IntVar(&opts.maxConnsPerHost, "max-conns-per-host", httpclient.DefaultMaxConnsPerHost, "Maximum simultaneous connections to one API host; requests over it wait for a free connection")
 	return cmd
 }
 
@@ -54,7 +90,8 @@ func runRepair(cmd *cobra.Command, args []string, opts *repairOptions) error {
 	if opts.debug {
 		logLevel = logger.LevelDebug
 	}
-	logger.Init(logLevel, nil)
+	initLogger(logLevel, nil, opts.noColor, opts.logUnredacted)
+	configureTransport(httpclient.TransportOptions{InsecureSkipVerify: opts.insecureSkipVerify, MaxConnsPerHost: opts.maxConnsPerHost})
 
 	actualKey, source, err := resolveAPIKey("gemini", opts.allowEnv, opts.envOnly)
 	if err != nil {
@@ -63,14 +100,27 @@ func runRepair(cmd *cobra.Command, args []string, opts *repairOptions) error {
 	logger.Info("Using API Key", "service", "gemini", "source", source)
 
 	cfg := pipeline.Config{
-		LogPath:          logPath,
-		APIKey:           actualKey,
-		RetryOnLongLines: false,
-		ForceRepair:      opts.forceRepair,
-		OnProgress: func(p translator.TranslationProgress) {
+		LogPath:              logPath,
+		APIKey:               actualKey,
+		GeminiEndpoint:       opts.geminiEndpoint,
+		RequestTimeout:       opts.requestTimeout,
+		RampUp:               opts.rampUp,
+		JitterMax:            opts.jitterMax,
+		NoJitter:             opts.noJitter,
+		RelaxSafety:          opts.relaxSafety,
+		MaxSegmentsPerMinute: opts.maxSegmentsPerMin,
+		RetryOnLongLines:     false,
+		ForceRepair:          opts.forceRepair,
+		MergeOutput:          opts.mergeOutput,
+		RestoreCached:        opts.restoreCached,
+		BackupOutput:         opts.backup,
+		DumpFailed:           opts.dumpFailed,
+		RepairMaxAge:         opts.maxAge,
+		ForceStaleRepair:     opts.force,
+		OnRepairProgress: func(p recovery.RepairProgress) {
 			switch p.State {
 			case translator.StateCompleted:
-				logger.Info("Chunk completed", "chunk", p.ChunkIndex)
+				logger.Info("Chunk repaired", "chunk", p.ChunkIndex, "repaired", p.Repaired, "targets", p.Targets)
 			case translator.StateInProgress:
 				logger.Warn("Chunk retry", "chunk", p.ChunkIndex, "attempt", p.Attempt, "error", p.Error)
 			}
@@ -89,6 +139,9 @@ func runRepair(cmd *cobra.Command, args []string, opts *repairOptions) error {
 		if shouldPrintRepairStats(result) {
 			printRepairStatsFunc(&result.Usage, time.Since(startTime), result.Model)
 		}
+		if result.FailedChunks > 0 {
+			return withExitCode(exitPartial, err)
+		}
 		return err
 	}
 	printRepairStatsFunc(&result.Usage, time.Since(startTime), result.Model)
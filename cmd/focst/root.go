@@ -20,7 +20,7 @@ func execute() {
 		}
 	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -31,16 +31,16 @@ func newRootCmd() *cobra.Command {
 		Use:   "focst",
 		Short: "Format Constrained Subtitle Translator",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 {
+			if len(args) == 0 && !translateOpts.printPrompt {
 				if hasAnyFlagSet(cmd) {
 					_ = cmd.Usage()
-					return fmt.Errorf("input and output files are required")
+					return withExitCode(exitBadInput, fmt.Errorf("input and output files are required"))
 				}
 				return cmd.Help()
 			}
-			if isSubcommand(cmd, args[0]) {
+			if len(args) > 0 && isSubcommand(cmd, args[0]) {
 				_ = cmd.Usage()
-				return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+				return withExitCode(exitBadInput, fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath()))
 			}
 			return runTranslate(cmd, args, &translateOpts)
 		},
@@ -48,6 +48,9 @@ func newRootCmd() *cobra.Command {
 		SilenceUsage: true,
 	}
 
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(exitBadInput, err)
+	})
 	cmd.Version = version.Info()
 	cmd.SetVersionTemplate("{{.Version}}\n")
 	cmd.SetUsageTemplate(rootUsageTemplate)
@@ -56,11 +59,18 @@ func newRootCmd() *cobra.Command {
 
 	cmd.AddCommand(
 		newAboutCmd(),
+		newVersionCmd(),
 		newDisclaimerCmd(),
 		newTranslateCmd(),
 		newRepairCmd(),
 		newNamesCmd(),
+		newApplyGlossaryCmd(),
+		newQCCmd(),
+		newSplitCmd(),
+		newIDMapCmd(),
 		newListCmd(),
+		newLangsCmd(),
+		newModelsCmd(),
 		newEnvCmd(),
 		newLicensesCmd(),
 	)
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/spf13/cobra"
+)
+
+type splitOptions struct {
+	byDuration time.Duration
+	byCount    int
+	rebase     bool
+	yes        bool
+}
+
+func newSplitCmd() *cobra.Command {
+	opts := splitOptions{}
+	cmd := &cobra.Command{
+		Use:   "split [options] <input.srt>",
+		Short: "Split a subtitle file into parts by duration or segment count (no API calls)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Usage()
+				return withExitCode(exitBadInput, fmt.Errorf("exactly one subtitle file is required"))
+			}
+			return runSplit(cmd, args[0], &opts)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	cmd.Flags().DurationVar(&opts.byDuration, "by-duration", 0, "Start a new part every DURATION of the timeline (e.g. 45m)")
+	cmd.Flags().IntVar(&opts.byCount, "by-count", 0, "Put at most N segments in each part")
+	cmd.Flags().BoolVar(&opts.rebase, "rebase", false, "With --by-duration, shift each part so its window starts at 00:00:00")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Overwrite existing part files without failing")
+	return cmd
+}
+
+func runSplit(cmd *cobra.Command, inputPath string, opts *splitOptions) error {
+	if (opts.byDuration > 0) == (opts.byCount > 0) {
+		return withExitCode(exitBadInput, fmt.Errorf("exactly one of --by-duration or --by-count must be a positive value"))
+	}
+	if opts.rebase && opts.byDuration <= 0 {
+		return withExitCode(exitBadInput, fmt.Errorf("--rebase requires --by-duration"))
+	}
+	if err := validateSubtitleExtension("input", inputPath); err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	segments, err := srt.Load(inputPath)
+	if err != nil {
+		return withExitCode(exitBadInput, fmt.Errorf("failed to load subtitle file: %w", err))
+	}
+
+	var parts []srt.Part
+	if opts.byDuration > 0 {
+		srt.SortByStart(segments)
+		parts, err = srt.SplitByDuration(segments, opts.byDuration)
+	} else {
+		parts, err = srt.SplitByCount(segments, opts.byCount)
+	}
+	if err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+
+	paths := make([]string, len(parts))
+	for i := range parts {
+		paths[i] = srt.PartPath(inputPath, i+1)
+		if !opts.yes {
+			if _, err := os.Stat(paths[i]); err == nil {
+				return withExitCode(exitBadInput, fmt.Errorf("%s already exists (use --yes to overwrite)", paths[i]))
+			}
+		}
+		if err := files.RejectSymlinkPath(paths[i]); err != nil {
+			return err
+		}
+	}
+
+	for i, part := range parts {
+		if opts.rebase {
+			if err := srt.Shift(part.Segments, -part.Offset); err != nil {
+				return withExitCode(exitBadInput, err)
+			}
+		}
+		if err := srt.SaveWithOptions(paths[i], part.Segments, srt.SaveOptions{Verify: true, CueSettings: true}); err != nil {
+			return fmt.Errorf("failed to save %s: %w", paths[i], err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%d segments, from %s)\n", paths[i], len(part.Segments), srt.FormatTimestamp(part.Offset))
+	}
+	return nil
+}
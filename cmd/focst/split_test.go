@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "in.srt")
+	sub := "1\n00:00:01,000 --> 00:00:02,000\nFirst\n\n2\n00:45:10,000 --> 00:45:12,000\nSecond\n\n3\n00:50:00,000 --> 00:50:01,000\nThird\n\n"
+	if err := os.WriteFile(path, []byte(sub), 0600); err != nil {
+		t.Fatalf("write subtitle: %v", err)
+	}
+
+	out, err := executeCommand(t, "split", path, "--by-duration", "45m", "--rebase")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if !strings.Contains(out, "in.part2.srt (2 segments, from 00:45:00,000)") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	part2, err := os.ReadFile(filepath.Join(dir, "in.part2.srt"))
+	if err != nil {
+		t.Fatalf("read part 2: %v", err)
+	}
+	if !strings.Contains(string(part2), "1\n00:00:10,000 --> 00:00:12,000\nSecond") ||
+		!strings.Contains(string(part2), "2\n00:05:00,000 --> 00:05:01,000\nThird") {
+		t.Fatalf("part 2 not rebased:\n%s", part2)
+	}
+
+	if _, err := executeCommand(t, "split", path, "--by-count", "2"); exitCode(err) != exitBadInput {
+		t.Fatalf("expected refusal to overwrite existing parts, got %v", err)
+	}
+	if _, err := executeCommand(t, "split", path, "--by-count", "2", "--yes"); err != nil {
+		t.Fatalf("split by count failed: %v", err)
+	}
+	part1, _ := os.ReadFile(filepath.Join(dir, "in.part1.srt"))
+	if !strings.Contains(string(part1), "00:45:10,000 --> 00:45:12,000") {
+		t.Fatalf("count split should keep timings:\n%s", part1)
+	}
+
+	for _, args := range [][]string{
+		{"split", path},
+		{"split", path, "--by-count", "2", "--by-duration", "1m"},
+		{"split", path, "--by-count", "2", "--rebase"},
+	} {
+		if _, err := executeCommand(t, args...); exitCode(err) != exitBadInput {
+			t.Fatalf("%v: expected bad input, got %v", args, err)
+		}
+	}
+}
@@ -10,32 +10,106 @@ import (
 
 	"github.com/oukeidos/focst/internal/cleanup"
 	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/httpclient"
+	"github.com/oukeidos/focst/internal/language"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/pipeline"
 	"github.com/oukeidos/focst/internal/prompt"
+	"github.com/oukeidos/focst/internal/srt"
 	"github.com/oukeidos/focst/internal/translator"
 	"github.com/spf13/cobra"
 )
 
 type translateOptions struct {
-	modelName         string
-	chunkSize         int
-	contextSize       int
-	concurrency       int
-	validateCPL       bool
-	noPromptCPL       bool
-	yes               bool
-	logFilePath       string
-	namesPath         string
-	noPreprocess      bool
-	noPostprocess     bool
-	noLangPreprocess  bool
-	noLangPostprocess bool
-	sourceLangCode    string
-	targetLangCode    string
-	allowEnv          bool
-	envOnly           bool
-	debug             bool
+	modelName          string
+	polishModel        string
+	inPlace            bool
+	chunkSize          int
+	contextSize        int
+	clampContext       bool
+	concurrency        int
+	validateCPL        bool
+	noPromptCPL        bool
+	cplMetric          string
+	cplTolerance       float64
+	yes                bool
+	mkdir              bool
+	logFilePath        string
+	namesPath          string
+	autoNames          bool
+	title              string
+	year               string
+	workType           string
+	noPreprocess       bool
+	noPostprocess      bool
+	noLangPreprocess   bool
+	noBracketRemoval   bool
+	noAngleStrip       bool
+	noMeaningless      bool
+	noLangPostprocess  bool
+	noTimingFix        bool
+	normalizeQuotes    bool
+	savePartial        bool
+	filterRegex        string
+	forcedOnly         bool
+	excludeIDs         string
+	noSkipNonText      bool
+	allowSameLang      bool
+	allowNoDialogue    bool
+	chunkCache         bool
+	dumpFailed         bool
+	maxCost            float64
+	maxSegments        int
+	confirmOverCost    float64
+	geminiEndpoint     string
+	requestTimeout     time.Duration
+	rampUp             time.Duration
+	jitterMax          time.Duration
+	noJitter           bool
+	relaxSafety        bool
+	maxSegmentsPerMin  int
+	stallTimeout       time.Duration
+	cancelOnStall      bool
+	priorityFirst      bool
+	termMemoryPath     string
+	glossaryReport     string
+	failureReport      string
+	dumpIDMap          string
+	tmxPath            string
+	tmxImportPath      string
+	keepLog            bool
+	embedMetadata      bool
+	keepCueSettings    bool
+	keepDashes         bool
+	onEmpty            string
+	onEmptied          string
+	postprocessLang    string
+	compatProfile      string
+	contextFromFile    string
+	formality          string
+	narrativeTag       string
+	singleLine         bool
+	autoLinebreak      bool
+	translateNotes     bool
+	dedupRepeats       bool
+	improve            bool
+	failFast           bool
+	sample             int
+	inputFormat        string
+	inputEncoding      string
+	outputFormat       string
+	strictExtensions   bool
+	sourceLangCode     string
+	targetLangCode     string
+	allowEnv           bool
+	envOnly            bool
+	debug              bool
+	logUnredacted      bool
+	noColor            bool
+	insecureSkipVerify bool
+	maxConnsPerHost    int
+	printPrompt        bool
 }
 
 func newTranslateCmd() *cobra.Command {
@@ -44,9 +118,9 @@ func newTranslateCmd() *cobra.Command {
 		Use:   "translate <input.srt> <output.srt>",
 		Short: "Translate subtitle files using Gemini",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 2 {
+			if len(args) < 2 && !opts.printPrompt && !opts.inPlace {
 				_ = cmd.Usage()
-				return fmt.Errorf("input and output files are required")
+				return withExitCode(exitBadInput, fmt.Errorf("input and output files are required"))
 			}
 			return runTranslate(cmd, args, &opts)
 		},
@@ -60,37 +134,139 @@ func newTranslateCmd() *cobra.Command {
 
 func addTranslateFlags(cmd *cobra.Command, opts *translateOptions) {
 	cmd.Flags().StringVar(&opts.modelName, "model", "gemini-3-flash-preview", "Gemini model name")
-	cmd.Flags().IntVar(&opts.chunkSize, "chunk-size", 100, "Number of segments per chunk")
+	cmd.Flags().StringVar(&opts.polishModel, "polish-model", "", "Run a second pass with this Gemini model, improving the first-pass translation as a draft")
+	cmd.Flags().IntVar(&opts.chunkSize, "chunk-size", language.DefaultChunkSize, "Number of segments per chunk (unset: suggested size for the language pair)")
 	cmd.Flags().IntVar(&opts.contextSize, "context-size", 5, "Number of context segments before/after")
+	cmd.Flags().BoolVar(&opts.clampContext, "clamp-context", false, "Cap --context-size at half of --chunk-size instead of only warning when it is larger")
 	cmd.Flags().IntVar(&opts.concurrency, "concurrency", 7, "Number of concurrent API requests (1-20)")
 	cmd.Flags().BoolVar(&opts.validateCPL, "retry-on-long-line", false, "Retry validation if line > 24 graphemes (default false)")
 	cmd.Flags().BoolVar(&opts.noPromptCPL, "no-prompt-cpl", false, "Disable CPL constraints in the translation prompt")
+	cmd.Flags().StringVar(&opts.cplMetric, "cpl-metric", string(translator.CPLMetricGraphemes), "Line length metric for CPL validation: graphemes|width (width counts full-width as 2; CJK targets only)")
+	cmd.Flags().Float64Var(&opts.cplTolerance, "cpl-tolerance", translator.DefaultCPLTolerance, "With --retry-on-long-line, retry lines longer than this multiple of the target CPL (>= 1.0)")
 	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Overwrite output file without asking")
+	cmd.Flags().BoolVar(&opts.mkdir, "mkdir", false, "Create the output file's directory if it does not exist")
+	cmd.Flags().BoolVar(&opts.inPlace, "in-place", false, "Replace the input with its translation, keeping the original as <input>.orig.<ext> (give only the input file)")
 	cmd.Flags().StringVar(&opts.logFilePath, "log-file", "", "Path to save machine-readable JSONL logs")
 	cmd.Flags().StringVar(&opts.namesPath, "names", "", "Path to character name mapping JSON file")
+	cmd.Flags().BoolVar(&opts.autoNames, "auto-names", false, "Extract a character name mapping with OpenAI (needs --title) and use it for this run")
+	cmd.Flags().StringVar(&opts.title, "title", "", "With --auto-names, title of the work")
+	cmd.Flags().StringVar(&opts.year, "year", "", "With --auto-names, release year")
+	cmd.Flags().StringVar(&opts.workType, "type", "movie", "With --auto-names, type of work (movie, show, etc.)")
 	cmd.Flags().BoolVar(&opts.noPreprocess, "no-preprocess", false, "Disable all preprocessing (bracket removal, symbol filtering)")
 	cmd.Flags().BoolVar(&opts.noLangPreprocess, "no-lang-preprocess", false, "Disable language-specific preprocessing only")
+	cmd.Flags().BoolVar(&opts.noBracketRemoval, "no-bracket-removal", false, "Keep text in (), [], （）, and ［］ during Japanese preprocessing")
+	cmd.Flags().BoolVar(&opts.noAngleStrip, "no-angle-strip", false, "Keep < and > characters during Japanese preprocessing")
+	cmd.Flags().BoolVar(&opts.noMeaningless, "no-meaningless-filter", false, "Keep symbol-only segments during Japanese preprocessing")
 	cmd.Flags().BoolVar(&opts.noPostprocess, "no-postprocess", false, "Disable all post-processing (punctuation, timing correction)")
 	cmd.Flags().BoolVar(&opts.noLangPostprocess, "no-lang-postprocess", false, "Disable language-specific post-processing only")
+	cmd.Flags().BoolVar(&opts.noTimingFix, "no-timing-correction", false, "Keep source timing untouched during post-processing (punctuation cleanup still runs)")
+	cmd.Flags().BoolVar(&opts.normalizeQuotes, "normalize-quotes", false, "Replace straight quotes with the target language's quotation marks during post-processing (e.g. “ ” for en, « » for fr, „ “ for de)")
+	cmd.Flags().StringVar(&opts.onEmptied, "on-emptied", string(srt.EmptiedKeep), "What to do with a segment that punctuation cleanup leaves empty: keep (keep its text from before cleanup) or drop (remove the cue)")
+	cmd.Flags().StringVar(&opts.postprocessLang, "postprocess-lang", "", "Run this language's punctuation rules during post-processing instead of the target's (ko, ja, zh-Hans, zh-Hant)")
+	cmd.Flags().StringVar(&opts.compatProfile, "compat-profile", "", "Apply a player compatibility profile to SRT/WebVTT output: "+srt.CompatProfileNamesLabel)
+	cmd.Flags().BoolVar(&opts.savePartial, "save-partial-on-failure", false, "Save output even when all chunks fail (failed chunks keep source text)")
+	cmd.Flags().StringVar(&opts.filterRegex, "filter-regex", "", "Translate only segments whose text matches this regex; others pass through unchanged")
+	cmd.Flags().BoolVar(&opts.forcedOnly, "forced-only", false, "Translate only SSA/ASS events with a \"forced\" style; others pass through unchanged")
+	cmd.Flags().StringVar(&opts.excludeIDs, "exclude", "", "Keep these segment IDs verbatim and translate the rest, e.g. 10-20,45")
+	cmd.Flags().BoolVar(&opts.noSkipNonText, "no-skip-non-translatable", false, "Send pure numbers, URLs, and product codes to the model instead of passing them through verbatim")
+	cmd.Flags().BoolVar(&opts.allowSameLang, "allow-same-lang", false, "When source equals target, copy subtitles through (with pre/post-processing) instead of failing")
+	cmd.Flags().BoolVar(&opts.allowNoDialogue, "allow-no-dialogue", false, "Accept files without dialogue text (e.g. only music cues) and copy them through with post-processing")
+	cmd.Flags().BoolVar(&opts.chunkCache, "chunk-cache", false, "Store completed chunks next to the output so an interrupted run can reuse them")
+	cmd.Flags().BoolVar(&opts.dumpFailed, "dump-failed", false, "Write the source text of failed chunks next to the recovery log")
+	cmd.Flags().Float64Var(&opts.maxCost, "max-cost", 0, "Stop and save partial output once estimated spend reaches this many USD (0 = no cap)")
+	cmd.Flags().IntVar(&opts.maxSegments, "max-segments", 0, "Ask before translating more than this many segments; needs --yes when not interactive (0 = no limit)")
+	cmd.Flags().Float64Var(&opts.confirmOverCost, "confirm-over-cost", 0, "Ask before a run whose estimated cost exceeds this many USD; needs --yes when not interactive (0 = never ask)")
+	cmd.Flags().StringVar(&opts.geminiEndpoint, "gemini-endpoint", "", "Gemini API endpoint URL (e.g. a proxy or compatible gateway)")
+	cmd.Flags().DurationVar(&opts.requestTimeout, "request-timeout", httpclient.DefaultTimeout, "Timeout for each Gemini API call; timed-out calls are retried")
+	cmd.Flags().DurationVar(&opts.rampUp, "ramp-up", translator.DefaultRampUp, "Stagger concurrent worker starts over this window to avoid an initial burst (0 = start all at once)")
+	cmd.Flags().DurationVar(&opts.jitterMax, "jitter-max", translator.DefaultJitterMax, "Upper bound of the random delay added to each retry backoff")
+	cmd.Flags().BoolVar(&opts.noJitter, "no-jitter", false, "Retry after exactly the computed backoff, without random jitter")
+	cmd.Flags().BoolVar(&opts.relaxSafety, "relax-safety", false, "Resend a chunk blocked by Gemini's safety filters once with blocking turned off before splitting it")
+	cmd.Flags().IntVar(&opts.maxSegmentsPerMin, "max-segments-per-minute", 0, "Submit at most this many segments per minute, on top of the request rate limit (0 = no cap)")
+	cmd.Flags().DurationVar(&opts.stallTimeout, "stall-timeout", 0, "Warn when no chunk completes for this long, e.g. because every call is hung (0 = off)")
+	cmd.Flags().BoolVar(&opts.cancelOnStall, "cancel-on-stall", false, "With --stall-timeout, cancel the run on a stall and keep completed chunks for repair")
+	cmd.Flags().BoolVar(&opts.priorityFirst, "priority-first", false, "Stream finished chunks, front of file first, to <output>.partial.<ext> while translating (removed once the output is saved)")
+	cmd.Flags().StringVar(&opts.termMemoryPath, "term-memory", "", "JSON file of remembered phrase choices shared across files in a series (created if missing)")
+	cmd.Flags().BoolVar(&opts.printPrompt, "print-prompt", false, "Print the system prompt these options would send (names mapping, term memory, CPL rules) and exit without calling the API")
+	cmd.Flags().StringVar(&opts.glossaryReport, "glossary-report", "", "With --names, write a JSON report of which name mappings the translation honored")
+	cmd.Flags().StringVar(&opts.failureReport, "failure-report", "", "Write a JSON report of failed chunks with the kind of their last error and whether repair can fix them")
+	cmd.Flags().StringVar(&opts.dumpIDMap, "dump-idmap", "", "Write the mapping between internal segment IDs and the input's cue numbers to this JSON file")
+	cmd.Flags().StringVar(&opts.tmxPath, "tmx", "", "After a successful run, export the source and translated segments to this TMX 1.4 translation memory")
+	cmd.Flags().StringVar(&opts.tmxImportPath, "tmx-import", "", "Send translations from this TMX translation memory as drafts for segments whose source matches")
+	cmd.Flags().BoolVar(&opts.keepLog, "keep-log", false, "Write the session log even when every chunk succeeds (for audits; repair rejects it)")
+	cmd.Flags().BoolVar(&opts.embedMetadata, "embed-metadata", false, "Record model, languages, date, focst version, and a settings hash as comments in VTT/ASS/SSA output")
+	cmd.Flags().BoolVar(&opts.keepCueSettings, "keep-cue-settings", false, "Keep WebVTT cue positioning (align, line, position, size, vertical) on translated cues (VTT input and output only)")
+	cmd.Flags().BoolVar(&opts.keepDashes, "preserve-dialogue-dashes", false, "Strip leading speaker dashes (\"- \", \"—\") before translation and re-apply the source's dashes afterward")
+	cmd.Flags().StringVar(&opts.onEmpty, "on-empty", string(translator.EmptyPolicyFail), "What to do when a segment's translation comes back empty: fail (retry the chunk) or keep-source (keep the original text for that segment)")
+	cmd.Flags().StringVar(&opts.contextFromFile, "context-from-file", "", "Add this text file (a synopsis, character notes) to the system prompt as background information; files over 4 KiB are cut")
+	cmd.Flags().StringVar(&opts.formality, "formality", string(translator.FormalityAuto), "How to address people in languages with formal and informal \"you\" (du/Sie, tu/vous, 반말/존댓말): formal, informal, or auto (left to the model)")
+	cmd.Flags().StringVar(&opts.narrativeTag, "narrative-tag", "", "Translate on-screen text (signs, notes) concisely and literally, apart from dialogue: forced (SSA/ASS forced style), positioned (SSA/ASS \\pos or \\move), or bracketed (whole text in brackets)")
+	cmd.Flags().BoolVar(&opts.singleLine, "single-line", false, "Never produce two-line subtitles: the model is told to keep each subtitle on one line, and any second line is joined onto the first")
+	cmd.Flags().BoolVar(&opts.autoLinebreak, "auto-linebreak", false, "Split a one-line translation longer than the target CPL into two lines at a space or clause punctuation")
+	cmd.Flags().BoolVar(&opts.translateNotes, "translate-notes", false, "Also translate WebVTT NOTE blocks (kept verbatim by default)")
+	cmd.Flags().BoolVar(&opts.dedupRepeats, "dedup-repeats", false, "Translate identical repeated lines once per run and reuse the result for every occurrence")
+	cmd.Flags().BoolVar(&opts.improve, "improve", false, "Send target-language lines already in a segment to the model as a draft to improve")
+	cmd.Flags().BoolVar(&opts.failFast, "fail-fast", false, "Stop at the first chunk failure that cannot be retried (e.g. auth or bad request) instead of finishing the remaining chunks")
+	cmd.Flags().IntVar(&opts.sample, "sample", 0, "Translate only the first N segments into <output>.sample.<ext> for a quick quality check (no recovery log)")
+	cmd.Flags().StringVar(&opts.inputFormat, "input-format", "", "Parse the input as this format regardless of its extension: "+srt.FormatNamesLabel)
+	cmd.Flags().StringVar(&opts.inputEncoding, "input-encoding", "", "Read the input in this text encoding instead of detecting it: "+srt.EncodingNamesLabel)
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "", "Write the output in this format regardless of its extension: "+srt.FormatNamesLabel)
+	cmd.Flags().BoolVar(&opts.strictExtensions, "strict-extensions", true, "Reject input/output paths with unrecognized extensions; when false they are read and written as SRT unless --input-format/--output-format is given")
 	cmd.Flags().StringVar(&opts.sourceLangCode, "source", "ja", "Source language code (default: ja)")
 	cmd.Flags().StringVar(&opts.targetLangCode, "target", "ko", "Target language code (default: ko)")
 	cmd.Flags().BoolVar(&opts.allowEnv, "allow-env", false, "Allow reading API key from environment variables")
 	cmd.Flags().BoolVar(&opts.envOnly, "env-only", false, "Use only environment variables for API keys")
 	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug logging")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable colored log output (also set by the NO_COLOR environment variable)")
+	cmd.Flags().BoolVar(&opts.logUnredacted, "log-unredacted", false, "Debugging only: log attributes without redacting keys, prompts, or subtitle text")
+	cmd.Flags().BoolVar(&opts.insecureSkipVerify, "insecure-skip-verify", false, "Disable TLS certificate verification (only for trusted TLS-intercepting corporate proxies)")
+	cmd.Flags().IntVar(&opts.maxConnsPerHost, "max-conns-per-host", httpclient.DefaultMaxConnsPerHost, "Maximum simultaneous connections to one API host; requests over it wait for a free connection")
 }
 
 func runTranslate(cmd *cobra.Command, args []string, opts *translateOptions) error {
+	if opts.printPrompt {
+		return printSystemPrompt(cmd, opts)
+	}
+	if opts.inPlace {
+		if len(args) != 1 {
+			return withExitCode(exitBadInput, fmt.Errorf("--in-place takes only the input file, which the translation replaces"))
+		}
+		if isRemoteInput(args[0]) {
+			return withExitCode(exitBadInput, fmt.Errorf("--in-place needs a local input file, not a URL"))
+		}
+		args = []string{args[0], args[0]}
+	}
 	if len(args) < 2 {
-		return fmt.Errorf("input and output files are required")
+		return withExitCode(exitBadInput, fmt.Errorf("input and output files are required"))
+	}
+	if err := opts.validateAutoNames(); err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	if opts.cancelOnStall && opts.stallTimeout <= 0 {
+		return withExitCode(exitBadInput, fmt.Errorf("--cancel-on-stall requires --stall-timeout"))
 	}
 	if len(args) > 2 {
 		fmt.Fprintf(os.Stderr, "Warning: expected 2 arguments but got %d. Did you forget quotes around file paths?\n", len(args))
 		fmt.Fprintf(os.Stderr, "  Using input: %s\n", args[0])
 		fmt.Fprintf(os.Stderr, "  Using output: %s\n", args[1])
 	}
-	if err := validateSubtitlePathExtensions(args[0], args[1]); err != nil {
-		return err
+	inputPath := args[0]
+	inputURL, remote, err := parseRemoteInput(args[0])
+	if err != nil {
+		return withExitCode(exitBadInput, err)
 	}
+	if remote {
+		inputPath = remoteInputPath(inputURL)
+	}
+	inputFormat, outputFormat, err := opts.subtitleFormats(inputPath, args[1])
+	if err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	if remote && inputFormat == "" {
+		// There is no local file for the pipeline to infer the format from.
+		inputFormat = strings.TrimPrefix(strings.ToLower(filepath.Ext(inputPath)), ".")
+	}
+	opts.inputFormat, opts.outputFormat = inputFormat, outputFormat
 
 	logLevel := logger.LevelInfo
 	if opts.debug {
@@ -108,7 +284,8 @@ func runTranslate(cmd *cobra.Command, args []string, opts *translateOptions) err
 		cleanup.Register(f.Close)
 		logFileW = f
 	}
-	logger.Init(logLevel, logFileW)
+	initLogger(logLevel, logFileW, opts.noColor, opts.logUnredacted)
+	configureTransport(httpclient.TransportOptions{InsecureSkipVerify: opts.insecureSkipVerify, MaxConnsPerHost: opts.maxConnsPerHost})
 
 	startTime := time.Now()
 
@@ -122,54 +299,74 @@ func runTranslate(cmd *cobra.Command, args []string, opts *translateOptions) err
 	if opts.namesPath != "" {
 		nameMapping, err = loadNamesMapping(opts.namesPath, opts.sourceLangCode, opts.targetLangCode)
 		if err != nil {
-			return err
+			return withExitCode(exitBadInput, err)
 		}
 	}
 
-	cfg := pipeline.Config{
-		InputPath:         args[0],
-		OutputPath:        args[1],
-		LogPath:           opts.logFilePath,
-		APIKey:            actualKey,
-		Model:             opts.modelName,
-		ChunkSize:         opts.chunkSize,
-		ContextSize:       opts.contextSize,
-		Concurrency:       opts.concurrency,
-		RetryOnLongLines:  opts.validateCPL,
-		NoPromptCPL:       opts.noPromptCPL,
-		NoPreprocess:      opts.noPreprocess,
-		NoPostprocess:     opts.noPostprocess,
-		NoLangPreprocess:  opts.noLangPreprocess,
-		NoLangPostprocess: opts.noLangPostprocess,
-		Overwrite:         opts.yes,
-		SourceLang:        opts.sourceLangCode,
-		TargetLang:        opts.targetLangCode,
-		NamesMapping:      nameMapping,
-		NamesPath:         opts.namesPath,
-		OnProgress: func(p translator.TranslationProgress) {
-			switch p.State {
-			case translator.StateCompleted:
-				logger.Info("Chunk completed", "index", p.ChunkIndex, "total", p.TotalChunks)
-			case translator.StateInProgress:
-				logger.Warn("Chunk retry", "index", p.ChunkIndex, "attempt", p.Attempt, "error", p.Error)
-			}
-		},
-		OnConfirmOverwrite: func(path string) bool {
-			confirmed, err := prompt.DefaultConfirmer().ConfirmOverwrite(path, opts.yes)
-			if err != nil {
-				logger.Error("Overwrite confirmation failed", "error", err)
-				return false
-			}
-			return confirmed
-		},
-	}
-
 	ctx, stop := signalContext()
 	defer stop()
+	var autoNames *autoNamesResult
+	if opts.autoNames {
+		autoNames, err = runAutoNames(ctx, opts)
+		if err != nil {
+			logger.Warn("Name extraction canceled", "error", err)
+			return nil
+		}
+		if autoNames != nil {
+			nameMapping = autoNames.mapping
+		}
+	}
+
+	inputLabel := args[0]
+	if remote {
+		inputLabel = remoteInputLabel(inputURL)
+	}
+	cfg, err := pipeline.NewConfig(inputLabel, args[1], actualKey, opts.pipelineOptions(cmd.Flags().Changed("chunk-size"), nameMapping))
+	if err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	cfg.LogPath = opts.logFilePath
+	cfg.OnProgress = func(p translator.TranslationProgress) {
+		switch p.State {
+		case translator.StateCompleted:
+			logger.Info("Chunk completed", "index", p.ChunkIndex, "total", p.TotalChunks)
+		case translator.StateInProgress:
+			logger.Warn("Chunk retry", "index", p.ChunkIndex, "attempt", p.Attempt, "error", p.Error)
+		}
+	}
+	cfg.OnEstimate = func(e pipeline.Estimate) {
+		if e.Remaining > 0 {
+			logger.Info("Estimated remaining", "eta", e.Remaining.Round(time.Second), "tokens_per_sec", int(e.TokensPerSecond), "projected_cost", fmt.Sprintf("$%.5f", e.ProjectedCost))
+		}
+	}
+	cfg.OnConfirmOverwrite = func(path string) bool {
+		confirmed, err := prompt.DefaultConfirmer().ConfirmOverwrite(path, opts.yes)
+		if err != nil {
+			logger.Error("Overwrite confirmation failed", "error", err)
+			return false
+		}
+		return confirmed
+	}
+	cfg.OnConfirmLargeRun = func(reason string) (bool, error) {
+		return prompt.DefaultConfirmer().ConfirmLargeRun(reason, opts.yes)
+	}
+
+	if remote {
+		cfg.InputData, err = fetchRemoteInput(ctx, inputURL, opts.requestTimeout)
+		if err != nil {
+			return err
+		}
+	}
 	result, err := pipeline.RunTranslation(ctx, cfg)
 
 	// Always print stats (even on partial success)
-	printUsageStats(&result.Usage, time.Since(startTime), opts.modelName)
+	usage := firstPassUsage(result)
+	printUsageStats(&usage, time.Since(startTime), opts.modelName)
+	geminiCost := estimateGeminiCost(opts.modelName, usage)
+	if result.PolishModel != "" {
+		geminiCost = printPolishStats(result.PolishModel, result.PolishUsage, geminiCost)
+	}
+	printAutoNamesStats(autoNames, geminiCost)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -182,6 +379,117 @@ func runTranslate(cmd *cobra.Command, args []string, opts *translateOptions) err
 	return translationStatusError(result)
 }
 
+// printSystemPrompt writes the system prompt the translate flags would send.
+// It needs no API key and no input file.
+func printSystemPrompt(cmd *cobra.Command, opts *translateOptions) error {
+	var nameMapping map[string]string
+	if opts.namesPath != "" {
+		var err error
+		nameMapping, err = loadNamesMapping(opts.namesPath, opts.sourceLangCode, opts.targetLangCode)
+		if err != nil {
+			return withExitCode(exitBadInput, err)
+		}
+	}
+	prompt, err := pipeline.SystemPrompt(opts.pipelineOptions(cmd.Flags().Changed("chunk-size"), nameMapping))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), prompt)
+	return nil
+}
+
+// firstPassUsage returns the usage of result without its polish pass.
+func firstPassUsage(result pipeline.TranslationResult) gemini.UsageMetadata {
+	usage := result.Usage
+	usage.PromptTokenCount -= result.PolishUsage.PromptTokenCount
+	usage.CandidatesTokenCount -= result.PolishUsage.CandidatesTokenCount
+	usage.TotalTokenCount -= result.PolishUsage.TotalTokenCount
+	return usage
+}
+
+// pipelineOptions maps the translate flags onto pipeline.Options.
+// chunkSizeSet reports whether --chunk-size was given explicitly.
+func (o *translateOptions) pipelineOptions(chunkSizeSet bool, nameMapping map[string]string) pipeline.Options {
+	return pipeline.Options{
+		Model:                 o.modelName,
+		PolishModel:           o.polishModel,
+		GeminiEndpoint:        o.geminiEndpoint,
+		RequestTimeout:        o.requestTimeout,
+		RampUp:                o.rampUp,
+		JitterMax:             o.jitterMax,
+		NoJitter:              o.noJitter,
+		RelaxSafety:           o.relaxSafety,
+		MaxSegmentsPerMinute:  o.maxSegmentsPerMin,
+		StallTimeout:          o.stallTimeout,
+		CancelOnStall:         o.cancelOnStall,
+		PriorityFirst:         o.priorityFirst,
+		ChunkSize:             o.chunkSize,
+		AutoChunkSize:         !chunkSizeSet,
+		ContextSize:           o.contextSize,
+		ClampContext:          o.clampContext,
+		Concurrency:           o.concurrency,
+		RetryOnLongLines:      o.validateCPL,
+		NoPromptCPL:           o.noPromptCPL,
+		CPLMetric:             o.cplMetric,
+		CPLTolerance:          o.cplTolerance,
+		NoPreprocess:          o.noPreprocess,
+		NoPostprocess:         o.noPostprocess,
+		Overwrite:             o.yes,
+		MakeOutputDir:         o.mkdir,
+		InPlace:               o.inPlace,
+		NoLangPreprocess:      o.noLangPreprocess,
+		NoBracketRemoval:      o.noBracketRemoval,
+		NoAngleStrip:          o.noAngleStrip,
+		NoMeaninglessFilter:   o.noMeaningless,
+		NoLangPostprocess:     o.noLangPostprocess,
+		NoTimingCorrection:    o.noTimingFix,
+		NormalizeQuotes:       o.normalizeQuotes,
+		SavePartialOnFailure:  o.savePartial,
+		FilterRegex:           o.filterRegex,
+		ForcedOnly:            o.forcedOnly,
+		ExcludeIDs:            o.excludeIDs,
+		NoSkipNonTranslatable: o.noSkipNonText,
+		AllowSameLang:         o.allowSameLang,
+		AllowNoDialogue:       o.allowNoDialogue,
+		ChunkCache:            o.chunkCache,
+		DumpFailed:            o.dumpFailed,
+		MaxCost:               o.maxCost,
+		MaxSegments:           o.maxSegments,
+		ConfirmOverCost:       o.confirmOverCost,
+		EmbedMetadata:         o.embedMetadata,
+		KeepCueSettings:       o.keepCueSettings,
+		KeepDialogueDashes:    o.keepDashes,
+		OnEmpty:               o.onEmpty,
+		OnEmptied:             o.onEmptied,
+		PostprocessLang:       o.postprocessLang,
+		CompatProfile:         o.compatProfile,
+		BackgroundPath:        o.contextFromFile,
+		Formality:             o.formality,
+		NarrativeTag:          o.narrativeTag,
+		SingleLine:            o.singleLine,
+		AutoLinebreak:         o.autoLinebreak,
+		TranslateNotes:        o.translateNotes,
+		DedupRepeats:          o.dedupRepeats,
+		ImproveDrafts:         o.improve,
+		FailFast:              o.failFast,
+		Sample:                o.sample,
+		TermMemoryPath:        o.termMemoryPath,
+		GlossaryReportPath:    o.glossaryReport,
+		FailureReportPath:     o.failureReport,
+		DumpIDMap:             o.dumpIDMap,
+		TMXPath:               o.tmxPath,
+		TMXImportPath:         o.tmxImportPath,
+		KeepLog:               o.keepLog,
+		InputFormat:           o.inputFormat,
+		InputEncoding:         o.inputEncoding,
+		OutputFormat:          o.outputFormat,
+		SourceLang:            o.sourceLangCode,
+		TargetLang:            o.targetLangCode,
+		NamesMapping:          nameMapping,
+		NamesPath:             o.namesPath,
+	}
+}
+
 func translationStatusError(result pipeline.TranslationResult) error {
 	switch result.Status {
 	case pipeline.TranslationStatusSuccess:
@@ -189,10 +497,21 @@ func translationStatusError(result pipeline.TranslationResult) error {
 	case pipeline.TranslationStatusSkipped:
 		return nil
 	case pipeline.TranslationStatusPartialSuccess, pipeline.TranslationStatusFailure:
+		outcome := "finished"
+		if result.CostCapped {
+			outcome = "stopped by --max-cost"
+		}
+		code := exitPartial
+		if result.Status == pipeline.TranslationStatusFailure {
+			code = failedRunExitCode(result.Throughput)
+		}
+		if result.Status == pipeline.TranslationStatusFailure && result.PartialOutput {
+			return withExitCode(code, fmt.Errorf("translation %s with status: %s (partial output: %s, recovery log: %s)", outcome, result.Status, result.OutputPath, result.RecoveryLogPath))
+		}
 		if result.RecoveryLogPath != "" {
-			return fmt.Errorf("translation finished with status: %s (recovery log: %s)", result.Status, result.RecoveryLogPath)
+			return withExitCode(code, fmt.Errorf("translation %s with status: %s (recovery log: %s)", outcome, result.Status, result.RecoveryLogPath))
 		}
-		return fmt.Errorf("translation finished with status: %s", result.Status)
+		return withExitCode(code, fmt.Errorf("translation %s with status: %s", outcome, result.Status))
 	default:
 		return fmt.Errorf("translation finished with unknown status: %q", result.Status)
 	}
@@ -209,14 +528,37 @@ var supportedSubtitleExtensions = map[string]struct{}{
 
 const supportedSubtitleExtensionsLabel = ".srt, .vtt, .ssa, .ass, .ttml, .stl"
 
-func validateSubtitlePathExtensions(inputPath, outputPath string) error {
-	if err := validateSubtitleExtension("input", inputPath); err != nil {
-		return err
+// subtitleFormats checks the input and output paths and returns the format to
+// force for each ("" = infer from the extension). An explicit format skips the
+// extension check; with --strict-extensions=false an unrecognized extension
+// falls back to SRT.
+func (o *translateOptions) subtitleFormats(inputPath, outputPath string) (string, string, error) {
+	inputFormat, err := resolveSubtitleFormat("input", inputPath, o.inputFormat, o.strictExtensions)
+	if err != nil {
+		return "", "", err
 	}
-	if err := validateSubtitleExtension("output", outputPath); err != nil {
-		return err
+	outputFormat, err := resolveSubtitleFormat("output", outputPath, o.outputFormat, o.strictExtensions)
+	if err != nil {
+		return "", "", err
 	}
-	return nil
+	return inputFormat, outputFormat, nil
+}
+
+func resolveSubtitleFormat(kind, path, format string, strict bool) (string, error) {
+	format, err := srt.ParseFormat(format)
+	if err != nil {
+		return "", fmt.Errorf("invalid --%s-format: %w", kind, err)
+	}
+	if format != "" {
+		return format, nil
+	}
+	if err := validateSubtitleExtension(kind, path); err != nil {
+		if strict {
+			return "", err
+		}
+		return "srt", nil
+	}
+	return "", nil
 }
 
 func validateSubtitleExtension(kind, path string) error {
@@ -7,13 +7,14 @@ import (
 
 func TestValidateSubtitlePathExtensions(t *testing.T) {
 	t.Run("accepts_supported_extensions", func(t *testing.T) {
-		if err := validateSubtitlePathExtensions("in.srt", "out.ass"); err != nil {
+		strict := &translateOptions{strictExtensions: true}
+		if _, _, err := strict.subtitleFormats("in.srt", "out.ass"); err != nil {
 			t.Fatalf("expected nil error, got %v", err)
 		}
 	})
 
 	t.Run("rejects_unsupported_input_extension", func(t *testing.T) {
-		err := validateSubtitlePathExtensions("in.txt", "out.srt")
+		_, _, err := (&translateOptions{strictExtensions: true}).subtitleFormats("in.txt", "out.srt")
 		if err == nil {
 			t.Fatalf("expected error")
 		}
@@ -23,7 +24,7 @@ func TestValidateSubtitlePathExtensions(t *testing.T) {
 	})
 
 	t.Run("rejects_unsupported_output_extension", func(t *testing.T) {
-		err := validateSubtitlePathExtensions("in.srt", "out.foo")
+		_, _, err := (&translateOptions{strictExtensions: true}).subtitleFormats("in.srt", "out.foo")
 		if err == nil {
 			t.Fatalf("expected error")
 		}
@@ -33,6 +34,40 @@ func TestValidateSubtitlePathExtensions(t *testing.T) {
 	})
 }
 
+func TestSubtitleFormats_Relaxed(t *testing.T) {
+	cases := []struct {
+		name       string
+		opts       translateOptions
+		in, out    string
+		wantIn     string
+		wantOut    string
+		wantErrSub string
+	}{
+		{name: "explicit_formats_skip_extension_check", opts: translateOptions{strictExtensions: true, inputFormat: "SRT", outputFormat: ".vtt"}, in: "in.txt", out: "out", wantIn: "srt", wantOut: "vtt"},
+		{name: "non_strict_falls_back_to_srt", opts: translateOptions{}, in: "in.txt", out: "out.srt", wantIn: "srt", wantOut: ""},
+		{name: "known_extension_inferred", opts: translateOptions{}, in: "in.vtt", out: "out.ass", wantIn: "", wantOut: ""},
+		{name: "strict_without_format", opts: translateOptions{strictExtensions: true, outputFormat: "srt"}, in: "in", out: "out.txt", wantErrSub: `unsupported input extension "(none)"`},
+		{name: "unknown_format", opts: translateOptions{inputFormat: "docx"}, in: "in.srt", out: "out.srt", wantErrSub: "invalid --input-format"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotIn, gotOut, err := tc.opts.subtitleFormats(tc.in, tc.out)
+			if tc.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSub) {
+					t.Fatalf("err = %v, want %q", err, tc.wantErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotIn != tc.wantIn || gotOut != tc.wantOut {
+				t.Fatalf("formats = %q, %q; want %q, %q", gotIn, gotOut, tc.wantIn, tc.wantOut)
+			}
+		})
+	}
+}
+
 func TestDefaultAndTranslateInvocation_ExtensionValidationConsistency(t *testing.T) {
 	t.Run("unsupported_input_extension", func(t *testing.T) {
 		rootOut, rootErr := executeCommand(t, "/tmp/focst_sample.txt", "/tmp/out.srt")
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/oukeidos/focst/internal/pipeline"
+	"github.com/spf13/cobra"
 )
 
 func TestTranslationStatusError(t *testing.T) {
@@ -28,6 +33,25 @@ func TestTranslationStatusError(t *testing.T) {
 			result:  pipeline.TranslationResult{Status: pipeline.TranslationStatusFailure},
 			wantErr: "translation finished with status: Failure",
 		},
+		{
+			name: "failure_with_partial_output",
+			result: pipeline.TranslationResult{
+				Status:          pipeline.TranslationStatusFailure,
+				OutputPath:      "/tmp/out.srt",
+				PartialOutput:   true,
+				RecoveryLogPath: "/tmp/session.json",
+			},
+			wantErr: "translation finished with status: Failure (partial output: /tmp/out.srt, recovery log: /tmp/session.json)",
+		},
+		{
+			name: "partial_cost_capped",
+			result: pipeline.TranslationResult{
+				Status:          pipeline.TranslationStatusPartialSuccess,
+				RecoveryLogPath: "/tmp/session.json",
+				CostCapped:      true,
+			},
+			wantErr: "translation stopped by --max-cost with status: Partial Success (recovery log: /tmp/session.json)",
+		},
 		{
 			name:    "skipped",
 			result:  pipeline.TranslationResult{Status: pipeline.TranslationStatusSkipped},
@@ -58,3 +82,129 @@ func TestTranslationStatusError(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatePipelineOptions(t *testing.T) {
+	cmd := &cobra.Command{Use: "translate"}
+	opts := translateOptions{}
+	addTranslateFlags(cmd, &opts)
+	if err := cmd.ParseFlags([]string{
+		"--chunk-size", "40", "--context-size", "3", "--concurrency", "4",
+		"--retry-on-long-line", "--no-prompt-cpl",
+		"--no-preprocess", "--no-postprocess", "--no-lang-preprocess", "--no-lang-postprocess", "--no-timing-correction",
+		"--source", "en", "--target", "fr", "--yes",
+	}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	got := opts.pipelineOptions(cmd.Flags().Changed("chunk-size"), map[string]string{"a": "b"})
+	want := pipeline.DefaultOptions()
+	want.Model = "gemini-3-flash-preview"
+	want.ChunkSize = 40
+	want.ContextSize = 3
+	want.Concurrency = 4
+	want.RetryOnLongLines = true
+	want.NoPromptCPL = true
+	want.NoPreprocess = true
+	want.NoPostprocess = true
+	want.NoLangPreprocess = true
+	want.NoLangPostprocess = true
+	want.NoTimingCorrection = true
+	want.Overwrite = true
+	want.SourceLang = "en"
+	want.TargetLang = "fr"
+	want.NamesMapping = map[string]string{"a": "b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("pipelineOptions() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestTranslatePipelineOptions_Defaults(t *testing.T) {
+	cmd := &cobra.Command{Use: "translate"}
+	opts := translateOptions{}
+	addTranslateFlags(cmd, &opts)
+	if err := cmd.ParseFlags(nil); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	got := opts.pipelineOptions(cmd.Flags().Changed("chunk-size"), nil)
+	defaults := pipeline.DefaultOptions()
+	if !got.AutoChunkSize {
+		t.Fatalf("AutoChunkSize = false without --chunk-size")
+	}
+	if got.ChunkSize != defaults.ChunkSize || got.ContextSize != defaults.ContextSize || got.Concurrency != defaults.Concurrency ||
+		got.RampUp != defaults.RampUp || got.CPLTolerance != defaults.CPLTolerance || got.RequestTimeout != defaults.RequestTimeout || got.CPLMetric != defaults.CPLMetric || got.OnEmpty != defaults.OnEmpty {
+		t.Fatalf("flag defaults %+v differ from pipeline.DefaultOptions() %+v", got, defaults)
+	}
+}
+
+func TestPrintPrompt_IncludesNamesMapping(t *testing.T) {
+	namesPath := filepath.Join(t.TempDir(), "names.json")
+	if err := os.WriteFile(namesPath, []byte(`[{"ja":"太郎","ko":"타로"}]`), 0600); err != nil {
+		t.Fatalf("write names: %v", err)
+	}
+	for _, args := range [][]string{
+		{"translate", "--print-prompt", "--names", namesPath},
+		{"--print-prompt", "--names", namesPath},
+	} {
+		out, err := executeCommand(t, args...)
+		if err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		if !strings.Contains(out, "character names MUST be translated as specified:\n- 太郎 -> 타로") {
+			t.Fatalf("%v: prompt missing names mapping:\n%s", args, out)
+		}
+		if !strings.Contains(out, "characters or less") {
+			t.Fatalf("%v: expected CPL rules by default:\n%s", args, out)
+		}
+	}
+
+	out, err := executeCommand(t, "translate", "--print-prompt", "--no-prompt-cpl")
+	if err != nil {
+		t.Fatalf("--no-prompt-cpl: %v", err)
+	}
+	if strings.Contains(out, "characters or less") || strings.Contains(out, "MUST be translated as specified") {
+		t.Fatalf("unexpected CPL rules or names section:\n%s", out)
+	}
+}
+
+func TestTranslate_AutoNamesFlags(t *testing.T) {
+	namesPath := filepath.Join(t.TempDir(), "names.json")
+	for _, args := range [][]string{
+		{"translate", "in.srt", "out.srt", "--auto-names"},
+		{"translate", "in.srt", "out.srt", "--auto-names", "--title", "Movie", "--names", namesPath},
+	} {
+		if _, err := executeCommand(t, args...); exitCode(err) != exitBadInput {
+			t.Fatalf("%v: expected bad input, got %v", args, err)
+		}
+	}
+}
+
+func TestTranslate_CancelOnStallRequiresTimeout(t *testing.T) {
+	if _, err := executeCommand(t, "translate", "in.srt", "out.srt", "--cancel-on-stall"); exitCode(err) != exitBadInput {
+		t.Fatalf("expected bad input, got %v", err)
+	}
+}
+
+func TestTranslate_InPlaceTakesOnlyTheInput(t *testing.T) {
+	if _, err := executeCommand(t, "translate", "--in-place", "in.srt", "out.srt"); exitCode(err) != exitBadInput {
+		t.Fatalf("expected bad input, got %v", err)
+	}
+}
+
+func TestTranslate_InPlaceRejectsURL(t *testing.T) {
+	_, err := executeCommand(t, "translate", "--in-place", "https://example.com/in.srt")
+	if exitCode(err) != exitBadInput || !strings.Contains(err.Error(), "local input file") {
+		t.Fatalf("expected bad input for a URL, got %v", err)
+	}
+}
+
+func TestRunAutoNames_SkipsWithoutOpenAIKey(t *testing.T) {
+	_, restore := withKeyStubs(t, false, "", "", "")
+	defer restore()
+
+	opts := &translateOptions{autoNames: true, title: "Movie", sourceLangCode: "ja", targetLangCode: "ko"}
+	result, err := runAutoNames(context.Background(), opts)
+	if err != nil || result != nil {
+		t.Fatalf("runAutoNames = %+v, %v; want nil result and no error", result, err)
+	}
+}
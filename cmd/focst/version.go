@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oukeidos/focst/internal/version"
+	"github.com/spf13/cobra"
+)
+
+func newVersionCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show version, commit, build date, and Go runtime/platform",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(version.Get())
+			}
+			_, err := fmt.Fprintln(out, version.Info())
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print version information as JSON")
+	cmd.SetUsageTemplate(subcommandUsageTemplate)
+	return cmd
+}
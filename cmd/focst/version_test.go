@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/version"
+)
+
+func TestVersionCommand(t *testing.T) {
+	for _, args := range [][]string{{"version"}, {"--version"}} {
+		out, err := executeCommand(t, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		if !strings.HasPrefix(out, "focst "+version.Version+"\n") || !strings.Contains(out, "commit: ") || !strings.Contains(out, runtime.GOOS+"/"+runtime.GOARCH) {
+			t.Fatalf("%v output = %q", args, out)
+		}
+	}
+}
+
+func TestVersionCommand_JSON(t *testing.T) {
+	out, err := executeCommand(t, "version", "--json")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	var info version.BuildInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if info != version.Get() {
+		t.Fatalf("JSON = %+v, want %+v", info, version.Get())
+	}
+}
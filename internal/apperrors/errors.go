@@ -3,6 +3,7 @@ package apperrors
 import (
 	"errors"
 	"strings"
+	"time"
 )
 
 type Kind string
@@ -13,6 +14,10 @@ const (
 	KindAuth       Kind = "auth"
 	KindValidation Kind = "validation"
 	KindBadRequest Kind = "bad_request"
+	// KindSafetyBlock is a response withheld by the upstream safety filters.
+	// Resending the same content is futile, so it is not retryable; the
+	// translator has its own recovery for it (see Translator.SetRelaxSafety).
+	KindSafetyBlock Kind = "safety_block"
 )
 
 type Error struct {
@@ -25,6 +30,9 @@ type Error struct {
 	// Deprecated compatibility fields. New code should use SafeMessage/Cause.
 	Err error
 	Msg string
+
+	// RetryAfter is the upstream-suggested delay before retrying (zero if none).
+	RetryAfter time.Duration
 }
 
 func (e *Error) Error() string {
@@ -70,6 +78,8 @@ func defaultSafeMessage(kind Kind) string {
 		return "Response validation failed."
 	case KindBadRequest:
 		return "Request rejected by upstream API."
+	case KindSafetyBlock:
+		return "Response blocked by upstream safety filters."
 	default:
 		return "Request failed."
 	}
@@ -107,6 +117,30 @@ func BadRequest(err error) error {
 	return New(KindBadRequest, "", err)
 }
 
+func SafetyBlock(err error) error {
+	return New(KindSafetyBlock, "", err)
+}
+
+// WithRetryAfter attaches an upstream-suggested retry delay to err.
+// Non-positive delays and errors that are not *Error are returned unchanged.
+func WithRetryAfter(err error, d time.Duration) error {
+	var e *Error
+	if d <= 0 || !errors.As(err, &e) {
+		return err
+	}
+	e.RetryAfter = d
+	return err
+}
+
+// RetryAfterOf reports the upstream-suggested retry delay carried by err, if any.
+func RetryAfterOf(err error) (time.Duration, bool) {
+	var e *Error
+	if !errors.As(err, &e) || e.RetryAfter <= 0 {
+		return 0, false
+	}
+	return e.RetryAfter, true
+}
+
 func KindOf(err error) (Kind, bool) {
 	var e *Error
 	if !errors.As(err, &e) {
@@ -138,6 +172,22 @@ func IsRetryable(err error) bool {
 	return e.Kind == KindTransient || e.Kind == KindRateLimit || e.Kind == KindValidation
 }
 
+func IsValidation(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Kind == KindValidation
+}
+
+func IsSafetyBlock(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Kind == KindSafetyBlock
+}
+
 func IsRateLimit(err error) bool {
 	var e *Error
 	if !errors.As(err, &e) {
@@ -2,7 +2,9 @@ package apperrors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 )
 
 func TestPublicMessage_UsesSafeMessage(t *testing.T) {
@@ -25,6 +27,16 @@ func TestKindOfAndRetryable(t *testing.T) {
 	if !IsRetryable(err) {
 		t.Fatalf("expected rate_limit error to be retryable")
 	}
+	if IsValidation(err) || !IsValidation(Validation(errors.New("bad"))) {
+		t.Fatalf("IsValidation should match only validation errors")
+	}
+	blocked := SafetyBlock(errors.New("no candidates"))
+	if !IsSafetyBlock(blocked) || IsSafetyBlock(err) {
+		t.Fatalf("IsSafetyBlock should match only safety blocks")
+	}
+	if IsRetryable(blocked) {
+		t.Fatalf("expected safety_block error not to be retryable")
+	}
 }
 
 func TestPublicMessage_NonAppError(t *testing.T) {
@@ -33,3 +45,18 @@ func TestPublicMessage_NonAppError(t *testing.T) {
 		t.Fatalf("PublicMessage() = %q, want %q", got, "plain")
 	}
 }
+
+func TestRetryAfterOf(t *testing.T) {
+	err := WithRetryAfter(RateLimit(errors.New("boom")), 3*time.Second)
+	wrapped := fmt.Errorf("outer: %w", err)
+	if d, ok := RetryAfterOf(wrapped); !ok || d != 3*time.Second {
+		t.Fatalf("RetryAfterOf() = (%v, %v), want (3s, true)", d, ok)
+	}
+	if _, ok := RetryAfterOf(RateLimit(errors.New("boom"))); ok {
+		t.Fatalf("expected no retry-after without attached delay")
+	}
+	plain := errors.New("plain")
+	if got := WithRetryAfter(plain, time.Second); got != plain {
+		t.Fatalf("expected non-app error to be returned unchanged")
+	}
+}
@@ -4,11 +4,29 @@ import "github.com/oukeidos/focst/internal/srt"
 
 // Chunk represents a chunk of segments to be translated along with surrounding context.
 type Chunk struct {
-	Index   int
+	Index int
+	// Offset is the position of Target[0] in the segments that were split, so
+	// results can be placed back without assuming a uniform chunk size.
+	Offset  int
 	Target  []srt.Segment
 	Context BeforeAfterContext
 }
 
+// Span locates the target segments of a chunk in the segments that were split.
+type Span struct {
+	Offset int
+	Len    int
+}
+
+// Spans returns the span of each chunk, by chunk index.
+func Spans(chunks []Chunk) []Span {
+	spans := make([]Span, len(chunks))
+	for i, c := range chunks {
+		spans[i] = Span{Offset: c.Offset, Len: len(c.Target)}
+	}
+	return spans
+}
+
 // BeforeAfterContext holds context segments.
 type BeforeAfterContext struct {
 	Before []srt.Segment
@@ -45,6 +63,7 @@ func SplitIntoChunks(segments []srt.Segment, chunkSize, contextSize int) []Chunk
 
 		chunks = append(chunks, Chunk{
 			Index:  len(chunks),
+			Offset: i,
 			Target: target,
 			Context: BeforeAfterContext{
 				Before: before,
@@ -1,6 +1,7 @@
 package chunker
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/oukeidos/focst/internal/srt"
@@ -59,4 +60,23 @@ func TestSplitIntoChunks(t *testing.T) {
 	if len(chunks[2].Context.After) != 0 {
 		t.Errorf("Chunk 2: expected 0 after context segments, got %d", len(chunks[2].Context.After))
 	}
+
+	// Offsets locate each chunk's first target segment, including the short final chunk.
+	for i, want := range []int{0, 100, 200} {
+		if chunks[i].Offset != want {
+			t.Errorf("Chunk %d: expected offset %d, got %d", i, want, chunks[i].Offset)
+		}
+		if chunks[i].Target[0].ID != want+1 {
+			t.Errorf("Chunk %d: offset %d does not match first target ID %d", i, want, chunks[i].Target[0].ID)
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	segments := make([]srt.Segment, 5)
+	chunks := SplitIntoChunks(segments, 2, 1)
+	want := []Span{{Offset: 0, Len: 2}, {Offset: 2, Len: 2}, {Offset: 4, Len: 1}}
+	if got := Spans(chunks); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Spans = %+v, want %+v", got, want)
+	}
 }
@@ -13,6 +13,14 @@ import (
 
 // AtomicWrite writes data to a temp file and renames it into place.
 func AtomicWrite(path string, data []byte, perms os.FileMode) error {
+	return AtomicWriteVerified(path, data, perms, nil)
+}
+
+// AtomicWriteVerified is AtomicWrite that reads the synced temp file back and
+// passes its bytes to verify before the rename. If verify fails, the temp file
+// is removed and any existing file at path is left untouched. A nil verify
+// skips the check.
+func AtomicWriteVerified(path string, data []byte, perms os.FileMode, verify func([]byte) error) error {
 	if err := RejectSymlinkPath(path); err != nil {
 		return err
 	}
@@ -43,6 +51,15 @@ func AtomicWrite(path string, data []byte, perms os.FileMode) error {
 	if err := tmpFile.Close(); err != nil {
 		return fmt.Errorf("failed to close temp file: %w", err)
 	}
+	if verify != nil {
+		written, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return fmt.Errorf("failed to read back temp file: %w", err)
+		}
+		if err := verify(written); err != nil {
+			return fmt.Errorf("written file failed verification: %w", err)
+		}
+	}
 	if err := renameAtomic(tmpPath, path); err != nil {
 		return fmt.Errorf("failed to rename temp file to destination: %w", err)
 	}
@@ -0,0 +1,59 @@
+package files
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAtomicWriteVerified(t *testing.T) {
+	tests := []struct {
+		name      string
+		verifyErr error
+		want      string
+	}{
+		{name: "verified", verifyErr: nil, want: "new"},
+		{name: "rejected keeps old", verifyErr: errors.New("bad output"), want: "old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "out.srt")
+			if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+				t.Fatalf("write existing: %v", err)
+			}
+
+			var seen string
+			err := AtomicWriteVerified(path, []byte("new"), 0600, func(data []byte) error {
+				seen = string(data)
+				return tt.verifyErr
+			})
+			if (err != nil) != (tt.verifyErr != nil) {
+				t.Fatalf("AtomicWriteVerified error = %v, want error %v", err, tt.verifyErr != nil)
+			}
+			if seen != "new" {
+				t.Fatalf("verify saw %q, want the written bytes", seen)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("output = %q, want %q", got, tt.want)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("read dir: %v", err)
+			}
+			for _, entry := range entries {
+				if strings.HasSuffix(entry.Name(), ".tmp") {
+					t.Fatalf("leaked temp file: %s", entry.Name())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package files
+
+import (
+	"runtime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxFilenameBytes leaves room under the common 255-byte name limit for the
+// suffixes SafePath and GenerateOutputPath append.
+const maxFilenameBytes = 200
+
+// windowsReservedNames are device names Windows refuses as a file name,
+// with or without an extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizeFilename turns name, e.g. a work title, into a single file name
+// component for the current OS. Characters the OS reserves become "_", so
+// "Fate/Zero" is "Fate_Zero" everywhere and "Re: Zero" is "Re_ Zero" on
+// Windows. Control characters are always replaced, and on Windows trailing
+// dots and spaces are dropped and device names such as "CON" get a "_"
+// suffix. Sanitizing a sanitized name returns it unchanged; an empty
+// result is "untitled".
+func SanitizeFilename(name string) string {
+	return sanitizeFilename(name, runtime.GOOS)
+}
+
+func sanitizeFilename(name, goos string) string {
+	reserved := "/"
+	if goos == "windows" {
+		reserved = `<>:"/\|?*`
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError || strings.ContainsRune(reserved, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if len(name) > maxFilenameBytes {
+		cut := maxFilenameBytes
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = name[:cut]
+	}
+	if goos == "windows" {
+		name = strings.TrimRight(name, ". ")
+		stem, _, _ := strings.Cut(name, ".")
+		if windowsReservedNames[strings.ToUpper(strings.TrimSpace(stem))] {
+			name = stem + "_" + strings.TrimPrefix(name, stem)
+		}
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		return "untitled"
+	}
+	return name
+}
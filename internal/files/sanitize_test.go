@@ -0,0 +1,66 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		in   string
+		want string
+	}{
+		{name: "slash everywhere", goos: "linux", in: "Fate/Zero", want: "Fate_Zero"},
+		{name: "colon kept on unix", goos: "darwin", in: "Re: Zero", want: "Re: Zero"},
+		{name: "windows reserved characters", goos: "windows", in: `Re: Zero <Part 1> "A|B" a\b?*`, want: "Re_ Zero _Part 1_ _A_B_ a_b__"},
+		{name: "windows trailing dots and spaces", goos: "windows", in: "Title... ", want: "Title"},
+		{name: "windows device name", goos: "windows", in: "con.json", want: "con_.json"},
+		{name: "device name allowed on unix", goos: "linux", in: "CON", want: "CON"},
+		{name: "control characters", goos: "linux", in: "a\tb\x00c", want: "a_b_c"},
+		{name: "unicode kept", goos: "windows", in: "進撃の巨人: 完結編", want: "進撃の巨人_ 完結編"},
+		{name: "empty", goos: "linux", in: "  ", want: "untitled"},
+		{name: "dot dot", goos: "linux", in: "..", want: "untitled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeFilename(tt.in, tt.goos)
+			if got != tt.want {
+				t.Fatalf("sanitizeFilename(%q, %s) = %q, want %q", tt.in, tt.goos, got, tt.want)
+			}
+			if again := sanitizeFilename(got, tt.goos); again != got {
+				t.Fatalf("sanitizing %q again gave %q", got, again)
+			}
+		})
+	}
+}
+
+func TestSanitizeFilename_LongNameCutOnRuneBoundary(t *testing.T) {
+	got := sanitizeFilename(strings.Repeat("あ", 100), "linux")
+	if len(got) > maxFilenameBytes || !strings.HasPrefix(strings.Repeat("あ", 100), got) {
+		t.Fatalf("got %d bytes %q", len(got), got)
+	}
+}
+
+func TestSanitizeFilename_TitleWithSlashIsUniqueAndRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	name := SanitizeFilename("AC/DC: Live")
+	path := filepath.Join(dir, name+".json")
+	if filepath.Dir(path) != dir {
+		t.Fatalf("%q escapes %q", path, dir)
+	}
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatalf("write %q: %v", path, err)
+	}
+	if got := strings.TrimSuffix(filepath.Base(path), ".json"); got != name {
+		t.Fatalf("name read back = %q, want %q", got, name)
+	}
+
+	unique, changed, err := SafePath(filepath.Join(dir, SanitizeFilename("AC/DC: Live")+".json"))
+	if err != nil || !changed || unique == path {
+		t.Fatalf("SafePath = %q, %v, %v; want a new name", unique, changed, err)
+	}
+}
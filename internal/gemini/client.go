@@ -3,26 +3,58 @@ package gemini
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/oukeidos/focst/internal/apperrors"
 	"github.com/oukeidos/focst/internal/httpclient"
 	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 )
 
+// ErrMalformedResponse marks a response whose text could not be decoded as
+// the expected JSON object or array.
+var ErrMalformedResponse = errors.New("failed to unmarshal response")
+
+// ErrNoCandidates marks a response without candidates, which Gemini returns
+// when its safety filters withhold every candidate. Like a blocked prompt, it
+// is classified as apperrors.KindSafetyBlock.
+var ErrNoCandidates = errors.New("no candidates returned from Gemini")
+
 // Client handles communication with the Gemini API.
 type Client struct {
-	client *genai.Client
-	model  *genai.GenerativeModel
+	client  *genai.Client
+	model   *genai.GenerativeModel
+	timeout time.Duration
+}
+
+// ClientOptions holds optional connection settings for NewClient and ListModels.
+type ClientOptions struct {
+	// Endpoint overrides the default API endpoint (e.g. a corporate proxy or
+	// compatible gateway). Empty uses the default.
+	Endpoint string
+	// RequestTimeout bounds each API call. Zero uses httpclient.DefaultTimeout.
+	RequestTimeout time.Duration
+}
+
+func (o ClientOptions) requestTimeout() time.Duration {
+	if o.RequestTimeout > 0 {
+		return o.RequestTimeout
+	}
+	return httpclient.DefaultTimeout
 }
 
 // NewClient creates a new Gemini client.
-func NewClient(ctx context.Context, apiKey string, modelName string) (*Client, error) {
-	// Note: We avoid using option.WithHTTPClient because it interferes with the genai library's
-	// internal header injection for API keys, causing 403 errors.
-	// Instead, we enforce timeouts via context in the Translate method.
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+func NewClient(ctx context.Context, apiKey string, modelName string, opts ClientOptions) (*Client, error) {
+	genaiOpts, err := clientOptions(ctx, apiKey, opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	client, err := genai.NewClient(ctx, genaiOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,11 +66,35 @@ func NewClient(ctx context.Context, apiKey string, modelName string) (*Client, e
 	// For now, we'll rely on the prompt and ResponseMIMEType.
 
 	return &Client{
-		client: client,
-		model:  model,
+		client:  client,
+		model:   model,
+		timeout: opts.requestTimeout(),
 	}, nil
 }
 
+func clientOptions(ctx context.Context, apiKey, endpoint string) ([]option.ClientOption, error) {
+	// Note: A plain option.WithHTTPClient interferes with the genai library's
+	// internal header injection for API keys, causing 403 errors, and we
+	// enforce timeouts via context in the Translate method anyway.
+	// Our transport (TLS options, per-host connection cap) is therefore
+	// wrapped in the library's own API key transport to keep the key
+	// injection intact. The cache client drops WithHTTPClient and falls back
+	// to WithAPIKey.
+	opts := []option.ClientOption{option.WithAPIKey(apiKey)}
+	transport, err := htransport.NewTransport(ctx, httpclient.NewTransport(), option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini transport: %w", err)
+	}
+	opts = append(opts, option.WithHTTPClient(&http.Client{Transport: transport}))
+	if endpoint != "" {
+		if _, err := httpclient.ValidateBaseURL(endpoint); err != nil {
+			return nil, fmt.Errorf("invalid Gemini endpoint: %w", err)
+		}
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+	return opts, nil
+}
+
 // Close closes the underlying genai client.
 func (c *Client) Close() error {
 	return c.client.Close()
@@ -62,34 +118,50 @@ var _ Translator = (*Client)(nil)
 
 // Translate sends a request to Gemini and returns the translated data.
 func (c *Client) Translate(ctx context.Context, request RequestData) (*ResponseData, error) {
-	// Enforce default timeout to prevent indefinite hangs, since we are not using a custom HTTP client with timeout.
-	ctx, cancel := context.WithTimeout(ctx, httpclient.DefaultTimeout)
+	// Enforce a per-call timeout to prevent indefinite hangs, since we are not using a custom HTTP client with timeout.
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := c.model.GenerateContent(ctx, genai.Text(string(requestJSON)))
+	model := c.model
+	if request.Temperature != nil || request.SystemInstruction != "" || request.RelaxSafety {
+		// Override on a copy so concurrent requests keep the shared settings.
+		override := *c.model
+		if request.Temperature != nil {
+			override.SetTemperature(*request.Temperature)
+		}
+		if request.SystemInstruction != "" {
+			override.SystemInstruction = &genai.Content{
+				Parts: []genai.Part{genai.Text(request.SystemInstruction)},
+			}
+		}
+		if request.RelaxSafety {
+			override.SafetySettings = relaxedSafetySettings()
+		}
+		model = &override
+	}
+	resp, err := model.GenerateContent(callCtx, genai.Text(string(requestJSON)))
 	if err != nil {
+		if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return nil, requestTimeoutError(c.timeout, err)
+		}
 		return nil, classifyGeminiError(err)
 	}
 
-	var responseData ResponseData
 	text, err := extractResponseText(resp)
+	if errors.Is(err, ErrNoCandidates) {
+		return nil, apperrors.SafetyBlock(err)
+	}
 	if err != nil {
 		return nil, apperrors.Validation(err)
 	}
-	// Try unmarshaling as the expected object format
-	if err := json.Unmarshal([]byte(text), &responseData); err != nil {
-		// Fallback: Try unmarshaling as a direct array
-		var transArray []TranslatedSegment
-		if err2 := json.Unmarshal([]byte(text), &transArray); err2 == nil {
-			responseData.Translations = transArray
-		} else {
-			// If both fail, return the unmarshal error but omit the raw text as requested
-			return nil, apperrors.Validation(fmt.Errorf("failed to unmarshal response: %w", err))
-		}
+	responseData, err := decodeResponseText(text)
+	if err != nil {
+		// Omit the raw text from the error; it holds subtitle content.
+		return nil, apperrors.Validation(fmt.Errorf("%w: %w", ErrMalformedResponse, err))
 	}
 
 	// Extract Usage Metadata
@@ -104,12 +176,80 @@ func (c *Client) Translate(ctx context.Context, request RequestData) (*ResponseD
 	return &responseData, nil
 }
 
+// decodeResponseText parses the model's JSON: the schema object first, then a
+// bare array of segments. If neither parses, it retries both on the JSON found
+// inside markdown code fences or surrounding prose, which some models add
+// despite the JSON response MIME type. The error is from the strict attempt.
+func decodeResponseText(text string) (ResponseData, error) {
+	data, err := decodeResponseJSON(text)
+	if err == nil {
+		return data, nil
+	}
+	if inner := unwrapJSON(text); inner != "" && inner != text {
+		if data, innerErr := decodeResponseJSON(inner); innerErr == nil {
+			return data, nil
+		}
+	}
+	return ResponseData{}, err
+}
+
+func decodeResponseJSON(text string) (ResponseData, error) {
+	var responseData ResponseData
+	err := json.Unmarshal([]byte(text), &responseData)
+	if err == nil {
+		return responseData, nil
+	}
+	var transArray []TranslatedSegment
+	if json.Unmarshal([]byte(text), &transArray) == nil {
+		return ResponseData{Translations: transArray}, nil
+	}
+	return ResponseData{}, err
+}
+
+// unwrapJSON returns the body of the first ``` fence (dropping a language tag
+// such as "json"), or else the span from the first '{' or '[' to the last
+// '}' or ']'. It returns "" when text holds neither.
+func unwrapJSON(text string) string {
+	if start := strings.Index(text, "```"); start >= 0 {
+		body := text[start+3:]
+		if nl := strings.IndexByte(body, '\n'); nl >= 0 && !strings.ContainsAny(body[:nl], "{[") {
+			body = body[nl+1:]
+		}
+		if end := strings.Index(body, "```"); end >= 0 {
+			body = body[:end]
+		}
+		return strings.TrimSpace(body)
+	}
+	start := strings.IndexAny(text, "{[")
+	end := strings.LastIndexAny(text, "}]")
+	if start < 0 || end < start {
+		return ""
+	}
+	return text[start : end+1]
+}
+
+// relaxedSafetySettings turns off blocking for every adjustable harm
+// category, for RequestData.RelaxSafety.
+func relaxedSafetySettings() []*genai.SafetySetting {
+	categories := []genai.HarmCategory{
+		genai.HarmCategoryHarassment,
+		genai.HarmCategoryHateSpeech,
+		genai.HarmCategorySexuallyExplicit,
+		genai.HarmCategoryDangerousContent,
+	}
+	settings := make([]*genai.SafetySetting, len(categories))
+	for i, category := range categories {
+		settings[i] = &genai.SafetySetting{Category: category, Threshold: genai.HarmBlockNone}
+	}
+	return settings
+}
+
 func extractResponseText(resp *genai.GenerateContentResponse) (string, error) {
 	if resp == nil {
 		return "", fmt.Errorf("no response received from Gemini")
 	}
 	if len(resp.Candidates) == 0 {
-		return "", fmt.Errorf("no candidates returned from Gemini")
+		return "", ErrNoCandidates
 	}
 	for i, candidate := range resp.Candidates {
 		if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
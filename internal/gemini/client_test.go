@@ -2,9 +2,17 @@ package gemini
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/httpclient"
 )
 
 func TestMockPerformance(t *testing.T) {
@@ -85,3 +93,165 @@ func TestExtractResponseText(t *testing.T) {
 		}
 	})
 }
+
+func TestClientTranslate_RequestTimeoutIsRetryable(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewClient(context.Background(), "test-key", "test-model", ClientOptions{
+		Endpoint:       server.URL,
+		RequestTimeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Translate(context.Background(), RequestData{})
+	if err == nil {
+		t.Fatal("expected timeout error, got nil")
+	}
+	if !apperrors.IsRetryable(err) {
+		t.Fatalf("expected timeout to be retryable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Fatalf("expected timeout message, got %q", err.Error())
+	}
+}
+
+func TestDecodeResponseText(t *testing.T) {
+	cases := map[string]string{
+		"object":                  `{"translations":[{"id":1,"line1":"안녕"}]}`,
+		"bare_array":              `[{"id":1,"line1":"안녕"}]`,
+		"fenced":                  "```json\n{\"translations\":[{\"id\":1,\"line1\":\"안녕\"}]}\n```",
+		"fenced_array_with_prose": "Here is the translation:\n```\n[{\"id\":1,\"line1\":\"안녕\"}]\n```\nLet me know if you need changes.",
+		"prose":                   "Sure! {\"translations\":[{\"id\":1,\"line1\":\"안녕\"}]} Done.",
+	}
+	for name, text := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := decodeResponseText(text)
+			if err != nil {
+				t.Fatalf("decodeResponseText: %v", err)
+			}
+			if len(data.Translations) != 1 || data.Translations[0].ID != 1 || data.Translations[0].Line1 != "안녕" {
+				t.Fatalf("unexpected translations: %+v", data.Translations)
+			}
+		})
+	}
+
+	for _, text := range []string{"no json here", "```json\nnot json\n```"} {
+		if _, err := decodeResponseText(text); err == nil {
+			t.Fatalf("decodeResponseText(%q) succeeded, want error", text)
+		}
+	}
+}
+
+func TestClientTranslate_InsecureTransportKeepsAPIKey(t *testing.T) {
+	var gotKey string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.URL.Query().Get("key")
+		if gotKey == "" {
+			gotKey = r.Header.Get("X-Goog-Api-Key")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"{\"translations\":[{\"id\":1,\"line1\":\"hi\"}]}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	httpclient.SetTransportOptions(httpclient.TransportOptions{InsecureSkipVerify: true})
+	defer httpclient.SetTransportOptions(httpclient.TransportOptions{})
+
+	client, err := NewClient(context.Background(), "test-key", "test-model", ClientOptions{Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	resp, err := client.Translate(context.Background(), RequestData{})
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if len(resp.Translations) != 1 || resp.Translations[0].Line1 != "hi" {
+		t.Fatalf("unexpected response: %+v", resp.Translations)
+	}
+	if gotKey != "test-key" {
+		t.Fatalf("API key not sent through the insecure transport, got %q", gotKey)
+	}
+}
+
+func TestClientTranslate_TemperatureOverrideIsPerRequest(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"{\"translations\":[{\"id\":1,\"line1\":\"hi\"}]}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), "test-key", "test-model", ClientOptions{Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	temperature := float32(0.4)
+	if _, err := client.Translate(context.Background(), RequestData{Temperature: &temperature}); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if _, err := client.Translate(context.Background(), RequestData{}); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	if !strings.Contains(bodies[0], `"temperature":0.4`) {
+		t.Errorf("first request missing temperature override: %s", bodies[0])
+	}
+	if strings.Contains(bodies[1], `"temperature"`) {
+		t.Errorf("override leaked into the next request: %s", bodies[1])
+	}
+}
+
+func TestClientTranslate_NoCandidatesIsSafetyBlock(t *testing.T) {
+	var bodies []string
+	responses := []string{`{}`, `{"promptFeedback":{"blockReason":"SAFETY"}}`}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[len(bodies)-1]))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), "test-key", "test-model", ClientOptions{Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Translate(context.Background(), RequestData{})
+	if !apperrors.IsSafetyBlock(err) || !errors.Is(err, ErrNoCandidates) {
+		t.Fatalf("expected a safety block, got %v", err)
+	}
+	if apperrors.IsRetryable(err) {
+		t.Fatalf("expected safety block not to be retryable, got %v", err)
+	}
+	if strings.Contains(bodies[0], "safetySettings") {
+		t.Errorf("default request sent safety settings: %s", bodies[0])
+	}
+
+	if _, err := client.Translate(context.Background(), RequestData{RelaxSafety: true}); !apperrors.IsSafetyBlock(err) {
+		t.Fatalf("expected a safety block, got %v", err)
+	}
+	if !strings.Contains(bodies[1], `"threshold":4`) {
+		t.Errorf("relaxed request missing safety settings: %s", bodies[1])
+	}
+}
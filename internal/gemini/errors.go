@@ -3,7 +3,12 @@ package gemini
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/generative-ai-go/genai"
 	"github.com/oukeidos/focst/internal/apperrors"
 	"google.golang.org/api/googleapi"
 )
@@ -15,6 +20,11 @@ func classifyGeminiError(err error) error {
 
 	wrapped := fmt.Errorf("gemini generate content failed: %w", err)
 
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return apperrors.New(apperrors.KindSafetyBlock, "Gemini blocked the request or response on safety grounds.", wrapped)
+	}
+
 	var gerr *googleapi.Error
 	if errors.As(err, &gerr) {
 		switch gerr.Code {
@@ -26,7 +36,8 @@ func classifyGeminiError(err error) error {
 		case 401, 403:
 			return apperrors.New(apperrors.KindAuth, fmt.Sprintf("Gemini authentication/authorization failed (%d).", gerr.Code), wrapped)
 		case 429:
-			return apperrors.New(apperrors.KindRateLimit, "Gemini rate limit exceeded (429). Please try again later.", wrapped)
+			rateErr := apperrors.New(apperrors.KindRateLimit, "Gemini rate limit exceeded (429). Please try again later.", wrapped)
+			return apperrors.WithRetryAfter(rateErr, retryAfterFromGoogleError(gerr, time.Now()))
 		case 500, 503, 504:
 			return apperrors.New(apperrors.KindTransient, fmt.Sprintf("Gemini service temporary error (%d). Please retry.", gerr.Code), wrapped)
 		default:
@@ -41,3 +52,47 @@ func classifyGeminiError(err error) error {
 	// should be retried because they are usually transient.
 	return apperrors.New(apperrors.KindTransient, "Gemini request failed due to a temporary network/runtime error.", wrapped)
 }
+
+// requestTimeoutError reports a call that exceeded the per-request timeout while
+// the caller's context was still live. It is transient so the chunk is retried.
+func requestTimeoutError(timeout time.Duration, err error) error {
+	return apperrors.New(
+		apperrors.KindTransient,
+		fmt.Sprintf("Gemini request timed out after %s. Please retry.", timeout),
+		fmt.Errorf("gemini generate content timed out: %w", err),
+	)
+}
+
+// retryAfterFromGoogleError extracts the server-suggested retry delay from a
+// Retry-After header (seconds or HTTP date) or a google.rpc.RetryInfo detail.
+// It returns zero when no usable delay is present.
+func retryAfterFromGoogleError(gerr *googleapi.Error, now time.Time) time.Duration {
+	if gerr == nil {
+		return 0
+	}
+	if v := strings.TrimSpace(gerr.Header.Get("Retry-After")); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			if d := at.Sub(now); d > 0 {
+				return d
+			}
+		}
+	}
+	for _, detail := range gerr.Details {
+		m, ok := detail.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		typ, _ := m["@type"].(string)
+		if !strings.HasSuffix(typ, "google.rpc.RetryInfo") {
+			continue
+		}
+		delay, _ := m["retryDelay"].(string)
+		if d, err := time.ParseDuration(delay); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -2,8 +2,10 @@ package gemini
 
 import (
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/oukeidos/focst/internal/apperrors"
 	"google.golang.org/api/googleapi"
@@ -51,6 +53,45 @@ func TestClassifyGeminiError_CodeMapping(t *testing.T) {
 	})
 }
 
+func TestClassifyGeminiError_RetryAfter(t *testing.T) {
+	t.Run("header seconds", func(t *testing.T) {
+		gerr := &googleapi.Error{Code: 429, Header: http.Header{"Retry-After": []string{"7"}}}
+		err := classifyGeminiError(gerr)
+		assertErrorKind(t, err, apperrors.KindRateLimit)
+		d, ok := apperrors.RetryAfterOf(err)
+		if !ok || d != 7*time.Second {
+			t.Fatalf("RetryAfterOf() = (%v, %v), want (7s, true)", d, ok)
+		}
+	})
+
+	t.Run("retry info detail", func(t *testing.T) {
+		gerr := &googleapi.Error{
+			Code: 429,
+			Details: []interface{}{
+				map[string]interface{}{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "31s"},
+			},
+		}
+		d, ok := apperrors.RetryAfterOf(classifyGeminiError(gerr))
+		if !ok || d != 31*time.Second {
+			t.Fatalf("RetryAfterOf() = (%v, %v), want (31s, true)", d, ok)
+		}
+	})
+
+	t.Run("http date", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		gerr := &googleapi.Error{Code: 429, Header: http.Header{"Retry-After": []string{now.Add(5 * time.Second).Format(http.TimeFormat)}}}
+		if d := retryAfterFromGoogleError(gerr, now); d != 5*time.Second {
+			t.Fatalf("retryAfterFromGoogleError() = %v, want 5s", d)
+		}
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		if _, ok := apperrors.RetryAfterOf(classifyGeminiError(&googleapi.Error{Code: 429})); ok {
+			t.Fatalf("expected no retry-after for bare 429")
+		}
+	})
+}
+
 func TestClassifyGeminiError_Unknown(t *testing.T) {
 	err := classifyGeminiError(errors.New("boom"))
 	assertErrorKind(t, err, apperrors.KindTransient)
@@ -0,0 +1,226 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/httpclient"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultRESTBaseURL is the Gemini REST API base URL used by GenerateClient
+// unless SetBaseURL overrides it.
+const DefaultRESTBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// GenerateRequest is a single-turn prompt for GenerateClient.
+type GenerateRequest struct {
+	Prompt string
+	// Schema is the JSON schema of the response object. nil asks for free
+	// text.
+	Schema map[string]any
+	// GoogleSearch grounds the answer with Google Search.
+	GoogleSearch    bool
+	MaxOutputTokens int
+}
+
+// GenerateResponse is the model's text and the call's usage. In Usage,
+// PromptTokenCount includes search results fed back to the model (billed as
+// input), the remainder of TotalTokenCount beyond prompt and candidates is
+// reasoning, and WebSearchCount is the number of search queries run.
+type GenerateResponse struct {
+	Text  string
+	Usage UsageMetadata
+}
+
+type restRequest struct {
+	Contents         []restContent         `json:"contents"`
+	Tools            []restTool            `json:"tools,omitempty"`
+	GenerationConfig *restGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type restContent struct {
+	Role  string     `json:"role,omitempty"`
+	Parts []restPart `json:"parts"`
+}
+
+type restPart struct {
+	Text    string `json:"text,omitempty"`
+	Thought bool   `json:"thought,omitempty"`
+}
+
+type restTool struct {
+	GoogleSearch *struct{} `json:"google_search,omitempty"`
+}
+
+type restGenerationConfig struct {
+	ResponseMIMEType   string         `json:"responseMimeType,omitempty"`
+	ResponseJSONSchema map[string]any `json:"responseJsonSchema,omitempty"`
+	MaxOutputTokens    int            `json:"maxOutputTokens,omitempty"`
+}
+
+type restResponse struct {
+	Candidates []struct {
+		Content           restContent `json:"content"`
+		FinishReason      string      `json:"finishReason"`
+		GroundingMetadata *struct {
+			WebSearchQueries []string `json:"webSearchQueries"`
+		} `json:"groundingMetadata"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount        int `json:"promptTokenCount"`
+		ToolUsePromptTokenCount int `json:"toolUsePromptTokenCount"`
+		CandidatesTokenCount    int `json:"candidatesTokenCount"`
+		TotalTokenCount         int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+type restErrorEnvelope struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GenerateClient calls generateContent over REST for requests the genai
+// library cannot express, such as Google Search grounding combined with a
+// JSON response schema. Translation uses Client.
+type GenerateClient struct {
+	apiKey           string
+	model            string
+	baseURL          string
+	timeout          time.Duration
+	maxResponseBytes int64
+}
+
+// NewGenerateClient returns a client for model with the default base URL
+// and timeout.
+func NewGenerateClient(apiKey, model string) *GenerateClient {
+	return &GenerateClient{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: DefaultRESTBaseURL,
+		timeout: httpclient.DefaultTimeout,
+	}
+}
+
+// SetRequestTimeout bounds each API call. Non-positive values keep the default.
+func (c *GenerateClient) SetRequestTimeout(d time.Duration) {
+	if d > 0 {
+		c.timeout = d
+	}
+}
+
+// SetMaxResponseBytes changes the response body cap. Non-positive values
+// keep httpclient.MaxResponseBytes.
+func (c *GenerateClient) SetMaxResponseBytes(n int64) {
+	c.maxResponseBytes = n
+}
+
+// SetBaseURL points the client at a compatible gateway. The URL must use
+// http or https; a trailing slash is ignored.
+func (c *GenerateClient) SetBaseURL(raw string) error {
+	if _, err := httpclient.ValidateBaseURL(raw); err != nil {
+		return err
+	}
+	c.baseURL = strings.TrimRight(raw, "/")
+	return nil
+}
+
+// GetModelID returns the configured model identifier.
+func (c *GenerateClient) GetModelID() string {
+	return c.model
+}
+
+// Generate sends req and returns the text of the first candidate, without
+// thought parts.
+func (c *GenerateClient) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	body := restRequest{
+		Contents: []restContent{{Role: "user", Parts: []restPart{{Text: req.Prompt}}}},
+		GenerationConfig: &restGenerationConfig{
+			MaxOutputTokens: req.MaxOutputTokens,
+		},
+	}
+	if req.Schema != nil {
+		body.GenerationConfig.ResponseMIMEType = "application/json"
+		body.GenerationConfig.ResponseJSONSchema = req.Schema
+	}
+	if req.GoogleSearch {
+		body.Tools = []restTool{{GoogleSearch: &struct{}{}}}
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	url := c.baseURL + "/models/" + c.model + ":generateContent"
+	httpReq, err := http.NewRequestWithContext(callCtx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", c.apiKey)
+
+	respBody, resp, err := httpclient.DoAndReadLimit(httpclient.GetDefaultClient(), httpReq, c.maxResponseBytes)
+	if err != nil {
+		if errors.Is(err, httpclient.ErrResponseTooLarge) {
+			return nil, apperrors.New(
+				apperrors.KindValidation,
+				"Gemini response exceeded the size limit. Retry with a smaller request or raise the limit.",
+				err,
+			)
+		}
+		if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return nil, requestTimeoutError(c.timeout, err)
+		}
+		return nil, classifyGeminiError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var envelope restErrorEnvelope
+		_ = json.Unmarshal(respBody, &envelope)
+		return nil, classifyGeminiError(&googleapi.Error{
+			Code:    resp.StatusCode,
+			Message: envelope.Error.Message,
+			Header:  resp.Header,
+		})
+	}
+
+	var result restResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, apperrors.Validation(fmt.Errorf("%w: %w", ErrMalformedResponse, err))
+	}
+	out := &GenerateResponse{Usage: UsageMetadata{
+		PromptTokenCount:     result.UsageMetadata.PromptTokenCount + result.UsageMetadata.ToolUsePromptTokenCount,
+		CandidatesTokenCount: result.UsageMetadata.CandidatesTokenCount,
+		TotalTokenCount:      result.UsageMetadata.TotalTokenCount,
+	}}
+	if len(result.Candidates) == 0 {
+		return out, apperrors.SafetyBlock(ErrNoCandidates)
+	}
+	candidate := result.Candidates[0]
+	if candidate.GroundingMetadata != nil {
+		out.Usage.WebSearchCount = len(candidate.GroundingMetadata.WebSearchQueries)
+	}
+	for _, part := range candidate.Content.Parts {
+		if !part.Thought {
+			out.Text += part.Text
+		}
+	}
+	slog.Debug("Gemini generate response", "finish_reason", candidate.FinishReason, "usage_total", out.Usage.TotalTokenCount)
+	if out.Text == "" {
+		if candidate.FinishReason == "MAX_TOKENS" {
+			return out, apperrors.Validation(fmt.Errorf("gemini response is incomplete (reason: MAX_TOKENS); try increasing the output token limit"))
+		}
+		return out, apperrors.Validation(fmt.Errorf("no text parts found in Gemini response"))
+	}
+	return out, nil
+}
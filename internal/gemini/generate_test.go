@@ -0,0 +1,76 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+)
+
+func TestGenerateClient_GroundedJSON(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/test-model:generateContent" || r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("unexpected request %s key=%q", r.URL.Path, r.Header.Get("x-goog-api-key"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"planning","thought":true},{"text":"{\"ok\":true}"}]},"finishReason":"STOP",
+			"groundingMetadata":{"webSearchQueries":["a","b"]}}],
+			"usageMetadata":{"promptTokenCount":10,"toolUsePromptTokenCount":5,"candidatesTokenCount":3,"thoughtsTokenCount":7,"totalTokenCount":25}}`))
+	}))
+	defer server.Close()
+
+	client := NewGenerateClient("test-key", "test-model")
+	if err := client.SetBaseURL(server.URL + "/"); err != nil {
+		t.Fatalf("SetBaseURL: %v", err)
+	}
+	resp, err := client.Generate(context.Background(), GenerateRequest{
+		Prompt:       "find names",
+		Schema:       map[string]any{"type": "object"},
+		GoogleSearch: true,
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if _, ok := got["tools"].([]any)[0].(map[string]any)["google_search"]; !ok {
+		t.Fatalf("expected the google_search tool, got %v", got["tools"])
+	}
+	config := got["generationConfig"].(map[string]any)
+	if config["responseMimeType"] != "application/json" || config["responseJsonSchema"] == nil {
+		t.Fatalf("expected a JSON schema response, got %v", config)
+	}
+	if resp.Text != `{"ok":true}` {
+		t.Fatalf("text = %q, want the non-thought part", resp.Text)
+	}
+	want := UsageMetadata{PromptTokenCount: 15, CandidatesTokenCount: 3, TotalTokenCount: 25, WebSearchCount: 2}
+	if resp.Usage != want {
+		t.Fatalf("usage = %+v, want %+v", resp.Usage, want)
+	}
+}
+
+func TestGenerateClient_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"code":429,"message":"quota SECRET_SUBTITLE_LINE","status":"RESOURCE_EXHAUSTED"}}`))
+	}))
+	defer server.Close()
+
+	client := NewGenerateClient("test-key", "test-model")
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatalf("SetBaseURL: %v", err)
+	}
+	_, err := client.Generate(context.Background(), GenerateRequest{Prompt: "x"})
+	if !apperrors.IsRateLimit(err) {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "SECRET_SUBTITLE_LINE") {
+		t.Fatalf("error exposes the raw message: %q", err.Error())
+	}
+}
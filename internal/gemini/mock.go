@@ -7,9 +7,15 @@ type MockClient struct {
 	Response              *ResponseData
 	Error                 error
 	LastSystemInstruction string
+	// TranslateFunc, when set, answers each request instead of Response/Error,
+	// e.g. to echo IDs or report per-call usage.
+	TranslateFunc func(ctx context.Context, request RequestData) (*ResponseData, error)
 }
 
 func (m *MockClient) Translate(ctx context.Context, request RequestData) (*ResponseData, error) {
+	if m.TranslateFunc != nil {
+		return m.TranslateFunc(ctx, request)
+	}
 	return m.Response, m.Error
 }
 
@@ -0,0 +1,75 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+)
+
+// RemoteModel describes a model returned by the Gemini list-models endpoint.
+type RemoteModel struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// ListModels queries the Gemini API for models available to apiKey and
+// returns those that support generateContent, sorted by ID.
+func ListModels(ctx context.Context, apiKey string, opts ClientOptions) ([]RemoteModel, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.requestTimeout())
+	defer cancel()
+
+	genaiOpts, err := clientOptions(ctx, apiKey, opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	client, err := genai.NewClient(ctx, genaiOpts...)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var infos []*genai.ModelInfo
+	it := client.ListModels(ctx)
+	for {
+		info, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, classifyGeminiError(err)
+		}
+		infos = append(infos, info)
+	}
+	return generateContentModels(infos), nil
+}
+
+func generateContentModels(infos []*genai.ModelInfo) []RemoteModel {
+	models := make([]RemoteModel, 0, len(infos))
+	seen := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		if info == nil || !supportsGenerateContent(info.SupportedGenerationMethods) {
+			continue
+		}
+		id := strings.TrimPrefix(info.Name, "models/")
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		models = append(models, RemoteModel{ID: id, DisplayName: info.DisplayName})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models
+}
+
+func supportsGenerateContent(methods []string) bool {
+	for _, m := range methods {
+		if m == "generateContent" {
+			return true
+		}
+	}
+	return false
+}
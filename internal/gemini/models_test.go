@@ -0,0 +1,32 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+func TestGenerateContentModels(t *testing.T) {
+	infos := []*genai.ModelInfo{
+		{Name: "models/text-embedding-004", SupportedGenerationMethods: []string{"embedContent"}},
+		{Name: "models/gemini-3-flash-preview", DisplayName: "Gemini 3 Flash", SupportedGenerationMethods: []string{"generateContent", "countTokens"}},
+		nil,
+		{Name: "models/aqa", SupportedGenerationMethods: []string{"generateAnswer"}},
+		{Name: "models/gemini-2.5-pro", SupportedGenerationMethods: []string{"generateContent"}},
+		{Name: "models/gemini-2.5-pro", SupportedGenerationMethods: []string{"generateContent"}},
+	}
+
+	got := generateContentModels(infos)
+	want := []RemoteModel{
+		{ID: "gemini-2.5-pro"},
+		{ID: "gemini-3-flash-preview", DisplayName: "Gemini 3 Flash"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d models, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("model %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
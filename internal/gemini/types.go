@@ -4,6 +4,9 @@ package gemini
 type SegmentData struct {
 	ID    int      `json:"id"`
 	Lines []string `json:"lines"`
+	// Draft holds existing target-language lines of the segment for the
+	// model to improve rather than translate from scratch.
+	Draft []string `json:"draft,omitempty"`
 }
 
 // RequestData represents the full input JSON structure sent to Gemini.
@@ -11,6 +14,21 @@ type RequestData struct {
 	ContextBefore []SegmentData `json:"context_before"`
 	Target        []SegmentData `json:"target"`
 	ContextAfter  []SegmentData `json:"context_after"`
+	// Note is a corrective instruction for this request only, such as a
+	// reminder to return valid JSON after a malformed response.
+	Note string `json:"note,omitempty"`
+	// Temperature overrides the model's sampling temperature for this
+	// request only. It is not part of the request JSON; nil keeps the
+	// client's setting.
+	Temperature *float32 `json:"-"`
+	// SystemInstruction replaces the client's system instruction for this
+	// request only, e.g. for a different register. It is not part of the
+	// request JSON; empty keeps the client's instruction.
+	SystemInstruction string `json:"-"`
+	// RelaxSafety lowers the safety thresholds for this request only, e.g.
+	// to retry a chunk the filters blocked. It is not part of the request
+	// JSON.
+	RelaxSafety bool `json:"-"`
 }
 
 // TranslatedSegment represents the structure of a single translated segment in the output JSON.
@@ -1,9 +1,12 @@
 package httpclient
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 )
@@ -14,6 +17,7 @@ const (
 	// while still preventing indefinite hangs.
 	DefaultTimeout = 10 * time.Minute
 	// MaxResponseBytes caps HTTP response bodies to prevent memory spikes.
+	// DoAndReadLimit takes a per-request cap for callers that expect more.
 	MaxResponseBytes = 8 * 1024 * 1024
 	// Transport tuning for stable, long-lived connections.
 	MaxIdleConns          = 100
@@ -21,26 +25,86 @@ const (
 	IdleConnTimeout       = 120 * time.Second
 	TLSHandshakeTimeout   = 30 * time.Second
 	ExpectContinueTimeout = 2 * time.Second
+	// DefaultMaxConnsPerHost caps simultaneous connections to one host. It
+	// matches the highest translation concurrency (pipeline.MaxConcurrency),
+	// so a full-speed run never waits for a connection but cannot open more.
+	DefaultMaxConnsPerHost = 20
 )
 
+// ErrResponseTooLarge marks a response body over the read limit. The body is
+// discarded rather than cut at the limit, so a truncated multi-byte character
+// or JSON document never reaches the caller.
+var ErrResponseTooLarge = errors.New("response body too large")
+
 var (
-	defaultClient     *http.Client
-	defaultClientOnce sync.Once
-	overrideClient    *http.Client
+	defaultClient    *http.Client
+	defaultClientMu  sync.Mutex
+	overrideClient   *http.Client
+	transportOptions TransportOptions
 )
 
-// NewClient returns a new http.Client with the specified timeout.
-func NewClient(timeout time.Duration) *http.Client {
+// TransportOptions adjusts every transport built by NewTransport.
+type TransportOptions struct {
+	// InsecureSkipVerify disables TLS certificate verification, for corporate
+	// proxies that intercept TLS with a self-signed certificate. It exposes
+	// API keys and subtitle text to any man-in-the-middle.
+	InsecureSkipVerify bool
+	// MaxConnsPerHost caps the connections (dialing, in use, and idle) to one
+	// host; requests over the cap wait for a free connection. Zero or less
+	// uses DefaultMaxConnsPerHost.
+	MaxConnsPerHost int
+}
+
+// SetTransportOptions changes the options for transports built afterwards and
+// rebuilds the default client. Call it at startup, before any requests.
+func SetTransportOptions(opts TransportOptions) {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	transportOptions = opts
+	defaultClient = nil
+}
+
+// CurrentTransportOptions returns the options set by SetTransportOptions.
+func CurrentTransportOptions() TransportOptions {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	return transportOptions
+}
+
+// NewTransport returns the tuned transport used by NewClient. Proxies come
+// from HTTP_PROXY, HTTPS_PROXY, and NO_PROXY.
+func NewTransport() *http.Transport {
+	return newTransport(CurrentTransportOptions())
+}
+
+func newTransport(opts TransportOptions) *http.Transport {
 	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
 		MaxIdleConns:          MaxIdleConns,
 		MaxIdleConnsPerHost:   MaxIdleConnsPerHost,
+		MaxConnsPerHost:       opts.maxConnsPerHost(),
 		IdleConnTimeout:       IdleConnTimeout,
 		TLSHandshakeTimeout:   TLSHandshakeTimeout,
 		ExpectContinueTimeout: ExpectContinueTimeout,
 	}
+	if opts.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return transport
+}
+
+func (o TransportOptions) maxConnsPerHost() int {
+	if o.MaxConnsPerHost <= 0 {
+		return DefaultMaxConnsPerHost
+	}
+	return o.MaxConnsPerHost
+}
+
+// NewClient returns a new http.Client with the specified timeout.
+func NewClient(timeout time.Duration) *http.Client {
 	return &http.Client{
 		Timeout:   timeout,
-		Transport: transport,
+		Transport: NewTransport(),
 	}
 }
 
@@ -49,9 +113,14 @@ func GetDefaultClient() *http.Client {
 	if overrideClient != nil {
 		return overrideClient
 	}
-	defaultClientOnce.Do(func() {
-		defaultClient = NewClient(DefaultTimeout)
-	})
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	if defaultClient == nil {
+		defaultClient = &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: newTransport(transportOptions),
+		}
+	}
 	return defaultClient
 }
 
@@ -69,24 +138,50 @@ func SetDefaultClientForTesting(client *http.Client) func() {
 // ensures the body is closed, and returns the body content and the response object.
 // This prevents resource leaks by always closing the response body.
 func DoAndRead(client *http.Client, req *http.Request) ([]byte, *http.Response, error) {
+	return DoAndReadLimit(client, req, MaxResponseBytes)
+}
+
+// DoAndReadLimit is DoAndRead with a per-request cap on the body size.
+// A non-positive limit uses MaxResponseBytes. Oversized bodies fail with an
+// error wrapping ErrResponseTooLarge.
+func DoAndReadLimit(client *http.Client, req *http.Request, limit int64) ([]byte, *http.Response, error) {
+	if limit <= 0 {
+		limit = MaxResponseBytes
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.ContentLength > MaxResponseBytes {
-		return nil, resp, fmt.Errorf("response body too large (limit %d bytes)", MaxResponseBytes)
+	if resp.ContentLength > limit {
+		return nil, resp, fmt.Errorf("%w (limit %d bytes)", ErrResponseTooLarge, limit)
 	}
 
-	limited := &io.LimitedReader{R: resp.Body, N: MaxResponseBytes + 1}
+	limited := &io.LimitedReader{R: resp.Body, N: limit + 1}
 	body, err := io.ReadAll(limited)
 	if err != nil {
 		return nil, resp, fmt.Errorf("failed to read response body: %w", err)
 	}
-	if int64(len(body)) > MaxResponseBytes {
-		return nil, resp, fmt.Errorf("response body too large (limit %d bytes)", MaxResponseBytes)
+	if int64(len(body)) > limit {
+		return nil, resp, fmt.Errorf("%w (limit %d bytes)", ErrResponseTooLarge, limit)
 	}
 
 	return body, resp, nil
 }
+
+// ValidateBaseURL checks that raw is an absolute http(s) URL with a host, as
+// required for API base URLs and endpoint overrides.
+func ValidateBaseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("invalid URL %q: scheme must be http or https", raw)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: missing host", raw)
+	}
+	return u, nil
+}
@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -41,6 +42,9 @@ func TestNewClient(t *testing.T) {
 	if transport.MaxIdleConnsPerHost != MaxIdleConnsPerHost {
 		t.Errorf("Expected MaxIdleConnsPerHost to be %d, got %d", MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
 	}
+	if transport.MaxConnsPerHost != DefaultMaxConnsPerHost {
+		t.Errorf("Expected MaxConnsPerHost to be %d, got %d", DefaultMaxConnsPerHost, transport.MaxConnsPerHost)
+	}
 	if transport.IdleConnTimeout != IdleConnTimeout {
 		t.Errorf("Expected IdleConnTimeout to be %v, got %v", IdleConnTimeout, transport.IdleConnTimeout)
 	}
@@ -50,6 +54,43 @@ func TestNewClient(t *testing.T) {
 	if transport.ExpectContinueTimeout != ExpectContinueTimeout {
 		t.Errorf("Expected ExpectContinueTimeout to be %v, got %v", ExpectContinueTimeout, transport.ExpectContinueTimeout)
 	}
+	if transport.Proxy == nil {
+		t.Errorf("Expected transport to read proxies from the environment")
+	}
+	if transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("Expected TLS verification by default")
+	}
+}
+
+func TestSetTransportOptions(t *testing.T) {
+	before := GetDefaultClient()
+	SetTransportOptions(TransportOptions{InsecureSkipVerify: true})
+	defer SetTransportOptions(TransportOptions{})
+
+	client := GetDefaultClient()
+	if client == before {
+		t.Fatalf("Expected default client to be rebuilt")
+	}
+	transport := client.Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Fatalf("Expected insecure TLS config on the default client")
+	}
+	if GetDefaultClient() != client {
+		t.Fatalf("Expected singleton client instance")
+	}
+}
+
+func TestSetTransportOptions_MaxConnsPerHost(t *testing.T) {
+	defer SetTransportOptions(TransportOptions{})
+	for limit, want := range map[int]int{4: 4, 0: DefaultMaxConnsPerHost, -1: DefaultMaxConnsPerHost} {
+		SetTransportOptions(TransportOptions{MaxConnsPerHost: limit})
+		if got := GetDefaultClient().Transport.(*http.Transport).MaxConnsPerHost; got != want {
+			t.Errorf("default client MaxConnsPerHost with option %d = %d, want %d", limit, got, want)
+		}
+		if got := NewClient(time.Second).Transport.(*http.Transport).MaxConnsPerHost; got != want {
+			t.Errorf("NewClient MaxConnsPerHost with option %d = %d, want %d", limit, got, want)
+		}
+	}
 }
 
 func TestDoAndRead(t *testing.T) {
@@ -105,6 +146,44 @@ func TestDoAndReadTooLarge(t *testing.T) {
 	}
 }
 
+func TestDoAndReadLimit_JustOverLimit(t *testing.T) {
+	// 3-byte runes: a 10-byte cap would cut the fourth rune in half.
+	body := strings.Repeat("한", 4)
+	const limit = 10
+	for _, tc := range []struct {
+		name    string
+		chunked bool
+	}{
+		{name: "content_length"},
+		{name: "chunked", chunked: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.chunked {
+					w.(http.Flusher).Flush()
+				}
+				fmt.Fprint(w, body)
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			got, _, err := DoAndReadLimit(GetDefaultClient(), req, limit)
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("expected ErrResponseTooLarge, got: %v", err)
+			}
+			if got != nil {
+				t.Fatalf("expected no partial body, got %q", got)
+			}
+
+			req, _ = http.NewRequest("GET", server.URL, nil)
+			got, _, err = DoAndReadLimit(GetDefaultClient(), req, int64(len(body)))
+			if err != nil || string(got) != body {
+				t.Fatalf("raised limit: body %q, err %v", got, err)
+			}
+		})
+	}
+}
+
 func TestSetDefaultClientForTesting(t *testing.T) {
 	custom := &http.Client{Timeout: 3 * time.Second}
 	restore := SetDefaultClientForTesting(custom)
@@ -114,3 +193,23 @@ func TestSetDefaultClientForTesting(t *testing.T) {
 		t.Fatalf("Expected overridden default client")
 	}
 }
+
+func TestValidateBaseURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		wantErr bool
+	}{
+		{"https://api.openai.com/v1", false},
+		{"http://localhost:4000", false},
+		{"ftp://proxy.example.com", true},
+		{"proxy.example.com/v1", true},
+		{"https://", true},
+		{"://bad", true},
+	}
+	for _, tt := range tests {
+		_, err := ValidateBaseURL(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBaseURL(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package language
+
+// DefaultChunkSize is the number of segments per chunk for languages without a
+// suggested size.
+const DefaultChunkSize = 100
+
+// suggestedChunkSizes holds smaller chunk sizes for languages whose subtitle
+// lines carry more tokens per segment than the average, so a chunk of dense
+// dialogue stays within a similar token budget.
+var suggestedChunkSizes = map[string]int{
+	"ja":      60,
+	"zh":      60,
+	"zh-Hans": 60,
+	"zh-Hant": 60,
+	"ko":      80,
+	"th":      80,
+}
+
+// SuggestedChunkSize returns the recommended chunk size for translating from
+// sourceCode to targetCode. Both sides count toward a request's tokens, so the
+// smaller suggestion of the two wins; unknown codes use DefaultChunkSize.
+func SuggestedChunkSize(sourceCode, targetCode string) int {
+	size := DefaultChunkSize
+	for _, code := range []string{sourceCode, targetCode} {
+		if s, ok := suggestedChunkSizes[code]; ok && s < size {
+			size = s
+		}
+	}
+	return size
+}
@@ -1,6 +1,7 @@
 package language
 
 import (
+	"fmt"
 	"sort"
 )
 
@@ -145,6 +146,31 @@ type LanguageEntry struct {
 	Language
 }
 
+// DisplayName returns the entry's Name, followed by its ID in brackets when
+// another entry (e.g. the "zh" alias of "zh-Hans") has the same Name, so
+// display names are unique across GetSupportedLanguages.
+func (e LanguageEntry) DisplayName() string {
+	if sharedNames[e.Name] {
+		return fmt.Sprintf("%s [%s]", e.Name, e.ID)
+	}
+	return e.Name
+}
+
+// sharedNames holds every Name used by more than one entry in Languages.
+var sharedNames = func() map[string]bool {
+	count := make(map[string]int, len(Languages))
+	for _, l := range Languages {
+		count[l.Name]++
+	}
+	shared := make(map[string]bool)
+	for name, n := range count {
+		if n > 1 {
+			shared[name] = true
+		}
+	}
+	return shared
+}()
+
 // GetSupportedLanguages returns a list of supported languages sorted by Name and then ID.
 func GetSupportedLanguages() []LanguageEntry {
 	entries := make([]LanguageEntry, 0, len(Languages))
@@ -159,3 +185,52 @@ func GetSupportedLanguages() []LanguageEntry {
 	})
 	return entries
 }
+
+// ChineseScript returns "Simplified" or "Traditional" for Chinese language codes
+// (including the "zh" alias, which resolves to Simplified), or "" otherwise.
+func ChineseScript(code string) string {
+	switch code {
+	case "zh", "zh-Hans":
+		return "Simplified"
+	case "zh-Hant":
+		return "Traditional"
+	default:
+		return ""
+	}
+}
+
+// IsCJK reports whether code is a Chinese, Japanese, or Korean target whose
+// text is typically set in full-width characters.
+func IsCJK(code string) bool {
+	switch code {
+	case "ja", "ko", "zh", "zh-Hans", "zh-Hant":
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesWordSpaces reports whether text in code separates words with spaces, so
+// whole-word matching can rely on word boundaries. Chinese, Japanese, and the
+// Southeast Asian scripts that run words together report false.
+func UsesWordSpaces(code string) bool {
+	switch code {
+	case "ja", "zh", "zh-Hans", "zh-Hant", "th", "lo", "km", "my":
+		return false
+	default:
+		return true
+	}
+}
+
+// ScriptInstruction returns an extra prompt rule that commits the output to the
+// target's writing system, or "" when the target has no script variants.
+func ScriptInstruction(code string) string {
+	switch ChineseScript(code) {
+	case "Simplified":
+		return "- Write ONLY Simplified Chinese characters (简体字). Never use Traditional characters, even if the source or context uses them."
+	case "Traditional":
+		return "- Write ONLY Traditional Chinese characters (繁體字). Never use Simplified characters, even if the source or context uses them."
+	default:
+		return ""
+	}
+}
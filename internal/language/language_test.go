@@ -0,0 +1,42 @@
+package language
+
+import "testing"
+
+func TestDisplayName_UniqueAcrossTable(t *testing.T) {
+	seen := make(map[string]string)
+	for _, e := range GetSupportedLanguages() {
+		name := e.DisplayName()
+		if other, ok := seen[name]; ok {
+			t.Fatalf("display name %q used by both %s and %s", name, other, e.ID)
+		}
+		seen[name] = e.ID
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "ko", want: "Korean"},
+		{id: "zh", want: "Chinese (Simplified) [zh]"},
+		{id: "zh-Hans", want: "Chinese (Simplified) [zh-Hans]"},
+		{id: "zh-Hant", want: "Chinese (Traditional)"},
+	}
+	for _, tt := range tests {
+		e := LanguageEntry{ID: tt.id, Language: Languages[tt.id]}
+		if got := e.DisplayName(); got != tt.want {
+			t.Fatalf("DisplayName(%s) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestGetSupportedLanguages_StableOrder(t *testing.T) {
+	first := GetSupportedLanguages()
+	for i := 0; i < 5; i++ {
+		again := GetSupportedLanguages()
+		for j := range first {
+			if first[j].ID != again[j].ID {
+				t.Fatalf("entry %d = %s, then %s", j, first[j].ID, again[j].ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package language
+
+import "unicode"
+
+// scriptsByCode lists the writing systems of languages not written in Latin
+// script. Japanese includes Han because kanji and kana are mixed.
+var scriptsByCode = map[string][]*unicode.RangeTable{
+	"ja":       {unicode.Hiragana, unicode.Katakana, unicode.Han},
+	"zh":       {unicode.Han},
+	"zh-Hans":  {unicode.Han},
+	"zh-Hant":  {unicode.Han},
+	"ko":       {unicode.Hangul},
+	"ru":       {unicode.Cyrillic},
+	"uk":       {unicode.Cyrillic},
+	"be":       {unicode.Cyrillic},
+	"bg":       {unicode.Cyrillic},
+	"mk":       {unicode.Cyrillic},
+	"sr":       {unicode.Cyrillic},
+	"kk":       {unicode.Cyrillic},
+	"ky":       {unicode.Cyrillic},
+	"mn":       {unicode.Cyrillic},
+	"tg":       {unicode.Cyrillic},
+	"ar":       {unicode.Arabic},
+	"fa":       {unicode.Arabic},
+	"ur":       {unicode.Arabic},
+	"ps":       {unicode.Arabic},
+	"sd":       {unicode.Arabic},
+	"ug":       {unicode.Arabic},
+	"ku":       {unicode.Arabic},
+	"iw":       {unicode.Hebrew},
+	"yi":       {unicode.Hebrew},
+	"el":       {unicode.Greek},
+	"hy":       {unicode.Armenian},
+	"ka":       {unicode.Georgian},
+	"am":       {unicode.Ethiopic},
+	"hi":       {unicode.Devanagari},
+	"mr":       {unicode.Devanagari},
+	"ne":       {unicode.Devanagari},
+	"bn":       {unicode.Bengali},
+	"as":       {unicode.Bengali},
+	"pa":       {unicode.Gurmukhi},
+	"gu":       {unicode.Gujarati},
+	"or":       {unicode.Oriya},
+	"ta":       {unicode.Tamil},
+	"te":       {unicode.Telugu},
+	"kn":       {unicode.Kannada},
+	"ml":       {unicode.Malayalam},
+	"si":       {unicode.Sinhala},
+	"th":       {unicode.Thai},
+	"lo":       {unicode.Lao},
+	"km":       {unicode.Khmer},
+	"my":       {unicode.Myanmar},
+	"dv":       {unicode.Thaana},
+	"mni-Mtei": {unicode.Meetei_Mayek},
+}
+
+// Scripts returns the Unicode scripts text in code is written in. Languages
+// without an entry are written in Latin script.
+func Scripts(code string) []*unicode.RangeTable {
+	if scripts, ok := scriptsByCode[code]; ok {
+		return scripts
+	}
+	return []*unicode.RangeTable{unicode.Latin}
+}
+
+// DistinctScripts reports whether text in a and b can be told apart by script
+// alone: their script sets differ (e.g. Japanese and Korean, or Japanese and
+// Chinese) rather than being the same (e.g. English and French).
+func DistinctScripts(a, b string) bool {
+	sa, sb := Scripts(a), Scripts(b)
+	if len(sa) != len(sb) {
+		return true
+	}
+	for _, t := range sa {
+		if !containsScript(sb, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsScript(scripts []*unicode.RangeTable, t *unicode.RangeTable) bool {
+	for _, s := range scripts {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
@@ -65,21 +65,68 @@ var sensitiveValuePatterns = []*regexp.Regexp{
 
 // RedactAttr is a slog.ReplaceAttr function that redacts sensitive information.
 func RedactAttr(_ []string, a slog.Attr) slog.Attr {
-	if shouldRedact(a) {
+	if shouldRedact(a, nil, nil) {
 		return slog.String(a.Key, "[REDACTED]")
 	}
 	return a
 }
 
-func shouldRedact(a slog.Attr) bool {
-	key := strings.ToLower(a.Key)
-	if sensitiveKeys[key] {
-		return true
+// Options tunes redaction for InitWithOptions. The zero value matches Init.
+type Options struct {
+	// DisableRedaction logs every attribute as-is. It is meant for local
+	// debugging only: API keys and subtitle text can reach the console and
+	// the log file.
+	DisableRedaction bool
+	// SensitiveKeys adds attribute keys (case-insensitive) to redact.
+	SensitiveKeys []string
+	// AllowKeys exempts attribute keys (case-insensitive) from key-based
+	// redaction, e.g. "text" for a field known to be harmless. Values that
+	// look like API keys or bearer tokens are still redacted.
+	AllowKeys []string
+	// NoColor disables ANSI color on the console even when stderr is a
+	// terminal. The NO_COLOR environment variable has the same effect.
+	NoColor bool
+}
+
+// ReplaceAttr returns the slog.ReplaceAttr function for o, or nil when
+// redaction is disabled.
+func (o Options) ReplaceAttr() func([]string, slog.Attr) slog.Attr {
+	if o.DisableRedaction {
+		return nil
+	}
+	if len(o.SensitiveKeys) == 0 && len(o.AllowKeys) == 0 {
+		return RedactAttr
+	}
+	extra, allow := lowerKeySet(o.SensitiveKeys), lowerKeySet(o.AllowKeys)
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if shouldRedact(a, extra, allow) {
+			return slog.String(a.Key, "[REDACTED]")
+		}
+		return a
+	}
+}
+
+func lowerKeySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(strings.TrimSpace(k))] = true
 	}
-	for _, sub := range sensitiveKeySubstrings {
-		if strings.Contains(key, sub) {
+	return set
+}
+
+// shouldRedact checks a's key against the built-in sensitive keys plus extra
+// (unless allow exempts it), then its value against known secret patterns.
+func shouldRedact(a slog.Attr, extra, allow map[string]bool) bool {
+	key := strings.ToLower(a.Key)
+	if !allow[key] {
+		if sensitiveKeys[key] || extra[key] {
 			return true
 		}
+		for _, sub := range sensitiveKeySubstrings {
+			if strings.Contains(key, sub) {
+				return true
+			}
+		}
 	}
 
 	var value string
@@ -109,13 +156,18 @@ func init() {
 // logLevel sets the minimum level to log.
 // logFile is an optional writer for JSONL output (e.g., an os.File).
 func Init(level slog.Level, logFile io.Writer) {
+	InitWithOptions(level, logFile, Options{})
+}
+
+// InitWithOptions is Init with configurable redaction.
+func InitWithOptions(level slog.Level, logFile io.Writer, o Options) {
 	opts := &slog.HandlerOptions{
 		Level:       level,
-		ReplaceAttr: RedactAttr,
+		ReplaceAttr: o.ReplaceAttr(),
 	}
 
 	// Console Handler (Pretty)
-	useColor := logFile == nil && isTerminal(int(os.Stderr.Fd()))
+	useColor := !o.NoColor && os.Getenv("NO_COLOR") == "" && logFile == nil && isTerminal(int(os.Stderr.Fd()))
 	consoleHandler := NewPrettyHandler(os.Stderr, opts, useColor)
 
 	var handler slog.Handler = consoleHandler
@@ -81,6 +81,40 @@ func TestRedactAttr(t *testing.T) {
 	})
 }
 
+func TestInitWithOptions_Redaction(t *testing.T) {
+	prevStderr := os.Stderr
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("open devnull: %v", err)
+	}
+	os.Stderr = devNull
+	defer func() {
+		os.Stderr = prevStderr
+		_ = devNull.Close()
+		Init(LevelInfo, nil)
+	}()
+
+	logLine := func(o Options, args ...any) string {
+		var buf bytes.Buffer
+		InitWithOptions(LevelInfo, &buf, o)
+		Info("test message", args...)
+		return buf.String()
+	}
+
+	if out := logLine(Options{SensitiveKeys: []string{"Episode"}}, "episode", "s01e02"); strings.Contains(out, "s01e02") || !strings.Contains(out, "[REDACTED]") {
+		t.Fatalf("expected custom key to be redacted: %s", out)
+	}
+	if out := logLine(Options{AllowKeys: []string{"text"}}, "text", "hello", "api_key", "k1"); !strings.Contains(out, "hello") || strings.Contains(out, "k1") {
+		t.Fatalf("expected only the allowed key to be logged: %s", out)
+	}
+	if out := logLine(Options{AllowKeys: []string{"text"}}, "text", "AIzaSyA1234567890abcdef"); strings.Contains(out, "AIzaSy") {
+		t.Fatalf("expected value patterns to apply to allowed keys: %s", out)
+	}
+	if out := logLine(Options{DisableRedaction: true}, "api_key", "sk-1234567890abcdef", "text", "hello"); !strings.Contains(out, "sk-1234567890abcdef") || !strings.Contains(out, "hello") {
+		t.Fatalf("expected no redaction when disabled: %s", out)
+	}
+}
+
 func TestPrettyHandler_NoColorWhenNotTTY(t *testing.T) {
 	prevIsTerminal := isTerminal
 	isTerminal = func(_ int) bool { return false }
@@ -127,3 +161,42 @@ func TestPrettyHandler_NoColorWhenLogFileEnabled(t *testing.T) {
 		t.Fatalf("unexpected ANSI codes in output: %q", string(out))
 	}
 }
+
+func TestPrettyHandler_NoColorWhenDisabled(t *testing.T) {
+	prevIsTerminal := isTerminal
+	isTerminal = func(_ int) bool { return true }
+	defer func() { isTerminal = prevIsTerminal }()
+
+	cases := []struct {
+		name    string
+		noColor string
+		opts    Options
+		want    bool
+	}{
+		{name: "tty", want: true},
+		{name: "NO_COLOR", noColor: "1", want: false},
+		{name: "option", opts: Options{NoColor: true}, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			prevStderr := os.Stderr
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("pipe: %v", err)
+			}
+			os.Stderr = w
+			defer func() { os.Stderr = prevStderr }()
+
+			InitWithOptions(LevelInfo, nil, tc.opts)
+			Info("test message", "key", "value")
+
+			_ = w.Close()
+			out, _ := io.ReadAll(r)
+			if got := strings.Contains(string(out), "\033["); got != tc.want {
+				t.Fatalf("ANSI codes present = %v, want %v: %q", got, tc.want, string(out))
+			}
+		})
+	}
+	Init(LevelInfo, nil)
+}
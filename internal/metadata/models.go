@@ -47,6 +47,7 @@ const (
 	DefaultGeminiInputPerMillion  = 2.00
 	DefaultGeminiOutputPerMillion = 12.00
 	WebSearchCostPerCall          = 0.01
+	GeminiSearchCostPerQuery      = 0.014
 )
 
 func GeminiModelIDs() []string {
@@ -72,6 +73,19 @@ func GeminiPricing(modelID string) (GeminiModel, bool) {
 	}, false
 }
 
+// EstimateGeminiCost returns the estimated USD cost of the given token counts.
+// Reasoning tokens (total minus prompt and candidates) are billed as output.
+func EstimateGeminiCost(modelID string, promptTokens, candidatesTokens, totalTokens int) float64 {
+	reasoningTokens := totalTokens - (promptTokens + candidatesTokens)
+	if reasoningTokens < 0 {
+		reasoningTokens = 0
+	}
+	pricing, _ := GeminiPricing(modelID)
+	inCost := (float64(promptTokens) / 1_000_000) * pricing.InputPerMillion
+	outCost := (float64(candidatesTokens+reasoningTokens) / 1_000_000) * pricing.OutputPerMillion
+	return inCost + outCost
+}
+
 func OpenAIPricing(modelID string) (OpenAIModel, bool) {
 	for _, m := range OpenAIModels {
 		if m.ID == modelID {
@@ -39,3 +39,12 @@ func TestGeminiPricing_Legacy3ProPreviewFallsBack(t *testing.T) {
 		t.Fatalf("unexpected fallback gemini pricing: %+v", m)
 	}
 }
+
+func TestEstimateGeminiCost_ReasoningBilledAsOutput(t *testing.T) {
+	// gemini-3-flash-preview: $0.50 in, $3.00 out per million.
+	got := EstimateGeminiCost("gemini-3-flash-preview", 1_000_000, 100_000, 1_200_000)
+	want := 0.50 + 0.2*3.00
+	if diff := got - want; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("EstimateGeminiCost = %f, want %f", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package names
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// Glossary replaces source names left in translated text with their target
+// names. Applying it again to its own output changes nothing.
+type Glossary struct {
+	mappings []CharacterMapping
+	targets  []string
+	// wordBoundaries requires a match to be a whole word; false for targets
+	// written without spaces between words.
+	wordBoundaries bool
+	// particles lets a Hangul particle follow a name (e.g. "민수가").
+	particles bool
+}
+
+// NewGlossary prepares mappings for text in targetCode. Longer source names
+// are tried first so "Mary Jane" wins over "Mary"; empty and no-op entries
+// are dropped.
+func NewGlossary(mappings []CharacterMapping, targetCode string) *Glossary {
+	g := &Glossary{
+		wordBoundaries: language.UsesWordSpaces(targetCode),
+		particles:      targetCode == "ko",
+	}
+	for _, m := range mappings {
+		if m.Source == "" || m.Source == m.Target {
+			continue
+		}
+		g.mappings = append(g.mappings, m)
+		if m.Target != "" {
+			g.targets = append(g.targets, m.Target)
+		}
+	}
+	sort.SliceStable(g.mappings, func(i, j int) bool {
+		return len(g.mappings[i].Source) > len(g.mappings[j].Source)
+	})
+	return g
+}
+
+// Apply returns text with every whole source name replaced and the number of
+// replacements. A source name inside an existing target name (e.g. "Ann" in
+// an already-applied "Annabel") is left alone.
+func (g *Glossary) Apply(text string) (string, int) {
+	if len(g.mappings) == 0 {
+		return text, 0
+	}
+	applied := g.appliedRanges(text)
+	var b strings.Builder
+	count := 0
+	for i := 0; i < len(text); {
+		if m, ok := g.matchAt(text, i, applied); ok {
+			b.WriteString(m.Target)
+			i += len(m.Source)
+			count++
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		b.WriteString(text[i : i+size])
+		i += size
+	}
+	if count == 0 {
+		return text, 0
+	}
+	return b.String(), count
+}
+
+// ApplySegments applies the glossary to every line and returns copies of the
+// segments along with the total number of replacements.
+func (g *Glossary) ApplySegments(segments []srt.Segment) ([]srt.Segment, int) {
+	out := make([]srt.Segment, len(segments))
+	total := 0
+	for i, seg := range segments {
+		lines := make([]string, len(seg.Lines))
+		for j, line := range seg.Lines {
+			var n int
+			lines[j], n = g.Apply(line)
+			total += n
+		}
+		seg.Lines = lines
+		out[i] = seg
+	}
+	return out, total
+}
+
+// appliedRanges marks the byte ranges of text already covered by a target name.
+func (g *Glossary) appliedRanges(text string) []bool {
+	var applied []bool
+	for _, target := range g.targets {
+		for from := 0; ; {
+			idx := strings.Index(text[from:], target)
+			if idx < 0 {
+				break
+			}
+			start := from + idx
+			if applied == nil {
+				applied = make([]bool, len(text))
+			}
+			for k := start; k < start+len(target); k++ {
+				applied[k] = true
+			}
+			_, size := utf8.DecodeRuneInString(text[start:])
+			from = start + size
+		}
+	}
+	return applied
+}
+
+func (g *Glossary) matchAt(text string, i int, applied []bool) (CharacterMapping, bool) {
+	for _, m := range g.mappings {
+		end := i + len(m.Source)
+		if !strings.HasPrefix(text[i:], m.Source) {
+			continue
+		}
+		if overlaps(applied, i, end) {
+			continue
+		}
+		if g.wordBoundaries && !g.atBoundary(text, i, end) {
+			continue
+		}
+		return m, true
+	}
+	return CharacterMapping{}, false
+}
+
+func overlaps(applied []bool, start, end int) bool {
+	if applied == nil {
+		return false
+	}
+	for k := start; k < end; k++ {
+		if applied[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// atBoundary reports whether text[start:end] is not glued to a neighbouring
+// letter or digit.
+func (g *Glossary) atBoundary(text string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(r) && !(g.particles && unicode.Is(unicode.Hangul, r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
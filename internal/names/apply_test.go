@@ -0,0 +1,101 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestGlossaryApply(t *testing.T) {
+	cases := []struct {
+		name     string
+		target   string
+		mappings []CharacterMapping
+		in       string
+		want     string
+		count    int
+	}{
+		{
+			name:     "word_boundaries",
+			target:   "en",
+			mappings: []CharacterMapping{{Source: "Ann", Target: "Anne"}},
+			in:       "Ann met Annie and Hannah. Ann!",
+			want:     "Anne met Annie and Hannah. Anne!",
+			count:    2,
+		},
+		{
+			name:     "target_contains_source",
+			target:   "en",
+			mappings: []CharacterMapping{{Source: "Ann", Target: "Ann Marie"}},
+			in:       "Ann Marie told Ann.",
+			want:     "Ann Marie told Ann Marie.",
+			count:    1,
+		},
+		{
+			name:     "longest_source_first",
+			target:   "en",
+			mappings: []CharacterMapping{{Source: "Mary", Target: "Maria"}, {Source: "Mary Jane", Target: "MJ"}},
+			in:       "Mary Jane and Mary",
+			want:     "MJ and Maria",
+			count:    2,
+		},
+		{
+			name:     "korean_particles",
+			target:   "ko",
+			mappings: []CharacterMapping{{Source: "민수", Target: "지수"}},
+			in:       "민수가 왔어. 김민수는 아니야.",
+			want:     "지수가 왔어. 김민수는 아니야.",
+			count:    1,
+		},
+		{
+			name:     "unspaced_target",
+			target:   "ja",
+			mappings: []CharacterMapping{{Source: "Tom", Target: "トム"}},
+			in:       "Tomさんはどこ?",
+			want:     "トムさんはどこ?",
+			count:    1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGlossary(tc.mappings, tc.target)
+			got, n := g.Apply(tc.in)
+			if got != tc.want || n != tc.count {
+				t.Fatalf("Apply(%q) = %q (%d), want %q (%d)", tc.in, got, n, tc.want, tc.count)
+			}
+			again, n := g.Apply(got)
+			if again != got || n != 0 {
+				t.Fatalf("second Apply changed %q to %q (%d replacements)", got, again, n)
+			}
+		})
+	}
+}
+
+func TestGlossaryApplySegments_Idempotent(t *testing.T) {
+	g := NewGlossary([]CharacterMapping{{Source: "Jon", Target: "Jonathan"}}, "en")
+	segments := []srt.Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"Jonathan?", "Jon, wait."}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"Where's Jon?"}},
+	}
+	once, n := g.ApplySegments(segments)
+	if n != 2 {
+		t.Fatalf("first pass replaced %d names, want 2", n)
+	}
+	if once[0].Lines[1] != "Jonathan, wait." || once[1].Lines[0] != "Where's Jonathan?" {
+		t.Fatalf("unexpected first pass: %+v", once)
+	}
+	if segments[0].Lines[1] != "Jon, wait." {
+		t.Fatalf("input segments were modified")
+	}
+	twice, n := g.ApplySegments(once)
+	if n != 0 {
+		t.Fatalf("second pass replaced %d names, want 0", n)
+	}
+	for i := range once {
+		for j := range once[i].Lines {
+			if twice[i].Lines[j] != once[i].Lines[j] {
+				t.Fatalf("second pass changed %q to %q", once[i].Lines[j], twice[i].Lines[j])
+			}
+		}
+	}
+}
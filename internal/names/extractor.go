@@ -3,20 +3,47 @@ package names
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/oukeidos/focst/internal/language"
-	"github.com/oukeidos/focst/internal/openai"
+	"github.com/oukeidos/focst/internal/logger"
 )
 
 type Extractor struct {
-	client *openai.Client
+	provider Provider
+	// expandLimit is the largest output token budget an incomplete response
+	// is retried with; 0 disables the retry.
+	expandLimit int
 }
 
-func NewExtractor(client *openai.Client) *Extractor {
-	return &Extractor{client: client}
+// NewExtractor returns an Extractor that runs on provider, e.g.
+// NewOpenAIProvider or NewGeminiProvider.
+func NewExtractor(provider Provider) *Extractor {
+	return &Extractor{provider: provider}
+}
+
+// SetAutoExpandTokens makes a response cut off at the output token limit be
+// retried once with four times the budget, up to limit (the provider's
+// maximum). Usage covers both attempts. A limit of 0 disables the retry.
+func (e *Extractor) SetAutoExpandTokens(limit int) {
+	e.expandLimit = limit
+}
+
+// expandedTokens returns the budget to retry an incomplete response with, or
+// 0 when err is not one or the budget cannot grow.
+func (e *Extractor) expandedTokens(maxTokens int, err error) int {
+	var incomplete *IncompleteError
+	if e.expandLimit <= 0 || !errors.As(err, &incomplete) || incomplete.Reason != "max_output_tokens" {
+		return 0
+	}
+	expanded := min(maxTokens*4, e.expandLimit)
+	if expanded <= maxTokens {
+		return 0
+	}
+	return expanded
 }
 
 type CharacterMapping struct {
@@ -26,137 +53,150 @@ type CharacterMapping struct {
 
 type ExtractionResult struct {
 	Characters []CharacterMapping `json:"characters"`
-	Usage      openai.Usage       `json:"-"`
+	Usage      Usage              `json:"-"`
 }
 
-func (e *Extractor) Extract(ctx context.Context, workType, title, year string, maxTokens int, sourceCode, targetCode string) ([]CharacterMapping, openai.Usage, error) {
-	sourceLang, ok := language.GetLanguage(sourceCode)
-	if !ok {
-		return nil, openai.Usage{}, fmt.Errorf("unsupported source language: %s", sourceCode)
-	}
-	targetLang, ok := language.GetLanguage(targetCode)
-	if !ok {
-		return nil, openai.Usage{}, fmt.Errorf("unsupported target language: %s", targetCode)
+func (e *Extractor) Extract(ctx context.Context, workType, title, year string, maxTokens int, sourceCode, targetCode string) ([]CharacterMapping, Usage, error) {
+	sourceLang, targetLang, err := resolveLanguages(sourceCode, targetCode)
+	if err != nil {
+		return nil, Usage{}, err
 	}
-	sourceKey := sourceLang.Code
-	targetKey := targetLang.Code
 
 	prompt := fmt.Sprintf(`Search for the %s %s titled "%s" released in %s. 
 Extract a list of major characters. For each character, provide their name in %s and its standard %s transliteration.
 IMPORTANT: Return ONLY the name itself. Do NOT include any URLs, source links, brackets, or explanations.`,
 		sourceLang.Name, workType, title, year, sourceLang.Name, targetLang.Name)
 
+	req := Request{
+		Prompt:          prompt,
+		WebSearch:       true,
+		MaxOutputTokens: outputTokens(maxTokens),
+	}
+
+	return e.generate(ctx, req, sourceLang.Code, targetLang.Code)
+}
+
+// ExtractFromText asks the model for likely character and place names found in
+// subtitle lines, without web search. Lines are sampled across the file to fit
+// inputTokenBudget (DefaultTextTokenBudget if <= 0), and names that do not
+// appear in the sampled text are dropped.
+func (e *Extractor) ExtractFromText(ctx context.Context, lines []string, inputTokenBudget, maxTokens int, sourceCode, targetCode string) ([]CharacterMapping, Usage, error) {
+	sourceLang, targetLang, err := resolveLanguages(sourceCode, targetCode)
+	if err != nil {
+		return nil, Usage{}, err
+	}
+	if inputTokenBudget <= 0 {
+		inputTokenBudget = DefaultTextTokenBudget
+	}
+	sampled := SampleLines(lines, inputTokenBudget)
+	if len(sampled) == 0 {
+		return nil, Usage{}, fmt.Errorf("no subtitle text to extract names from")
+	}
+	text := strings.Join(sampled, "\n")
+
+	prompt := fmt.Sprintf(`The following lines are sampled from a %s subtitle file.
+List the proper nouns in them that are likely character names or place names. For each, provide the name exactly as written in the %s lines and its standard %s transliteration.
+Include only names that actually appear in the lines. Do NOT include common nouns, honorifics alone, or explanations.
+IMPORTANT: Return ONLY the name itself. Do NOT include any URLs, source links, brackets, or explanations.
+
+Lines:
+%s`,
+		sourceLang.Name, sourceLang.Name, targetLang.Name, text)
+
+	req := Request{
+		Prompt:          prompt,
+		MaxOutputTokens: outputTokens(maxTokens),
+	}
+
+	mappings, usage, err := e.generate(ctx, req, sourceLang.Code, targetLang.Code)
+	if err != nil {
+		return nil, usage, err
+	}
+	return filterMappings(mappings, text), usage, nil
+}
+
+func resolveLanguages(sourceCode, targetCode string) (language.Language, language.Language, error) {
+	sourceLang, ok := language.GetLanguage(sourceCode)
+	if !ok {
+		return language.Language{}, language.Language{}, fmt.Errorf("unsupported source language: %s", sourceCode)
+	}
+	targetLang, ok := language.GetLanguage(targetCode)
+	if !ok {
+		return language.Language{}, language.Language{}, fmt.Errorf("unsupported target language: %s", targetCode)
+	}
+	return sourceLang, targetLang, nil
+}
+
+func outputTokens(maxTokens int) int {
 	if maxTokens <= 0 {
-		maxTokens = 16384 // Default to 16k if not specified
+		return 16384 // Default to 16k if not specified
 	}
+	return maxTokens
+}
 
-	req := openai.RequestData{
-		Input: []openai.InputItem{
-			{
-				Type:    "message",
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-		Tools: []openai.Tool{
-			{Type: "web_search"},
-		},
-		ToolChoice: "required", // Force tool use as requested
-		Reasoning: &openai.ReasoningOptions{
-			Effort: "medium",
-		},
-		Text: &openai.TextOptions{
-			Format: &openai.ResponseFormat{
-				Type:   "json_schema",
-				Name:   "character_extraction",
-				Strict: true,
-				Schema: map[string]interface{}{
+// characterSchema returns the structured-output schema keyed by language code.
+func characterSchema(sourceKey, targetKey string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"characters": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
-						"characters": map[string]interface{}{
-							"type": "array",
-							"items": map[string]interface{}{
-								"type": "object",
-								"properties": map[string]interface{}{
-									sourceKey: map[string]interface{}{
-										"type":        "string",
-										"description": "The name of the character in the source language. MUST contain ONLY the name, no URLs or comments.",
-									},
-									targetKey: map[string]interface{}{
-										"type":        "string",
-										"description": "Standard transliteration of the name. ONLY the name.",
-									},
-								},
-								"required":             []string{sourceKey, targetKey},
-								"additionalProperties": false,
-							},
+						sourceKey: map[string]interface{}{
+							"type":        "string",
+							"description": "The name of the character in the source language. MUST contain ONLY the name, no URLs or comments.",
+						},
+						targetKey: map[string]interface{}{
+							"type":        "string",
+							"description": "Standard transliteration of the name. ONLY the name.",
 						},
 					},
-					"required":             []string{"characters"},
+					"required":             []string{sourceKey, targetKey},
 					"additionalProperties": false,
 				},
 			},
 		},
-		MaxOutputTokens: maxTokens,
-	}
-
-	resp, err := e.client.Generate(ctx, req)
-	if err != nil {
-		return nil, openai.Usage{}, err
-	}
-
-	if resp.Status == "incomplete" {
-		reason := "unknown"
-		if resp.IncompleteDetails != nil {
-			reason = resp.IncompleteDetails.Reason
-		}
-		return nil, resp.Usage, fmt.Errorf("API response is incomplete (reason: %s). Try increasing MaxOutputTokens or reducing reasoning effort.", reason)
+		"required":             []string{"characters"},
+		"additionalProperties": false,
 	}
+}
 
-	if len(resp.Output) == 0 {
-		return nil, openai.Usage{}, fmt.Errorf("no output from API")
+func (e *Extractor) generate(ctx context.Context, req Request, sourceKey, targetKey string) ([]CharacterMapping, Usage, error) {
+	req.SchemaName = "character_extraction"
+	req.Schema = characterSchema(sourceKey, targetKey)
+	content, usage, err := e.provider.Generate(ctx, req)
+	if expanded := e.expandedTokens(req.MaxOutputTokens, err); expanded > 0 {
+		logger.Warn("Response stopped at the output limit; retrying with a larger budget", "limit", req.MaxOutputTokens, "retry_limit", expanded)
+		req.MaxOutputTokens = expanded
+		var retryUsage Usage
+		content, retryUsage, err = e.provider.Generate(ctx, req)
+		usage = usage.plus(retryUsage)
 	}
-
-	// Find assistant's message text
-	var content string
-	for _, item := range resp.Output {
-		if item.Type == "message" && item.Role == "assistant" {
-			for _, c := range item.Content {
-				// Responses API uses "output_text" for the assistant's response content
-				if c.Type == "output_text" {
-					content = c.Text
-					break
-				}
-			}
-		}
-		if content != "" {
-			break
-		}
-	}
-
-	if content == "" {
-		return nil, openai.Usage{}, fmt.Errorf("no assistant text message found in output")
+	if err != nil {
+		return nil, usage, err
 	}
 
 	var raw struct {
 		Characters []map[string]string `json:"characters"`
 	}
 	if err := json.Unmarshal([]byte(content), &raw); err != nil {
-		return nil, openai.Usage{}, fmt.Errorf("failed to parse character mapping: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to parse character mapping: %w", err)
 	}
 	result := ExtractionResult{
 		Characters: make([]CharacterMapping, 0, len(raw.Characters)),
-		Usage:      resp.Usage,
+		Usage:      usage,
 	}
 
 	for _, entry := range raw.Characters {
 		srcVal, ok := entry[sourceKey]
 		if !ok {
-			return nil, openai.Usage{}, fmt.Errorf("missing source field %q in response", sourceKey)
+			return nil, Usage{}, fmt.Errorf("missing source field %q in response", sourceKey)
 		}
 		tgtVal, ok := entry[targetKey]
 		if !ok {
-			return nil, openai.Usage{}, fmt.Errorf("missing target field %q in response", targetKey)
+			return nil, Usage{}, fmt.Errorf("missing target field %q in response", targetKey)
 		}
 		result.Characters = append(result.Characters, CharacterMapping{
 			Source: cleanName(srcVal),
@@ -0,0 +1,179 @@
+package names
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/openai"
+)
+
+func TestExtractor_ExtractFromText(t *testing.T) {
+	var got openai.RequestData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		content := `{"characters":[{"ja":"太郎","ko":"타로"},{"ja":"東京","ko":"도쿄"},{"ja":"花子","ko":"하나코"},{"ja":"太郎","ko":"타로"}]}`
+		text, _ := json.Marshal(content)
+		fmt.Fprintf(w, `{"status":"completed","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":%s}]}]}`, text)
+	}))
+	defer server.Close()
+
+	client := openai.NewClient("test-key", "test-model")
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatalf("SetBaseURL: %v", err)
+	}
+	lines := []string{"太郎、東京へ行こう", "", "太郎、東京へ行こう", "うん"}
+	mappings, _, err := NewExtractor(NewOpenAIProvider(client)).ExtractFromText(context.Background(), lines, 0, 0, "ja", "ko")
+	if err != nil {
+		t.Fatalf("ExtractFromText failed: %v", err)
+	}
+
+	if len(got.Tools) != 0 || got.ToolChoice != nil {
+		t.Fatalf("expected no web search tool, got tools=%v choice=%v", got.Tools, got.ToolChoice)
+	}
+	if got.Text == nil || got.Text.Format == nil || got.Text.Format.Name != "character_extraction" {
+		t.Fatalf("expected structured-output schema, got %+v", got.Text)
+	}
+	prompt := got.Input[0].Content
+	if strings.Count(prompt, "太郎、東京へ行こう") != 1 || !strings.Contains(prompt, "うん") {
+		t.Fatalf("expected deduplicated lines in prompt, got %q", prompt)
+	}
+
+	want := []CharacterMapping{{Source: "太郎", Target: "타로"}, {Source: "東京", Target: "도쿄"}}
+	if len(mappings) != len(want) {
+		t.Fatalf("mappings = %+v, want %+v", mappings, want)
+	}
+	for i := range want {
+		if mappings[i] != want[i] {
+			t.Fatalf("mappings[%d] = %+v, want %+v", i, mappings[i], want[i])
+		}
+	}
+}
+
+func TestExtractor_AutoExpandTokens(t *testing.T) {
+	var budgets []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openai.RequestData
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		budgets = append(budgets, req.MaxOutputTokens)
+		if req.MaxOutputTokens < 100000 {
+			fmt.Fprint(w, `{"status":"incomplete","incomplete_details":{"reason":"max_output_tokens"},"output":[],"usage":{"input_tokens":100,"output_tokens":40000,"total_tokens":40100}}`)
+			return
+		}
+		text, _ := json.Marshal(`{"characters":[{"ja":"太郎","ko":"타로"}]}`)
+		fmt.Fprintf(w, `{"status":"completed","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":%s}]}],"usage":{"input_tokens":100,"output_tokens":50000,"total_tokens":50100}}`, text)
+	}))
+	defer server.Close()
+	client := openai.NewClient("test-key", "test-model")
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatalf("SetBaseURL: %v", err)
+	}
+
+	_, _, err := NewExtractor(NewOpenAIProvider(client)).Extract(context.Background(), "show", "Title", "", 40000, "ja", "ko")
+	var incomplete *IncompleteError
+	if !errors.As(err, &incomplete) || incomplete.Reason != "max_output_tokens" {
+		t.Fatalf("without auto-expand: err = %v, want IncompleteError", err)
+	}
+	if !reflect.DeepEqual(budgets, []int{40000}) {
+		t.Fatalf("without auto-expand: budgets = %v, want one request", budgets)
+	}
+
+	budgets = nil
+	extractor := NewExtractor(NewOpenAIProvider(client))
+	extractor.SetAutoExpandTokens(128000)
+	mappings, usage, err := extractor.Extract(context.Background(), "show", "Title", "", 40000, "ja", "ko")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if !reflect.DeepEqual(budgets, []int{40000, 128000}) {
+		t.Fatalf("budgets = %v, want a retry capped at 128000", budgets)
+	}
+	if want := []CharacterMapping{{Source: "太郎", Target: "타로"}}; !reflect.DeepEqual(mappings, want) {
+		t.Fatalf("mappings = %+v, want %+v", mappings, want)
+	}
+	if want := (Usage{InputTokens: 200, OutputTokens: 90000, TotalTokens: 90200}); usage != want {
+		t.Fatalf("usage = %+v, want both attempts %+v", usage, want)
+	}
+}
+
+// mockProvider records the request and answers with content and usage.
+type mockProvider struct {
+	content string
+	usage   Usage
+	got     Request
+}
+
+func (m *mockProvider) GetModelID() string { return "mock" }
+
+func (m *mockProvider) Generate(ctx context.Context, req Request) (string, Usage, error) {
+	m.got = req
+	return m.content, m.usage, nil
+}
+
+func TestExtractor_ExtractWithProvider(t *testing.T) {
+	provider := &mockProvider{
+		content: `{"characters":[{"ja":"太郎 (tbs.co.jp)","ko":"타로"},{"ja":"花子","ko":"하나코 [source]"}]}`,
+		usage:   Usage{InputTokens: 100, OutputTokens: 20, TotalTokens: 120, WebSearchCalls: 2},
+	}
+	mappings, usage, err := NewExtractor(provider).Extract(context.Background(), "show", "Title", "2024", 0, "ja", "ko")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if !provider.got.WebSearch {
+		t.Fatalf("expected a web search request")
+	}
+	if provider.got.SchemaName != "character_extraction" || provider.got.Schema == nil {
+		t.Fatalf("expected the character schema, got %q %v", provider.got.SchemaName, provider.got.Schema)
+	}
+	if provider.got.MaxOutputTokens != 16384 {
+		t.Fatalf("MaxOutputTokens = %d, want the 16384 default", provider.got.MaxOutputTokens)
+	}
+	if !strings.Contains(provider.got.Prompt, `"Title"`) {
+		t.Fatalf("prompt does not name the title: %q", provider.got.Prompt)
+	}
+	want := []CharacterMapping{{Source: "太郎", Target: "타로"}, {Source: "花子", Target: "하나코"}}
+	if !reflect.DeepEqual(mappings, want) {
+		t.Fatalf("mappings = %+v, want %+v", mappings, want)
+	}
+	if usage != provider.usage {
+		t.Fatalf("usage = %+v, want the provider's %+v", usage, provider.usage)
+	}
+
+	if _, _, err := NewExtractor(&mockProvider{content: `{"characters":[{"ja":"太郎"}]}`}).Extract(context.Background(), "show", "Title", "", 0, "ja", "ko"); err == nil {
+		t.Fatalf("expected an error for a missing target field")
+	}
+}
+
+func TestSampleLines_FitsBudgetAcrossFile(t *testing.T) {
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %03d", i)) // 9 tokens each with the line break
+	}
+
+	if got := SampleLines(lines, 10000); len(got) != 100 {
+		t.Fatalf("expected all lines under a large budget, got %d", len(got))
+	}
+
+	got := SampleLines(lines, 90)
+	used := 0
+	for _, line := range got {
+		used += estimateTokens(line)
+	}
+	if used > 90 {
+		t.Fatalf("sampled %d tokens, budget 90", used)
+	}
+	if len(got) < 2 || got[0] != "line 000" || got[len(got)-1] < "line 050" {
+		t.Fatalf("expected samples spread across the file, got %v", got)
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"os"
 )
 
+// LoadMappingFile reads a names mapping in the format its extension selects
+// (see MappingFormatForPath) and returns it as a source-to-target dictionary.
 func LoadMappingFile(path, sourceCode, targetCode string) (map[string]string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read names mapping file %s: %w", path, err)
 	}
-	mappings, err := DecodeMappings(data, sourceCode, targetCode)
+	mappings, err := DecodeMappingsAs(MappingFormatForPath(path), data, sourceCode, targetCode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse names mapping file %s: %w", path, err)
 	}
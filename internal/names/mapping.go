@@ -1,8 +1,12 @@
 package names
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/oukeidos/focst/internal/language"
 )
@@ -68,3 +72,110 @@ func DecodeMappings(data []byte, sourceCode, targetCode string) ([]CharacterMapp
 	}
 	return mappings, nil
 }
+
+// MappingFormat is the file format of a names mapping.
+type MappingFormat int
+
+const (
+	// MappingJSON is an array of objects keyed by language code.
+	MappingJSON MappingFormat = iota
+	// MappingCSV is comma-separated values with a header row naming the
+	// source and target language codes (e.g. "en,ko").
+	MappingCSV
+	// MappingTSV is MappingCSV with tab separators.
+	MappingTSV
+)
+
+// MappingFormatForPath picks the format from the file extension: .csv and
+// .tsv select the delimited formats, anything else is JSON.
+func MappingFormatForPath(path string) MappingFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return MappingCSV
+	case ".tsv":
+		return MappingTSV
+	default:
+		return MappingJSON
+	}
+}
+
+func (f MappingFormat) comma() rune {
+	if f == MappingTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// EncodeMappingsAs encodes mappings in format.
+func EncodeMappingsAs(format MappingFormat, mappings []CharacterMapping, sourceCode, targetCode string) ([]byte, error) {
+	if format == MappingJSON {
+		return EncodeMappings(mappings, sourceCode, targetCode)
+	}
+	sourceKey, targetKey, err := schemaKeys(sourceCode, targetCode)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = format.comma()
+	records := make([][]string, 0, len(mappings)+1)
+	records = append(records, []string{sourceKey, targetKey})
+	for _, m := range mappings {
+		records = append(records, []string{m.Source, m.Target})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeMappingsAs decodes data in format. Delimited files need a header row
+// with the source and target language codes, in either order, and exactly
+// two columns in every row.
+func DecodeMappingsAs(format MappingFormat, data []byte, sourceCode, targetCode string) ([]CharacterMapping, error) {
+	if format == MappingJSON {
+		return DecodeMappings(data, sourceCode, targetCode)
+	}
+	sourceKey, targetKey, err := schemaKeys(sourceCode, targetCode)
+	if err != nil {
+		return nil, err
+	}
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	r.Comma = format.comma()
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row %q", sourceKey+string(r.Comma)+targetKey)
+	}
+	header := records[0]
+	if len(header) != 2 {
+		return nil, fmt.Errorf("expected 2 columns (%s, %s), got %d", sourceKey, targetKey, len(header))
+	}
+	srcCol, tgtCol := -1, -1
+	for i, name := range header {
+		code, err := normalizeCode(strings.TrimSpace(name))
+		switch {
+		case err != nil:
+		case code == sourceKey && srcCol < 0:
+			srcCol = i
+		case code == targetKey && tgtCol < 0:
+			tgtCol = i
+		}
+	}
+	if srcCol < 0 || tgtCol < 0 {
+		return nil, fmt.Errorf("header %q does not name languages %q and %q", strings.Join(header, string(r.Comma)), sourceKey, targetKey)
+	}
+	mappings := make([]CharacterMapping, 0, len(records)-1)
+	for _, record := range records[1:] {
+		mappings = append(mappings, CharacterMapping{
+			Source: record[srcCol],
+			Target: record[tgtCol],
+		})
+	}
+	return mappings, nil
+}
+
+// utf8BOM prefixes CSV files exported by some spreadsheet applications.
+var utf8BOM = []byte("\xef\xbb\xbf")
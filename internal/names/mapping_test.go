@@ -1,6 +1,7 @@
 package names
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -30,3 +31,69 @@ func TestDecodeMappings_MissingKey(t *testing.T) {
 		t.Fatalf("expected error for missing target key")
 	}
 }
+
+func TestMappingFormatForPath(t *testing.T) {
+	cases := map[string]MappingFormat{
+		"names.json": MappingJSON,
+		"names.CSV":  MappingCSV,
+		"names.tsv":  MappingTSV,
+		"names":      MappingJSON,
+	}
+	for path, want := range cases {
+		if got := MappingFormatForPath(path); got != want {
+			t.Errorf("MappingFormatForPath(%q) = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestDelimitedMappings_RoundTrip(t *testing.T) {
+	in := "\xef\xbb\xbfko,ja\n\"김, 철수\",金哲洙\n영희,ヨンヒ\n"
+	mappings, err := DecodeMappingsAs(MappingCSV, []byte(in), "ja", "ko")
+	if err != nil {
+		t.Fatalf("DecodeMappingsAs failed: %v", err)
+	}
+	want := []CharacterMapping{{Source: "金哲洙", Target: "김, 철수"}, {Source: "ヨンヒ", Target: "영희"}}
+	if !reflect.DeepEqual(mappings, want) {
+		t.Fatalf("decoded %+v, want %+v", mappings, want)
+	}
+
+	out, err := EncodeMappingsAs(MappingCSV, mappings, "ja", "ko")
+	if err != nil {
+		t.Fatalf("EncodeMappingsAs failed: %v", err)
+	}
+	if got, want := string(out), "ja,ko\n金哲洙,\"김, 철수\"\nヨンヒ,영희\n"; got != want {
+		t.Fatalf("encoded %q, want %q", got, want)
+	}
+	again, err := DecodeMappingsAs(MappingCSV, out, "ja", "ko")
+	if err != nil || !reflect.DeepEqual(again, want) {
+		t.Fatalf("round trip = %+v, %v", again, err)
+	}
+
+	tsv, err := EncodeMappingsAs(MappingTSV, mappings, "ja", "ko")
+	if err != nil {
+		t.Fatalf("EncodeMappingsAs(TSV) failed: %v", err)
+	}
+	if !strings.HasPrefix(string(tsv), "ja\tko\n") {
+		t.Fatalf("unexpected TSV header: %q", tsv)
+	}
+	if again, err := DecodeMappingsAs(MappingTSV, tsv, "ja", "ko"); err != nil || !reflect.DeepEqual(again, want) {
+		t.Fatalf("TSV round trip = %+v, %v", again, err)
+	}
+}
+
+func TestDecodeDelimitedMappings_Errors(t *testing.T) {
+	cases := map[string]string{
+		"empty":         "",
+		"wrong header":  "en,ko\nBob,밥\n",
+		"same language": "ja,ja\n太郎,太郎\n",
+		"extra column":  "ja,ko,note\n太郎,타로,lead\n",
+		"ragged row":    "ja,ko\n太郎,타로,extra\n",
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeMappingsAs(MappingCSV, []byte(data), "ja", "ko"); err == nil {
+				t.Fatalf("expected error for %q", data)
+			}
+		})
+	}
+}
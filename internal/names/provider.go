@@ -0,0 +1,169 @@
+package names
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/openai"
+)
+
+// Request is a provider-neutral structured-output prompt.
+type Request struct {
+	Prompt string
+	// SchemaName and Schema describe the JSON object to return.
+	SchemaName string
+	Schema     map[string]interface{}
+	// WebSearch requires the model to ground its answer with a web search.
+	WebSearch       bool
+	MaxOutputTokens int
+}
+
+// Usage is what a request consumed. OutputTokens includes reasoning tokens.
+// WebSearchCalls counts search tool calls (OpenAI) or search queries
+// (Gemini), which each provider bills differently.
+type Usage struct {
+	InputTokens    int
+	OutputTokens   int
+	TotalTokens    int
+	WebSearchCalls int
+}
+
+func (u Usage) plus(o Usage) Usage {
+	return Usage{
+		InputTokens:    u.InputTokens + o.InputTokens,
+		OutputTokens:   u.OutputTokens + o.OutputTokens,
+		TotalTokens:    u.TotalTokens + o.TotalTokens,
+		WebSearchCalls: u.WebSearchCalls + o.WebSearchCalls,
+	}
+}
+
+// IncompleteError reports a response the API stopped before it was done.
+// Reason is "max_output_tokens" when it ran out of output tokens.
+type IncompleteError struct {
+	Reason string
+}
+
+func (e *IncompleteError) Error() string {
+	return fmt.Sprintf("API response is incomplete (reason: %s). Try increasing MaxOutputTokens or reducing reasoning effort.", e.Reason)
+}
+
+// Provider is a model API the Extractor can run on. Generate returns the
+// JSON text of the response; usage may be non-zero alongside an error.
+type Provider interface {
+	Generate(ctx context.Context, req Request) (string, Usage, error)
+	GetModelID() string
+}
+
+// OpenAIProvider runs extraction on the OpenAI Responses API.
+type OpenAIProvider struct {
+	client *openai.Client
+}
+
+func NewOpenAIProvider(client *openai.Client) *OpenAIProvider {
+	return &OpenAIProvider{client: client}
+}
+
+func (p *OpenAIProvider) GetModelID() string {
+	return p.client.GetModelID()
+}
+
+func (p *OpenAIProvider) Generate(ctx context.Context, req Request) (string, Usage, error) {
+	oreq := openai.RequestData{
+		Input: []openai.InputItem{
+			{
+				Type:    "message",
+				Role:    "user",
+				Content: req.Prompt,
+			},
+		},
+		Reasoning: &openai.ReasoningOptions{
+			Effort: "medium",
+		},
+		Text: &openai.TextOptions{
+			Format: &openai.ResponseFormat{
+				Type:   "json_schema",
+				Name:   req.SchemaName,
+				Strict: true,
+				Schema: req.Schema,
+			},
+		},
+		MaxOutputTokens: req.MaxOutputTokens,
+	}
+	if req.WebSearch {
+		oreq.Tools = []openai.Tool{{Type: "web_search"}}
+		oreq.ToolChoice = "required" // Force tool use as requested
+	}
+
+	resp, err := p.client.Generate(ctx, oreq)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	usage := Usage{
+		InputTokens:    resp.Usage.InputTokens,
+		OutputTokens:   resp.Usage.OutputTokens,
+		TotalTokens:    resp.Usage.TotalTokens,
+		WebSearchCalls: resp.Usage.WebSearchCalls,
+	}
+
+	if resp.Status == "incomplete" {
+		reason := "unknown"
+		if resp.IncompleteDetails != nil {
+			reason = resp.IncompleteDetails.Reason
+		}
+		return "", usage, &IncompleteError{Reason: reason}
+	}
+
+	if len(resp.Output) == 0 {
+		return "", Usage{}, fmt.Errorf("no output from API")
+	}
+
+	// Find assistant's message text
+	for _, item := range resp.Output {
+		if item.Type == "message" && item.Role == "assistant" {
+			for _, c := range item.Content {
+				// Responses API uses "output_text" for the assistant's response content
+				if c.Type == "output_text" && c.Text != "" {
+					return c.Text, usage, nil
+				}
+			}
+		}
+	}
+	return "", Usage{}, fmt.Errorf("no assistant text message found in output")
+}
+
+// GeminiProvider runs extraction on Gemini, grounding with Google Search
+// when the request asks for web search.
+type GeminiProvider struct {
+	client *gemini.GenerateClient
+}
+
+func NewGeminiProvider(client *gemini.GenerateClient) *GeminiProvider {
+	return &GeminiProvider{client: client}
+}
+
+func (p *GeminiProvider) GetModelID() string {
+	return p.client.GetModelID()
+}
+
+func (p *GeminiProvider) Generate(ctx context.Context, req Request) (string, Usage, error) {
+	resp, err := p.client.Generate(ctx, gemini.GenerateRequest{
+		Prompt:          req.Prompt,
+		Schema:          req.Schema,
+		GoogleSearch:    req.WebSearch,
+		MaxOutputTokens: req.MaxOutputTokens,
+	})
+	if resp == nil {
+		return "", Usage{}, err
+	}
+	usage := Usage{
+		InputTokens:    resp.Usage.PromptTokenCount,
+		OutputTokens:   max(resp.Usage.TotalTokenCount-resp.Usage.PromptTokenCount, resp.Usage.CandidatesTokenCount), // reasoning included
+		TotalTokens:    resp.Usage.TotalTokenCount,
+		WebSearchCalls: resp.Usage.WebSearchCount,
+	}
+	if err != nil {
+		return "", usage, err
+	}
+	return resp.Text, usage, nil
+}
@@ -0,0 +1,64 @@
+package names
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultTextTokenBudget caps the subtitle text sent by ExtractFromText.
+const DefaultTextTokenBudget = 8000
+
+// estimateTokens is a conservative token estimate: one token per rune, which
+// is close for CJK text and overcounts for Latin scripts.
+func estimateTokens(s string) int {
+	return utf8.RuneCountInString(s) + 1 // +1 for the line break
+}
+
+// SampleLines returns distinct non-empty lines that fit within tokenBudget.
+// When the file is larger than the budget, lines are taken at an even stride
+// so names from the whole file (not just the opening) are represented.
+func SampleLines(lines []string, tokenBudget int) []string {
+	seen := make(map[string]bool, len(lines))
+	distinct := make([]string, 0, len(lines))
+	total := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		distinct = append(distinct, line)
+		total += estimateTokens(line)
+	}
+	if total <= tokenBudget {
+		return distinct
+	}
+
+	stride := (total + tokenBudget - 1) / tokenBudget
+	sampled := make([]string, 0, len(distinct)/stride+1)
+	used := 0
+	for i := 0; i < len(distinct); i += stride {
+		cost := estimateTokens(distinct[i])
+		if used+cost > tokenBudget {
+			continue
+		}
+		sampled = append(sampled, distinct[i])
+		used += cost
+	}
+	return sampled
+}
+
+// filterMappings drops empty or duplicate entries and names that do not occur
+// in the source text, guarding against names invented by the model.
+func filterMappings(mappings []CharacterMapping, text string) []CharacterMapping {
+	seen := make(map[string]bool, len(mappings))
+	out := make([]CharacterMapping, 0, len(mappings))
+	for _, m := range mappings {
+		if m.Source == "" || m.Target == "" || seen[m.Source] || !strings.Contains(text, m.Source) {
+			continue
+		}
+		seen[m.Source] = true
+		out = append(out, m)
+	}
+	return out
+}
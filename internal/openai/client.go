@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/oukeidos/focst/internal/apperrors"
 	"github.com/oukeidos/focst/internal/httpclient"
@@ -110,18 +112,52 @@ func (e errorDetails) codeString() string {
 	return fmt.Sprint(e.Code)
 }
 
+// DefaultBaseURL is the OpenAI API base URL used unless SetBaseURL overrides it.
+const DefaultBaseURL = "https://api.openai.com/v1"
+
 type Client struct {
-	apiKey  string
-	model   string
-	baseURL string
+	apiKey           string
+	model            string
+	baseURL          string
+	timeout          time.Duration
+	maxResponseBytes int64
 }
 
 func NewClient(apiKey, model string) *Client {
 	return &Client{
 		apiKey:  apiKey,
 		model:   model,
-		baseURL: "https://api.openai.com/v1",
+		baseURL: DefaultBaseURL,
+		timeout: httpclient.DefaultTimeout,
+	}
+}
+
+// SetRequestTimeout bounds each API call. Non-positive values keep the default.
+func (c *Client) SetRequestTimeout(d time.Duration) {
+	if d > 0 {
+		c.timeout = d
+	}
+}
+
+// SetMaxResponseBytes changes the response body cap, e.g. for large structured
+// outputs. Non-positive values keep httpclient.MaxResponseBytes.
+func (c *Client) SetMaxResponseBytes(n int64) {
+	c.maxResponseBytes = n
+}
+
+// SetBaseURL points the client at an OpenAI-compatible gateway (e.g. a proxy or LiteLLM).
+// The URL must use http or https; a trailing slash is ignored.
+func (c *Client) SetBaseURL(raw string) error {
+	if _, err := httpclient.ValidateBaseURL(raw); err != nil {
+		return err
 	}
+	c.baseURL = strings.TrimRight(raw, "/")
+	return nil
+}
+
+// BaseURL returns the API base URL the client sends requests to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
 }
 
 // GetModelID returns the configured model identifier.
@@ -137,8 +173,11 @@ func (c *Client) Generate(ctx context.Context, req RequestData) (*ResponseData,
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
 	url := c.baseURL + "/responses"
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(callCtx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -147,8 +186,24 @@ func (c *Client) Generate(ctx context.Context, req RequestData) (*ResponseData,
 	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
 
 	client := httpclient.GetDefaultClient()
-	body, resp, err := httpclient.DoAndRead(client, httpReq)
+	body, resp, err := httpclient.DoAndReadLimit(client, httpReq, c.maxResponseBytes)
 	if err != nil {
+		if errors.Is(err, httpclient.ErrResponseTooLarge) {
+			// Retryable like other malformed output; a smaller request or a
+			// raised limit (SetMaxResponseBytes) avoids it.
+			return nil, apperrors.New(
+				apperrors.KindValidation,
+				"OpenAI response exceeded the size limit. Retry with a smaller request or raise the limit.",
+				err,
+			)
+		}
+		if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return nil, apperrors.New(
+				apperrors.KindTransient,
+				fmt.Sprintf("OpenAI request timed out after %s. Please retry.", c.timeout),
+				fmt.Errorf("request timed out: %w", err),
+			)
+		}
 		return nil, apperrors.New(
 			apperrors.KindTransient,
 			"OpenAI request failed due to a temporary network/runtime error.",
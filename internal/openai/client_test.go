@@ -2,11 +2,16 @@ package openai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/httpclient"
 )
 
 func TestClient_Generate_Errors(t *testing.T) {
@@ -72,3 +77,103 @@ func TestClient_Generate_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_SetBaseURL(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":"r","status":"completed","output":[]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-model")
+	if err := client.SetBaseURL(server.URL + "/gateway/v1/"); err != nil {
+		t.Fatalf("SetBaseURL failed: %v", err)
+	}
+	if _, err := client.Generate(context.Background(), RequestData{}); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if gotPath != "/gateway/v1/responses" {
+		t.Fatalf("request path = %q, want /gateway/v1/responses", gotPath)
+	}
+
+	if err := client.SetBaseURL("ftp://proxy.example.com"); err == nil {
+		t.Fatalf("expected non-http scheme to be rejected")
+	}
+	if client.BaseURL() != server.URL+"/gateway/v1" {
+		t.Fatalf("base URL changed after rejected update: %q", client.BaseURL())
+	}
+}
+
+func TestClient_RequestTimeoutIsRetryable(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("test-key", "test-model")
+	client.baseURL = server.URL
+	client.SetRequestTimeout(50 * time.Millisecond)
+
+	_, err := client.Generate(context.Background(), RequestData{})
+	if err == nil {
+		t.Fatal("expected timeout error, got nil")
+	}
+	if !apperrors.IsRetryable(err) || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Fatalf("expected retryable timeout error, got %v", err)
+	}
+}
+
+func TestClient_ResponseTooLargeIsRetryable(t *testing.T) {
+	body := `{"id":"resp_1","status":"completed","output":[]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-model")
+	client.baseURL = server.URL
+	client.SetMaxResponseBytes(int64(len(body) - 1))
+
+	_, err := client.Generate(context.Background(), RequestData{})
+	if !errors.Is(err, httpclient.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if !apperrors.IsRetryable(err) {
+		t.Fatalf("expected retryable error, got %v", err)
+	}
+
+	client.SetMaxResponseBytes(int64(len(body)))
+	if _, err := client.Generate(context.Background(), RequestData{}); err != nil {
+		t.Fatalf("raised limit: %v", err)
+	}
+}
+
+func TestClient_UsesConfiguredTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"r","status":"completed","output":[]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-model")
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatalf("SetBaseURL failed: %v", err)
+	}
+
+	// The test server's certificate is self-signed, so the default transport
+	// rejects it and only the insecure transport gets through.
+	if _, err := client.Generate(context.Background(), RequestData{}); err == nil {
+		t.Fatal("expected certificate error with the default transport")
+	}
+	httpclient.SetTransportOptions(httpclient.TransportOptions{InsecureSkipVerify: true})
+	defer httpclient.SetTransportOptions(httpclient.TransportOptions{})
+	if _, err := client.Generate(context.Background(), RequestData{}); err != nil {
+		t.Fatalf("Generate with insecure transport failed: %v", err)
+	}
+}
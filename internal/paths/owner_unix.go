@@ -0,0 +1,15 @@
+//go:build !windows
+
+package paths
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownedPrivate reports whether info is owned by the current user and has
+// mode 0700.
+func ownedPrivate(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid() && info.Mode().Perm() == 0700
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package paths
+
+import "os"
+
+// ownedPrivate always reports true: the Windows temp dir is per user, and
+// Unix permission bits do not describe its access control.
+func ownedPrivate(_ os.FileInfo) bool {
+	return true
+}
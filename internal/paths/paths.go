@@ -0,0 +1,116 @@
+// Package paths resolves where focst keeps user data such as name dictionaries.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oukeidos/focst/internal/logger"
+)
+
+// EnvHome overrides the base data directory (default ~/.focst).
+const EnvHome = "FOCST_HOME"
+
+// Swapped in tests to simulate a missing home directory.
+var (
+	userHomeDir  = os.UserHomeDir
+	userCacheDir = os.UserCacheDir
+	tempDir      = os.TempDir
+)
+
+// ConfigDir returns the base data directory, creating it if needed.
+//
+// It uses $FOCST_HOME when set (which must be an absolute path), otherwise
+// ~/.focst. When the home directory cannot be resolved or created (e.g. it is
+// read-only), it falls back to $XDG_CONFIG_HOME/focst and then to a private
+// directory under the system temp dir (see tempConfigDir), logging a warning.
+func ConfigDir() (string, error) {
+	if dir := os.Getenv(EnvHome); dir != "" {
+		if !filepath.IsAbs(dir) {
+			return "", fmt.Errorf("%s must be an absolute path, got %q", EnvHome, dir)
+		}
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", fmt.Errorf("failed to create %s directory: %w", EnvHome, err)
+		}
+		return dir, nil
+	}
+
+	home, err := userHomeDir()
+	if err == nil && home == "" {
+		err = fmt.Errorf("home directory is empty")
+	}
+	if err == nil {
+		dir := filepath.Join(home, ".focst")
+		if err = os.MkdirAll(dir, 0700); err == nil {
+			return dir, nil
+		}
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		dir := filepath.Join(xdg, "focst")
+		if mkErr := os.MkdirAll(dir, 0700); mkErr == nil {
+			logger.Warn("Home directory unavailable; using fallback data directory", "dir", dir, "error", err)
+			return dir, nil
+		}
+	}
+	if dir, mkErr := tempConfigDir(); mkErr == nil {
+		logger.Warn("Home directory unavailable; using fallback data directory", "dir", dir, "error", err)
+		return dir, nil
+	}
+	return "", fmt.Errorf("no writable data directory (set %s): %w", EnvHome, err)
+}
+
+// tempConfigDir returns <temp>/focst, which another user of a shared temp dir
+// may have created first. It is used only when it is a directory (not a
+// symlink) owned by the current user with mode 0700; otherwise a new private
+// directory is made with os.MkdirTemp.
+func tempConfigDir() (string, error) {
+	dir := filepath.Join(tempDir(), "focst")
+	if err := os.MkdirAll(dir, 0700); err == nil {
+		if info, err := os.Lstat(dir); err == nil && info.IsDir() && ownedPrivate(info) {
+			return dir, nil
+		}
+	}
+	return os.MkdirTemp(tempDir(), "focst-")
+}
+
+// NamesDir returns the directory holding name dictionaries, creating it if needed.
+func NamesDir() (string, error) {
+	base, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, "names")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create names directory: %w", err)
+	}
+	return dir, nil
+}
+
+// ResumeTokenPath returns where the GUI keeps the resume token of its
+// running translation (see pipeline.ResumeToken): ConfigDir()/resume.json.
+// The file itself is not created.
+func ResumeTokenPath() (string, error) {
+	base, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "resume.json"), nil
+}
+
+// CacheDir returns the directory for disposable caches. It is $FOCST_HOME/cache
+// when the override is set, otherwise the OS user cache dir, falling back to
+// ConfigDir()/cache when that is unavailable. The directory is not created.
+func CacheDir() (string, error) {
+	if os.Getenv(EnvHome) == "" {
+		if dir, err := userCacheDir(); err == nil && dir != "" {
+			return filepath.Join(dir, "focst"), nil
+		}
+	}
+	base, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "cache"), nil
+}
@@ -0,0 +1,147 @@
+package paths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func withHome(t *testing.T, home func() (string, error)) {
+	t.Helper()
+	prev := userHomeDir
+	userHomeDir = home
+	t.Cleanup(func() { userHomeDir = prev })
+}
+
+func TestConfigDir_EnvOverride(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "custom")
+	t.Setenv(EnvHome, dir)
+	withHome(t, func() (string, error) { t.Fatal("home lookup should be skipped"); return "", nil })
+
+	got, err := ConfigDir()
+	if err != nil || got != dir {
+		t.Fatalf("ConfigDir() = %q, %v, want %q", got, err, dir)
+	}
+	names, err := NamesDir()
+	if err != nil || names != filepath.Join(dir, "names") {
+		t.Fatalf("NamesDir() = %q, %v", names, err)
+	}
+	if info, err := os.Stat(names); err != nil || !info.IsDir() {
+		t.Fatalf("names dir not created: %v", err)
+	}
+	cache, err := CacheDir()
+	if err != nil || cache != filepath.Join(dir, "cache") {
+		t.Fatalf("CacheDir() = %q, %v", cache, err)
+	}
+	resume, err := ResumeTokenPath()
+	if err != nil || resume != filepath.Join(dir, "resume.json") {
+		t.Fatalf("ResumeTokenPath() = %q, %v", resume, err)
+	}
+}
+
+func TestConfigDir_RejectsRelativeOverride(t *testing.T) {
+	t.Setenv(EnvHome, "relative/dir")
+	if _, err := ConfigDir(); err == nil {
+		t.Fatalf("expected error for relative %s", EnvHome)
+	}
+}
+
+func TestConfigDir_DefaultHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(EnvHome, "")
+	withHome(t, func() (string, error) { return home, nil })
+
+	got, err := ConfigDir()
+	if err != nil || got != filepath.Join(home, ".focst") {
+		t.Fatalf("ConfigDir() = %q, %v", got, err)
+	}
+}
+
+func TestConfigDir_FallbackWhenHomeUnavailable(t *testing.T) {
+	t.Setenv(EnvHome, "")
+	withHome(t, func() (string, error) { return "", errors.New("no home") })
+
+	t.Run("xdg", func(t *testing.T) {
+		xdg := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", xdg)
+		got, err := ConfigDir()
+		if err != nil || got != filepath.Join(xdg, "focst") {
+			t.Fatalf("ConfigDir() = %q, %v", got, err)
+		}
+	})
+
+	t.Run("temp", func(t *testing.T) {
+		tmp := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", "")
+		prev := tempDir
+		tempDir = func() string { return tmp }
+		t.Cleanup(func() { tempDir = prev })
+		got, err := ConfigDir()
+		if err != nil || got != filepath.Join(tmp, "focst") {
+			t.Fatalf("ConfigDir() = %q, %v", got, err)
+		}
+	})
+}
+
+func TestConfigDir_FallbackWhenHomeReadOnly(t *testing.T) {
+	// A regular file where ~/.focst should live makes the directory uncreatable.
+	home := t.TempDir()
+	if err := os.WriteFile(filepath.Join(home, ".focst"), nil, 0600); err != nil {
+		t.Fatalf("write blocker: %v", err)
+	}
+	xdg := t.TempDir()
+	t.Setenv(EnvHome, "")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	withHome(t, func() (string, error) { return home, nil })
+
+	got, err := ConfigDir()
+	if err != nil || got != filepath.Join(xdg, "focst") {
+		t.Fatalf("ConfigDir() = %q, %v", got, err)
+	}
+}
+
+func TestConfigDir_TempFallbackRejectsForeignDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions and symlinks only")
+	}
+	t.Setenv(EnvHome, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	withHome(t, func() (string, error) { return "", errors.New("no home") })
+
+	cases := map[string]func(t *testing.T, dir string){
+		"open mode": func(t *testing.T, dir string) {
+			if err := os.Mkdir(dir, 0755); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			if err := os.Chmod(dir, 0755); err != nil {
+				t.Fatalf("chmod: %v", err)
+			}
+		},
+		"symlink": func(t *testing.T, dir string) {
+			if err := os.Symlink(t.TempDir(), dir); err != nil {
+				t.Fatalf("symlink: %v", err)
+			}
+		},
+	}
+	for name, plant := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmp := t.TempDir()
+			prev := tempDir
+			tempDir = func() string { return tmp }
+			t.Cleanup(func() { tempDir = prev })
+			shared := filepath.Join(tmp, "focst")
+			plant(t, shared)
+
+			got, err := ConfigDir()
+			if err != nil || got == shared || !strings.HasPrefix(got, filepath.Join(tmp, "focst-")) {
+				t.Fatalf("ConfigDir() = %q, %v, want a new private temp dir", got, err)
+			}
+			if info, err := os.Lstat(got); err != nil || !info.IsDir() || info.Mode().Perm() != 0700 {
+				t.Fatalf("fallback dir %q is not private: %v", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"unicode/utf8"
+
+	"github.com/oukeidos/focst/internal/logger"
+)
+
+// maxBackgroundBytes bounds the background information added to every
+// request's system prompt (about 1,000 tokens of English).
+const maxBackgroundBytes = 4 << 10
+
+// loadBackground reads the background information file at path for
+// Translator.SetBackground. A file over maxBackgroundBytes is cut at that
+// size, on a character boundary, with a warning.
+func loadBackground(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", inputErrorf("failed to read background file: %w", err)
+	}
+	if !utf8.Valid(data) {
+		return "", inputErrorf("background file is not valid UTF-8: %s", path)
+	}
+	if len(data) > maxBackgroundBytes {
+		cut := maxBackgroundBytes
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		logger.Warn("Background file truncated", "path", path, "size_bytes", len(data), "limit_bytes", maxBackgroundBytes)
+		data = data[:cut]
+	}
+	return string(data), nil
+}
+
+// backgroundHash identifies the background a run used, so repair can tell
+// when the file changed since.
+func backgroundHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/oukeidos/focst/internal/logger"
+)
+
+func TestSystemPrompt_IncludesBackground(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "background.txt")
+	if err := os.WriteFile(path, []byte("Taro and Hanako are siblings running an inn.\n"), 0600); err != nil {
+		t.Fatalf("write background: %v", err)
+	}
+	opts := DefaultOptions()
+	opts.SourceLang = "ja"
+	opts.TargetLang = "en"
+	opts.BackgroundPath = path
+	prompt, err := SystemPrompt(opts)
+	if err != nil {
+		t.Fatalf("SystemPrompt: %v", err)
+	}
+	if !strings.Contains(prompt, "BACKGROUND INFORMATION") || !strings.Contains(prompt, "Taro and Hanako are siblings running an inn.") {
+		t.Fatalf("background missing from prompt:\n%s", prompt)
+	}
+
+	opts.BackgroundPath = filepath.Join(t.TempDir(), "missing.txt")
+	if _, err := SystemPrompt(opts); err == nil {
+		t.Fatal("expected an error for a missing background file")
+	}
+}
+
+func TestLoadBackground_TruncatesOversizedFile(t *testing.T) {
+	var logs bytes.Buffer
+	logger.Init(logger.LevelInfo, &logs)
+	t.Cleanup(func() { logger.Init(logger.LevelInfo, nil) })
+
+	path := filepath.Join(t.TempDir(), "background.txt")
+	// Three-byte runes, so the limit falls inside one.
+	if err := os.WriteFile(path, []byte(strings.Repeat("あ", maxBackgroundBytes)), 0600); err != nil {
+		t.Fatalf("write background: %v", err)
+	}
+	text, err := loadBackground(path)
+	if err != nil {
+		t.Fatalf("loadBackground: %v", err)
+	}
+	if len(text) > maxBackgroundBytes || len(text) < maxBackgroundBytes-2 || !utf8.ValidString(text) {
+		t.Fatalf("truncated to %d bytes (valid UTF-8: %v), want a whole-rune cut at %d", len(text), utf8.ValidString(text), maxBackgroundBytes)
+	}
+	if !strings.Contains(logs.String(), "Background file truncated") {
+		t.Fatalf("expected a truncation warning, got logs:\n%s", logs.String())
+	}
+
+	logs.Reset()
+	if err := os.WriteFile(path, []byte("short"), 0600); err != nil {
+		t.Fatalf("write background: %v", err)
+	}
+	if text, err := loadBackground(path); err != nil || text != "short" {
+		t.Fatalf("loadBackground = %q, %v", text, err)
+	}
+	if strings.Contains(logs.String(), "truncated") {
+		t.Fatalf("unexpected truncation warning for a short file:\n%s", logs.String())
+	}
+}
@@ -2,7 +2,15 @@ package pipeline
 
 import (
 	"fmt"
+	"regexp"
+	"time"
 
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/httpclient"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/srt"
 	"github.com/oukeidos/focst/internal/translator"
 )
 
@@ -12,25 +20,230 @@ type Config struct {
 	InputPath  string
 	OutputPath string
 	LogPath    string // Optional: for JSONL logs in CLI or specific log file in GUI
+	// InputFormat and OutputFormat force the subtitle format ("srt", "vtt", ...)
+	// regardless of file extension. Empty infers it from the extension.
+	InputFormat  string
+	OutputFormat string
+	// InputEncoding forces the input's text encoding ("shift-jis", "cp949",
+	// ...). Empty detects it (see srt.DecodeText).
+	InputEncoding string
+	// InputData, when non-nil, is the input subtitle file already in memory
+	// (e.g. downloaded from a URL). InputPath then only labels it in logs,
+	// InputFormat is required, and no recovery log is written.
+	InputData []byte
 
 	// API Configuration
 	APIKey string
 	Model  string
+	// PolishModel, when set, runs a second pass with this model over every
+	// chunk the first pass translated, sending each first-pass translation
+	// as a draft to improve (see Translator.SetDraftMemory).
+	PolishModel string
+	// GeminiEndpoint overrides the Gemini API endpoint (http/https URL). Empty uses the default.
+	GeminiEndpoint string
+	// RequestTimeout bounds each Gemini API call. Zero uses httpclient.DefaultTimeout.
+	RequestTimeout time.Duration
+	// RampUp staggers worker starts over this window to avoid an initial burst
+	// (translator.DefaultRampUp is the usual value). Zero starts all workers at once.
+	RampUp time.Duration
+	// JitterMax bounds the random delay added to each retry backoff. Zero uses
+	// translator.DefaultJitterMax.
+	JitterMax time.Duration
+	// NoJitter makes retry backoff deterministic, overriding JitterMax.
+	NoJitter bool
+	// RelaxSafety lets a chunk the safety filters blocked be resent once with
+	// relaxed thresholds before it is split (see Translator.SetRelaxSafety).
+	RelaxSafety bool
+	// MaxSegmentsPerMinute caps how many segments are submitted per minute,
+	// on top of the request rate limit. Zero disables the cap.
+	MaxSegmentsPerMinute int
 
 	// Processing Parameters
-	ChunkSize        int
-	ContextSize      int
+	ChunkSize int
+	// AutoChunkSize replaces ChunkSize with language.SuggestedChunkSize for the
+	// language pair. Set it only when the user left the chunk size at its default.
+	AutoChunkSize bool
+	ContextSize   int
+	// ClampContext makes Normalize cap ContextSize at half of ChunkSize
+	// (see contextLimit). Without it an oversized context only produces a note.
+	ClampContext     bool
 	Concurrency      int
 	RetryOnLongLines bool
 	NoPromptCPL      bool
+	// CPLMetric selects how CPL validation measures lines ("graphemes" or
+	// "width"). Empty means graphemes; width only affects CJK targets.
+	CPLMetric string
+	// CPLTolerance multiplies the target CPL to get the line length that
+	// RetryOnLongLines rejects. Zero uses translator.DefaultCPLTolerance.
+	CPLTolerance float64
 
 	// Flags
 	NoPreprocess      bool
 	NoPostprocess     bool
 	Overwrite         bool // If true, overwrite output file without asking (CLI mostly)
+	InPlace           bool // If true, OutputPath is the input, which is copied to InPlaceBackupPath before it is replaced
 	ForceRepair       bool // If true, ignore unusable existing output during repair
+	MergeOutput       bool // If true, repair overwrites only failed chunks of the existing output (see recovery.OutputMerge)
+	RestoreCached     bool // If true, repair takes passed chunks from the chunk cache instead of the existing output (see recovery.OutputRestoreCache)
+	BackupOutput      bool // If true, repair copies an existing output to <output>.bak before overwriting
+	MakeOutputDir     bool // If true, create a missing output directory instead of failing before translation
 	NoLangPreprocess  bool
 	NoLangPostprocess bool
+	// NoBracketRemoval, NoAngleStrip, and NoMeaninglessFilter skip single
+	// Japanese preprocessing steps (see srt.PreprocessOptions) while the rest
+	// still run.
+	NoBracketRemoval    bool
+	NoAngleStrip        bool
+	NoMeaninglessFilter bool
+	// RepairMaxAge makes repair refuse a session log older than this, since
+	// the model or input may have changed since the run. 0 disables the check.
+	RepairMaxAge time.Duration
+	// ForceStaleRepair repairs a log older than RepairMaxAge anyway, with a warning.
+	ForceStaleRepair bool
+	// NoTimingCorrection keeps source timing while still applying punctuation cleanup.
+	NoTimingCorrection bool
+	// NormalizeQuotes replaces straight quotes with the target language's
+	// quotation marks during post-processing. NoLangPostprocess skips it.
+	NormalizeQuotes bool
+	// OnEmptied selects what post-processing does with a segment that
+	// punctuation cleanup leaves without lines ("keep" or "drop"). Empty means
+	// keep, which restores the text from before cleanup.
+	OnEmptied string
+	// PostprocessLang runs another language's punctuation rules during
+	// post-processing instead of the target's (e.g. "zh-Hant" punctuation for a
+	// "zh-Hans" target). It must be a language with cleanup rules. Empty uses
+	// the target language.
+	PostprocessLang string
+	// CompatProfile applies a player compatibility profile's formatting quirks
+	// to SRT and WebVTT output (see srt.CompatProfile). Empty writes the
+	// serializer's output as is.
+	CompatProfile string
+	// SavePartialOnFailure writes the output even on Failure status
+	// (failed chunks keep their source text) so it can be inspected or repaired.
+	SavePartialOnFailure bool
+	// FilterRegex and ForcedOnly restrict translation to matching segments;
+	// all other segments pass through unchanged.
+	FilterRegex string
+	ForcedOnly  bool
+	// ExcludeIDs lists segment IDs and inclusive ranges (e.g. "10-20,45") that
+	// are kept verbatim, text and timing, while the rest is translated. Chunks
+	// are formed over the remaining segments, so an excluded cue inside a chunk
+	// is skipped rather than splitting it, and excluded cues are not sent as
+	// context either.
+	ExcludeIDs string
+	// NoSkipNonTranslatable sends pure numbers, URLs, and product codes to the
+	// model instead of passing them through verbatim (see srt.IsNonTranslatable).
+	NoSkipNonTranslatable bool
+	// AllowSameLang copies subtitles through (with pre/post-processing) instead of
+	// failing when the source and target languages are the same.
+	AllowSameLang bool
+	// AllowNoDialogue accepts files without dialogue text (e.g. only "♪" cues)
+	// and copies them through with post-processing instead of failing validation.
+	AllowNoDialogue bool
+	// ChunkCache stores each completed chunk under the output's chunk cache
+	// directory so an interrupted run (or repair) reuses it instead of re-translating.
+	ChunkCache bool
+	// Resume, when set, records every completed chunk in the token and reuses
+	// the chunks it already holds (see ResumeToken). The GUI sets it.
+	Resume *ResumeToken
+	// DumpFailed writes the source text of failed chunks next to the session
+	// log (see recovery.FailedSourcePath) for inspection or manual
+	// translation. Repair rewrites it for the chunks still failed.
+	DumpFailed bool
+	// MaxCost stops the run once the estimated spend (USD) reaches this cap,
+	// keeping completed chunks as partial output. 0 disables the cap.
+	MaxCost float64
+	// MaxSegments and ConfirmOverCost guard against expensive accidents: when
+	// the run would translate more segments, or its pre-run cost estimate
+	// (USD) is higher, OnConfirmLargeRun must approve it first. 0 disables
+	// each check.
+	MaxSegments     int
+	ConfirmOverCost float64
+	// PriorityFirst streams the translated front of the file, in order and
+	// without post-processing, to a ".partial" sidecar next to the output
+	// while the run goes on (see translator.SetPriorityFirst). The sidecar is
+	// removed once the final output is saved.
+	PriorityFirst bool
+	// StallTimeout logs a warning whenever this long passes without a chunk
+	// completing, e.g. when every worker waits on a hung API call. 0 disables
+	// the watchdog.
+	StallTimeout time.Duration
+	// CancelOnStall makes a StallTimeout cancel the run instead; chunks not yet
+	// done are recorded as failed for repair.
+	CancelOnStall bool
+	// EmbedMetadata writes a provenance comment block (model, languages, date,
+	// focst version, settings hash) into VTT and ASS/SSA outputs.
+	EmbedMetadata bool
+	// KeepCueSettings writes WebVTT cue settings (align, line, position, size,
+	// vertical) from the source back onto translated cues. It only applies when
+	// both input and output are .vtt.
+	KeepCueSettings bool
+	// KeepDialogueDashes strips leading speaker dashes ("- ", "—") before
+	// translation and re-applies the source's dashes to the result.
+	KeepDialogueDashes bool
+	// BackgroundPath is a text file of background information (a synopsis,
+	// character notes) added to the system prompt, cut at 4 KiB.
+	BackgroundPath string
+	// OnEmpty selects what happens when a segment comes back empty ("fail" or
+	// "keep-source"). Empty means fail, which retries the whole chunk.
+	OnEmpty string
+	// Formality fixes how the translation addresses people in languages with
+	// formal and informal "you" ("formal" or "informal"). Empty or "auto"
+	// leaves the choice to the model.
+	Formality string
+	// NarrativeTag selects on-screen text segments ("forced", "positioned",
+	// or "bracketed"; see srt.NarrativeTag) to translate in a literal register
+	// apart from dialogue. Empty disables the routing.
+	NarrativeTag string
+	// SingleLine limits every translated segment to one line: the prompt
+	// forbids a second line and any returned one is joined onto the first
+	// before the CPL check.
+	SingleLine bool
+	// TranslateNotes sends WebVTT NOTE blocks to the model after the cues.
+	// Otherwise they are kept verbatim.
+	TranslateNotes bool
+	// AutoLinebreak splits a one-line translation longer than the target CPL
+	// into two lines at a space or clause punctuation, before the CPL check.
+	// SingleLine takes precedence.
+	AutoLinebreak bool
+	// DedupRepeats sends each repeated source line to the model once and reuses
+	// its translation for every identical cue (timing stays per cue).
+	DedupRepeats bool
+	// ImproveDrafts sends target-language lines already in a segment to the
+	// model as a draft to improve (see Translator.SetImproveDrafts). The
+	// source and target must use different scripts.
+	ImproveDrafts bool
+	// FailFast stops the run with an error at the first chunk failure that
+	// cannot be retried, instead of translating the rest and collecting failures.
+	FailFast bool
+	// Sample, when positive, translates only the chunks covering the first
+	// Sample segments and writes them to SampleOutputPath(OutputPath) for a
+	// quick quality check. No recovery log is written for a sample run.
+	Sample int
+	// TermMemoryPath, when set, loads remembered phrase choices into the prompt
+	// and records this file's short lines back, keeping a series consistent.
+	TermMemoryPath string
+	// KeepLog writes the session log after a fully successful run too, for
+	// audits and re-runs. Repair rejects such logs (no failed chunks).
+	KeepLog bool
+	// GlossaryReportPath, when set, writes the names-mapping adherence report
+	// (see GlossaryReport) as JSON after a successful run.
+	GlossaryReportPath string
+	// FailureReportPath, when set, writes the failed chunks of the run with
+	// the kind of their last error (see FailureReport) as JSON. The report is
+	// empty when nothing failed.
+	FailureReportPath string
+	// DumpIDMap, when set, writes the mapping between internal segment IDs
+	// and the input's cue numbers (see srt.MarshalIDMap) after preprocessing.
+	// Without preprocessing the mapping is the identity.
+	DumpIDMap string
+	// TMXPath, when set, exports the run's source and translated segments
+	// as a TMX 1.4 translation memory after a successful run.
+	TMXPath string
+	// TMXImportPath, when set, loads a TMX translation memory and sends the
+	// translation of each segment whose source it holds as a draft (see
+	// Translator.SetDraftMemory).
+	TMXImportPath string
 
 	// Languages
 	SourceLang string
@@ -44,10 +257,23 @@ type Config struct {
 	// OnProgress is called with translation progress updates.
 	OnProgress func(translator.TranslationProgress)
 
+	// OnEstimate is called after each completed chunk with the projected
+	// remaining time and total cost of the run.
+	OnEstimate func(Estimate)
+
+	// OnRepairProgress is called during repair with the original chunk index and
+	// a running repaired count. If nil, repair falls back to OnProgress.
+	OnRepairProgress func(recovery.RepairProgress)
+
 	// OnConfirmOverwrite is called when the output file exists.
 	// It should return true if the file should be overwritten.
 	// If nil, it assumes Overwrite flag accounts for it or it's already checked.
 	OnConfirmOverwrite func(path string) bool
+
+	// OnConfirmLargeRun is called when MaxSegments or ConfirmOverCost is
+	// exceeded, with a description of why. It returns true to proceed; an
+	// error (e.g. no terminal to ask) fails the run. If nil, the run fails.
+	OnConfirmLargeRun func(reason string) (bool, error)
 }
 
 const (
@@ -57,6 +283,19 @@ const (
 	MaxContextSize = 20
 )
 
+// contextLimit is the largest context size Normalize accepts for chunkSize
+// without a note: half the chunk, rounded up. Context is sent on both sides of
+// every chunk, so at this limit a request is at most half context.
+func contextLimit(chunkSize int) int {
+	return (chunkSize + 1) / 2
+}
+
+// smallCPL is the largest target CPL at which Normalize notes a chunk size
+// above the language pair's suggestion under CPL validation. At CJK line
+// lengths a few characters over the limit already fail a line, and one such
+// line sends the whole chunk back.
+const smallCPL = 16
+
 func ClampConcurrency(value int) (int, bool) {
 	if value < MinConcurrency {
 		return MinConcurrency, true
@@ -82,9 +321,44 @@ func (c Config) Normalize() (Config, []string) {
 		notes = append(notes, fmt.Sprintf("context-size clamped from %d to %d (max %d)", c.ContextSize, MaxContextSize, MaxContextSize))
 		c.ContextSize = MaxContextSize
 	}
+	if limit := contextLimit(c.ChunkSize); c.ChunkSize > 0 && c.ContextSize > limit {
+		if c.ClampContext {
+			notes = append(notes, fmt.Sprintf("context-size clamped from %d to %d (half of chunk-size %d)", c.ContextSize, limit, c.ChunkSize))
+			c.ContextSize = limit
+		} else {
+			notes = append(notes, fmt.Sprintf("context-size %d is large for chunk-size %d: each request sends up to %d context segments with %d to translate (clamp-context caps it at %d)",
+				c.ContextSize, c.ChunkSize, 2*c.ContextSize, c.ChunkSize, limit))
+		}
+	}
+	if c.AutoLinebreak && c.SingleLine {
+		notes = append(notes, "auto-linebreak has no effect with single-line, which keeps every subtitle on one line")
+	}
+	notes = append(notes, c.cplNotes()...)
 	return c, notes
 }
 
+// cplNotes warns about CPL settings that work against each other. They are
+// left unchanged, since either side may be what the user wants.
+func (c Config) cplNotes() []string {
+	if !c.RetryOnLongLines {
+		return nil
+	}
+	var notes []string
+	if c.NoPromptCPL {
+		notes = append(notes, "retry-on-long-line checks the line limit but no-prompt-cpl keeps it out of the prompt: the model is never told the limit, so retries for long lines are likely to fail again (drop one of the two)")
+	}
+	tgt, ok := language.GetLanguage(c.TargetLang)
+	if !ok || c.AutoChunkSize || tgt.DefaultCPL > smallCPL {
+		return notes
+	}
+	src, _ := language.GetLanguage(c.SourceLang)
+	if suggested := language.SuggestedChunkSize(src.Code, tgt.Code); c.ChunkSize > suggested {
+		notes = append(notes, fmt.Sprintf("retry-on-long-line with chunk-size %d for %s (%d characters per line): one long line retries the whole chunk, so large chunks waste attempts (suggested chunk-size %d or less)",
+			c.ChunkSize, tgt.Code, tgt.DefaultCPL, suggested))
+	}
+	return notes
+}
+
 // Validate checks if the configuration is valid.
 func (c Config) Validate() error {
 	if c.ChunkSize <= 0 {
@@ -99,14 +373,229 @@ func (c Config) Validate() error {
 	if c.APIKey == "" {
 		return fmt.Errorf("API key is required")
 	}
+	if err := c.validateEndpoint(); err != nil {
+		return err
+	}
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("requestTimeout must be 0 or greater, got %s", c.RequestTimeout)
+	}
+	if c.RampUp < 0 {
+		return fmt.Errorf("rampUp must be 0 or greater, got %s", c.RampUp)
+	}
+	if c.JitterMax < 0 {
+		return fmt.Errorf("jitterMax must be 0 or greater, got %s", c.JitterMax)
+	}
+	if c.MaxSegmentsPerMinute < 0 {
+		return fmt.Errorf("maxSegmentsPerMinute must be 0 or greater, got %d", c.MaxSegmentsPerMinute)
+	}
+	if c.StallTimeout < 0 {
+		return fmt.Errorf("stallTimeout must be 0 or greater, got %s", c.StallTimeout)
+	}
+	if c.MaxCost < 0 {
+		return fmt.Errorf("maxCost must be 0 or greater, got %.2f", c.MaxCost)
+	}
+	if c.MaxSegments < 0 {
+		return fmt.Errorf("maxSegments must be 0 or greater, got %d", c.MaxSegments)
+	}
+	if c.ConfirmOverCost < 0 {
+		return fmt.Errorf("confirmOverCost must be 0 or greater, got %.2f", c.ConfirmOverCost)
+	}
+	if c.Sample < 0 {
+		return fmt.Errorf("sample must be 0 or greater, got %d", c.Sample)
+	}
+	if _, err := translator.ParseCPLMetric(c.CPLMetric); err != nil {
+		return err
+	}
+	if c.CPLTolerance != 0 {
+		if err := translator.ValidateCPLTolerance(c.CPLTolerance); err != nil {
+			return err
+		}
+	}
+	if _, err := translator.ParseEmptyPolicy(c.OnEmpty); err != nil {
+		return err
+	}
+	if _, err := srt.ParseEmptiedPolicy(c.OnEmptied); err != nil {
+		return err
+	}
+	if _, err := translator.ParseFormality(c.Formality); err != nil {
+		return err
+	}
+	if _, err := srt.ParseNarrativeTag(c.NarrativeTag); err != nil {
+		return err
+	}
+	if c.FilterRegex != "" {
+		if _, err := regexp.Compile(c.FilterRegex); err != nil {
+			return fmt.Errorf("invalid filter regex: %w", err)
+		}
+	}
+	if _, err := srt.ParseCompatProfile(c.CompatProfile); err != nil {
+		return err
+	}
+	if c.PostprocessLang != "" {
+		lang, ok := language.GetLanguage(c.PostprocessLang)
+		if !ok {
+			return fmt.Errorf("unsupported post-processing language: %s", c.PostprocessLang)
+		}
+		if !srt.HasPunctuationCleanup(lang.Code) {
+			return fmt.Errorf("no post-processing rules for %s (languages with rules: ko, ja, zh-Hans, zh-Hant)", c.PostprocessLang)
+		}
+	}
+	if c.ExcludeIDs != "" {
+		if _, err := srt.ParseIDRanges(c.ExcludeIDs); err != nil {
+			return err
+		}
+	}
+	if c.InputData != nil && c.InputFormat == "" {
+		return fmt.Errorf("input format is required for in-memory input")
+	}
+	if c.InPlace && c.InputData != nil {
+		return fmt.Errorf("in-place mode needs a local input file")
+	}
+	if _, err := srt.ParseFormat(c.InputFormat); err != nil {
+		return fmt.Errorf("invalid input format: %w", err)
+	}
+	if _, err := srt.ParseFormat(c.OutputFormat); err != nil {
+		return fmt.Errorf("invalid output format: %w", err)
+	}
+	if _, err := srt.ParseEncoding(c.InputEncoding); err != nil {
+		return fmt.Errorf("invalid input encoding: %w", err)
+	}
 	return nil
 }
 
+// resolveChunkSize returns the chunk size to use for a source/target pair and
+// whether it differs from c.ChunkSize because of AutoChunkSize.
+func (c Config) resolveChunkSize(sourceCode, targetCode string) (int, bool) {
+	if !c.AutoChunkSize {
+		return c.ChunkSize, false
+	}
+	suggested := language.SuggestedChunkSize(sourceCode, targetCode)
+	return suggested, suggested != c.ChunkSize
+}
+
+// preprocessOptions returns the preprocessing steps selected by c.
+func (c Config) preprocessOptions() srt.PreprocessOptions {
+	return srt.PreprocessOptions{
+		NoLangRules:         c.NoLangPreprocess,
+		NoBracketRemoval:    c.NoBracketRemoval,
+		NoAngleStrip:        c.NoAngleStrip,
+		NoMeaninglessFilter: c.NoMeaninglessFilter,
+	}
+}
+
+// keepCueSettings reports whether cue settings should be written back,
+// logging when the option is set but the input or output is not WebVTT.
+func (c Config) keepCueSettings(outputPath string) bool {
+	if !c.KeepCueSettings {
+		return false
+	}
+	if srt.FormatExt(c.InputPath, c.InputFormat) != ".vtt" || srt.FormatExt(outputPath, c.OutputFormat) != ".vtt" {
+		logger.Info("Cue settings not kept: input and output must both be WebVTT", "input", c.InputPath, "output", outputPath)
+		return false
+	}
+	return true
+}
+
+// HasSegmentFilter reports whether only a subset of segments should be translated.
+func (c Config) HasSegmentFilter() bool {
+	return c.FilterRegex != "" || c.ForcedOnly
+}
+
+// postprocessLangCode returns the canonical code of PostprocessLang, or ""
+// when it is unset.
+func (c Config) postprocessLangCode() string {
+	if c.PostprocessLang == "" {
+		return ""
+	}
+	lang, _ := language.GetLanguage(c.PostprocessLang) // validated by Validate
+	return lang.Code
+}
+
+// excludeRanges returns the parsed ExcludeIDs, or nil when none are set.
+func (c Config) excludeRanges() []srt.IDRange {
+	if c.ExcludeIDs == "" {
+		return nil
+	}
+	ranges, _ := srt.ParseIDRanges(c.ExcludeIDs) // validated by Validate
+	return ranges
+}
+
+// repairOutputPolicy returns how repair treats the existing output.
+func (c Config) repairOutputPolicy() recovery.OutputPolicy {
+	switch {
+	case c.ForceRepair:
+		return recovery.OutputForce
+	case c.MergeOutput:
+		return recovery.OutputMerge
+	case c.RestoreCached:
+		return recovery.OutputRestoreCache
+	default:
+		return recovery.OutputReuse
+	}
+}
+
+// retryJitter returns the jitter bound to give the translator.
+func (c Config) retryJitter() time.Duration {
+	if c.NoJitter {
+		return 0
+	}
+	if c.JitterMax > 0 {
+		return c.JitterMax
+	}
+	return translator.DefaultJitterMax
+}
+
 // ValidateRepairRuntime checks only runtime config required for repair.
 // Log-derived settings (chunk/concurrency/context/model/lang) are validated on the session log.
 func (c Config) ValidateRepairRuntime() error {
 	if c.APIKey == "" {
 		return fmt.Errorf("API key is required")
 	}
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("requestTimeout must be 0 or greater, got %s", c.RequestTimeout)
+	}
+	if c.RampUp < 0 {
+		return fmt.Errorf("rampUp must be 0 or greater, got %s", c.RampUp)
+	}
+	if c.JitterMax < 0 {
+		return fmt.Errorf("jitterMax must be 0 or greater, got %s", c.JitterMax)
+	}
+	if c.MaxSegmentsPerMinute < 0 {
+		return fmt.Errorf("maxSegmentsPerMinute must be 0 or greater, got %d", c.MaxSegmentsPerMinute)
+	}
+	if c.MergeOutput && c.ForceRepair {
+		return fmt.Errorf("mergeOutput cannot be combined with forceRepair")
+	}
+	if c.RestoreCached && (c.MergeOutput || c.ForceRepair) {
+		return fmt.Errorf("restoreCached cannot be combined with mergeOutput or forceRepair")
+	}
+	if c.RepairMaxAge < 0 {
+		return fmt.Errorf("repairMaxAge must be 0 or greater, got %s", c.RepairMaxAge)
+	}
+	return c.validateEndpoint()
+}
+
+func (c Config) geminiClientOptions() gemini.ClientOptions {
+	return gemini.ClientOptions{Endpoint: c.GeminiEndpoint, RequestTimeout: c.RequestTimeout}
+}
+
+func (c Config) validateEndpoint() error {
+	if c.GeminiEndpoint == "" {
+		return nil
+	}
+	if _, err := httpclient.ValidateBaseURL(c.GeminiEndpoint); err != nil {
+		return fmt.Errorf("invalid Gemini endpoint: %w", err)
+	}
 	return nil
 }
+
+// logGeminiEndpoint logs only the host of a custom endpoint so paths or
+// query strings carrying gateway tokens stay out of the logs.
+func logGeminiEndpoint(endpoint string) {
+	if endpoint == "" {
+		return
+	}
+	if u, err := httpclient.ValidateBaseURL(endpoint); err == nil {
+		logger.Info("Using custom Gemini endpoint", "host", u.Host)
+	}
+}
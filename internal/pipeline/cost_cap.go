@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/metadata"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// costGuard cancels a translation once its estimated spend reaches a cap.
+type costGuard struct {
+	model  string
+	limit  float64
+	usage  func() gemini.UsageMetadata
+	cancel context.CancelFunc
+
+	mu  sync.Mutex
+	hit bool
+}
+
+func newCostGuard(model string, limit float64, usage func() gemini.UsageMetadata, cancel context.CancelFunc) *costGuard {
+	return &costGuard{model: model, limit: limit, usage: usage, cancel: cancel}
+}
+
+// wrap returns a progress callback that checks the running cost after every
+// event before forwarding it to next.
+func (g *costGuard) wrap(next func(translator.TranslationProgress)) func(translator.TranslationProgress) {
+	return func(p translator.TranslationProgress) {
+		g.check()
+		if next != nil {
+			next(p)
+		}
+	}
+}
+
+func (g *costGuard) check() {
+	u := g.usage()
+	cost := metadata.EstimateGeminiCost(g.model, u.PromptTokenCount, u.CandidatesTokenCount, u.TotalTokenCount)
+	if cost < g.limit {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.hit {
+		return
+	}
+	g.hit = true
+	logger.Warn("Cost cap reached; stopping translation", "estimated_cost", cost, "max_cost", g.limit)
+	g.cancel()
+}
+
+// exceeded reports whether the cap was reached during the run.
+func (g *costGuard) exceeded() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.hit
+}
@@ -0,0 +1,27 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InputError marks a failure caused by what the user supplied (subtitle or
+// recovery files, languages, configuration) rather than by the API or the
+// environment. Its message is the wrapped error's, unchanged.
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string { return e.Err.Error() }
+
+func (e *InputError) Unwrap() error { return e.Err }
+
+// IsInputError reports whether err wraps an *InputError.
+func IsInputError(err error) bool {
+	var e *InputError
+	return errors.As(err, &e)
+}
+
+func inputErrorf(format string, args ...any) error {
+	return &InputError{Err: fmt.Errorf(format, args...)}
+}
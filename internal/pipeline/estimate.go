@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"github.com/oukeidos/focst/internal/metadata"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// Estimate projects the time and spend of a running translation from the
+// throughput observed so far.
+type Estimate struct {
+	translator.Throughput
+	// ProjectedCost prices Throughput.ProjectedUsage for the run's model.
+	ProjectedCost float64
+}
+
+func newEstimate(model string, tp translator.Throughput) Estimate {
+	u := tp.ProjectedUsage
+	return Estimate{
+		Throughput:    tp,
+		ProjectedCost: metadata.EstimateGeminiCost(model, u.PromptTokenCount, u.CandidatesTokenCount, u.TotalTokenCount),
+	}
+}
+
+// wrapEstimate returns a progress callback that reports a fresh Estimate to
+// onEstimate whenever a chunk completes, before forwarding the event to next.
+func wrapEstimate(model string, throughput func() translator.Throughput, onEstimate func(Estimate), next func(translator.TranslationProgress)) func(translator.TranslationProgress) {
+	return func(p translator.TranslationProgress) {
+		if p.State == translator.StateCompleted {
+			onEstimate(newEstimate(model, throughput()))
+		}
+		if next != nil {
+			next(p)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"os"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// dumpFailedSource writes the source text of the failed chunks next to the
+// session log at logPath. selected is the translated subset of segments, nil
+// when every segment is translated, and spans the run's chunk layout over
+// it. Failures only warn: the session log is what repair needs.
+func dumpFailedSource(logPath string, segments []srt.Segment, selected []int, spans []chunker.Span, failed []int) {
+	path := recovery.FailedSourcePath(logPath)
+	if err := files.RejectSymlinkPath(path); err != nil {
+		logger.Warn("Failed chunk dump skipped", "path", path, "error", err)
+		return
+	}
+	work := segments
+	if selected != nil {
+		work = make([]srt.Segment, len(selected))
+		for k, idx := range selected {
+			work[k] = segments[idx]
+		}
+	}
+	if err := recovery.WriteFailedSource(path, work, spans, failed); err != nil {
+		logger.Warn("Failed to write failed chunk dump", "path", path, "error", err)
+		return
+	}
+	logger.Info("Wrote source text of failed chunks", "path", path, "chunks", len(failed))
+}
+
+// removeFailedSource deletes a dump left by an earlier run once it no longer
+// matches the session log.
+func removeFailedSource(logPath string) {
+	path := recovery.FailedSourcePath(logPath)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logger.Warn("Failed to remove failed chunk dump", "path", path, "error", err)
+	}
+}
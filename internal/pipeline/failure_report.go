@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// Failure actions tell the user what to do about a failed chunk.
+const (
+	// FailureActionRepair means the failure may pass on another attempt, so
+	// running repair is worthwhile.
+	FailureActionRepair = "repair"
+	// FailureActionFixConfig means the request itself was refused (bad key,
+	// rejected request); repairing without changing the setup fails again.
+	FailureActionFixConfig = "fix_config"
+	// FailureActionRelaxSafety means the safety filters blocked the chunk;
+	// repair with --relax-safety or edit the source text.
+	FailureActionRelaxSafety = "relax_safety"
+)
+
+// Failure kinds for chunks whose last error has no apperrors kind.
+const (
+	failureKindCanceled = "canceled"
+	failureKindUnknown  = "unknown"
+)
+
+// FailureEntry reports why one chunk failed.
+type FailureEntry struct {
+	// Chunk is the chunk index used by the session log and repair.
+	Chunk int `json:"chunk"`
+	// FirstID and LastID are the IDs of the first and last segment in the chunk.
+	FirstID int `json:"first_segment_id"`
+	LastID  int `json:"last_segment_id"`
+	// Kind is the apperrors kind of the last error (e.g. "rate_limit",
+	// "auth"), "canceled" for a chunk the run stopped before finishing, or
+	// "unknown".
+	Kind     string `json:"kind"`
+	Message  string `json:"message,omitempty"`
+	Attempts int    `json:"attempts,omitempty"`
+	// Action is one of the FailureAction constants.
+	Action string `json:"action"`
+}
+
+// FailureReport lists the failed chunks of a run, in chunk order.
+type FailureReport struct {
+	Chunks []FailureEntry `json:"failed_chunks"`
+}
+
+// NeedsConfigFix reports whether any chunk failed in a way repair cannot fix.
+func (r FailureReport) NeedsConfigFix() bool {
+	for _, e := range r.Chunks {
+		if e.Action == FailureActionFixConfig {
+			return true
+		}
+	}
+	return false
+}
+
+// buildFailureReport describes each failed chunk using the last errors the
+// translator kept. Chunks are numbered over the selected subset when
+// selected is non-nil, as in TranslateSubset, and spans is the run's chunk
+// layout over them (see Translator.ChunkSpans). Failed chunks without a
+// recorded error did not finish before the run was canceled.
+func buildFailureReport(segments []srt.Segment, selected []int, spans []chunker.Span, failed []int, failures []translator.ChunkFailure) FailureReport {
+	byChunk := make(map[int]translator.ChunkFailure, len(failures))
+	for _, f := range failures {
+		byChunk[f.ChunkIndex] = f
+	}
+	segmentID := func(pos int) int {
+		if selected != nil {
+			pos = selected[pos]
+		}
+		return segments[pos].ID
+	}
+	report := FailureReport{Chunks: make([]FailureEntry, 0, len(failed))}
+	for _, idx := range failed {
+		if idx < 0 || idx >= len(spans) || spans[idx].Len == 0 {
+			continue
+		}
+		start := spans[idx].Offset
+		end := start + spans[idx].Len - 1
+		entry := FailureEntry{
+			Chunk:   idx,
+			FirstID: segmentID(start),
+			LastID:  segmentID(end),
+			Kind:    failureKindCanceled,
+			Action:  FailureActionRepair,
+		}
+		if f, ok := byChunk[idx]; ok {
+			entry.Kind = string(f.Kind)
+			if entry.Kind == "" {
+				entry.Kind = failureKindUnknown
+			}
+			entry.Message = f.Message
+			entry.Attempts = f.Attempts
+			entry.Action = failureAction(f.Kind)
+		}
+		report.Chunks = append(report.Chunks, entry)
+	}
+	return report
+}
+
+func failureAction(kind apperrors.Kind) string {
+	switch kind {
+	case apperrors.KindAuth, apperrors.KindBadRequest:
+		return FailureActionFixConfig
+	case apperrors.KindSafetyBlock:
+		return FailureActionRelaxSafety
+	default:
+		return FailureActionRepair
+	}
+}
+
+func saveFailureReport(path string, report FailureReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode failure report: %w", err)
+	}
+	if err := files.AtomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save failure report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+func TestBuildFailureReport(t *testing.T) {
+	segments := make([]srt.Segment, 7)
+	for i := range segments {
+		segments[i] = srt.Segment{ID: i + 1}
+	}
+	// Chunks of uneven size over the selected subset: [2] [3 5 6] [7].
+	selected := []int{1, 2, 4, 5, 6}
+	spans := []chunker.Span{{Offset: 0, Len: 1}, {Offset: 1, Len: 3}, {Offset: 4, Len: 1}}
+	failures := []translator.ChunkFailure{
+		{ChunkIndex: 0, Kind: apperrors.KindRateLimit, Message: "Rate limit exceeded.", Attempts: 3},
+		{ChunkIndex: 2, Kind: apperrors.KindAuth, Message: "Authentication failed.", Attempts: 1},
+	}
+	report := buildFailureReport(segments, selected, spans, []int{0, 1, 2}, failures)
+	want := []FailureEntry{
+		{Chunk: 0, FirstID: 2, LastID: 2, Kind: "rate_limit", Message: "Rate limit exceeded.", Attempts: 3, Action: FailureActionRepair},
+		{Chunk: 1, FirstID: 3, LastID: 6, Kind: "canceled", Action: FailureActionRepair},
+		{Chunk: 2, FirstID: 7, LastID: 7, Kind: "auth", Message: "Authentication failed.", Attempts: 1, Action: FailureActionFixConfig},
+	}
+	if !reflect.DeepEqual(report.Chunks, want) {
+		t.Fatalf("report = %+v\nwant %+v", report.Chunks, want)
+	}
+	if !report.NeedsConfigFix() {
+		t.Fatal("NeedsConfigFix() = false with an auth failure")
+	}
+}
+
+func TestRunTranslation_FailureReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	reportPath := filepath.Join(tmpDir, "failures.json")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nRefused\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nBlocked\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	errs := map[string]error{
+		"Refused": apperrors.BadRequest(fmt.Errorf("400 invalid argument")),
+		"Blocked": apperrors.SafetyBlock(fmt.Errorf("no candidates")),
+	}
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			if err, ok := errs[req.Target[0].Lines[0]]; ok {
+				return nil, err
+			}
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: req.Target[0].ID, Line1: "안녕"}}}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:         inPath,
+		OutputPath:        outPath,
+		APIKey:            "test",
+		Model:             "m",
+		ChunkSize:         1,
+		Concurrency:       1,
+		SourceLang:        "en",
+		TargetLang:        "ko",
+		NoPreprocess:      true,
+		FailureReportPath: reportPath,
+	}
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if result.Status != TranslationStatusPartialSuccess || result.Failures == nil {
+		t.Fatalf("status = %s, failures = %v", result.Status, result.Failures)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var saved FailureReport
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if !reflect.DeepEqual(saved, *result.Failures) {
+		t.Fatalf("saved report = %+v, result = %+v", saved, *result.Failures)
+	}
+	if len(saved.Chunks) != 2 {
+		t.Fatalf("report = %+v, want 2 failed chunks", saved.Chunks)
+	}
+	if e := saved.Chunks[0]; e.Chunk != 1 || e.FirstID != 2 || e.Kind != "bad_request" || e.Action != FailureActionFixConfig {
+		t.Errorf("first entry = %+v", e)
+	}
+	if e := saved.Chunks[1]; e.Chunk != 2 || e.FirstID != 3 || e.Kind != "safety_block" || e.Action != FailureActionRelaxSafety {
+		t.Errorf("second entry = %+v", e)
+	}
+}
@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// GlossaryEntry reports how one names-mapping entry was honored.
+type GlossaryEntry struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	// Occurrences counts translated segments whose source text contains Source.
+	Occurrences int `json:"occurrences"`
+	// Honored counts those whose translation contains Target.
+	Honored int `json:"honored"`
+	// Missed lists the IDs of segments whose translation lacks Target.
+	Missed []int `json:"missed_segment_ids,omitempty"`
+}
+
+// GlossaryReport is an advisory check of names-mapping adherence across a run.
+type GlossaryReport struct {
+	Entries []GlossaryEntry `json:"entries"`
+}
+
+// Used counts entries whose source term appears in the translated text.
+func (r GlossaryReport) Used() int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Occurrences > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// FullyApplied counts used entries honored in every segment.
+func (r GlossaryReport) FullyApplied() int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Occurrences > 0 && e.Honored == e.Occurrences {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary renders the report as e.g. "3/5 mappings fully applied".
+func (r GlossaryReport) Summary() string {
+	return fmt.Sprintf("%d/%d mappings fully applied", r.FullyApplied(), r.Used())
+}
+
+// checkGlossary compares each mapping against the source and translated
+// segments. Only selected segments (all when selected is nil) are checked,
+// since the rest were passed through untranslated.
+func checkGlossary(source, translated []srt.Segment, selected []int, mapping map[string]string) GlossaryReport {
+	indices := selected
+	if indices == nil {
+		indices = make([]int, len(source))
+		for i := range source {
+			indices[i] = i
+		}
+	}
+	report := GlossaryReport{Entries: make([]GlossaryEntry, 0, len(mapping))}
+	for src, tgt := range mapping {
+		if src == "" {
+			continue
+		}
+		entry := GlossaryEntry{Source: src, Target: tgt}
+		for _, idx := range indices {
+			if idx >= len(translated) || !strings.Contains(strings.Join(source[idx].Lines, "\n"), src) {
+				continue
+			}
+			entry.Occurrences++
+			if strings.Contains(strings.Join(translated[idx].Lines, "\n"), tgt) {
+				entry.Honored++
+			} else {
+				entry.Missed = append(entry.Missed, source[idx].ID)
+			}
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	sort.Slice(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Source < report.Entries[j].Source
+	})
+	return report
+}
+
+func saveGlossaryReport(path string, report GlossaryReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode glossary report: %w", err)
+	}
+	if err := files.AtomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save glossary report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestCheckGlossary(t *testing.T) {
+	seg := func(id int, line string) srt.Segment {
+		return srt.Segment{ID: id, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{line}}
+	}
+	source := []srt.Segment{
+		seg(1, "太郎、行くぞ"),
+		seg(2, "花子はどこ?"),
+		seg(3, "太郎と花子"),
+		seg(4, "次郎"),
+		seg(5, "太郎!"),
+	}
+	translated := []srt.Segment{
+		seg(1, "타로, 가자"),
+		seg(2, "하나코는 어디?"),
+		seg(3, "타로와 하나"),
+		seg(4, "지로"),
+		seg(5, "다로!"),
+	}
+	mapping := map[string]string{"太郎": "타로", "花子": "하나코", "次郎": "지로", "三郎": "사부로"}
+
+	report := checkGlossary(source, translated, nil, mapping)
+	if got := report.Summary(); got != "1/3 mappings fully applied" {
+		t.Fatalf("Summary() = %q", got)
+	}
+	byName := map[string]GlossaryEntry{}
+	for _, e := range report.Entries {
+		byName[e.Source] = e
+	}
+	if e := byName["太郎"]; e.Occurrences != 3 || e.Honored != 2 || len(e.Missed) != 1 || e.Missed[0] != 5 {
+		t.Fatalf("太郎 entry = %+v", e)
+	}
+	if e := byName["花子"]; e.Occurrences != 2 || e.Honored != 1 || e.Missed[0] != 3 {
+		t.Fatalf("花子 entry = %+v", e)
+	}
+	if e := byName["三郎"]; e.Occurrences != 0 {
+		t.Fatalf("unused entry counted: %+v", e)
+	}
+
+	// Segments outside the selection were passed through and are not checked.
+	subset := checkGlossary(source, translated, []int{0, 3}, mapping)
+	if got := subset.Summary(); got != "2/2 mappings fully applied" {
+		t.Fatalf("subset Summary() = %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "glossary.json")
+	if err := saveGlossaryReport(path, report); err != nil {
+		t.Fatalf("saveGlossaryReport: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var decoded GlossaryReport
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Entries) != 4 {
+		t.Fatalf("decoded report = %+v, err %v", decoded, err)
+	}
+}
@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/oukeidos/focst/internal/metadata"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+const (
+	// estimatedRequestOverhead approximates the system instruction and JSON
+	// framing sent with every chunk, in tokens.
+	estimatedRequestOverhead = 1000
+	// estimatedSegmentOverhead approximates the JSON framing of one segment
+	// in a request or response, in tokens.
+	estimatedSegmentOverhead = 10
+)
+
+// estimateRunCost is a rough pre-run cost (USD) of translating count
+// segments like those in sample. It counts one token per rune, which is
+// conservative for Latin scripts, and ignores reasoning tokens.
+func estimateRunCost(model string, sample []srt.Segment, count, chunkSize, contextSize int) float64 {
+	if count == 0 || len(sample) == 0 {
+		return 0
+	}
+	text := 0
+	for _, seg := range sample {
+		for _, line := range seg.Lines {
+			text += utf8.RuneCountInString(line) + 1
+		}
+	}
+	perSegment := float64(text)/float64(len(sample)) + estimatedSegmentOverhead
+	chunks := (count + chunkSize - 1) / chunkSize
+	prompt := perSegment*float64(count+chunks*2*contextSize) + float64(chunks*estimatedRequestOverhead)
+	candidates := perSegment * float64(count)
+	return metadata.EstimateGeminiCost(model, int(prompt), int(candidates), int(prompt+candidates))
+}
+
+// confirmLargeRun asks cfg.OnConfirmLargeRun to approve a run of translatable
+// segments that exceeds MaxSegments or ConfirmOverCost. It returns false when
+// the run should be skipped, and an input error when it cannot be confirmed.
+func confirmLargeRun(cfg Config, segments []srt.Segment, selected []int, translatable int) (bool, error) {
+	var reasons []string
+	if cfg.MaxSegments > 0 && translatable > cfg.MaxSegments {
+		reasons = append(reasons, fmt.Sprintf("%d segments to translate exceed --max-segments %d", translatable, cfg.MaxSegments))
+	}
+	if cfg.ConfirmOverCost > 0 {
+		sample := segments
+		if selected != nil {
+			sample = make([]srt.Segment, len(selected))
+			for i, idx := range selected {
+				sample[i] = segments[idx]
+			}
+		}
+		cost := estimateRunCost(cfg.Model, sample, translatable, cfg.ChunkSize, cfg.ContextSize)
+		if cfg.PolishModel != "" {
+			// The polish pass sends the same chunks again, with drafts.
+			cost += estimateRunCost(cfg.PolishModel, sample, translatable, cfg.ChunkSize, cfg.ContextSize)
+		}
+		if cost > cfg.ConfirmOverCost {
+			reasons = append(reasons, fmt.Sprintf("estimated cost $%.2f exceeds --confirm-over-cost $%.2f", cost, cfg.ConfirmOverCost))
+		}
+	}
+	if len(reasons) == 0 {
+		return true, nil
+	}
+	reason := strings.Join(reasons, "; ")
+	if cfg.OnConfirmLargeRun == nil {
+		return false, inputErrorf("%s: confirmation required", reason)
+	}
+	ok, err := cfg.OnConfirmLargeRun(reason)
+	if err != nil {
+		return false, inputErrorf("%s: %w", reason, err)
+	}
+	return ok, nil
+}
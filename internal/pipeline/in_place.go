@@ -0,0 +1,67 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/files"
+)
+
+// InPlaceBackupPath returns where an in-place run keeps the original input:
+// "name.orig.ext" next to it.
+func InPlaceBackupPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".orig" + ext
+}
+
+// backupInPlaceInput copies the input at path to InPlaceBackupPath and
+// syncs it, so the original is on disk before the translation replaces it.
+// An existing backup is never replaced: it may hold the original of an
+// earlier in-place run.
+func backupInPlaceInput(path string) (string, error) {
+	backup := InPlaceBackupPath(path)
+	if err := files.RejectSymlinkPath(backup); err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(backup)
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(backup)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(backup)
+		return "", err
+	}
+	return backup, nil
+}
+
+// checkInPlaceBackup fails before any API call when the backup of an in-place
+// run is already taken.
+func checkInPlaceBackup(path string) error {
+	backup := InPlaceBackupPath(path)
+	if _, err := os.Lstat(backup); err == nil {
+		return inputErrorf("backup %s already exists; move it away before translating in place", backup)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check backup path: %w", err)
+	}
+	return nil
+}
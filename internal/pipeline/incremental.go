@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// incrementalOutputPath returns the sidecar a PriorityFirst run streams its
+// finished front to, e.g. "out.partial.srt" for "out.srt".
+func incrementalOutputPath(outputPath string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + ".partial" + ext
+}
+
+// incrementalOutput writes the translated front of the file, without
+// post-processing, while a PriorityFirst run is still going.
+type incrementalOutput struct {
+	path string
+	opts srt.SaveOptions
+	// segments and selected map a prefix of the translated subset back onto
+	// the whole file; selected is nil when every segment is translated.
+	segments []srt.Segment
+	selected []int
+}
+
+// newIncrementalOutput returns nil, after a warning, when the sidecar path
+// is unusable.
+func newIncrementalOutput(cfg Config, segments []srt.Segment, selected []int) *incrementalOutput {
+	path := incrementalOutputPath(cfg.OutputPath)
+	if err := files.RejectSymlinkPath(path); err != nil {
+		logger.Warn("Incremental output disabled", "path", path, "error", err)
+		return nil
+	}
+	return &incrementalOutput{
+		path:     path,
+		opts:     srt.SaveOptions{Format: cfg.OutputFormat, CueSettings: cfg.keepCueSettings(cfg.OutputPath), Compat: srt.CompatProfile(cfg.CompatProfile)},
+		segments: segments,
+		selected: selected,
+	}
+}
+
+// flush writes done, the translated front of the (selected) segments.
+// Segments outside the selection keep their source text.
+func (o *incrementalOutput) flush(done []srt.Segment) {
+	out := done
+	if o.selected != nil {
+		end := len(o.segments)
+		if len(done) < len(o.selected) {
+			end = o.selected[len(done)]
+		}
+		out = append([]srt.Segment(nil), o.segments[:end]...)
+		for k, seg := range done {
+			out[o.selected[k]] = seg
+		}
+	}
+	if err := srt.SaveWithOptions(o.path, out, o.opts); err != nil {
+		logger.Warn("Failed to write incremental output", "path", o.path, "error", err)
+		return
+	}
+	logger.Debug("Wrote incremental output", "path", o.path, "segments", len(out))
+}
+
+// removeIncrementalOutput deletes the sidecar once the final output is saved.
+func removeIncrementalOutput(outputPath string) {
+	path := incrementalOutputPath(outputPath)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logger.Warn("Failed to remove incremental output", "path", path, "error", err)
+	}
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// translateNotes translates the WebVTT NOTE blocks of segments in place,
+// after the cues themselves. Each block is sent as one segment under its
+// cue's ID, by a translator without the chunk cache, term memory, drafts, or
+// in-order streaming, so notes never mix with the cue translations. Blocks
+// that fail keep their source text. When pending is non-nil, only the notes
+// of segments it reports true for are sent.
+func translateNotes(ctx context.Context, cfg Config, client translationClient, srcLang, tgtLang language.Language, background string, segments []srt.Segment, pending func(i int) bool) gemini.UsageMetadata {
+	var notes []srt.Segment
+	index := make(map[int]int)
+	for i, seg := range segments {
+		if len(seg.Notes) == 0 || (pending != nil && !pending(i)) {
+			continue
+		}
+		index[seg.ID] = i
+		notes = append(notes, srt.Segment{ID: seg.ID, StartTime: seg.StartTime, EndTime: seg.EndTime, Lines: seg.Notes})
+	}
+	if len(notes) == 0 {
+		return gemini.UsageMetadata{}
+	}
+
+	// Line-shaping settings are meant for cues, not free-form notes.
+	noteCfg := cfg
+	noteCfg.RetryOnLongLines = false
+	noteCfg.SingleLine = false
+	noteCfg.AutoLinebreak = false
+	noteCfg.KeepDialogueDashes = false
+	noteCfg.DedupRepeats = false
+	noteCfg.ImproveDrafts = false
+	noteCfg.NarrativeTag = ""
+	tr, err := newTranslator(noteCfg, client, srcLang, tgtLang, nil, nil, nil, background)
+	if err != nil {
+		logger.Warn("Failed to translate notes; keeping them verbatim", "error", err)
+		return gemini.UsageMetadata{}
+	}
+	logger.Info("Translating notes", "count", len(notes))
+	translated, failed, err := tr.TranslateSRT(ctx, notes, nil)
+	if err != nil {
+		logger.Warn("Failed to translate notes; keeping them verbatim", "error", err)
+		return tr.GetUsage()
+	}
+	if len(failed) > 0 {
+		logger.Warn("Some notes could not be translated; keeping them verbatim", "chunks", len(failed))
+	}
+	for _, note := range translated {
+		// Failed chunks come back with their source lines.
+		if len(note.Lines) > 0 {
+			segments[index[note.ID]].Notes = note.Lines
+		}
+	}
+	return tr.GetUsage()
+}
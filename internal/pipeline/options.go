@@ -0,0 +1,211 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oukeidos/focst/internal/httpclient"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// Options are the user-facing translation settings shared by the CLI and GUI.
+// Front ends fill Options and call NewConfig instead of building Config by hand,
+// so a setting added here reaches both of them. See Config for field meanings.
+type Options struct {
+	Model          string
+	PolishModel    string
+	GeminiEndpoint string
+	RequestTimeout time.Duration
+	RampUp         time.Duration
+	JitterMax      time.Duration
+	NoJitter       bool
+	RelaxSafety    bool
+
+	MaxSegmentsPerMinute int
+
+	ChunkSize        int
+	AutoChunkSize    bool
+	ContextSize      int
+	ClampContext     bool
+	Concurrency      int
+	RetryOnLongLines bool
+	NoPromptCPL      bool
+	CPLMetric        string
+	CPLTolerance     float64
+
+	NoPreprocess          bool
+	NoPostprocess         bool
+	Overwrite             bool
+	MakeOutputDir         bool
+	InPlace               bool
+	NoLangPreprocess      bool
+	NoBracketRemoval      bool
+	NoAngleStrip          bool
+	NoMeaninglessFilter   bool
+	NoLangPostprocess     bool
+	NoTimingCorrection    bool
+	NormalizeQuotes       bool
+	OnEmptied             string
+	PostprocessLang       string
+	CompatProfile         string
+	SavePartialOnFailure  bool
+	FilterRegex           string
+	ForcedOnly            bool
+	ExcludeIDs            string
+	NoSkipNonTranslatable bool
+	AllowSameLang         bool
+	AllowNoDialogue       bool
+	ChunkCache            bool
+	DumpFailed            bool
+	MaxCost               float64
+	MaxSegments           int
+	ConfirmOverCost       float64
+	StallTimeout          time.Duration
+	CancelOnStall         bool
+	PriorityFirst         bool
+	EmbedMetadata         bool
+	KeepCueSettings       bool
+	KeepDialogueDashes    bool
+	OnEmpty               string
+	BackgroundPath        string
+	Formality             string
+	NarrativeTag          string
+	SingleLine            bool
+	AutoLinebreak         bool
+	TranslateNotes        bool
+	DedupRepeats          bool
+	ImproveDrafts         bool
+	FailFast              bool
+	Sample                int
+	TermMemoryPath        string
+	GlossaryReportPath    string
+	FailureReportPath     string
+	DumpIDMap             string
+	TMXPath               string
+	TMXImportPath         string
+	KeepLog               bool
+	InputFormat           string
+	InputEncoding         string
+	OutputFormat          string
+
+	SourceLang   string
+	TargetLang   string
+	NamesMapping map[string]string
+	NamesPath    string
+}
+
+// DefaultOptions returns the settings used when the user changes nothing.
+func DefaultOptions() Options {
+	return Options{
+		RequestTimeout: httpclient.DefaultTimeout,
+		RampUp:         translator.DefaultRampUp,
+		JitterMax:      translator.DefaultJitterMax,
+		ChunkSize:      language.DefaultChunkSize,
+		ContextSize:    5,
+		Concurrency:    7,
+		CPLMetric:      string(translator.CPLMetricGraphemes),
+		CPLTolerance:   translator.DefaultCPLTolerance,
+		OnEmpty:        string(translator.EmptyPolicyFail),
+		OnEmptied:      string(srt.EmptiedKeep),
+		Formality:      string(translator.FormalityAuto),
+	}
+}
+
+// NewConfig builds the translation Config for one input/output pair from opts
+// and validates it the way RunTranslation will (after Normalize clamping).
+// Callbacks and LogPath are left for the caller to set.
+func NewConfig(inputPath, outputPath, apiKey string, opts Options) (Config, error) {
+	cfg := configFromOptions(inputPath, outputPath, apiKey, opts)
+	normalized, _ := cfg.Normalize()
+	if err := normalized.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// configFromOptions copies opts onto a Config without validating it.
+func configFromOptions(inputPath, outputPath, apiKey string, opts Options) Config {
+	return Config{
+		InputPath:             inputPath,
+		OutputPath:            outputPath,
+		APIKey:                apiKey,
+		Model:                 opts.Model,
+		PolishModel:           opts.PolishModel,
+		GeminiEndpoint:        opts.GeminiEndpoint,
+		RequestTimeout:        opts.RequestTimeout,
+		RampUp:                opts.RampUp,
+		JitterMax:             opts.JitterMax,
+		NoJitter:              opts.NoJitter,
+		RelaxSafety:           opts.RelaxSafety,
+		MaxSegmentsPerMinute:  opts.MaxSegmentsPerMinute,
+		ChunkSize:             opts.ChunkSize,
+		AutoChunkSize:         opts.AutoChunkSize,
+		ContextSize:           opts.ContextSize,
+		ClampContext:          opts.ClampContext,
+		Concurrency:           opts.Concurrency,
+		RetryOnLongLines:      opts.RetryOnLongLines,
+		NoPromptCPL:           opts.NoPromptCPL,
+		CPLMetric:             opts.CPLMetric,
+		CPLTolerance:          opts.CPLTolerance,
+		NoPreprocess:          opts.NoPreprocess,
+		NoPostprocess:         opts.NoPostprocess,
+		Overwrite:             opts.Overwrite,
+		MakeOutputDir:         opts.MakeOutputDir,
+		InPlace:               opts.InPlace,
+		NoLangPreprocess:      opts.NoLangPreprocess,
+		NoBracketRemoval:      opts.NoBracketRemoval,
+		NoAngleStrip:          opts.NoAngleStrip,
+		NoMeaninglessFilter:   opts.NoMeaninglessFilter,
+		NoLangPostprocess:     opts.NoLangPostprocess,
+		NoTimingCorrection:    opts.NoTimingCorrection,
+		NormalizeQuotes:       opts.NormalizeQuotes,
+		OnEmptied:             opts.OnEmptied,
+		PostprocessLang:       opts.PostprocessLang,
+		CompatProfile:         opts.CompatProfile,
+		SavePartialOnFailure:  opts.SavePartialOnFailure,
+		FilterRegex:           opts.FilterRegex,
+		ForcedOnly:            opts.ForcedOnly,
+		ExcludeIDs:            opts.ExcludeIDs,
+		NoSkipNonTranslatable: opts.NoSkipNonTranslatable,
+		AllowSameLang:         opts.AllowSameLang,
+		AllowNoDialogue:       opts.AllowNoDialogue,
+		ChunkCache:            opts.ChunkCache,
+		DumpFailed:            opts.DumpFailed,
+		MaxCost:               opts.MaxCost,
+		MaxSegments:           opts.MaxSegments,
+		ConfirmOverCost:       opts.ConfirmOverCost,
+		StallTimeout:          opts.StallTimeout,
+		CancelOnStall:         opts.CancelOnStall,
+		PriorityFirst:         opts.PriorityFirst,
+		EmbedMetadata:         opts.EmbedMetadata,
+		KeepCueSettings:       opts.KeepCueSettings,
+		KeepDialogueDashes:    opts.KeepDialogueDashes,
+		OnEmpty:               opts.OnEmpty,
+		BackgroundPath:        opts.BackgroundPath,
+		Formality:             opts.Formality,
+		NarrativeTag:          opts.NarrativeTag,
+		SingleLine:            opts.SingleLine,
+		AutoLinebreak:         opts.AutoLinebreak,
+		TranslateNotes:        opts.TranslateNotes,
+		DedupRepeats:          opts.DedupRepeats,
+		ImproveDrafts:         opts.ImproveDrafts,
+		FailFast:              opts.FailFast,
+		Sample:                opts.Sample,
+		TermMemoryPath:        opts.TermMemoryPath,
+		GlossaryReportPath:    opts.GlossaryReportPath,
+		FailureReportPath:     opts.FailureReportPath,
+		DumpIDMap:             opts.DumpIDMap,
+		TMXPath:               opts.TMXPath,
+		TMXImportPath:         opts.TMXImportPath,
+		KeepLog:               opts.KeepLog,
+		InputFormat:           opts.InputFormat,
+		InputEncoding:         opts.InputEncoding,
+		OutputFormat:          opts.OutputFormat,
+		SourceLang:            opts.SourceLang,
+		TargetLang:            opts.TargetLang,
+		NamesMapping:          opts.NamesMapping,
+		NamesPath:             opts.NamesPath,
+	}
+}
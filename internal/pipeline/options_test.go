@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewConfig_MapsEveryOption fails when an Options field is added without
+// being copied onto the Config field of the same name.
+func TestNewConfig_MapsEveryOption(t *testing.T) {
+	var opts Options
+	v := reflect.ValueOf(&opts).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Bool:
+			f.SetBool(true)
+		case reflect.Int, reflect.Int64:
+			if f.Type() == reflect.TypeOf(time.Duration(0)) {
+				f.SetInt(int64(3 * time.Second))
+			} else {
+				f.SetInt(3)
+			}
+		case reflect.Float64:
+			f.SetFloat(1.5)
+		case reflect.String:
+			f.SetString("x")
+		case reflect.Map:
+			f.Set(reflect.ValueOf(map[string]string{"a": "b"}))
+		default:
+			t.Fatalf("unhandled option kind %s for %s", f.Kind(), v.Type().Field(i).Name)
+		}
+	}
+	// String options that NewConfig validates need real values.
+	opts.GeminiEndpoint = "https://gemini-proxy.example.com"
+	opts.CPLMetric = "width"
+	opts.OnEmpty = "keep-source"
+	opts.OnEmptied = "drop"
+	opts.PostprocessLang = "zh-Hant"
+	opts.CompatProfile = "legacy"
+	opts.Formality = "formal"
+	opts.NarrativeTag = "forced"
+	opts.FilterRegex = "^x$"
+	opts.ExcludeIDs = "1-2"
+	opts.InputFormat = "srt"
+	opts.InputEncoding = "cp949"
+	opts.OutputFormat = "vtt"
+
+	cfg, err := NewConfig("in.srt", "out.srt", "k", opts)
+	if err != nil {
+		t.Fatalf("NewConfig: %v", err)
+	}
+	if cfg.InputPath != "in.srt" || cfg.OutputPath != "out.srt" || cfg.APIKey != "k" {
+		t.Fatalf("paths or key not set: %+v", cfg)
+	}
+	cv := reflect.ValueOf(cfg)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		got := cv.FieldByName(name)
+		if !got.IsValid() {
+			t.Fatalf("Config has no field %s", name)
+		}
+		if !reflect.DeepEqual(got.Interface(), v.Field(i).Interface()) {
+			t.Errorf("Config.%s = %v, want %v", name, got.Interface(), v.Field(i).Interface())
+		}
+	}
+}
+
+func TestNewConfig_Validates(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Concurrency = 50 // clamped by Normalize, not an error
+	if _, err := NewConfig("in.srt", "out.srt", "k", opts); err != nil {
+		t.Fatalf("NewConfig with clampable concurrency: %v", err)
+	}
+
+	opts = DefaultOptions()
+	opts.FilterRegex = "("
+	if _, err := NewConfig("in.srt", "out.srt", "k", opts); err == nil || !strings.Contains(err.Error(), "invalid filter regex") {
+		t.Fatalf("expected invalid regex error, got %v", err)
+	}
+	if _, err := NewConfig("in.srt", "out.srt", "", DefaultOptions()); err == nil {
+		t.Fatalf("expected missing API key error")
+	}
+}
@@ -2,10 +2,26 @@ package pipeline
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/prompt"
+	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/tmx"
+	"github.com/oukeidos/focst/internal/translator"
+	"github.com/oukeidos/focst/internal/version"
 )
 
 func TestRunTranslation_InvalidPaths(t *testing.T) {
@@ -67,6 +83,78 @@ func TestRunTranslation_InvalidPaths(t *testing.T) {
 	}
 }
 
+func TestConfigNormalize_ContextLargeForChunk(t *testing.T) {
+	tests := []struct {
+		name        string
+		chunk       int
+		context     int
+		clamp       bool
+		wantContext int
+		wantNote    string
+	}{
+		{"within_limit", 10, 5, false, 5, ""},
+		{"odd_chunk_rounds_up", 1, 1, true, 1, ""},
+		{"warn_only", 1, 20, false, 20, "context-size 20 is large for chunk-size 1"},
+		{"clamped", 1, 20, true, 1, "context-size clamped from 20 to 1"},
+		{"clamped_to_half", 6, 5, true, 3, "context-size clamped from 5 to 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Concurrency: 1, ChunkSize: tt.chunk, ContextSize: tt.context, ClampContext: tt.clamp}
+			got, notes := cfg.Normalize()
+			if got.ContextSize != tt.wantContext {
+				t.Fatalf("Normalize() context = %d, want %d", got.ContextSize, tt.wantContext)
+			}
+			if tt.wantNote == "" {
+				if len(notes) != 0 {
+					t.Fatalf("Normalize() unexpected notes: %v", notes)
+				}
+				return
+			}
+			if len(notes) != 1 || !strings.Contains(notes[0], tt.wantNote) {
+				t.Fatalf("Normalize() notes = %v, want one containing %q", notes, tt.wantNote)
+			}
+		})
+	}
+}
+
+func TestConfigNormalize_CPLConflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantNotes []string
+	}{
+		{"no_validation", Config{NoPromptCPL: true, ChunkSize: 150, TargetLang: "ja"}, nil},
+		{"validation_with_prompt", Config{RetryOnLongLines: true, ChunkSize: 50, SourceLang: "en", TargetLang: "ko"}, nil},
+		{"limit_not_in_prompt", Config{RetryOnLongLines: true, NoPromptCPL: true, ChunkSize: 50, TargetLang: "en"},
+			[]string{"no-prompt-cpl keeps it out of the prompt"}},
+		{"large_chunk_small_cpl", Config{RetryOnLongLines: true, ChunkSize: 150, SourceLang: "en", TargetLang: "ja"},
+			[]string{"chunk-size 150 for ja (13 characters per line)", "suggested chunk-size 60"}},
+		{"large_chunk_wide_cpl", Config{RetryOnLongLines: true, ChunkSize: 150, SourceLang: "ja", TargetLang: "en"}, nil},
+		{"auto_chunk_size", Config{RetryOnLongLines: true, ChunkSize: 150, AutoChunkSize: true, TargetLang: "ja"}, nil},
+		{"both", Config{RetryOnLongLines: true, NoPromptCPL: true, ChunkSize: 100, SourceLang: "en", TargetLang: "zh-Hant"},
+			[]string{"no-prompt-cpl", "chunk-size 100 for zh-Hant"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Concurrency = 1
+			got, notes := tt.cfg.Normalize()
+			if got.ChunkSize != tt.cfg.ChunkSize || got.NoPromptCPL != tt.cfg.NoPromptCPL || got.RetryOnLongLines != tt.cfg.RetryOnLongLines {
+				t.Fatalf("Normalize() changed CPL settings: %+v", got)
+			}
+			joined := strings.Join(notes, "\n")
+			for _, want := range tt.wantNotes {
+				if !strings.Contains(joined, want) {
+					t.Errorf("Normalize() notes = %v, want one containing %q", notes, want)
+				}
+			}
+			if len(tt.wantNotes) == 0 && len(notes) != 0 {
+				t.Errorf("Normalize() unexpected notes: %v", notes)
+			}
+		})
+	}
+}
+
 func TestConfigNormalize_ConcurrencyClamp(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -95,3 +183,1692 @@ func TestConfigNormalize_ConcurrencyClamp(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigValidate_GeminiEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		wantErr  bool
+	}{
+		{"", false},
+		{"https://gemini-proxy.example.com", false},
+		{"http://localhost:4000/gemini", false},
+		{"gemini-proxy.example.com", true},
+		{"grpc://gemini-proxy.example.com", true},
+	}
+	for _, tt := range tests {
+		cfg := Config{ChunkSize: 1, Concurrency: 1, APIKey: "k", GeminiEndpoint: tt.endpoint}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with endpoint %q error = %v, wantErr %v", tt.endpoint, err, tt.wantErr)
+		}
+		if err := cfg.ValidateRepairRuntime(); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateRepairRuntime() with endpoint %q error = %v, wantErr %v", tt.endpoint, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfigValidate_CPLMetric(t *testing.T) {
+	for metric, wantErr := range map[string]bool{"": false, "graphemes": false, "width": false, "bytes": true} {
+		cfg := Config{ChunkSize: 1, Concurrency: 1, APIKey: "k", CPLMetric: metric}
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("Validate() with CPL metric %q error = %v, wantErr %v", metric, err, wantErr)
+		}
+	}
+}
+
+func TestConfigValidate_CPLTolerance(t *testing.T) {
+	for tolerance, wantErr := range map[float64]bool{0: false, 1.0: false, 1.2: false, 0.8: true} {
+		cfg := Config{ChunkSize: 1, Concurrency: 1, APIKey: "k", CPLTolerance: tolerance}
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("Validate() with CPL tolerance %v error = %v, wantErr %v", tolerance, err, wantErr)
+		}
+	}
+}
+
+func TestConfigValidate_PostprocessLang(t *testing.T) {
+	for lang, wantErr := range map[string]bool{"": false, "zh-Hant": false, "zh": false, "ko": false, "en": true, "xx": true} {
+		cfg := Config{ChunkSize: 1, Concurrency: 1, APIKey: "k", PostprocessLang: lang}
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("Validate() with post-processing language %q error = %v, wantErr %v", lang, err, wantErr)
+		}
+	}
+}
+
+func TestConfigNormalize_AutoLinebreakWithSingleLine(t *testing.T) {
+	cfg := Config{ChunkSize: 1, Concurrency: 1, AutoLinebreak: true}
+	if _, notes := cfg.Normalize(); len(notes) != 0 {
+		t.Fatalf("Normalize() notes = %v, want none", notes)
+	}
+	cfg.SingleLine = true
+	if _, notes := cfg.Normalize(); len(notes) != 1 || !strings.Contains(notes[0], "auto-linebreak has no effect") {
+		t.Fatalf("Normalize() notes = %v, want the single-line note", notes)
+	}
+}
+
+func TestConfigValidate_RampUp(t *testing.T) {
+	for ramp, wantErr := range map[time.Duration]bool{0: false, 2 * time.Second: false, -time.Second: true} {
+		cfg := Config{ChunkSize: 1, Concurrency: 1, APIKey: "k", RampUp: ramp}
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("Validate() with ramp-up %s error = %v, wantErr %v", ramp, err, wantErr)
+		}
+		if err := cfg.ValidateRepairRuntime(); (err != nil) != wantErr {
+			t.Errorf("ValidateRepairRuntime() with ramp-up %s error = %v, wantErr %v", ramp, err, wantErr)
+		}
+	}
+}
+
+func TestConfigValidate_InPlaceNeedsLocalInput(t *testing.T) {
+	cfg := Config{ChunkSize: 1, Concurrency: 1, APIKey: "k", InputData: []byte("1\n"), InputFormat: "srt", InPlace: true}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "local input file") {
+		t.Fatalf("Validate() with in-place in-memory input error = %v, want rejection", err)
+	}
+	cfg.InPlace = false
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() with in-memory input error = %v", err)
+	}
+}
+
+func TestConfigResolveChunkSize(t *testing.T) {
+	cases := []struct {
+		name        string
+		cfg         Config
+		src, tgt    string
+		want        int
+		wantChanged bool
+	}{
+		{name: "explicit_size_kept", cfg: Config{ChunkSize: 100}, src: "ja", tgt: "ko", want: 100},
+		{name: "explicit_custom_size_kept", cfg: Config{ChunkSize: 150}, src: "ja", tgt: "en", want: 150},
+		{name: "auto_dense_source", cfg: Config{ChunkSize: 100, AutoChunkSize: true}, src: "ja", tgt: "en", want: 60, wantChanged: true},
+		{name: "auto_smaller_side_wins", cfg: Config{ChunkSize: 100, AutoChunkSize: true}, src: "ko", tgt: "zh-Hant", want: 60, wantChanged: true},
+		{name: "auto_dense_target", cfg: Config{ChunkSize: 100, AutoChunkSize: true}, src: "en", tgt: "ko", want: 80, wantChanged: true},
+		{name: "auto_no_suggestion", cfg: Config{ChunkSize: 100, AutoChunkSize: true}, src: "en", tgt: "fr", want: 100},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, changed := tc.cfg.resolveChunkSize(tc.src, tc.tgt)
+			if got != tc.want || changed != tc.wantChanged {
+				t.Fatalf("resolveChunkSize(%q, %q) = %d, %v, want %d, %v", tc.src, tc.tgt, got, changed, tc.want, tc.wantChanged)
+			}
+		})
+	}
+}
+
+type stubTranslationClient struct {
+	translate         func(req gemini.RequestData) (*gemini.ResponseData, error)
+	systemInstruction string
+}
+
+func (c *stubTranslationClient) Translate(_ context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	return c.translate(req)
+}
+
+func (c *stubTranslationClient) SetSystemInstruction(prompt string) { c.systemInstruction = prompt }
+
+func (c *stubTranslationClient) Close() error { return nil }
+
+func withStubClient(t *testing.T, client *stubTranslationClient) {
+	t.Helper()
+	prev := newGeminiClient
+	newGeminiClient = func(context.Context, string, string, gemini.ClientOptions) (translationClient, error) {
+		return client, nil
+	}
+	t.Cleanup(func() { newGeminiClient = prev })
+}
+
+func TestRunTranslation_SavePartialOnFailure(t *testing.T) {
+	failing := &stubTranslationClient{
+		translate: func(gemini.RequestData) (*gemini.ResponseData, error) {
+			return nil, apperrors.BadRequest(errors.New("rejected"))
+		},
+	}
+	withStubClient(t, failing)
+
+	for _, savePartial := range []bool{false, true} {
+		t.Run(fmt.Sprintf("save_partial_%v", savePartial), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inPath := filepath.Join(tmpDir, "input.srt")
+			outPath := filepath.Join(tmpDir, "out.srt")
+			if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+			cfg := Config{
+				InputPath:            inPath,
+				OutputPath:           outPath,
+				APIKey:               "test",
+				ChunkSize:            10,
+				Concurrency:          1,
+				SourceLang:           "en",
+				TargetLang:           "ko",
+				SavePartialOnFailure: savePartial,
+			}
+			result, err := RunTranslation(context.Background(), cfg)
+			if err != nil {
+				t.Fatalf("RunTranslation failed: %v", err)
+			}
+			if result.Status != TranslationStatusFailure {
+				t.Fatalf("status = %q, want Failure", result.Status)
+			}
+			_, statErr := os.Stat(outPath)
+			if !savePartial {
+				if statErr == nil || result.OutputPath != "" || result.PartialOutput {
+					t.Fatalf("expected no output on failure, got path=%q partial=%v", result.OutputPath, result.PartialOutput)
+				}
+				return
+			}
+			if statErr != nil {
+				t.Fatalf("expected partial output file: %v", statErr)
+			}
+			if !result.PartialOutput || result.OutputPath != outPath {
+				t.Fatalf("expected partial output at %q, got path=%q partial=%v", outPath, result.OutputPath, result.PartialOutput)
+			}
+			segments, err := srt.Load(outPath)
+			if err != nil {
+				t.Fatalf("load output: %v", err)
+			}
+			if len(segments) != 1 || segments[0].Lines[0] != "Hello" {
+				t.Fatalf("expected source text for failed chunk, got %+v", segments)
+			}
+			if result.RecoveryLogPath == "" {
+				t.Fatalf("expected recovery log path")
+			}
+		})
+	}
+}
+
+func TestRunTranslation_MissingOutputDir(t *testing.T) {
+	var calls atomic.Int32
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			calls.Add(1)
+			out := make([]gemini.TranslatedSegment, len(req.Target))
+			for i, seg := range req.Target {
+				out[i] = gemini.TranslatedSegment{ID: seg.ID, Line1: "안녕"}
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	outDir := filepath.Join(tmpDir, "missing", "subs")
+	cfg := Config{
+		InputPath:   inPath,
+		OutputPath:  filepath.Join(outDir, "out.srt"),
+		APIKey:      "test",
+		ChunkSize:   10,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+	}
+
+	_, err := RunTranslation(context.Background(), cfg)
+	if !IsInputError(err) || !strings.Contains(err.Error(), "output directory does not exist") {
+		t.Fatalf("err = %v, want a missing output directory InputError", err)
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("translated %d chunks before failing", calls.Load())
+	}
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Fatalf("output directory created without MakeOutputDir: %v", err)
+	}
+
+	cfg.MakeOutputDir = true
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil || result.Status != TranslationStatusSuccess {
+		t.Fatalf("RunTranslation with MakeOutputDir = %q, %v", result.Status, err)
+	}
+	segments, err := srt.Load(cfg.OutputPath)
+	if err != nil || len(segments) != 1 || segments[0].Lines[0] != "안녕" {
+		t.Fatalf("output = %+v, %v", segments, err)
+	}
+}
+
+func TestRunTranslation_TranslateNotes(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			out := make([]gemini.TranslatedSegment, len(req.Target))
+			mu.Lock()
+			for i, seg := range req.Target {
+				sent = append(sent, seg.Lines...)
+				out[i] = gemini.TranslatedSegment{ID: seg.ID, Line1: "번역 " + strings.Join(seg.Lines, " ")}
+			}
+			mu.Unlock()
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	for _, translateNotes := range []bool{false, true} {
+		t.Run(fmt.Sprintf("translate_notes_%v", translateNotes), func(t *testing.T) {
+			sent = nil
+			tmpDir := t.TempDir()
+			inPath := filepath.Join(tmpDir, "input.vtt")
+			outPath := filepath.Join(tmpDir, "out.vtt")
+			input := "WEBVTT\n\nNOTE Timed by Kim\n\n1\n00:00:01.000 --> 00:00:02.000\nHello\n"
+			if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+			cfg := Config{
+				InputPath:      inPath,
+				OutputPath:     outPath,
+				APIKey:         "test",
+				ChunkSize:      10,
+				Concurrency:    1,
+				SourceLang:     "en",
+				TargetLang:     "ko",
+				TranslateNotes: translateNotes,
+			}
+			result, err := RunTranslation(context.Background(), cfg)
+			if err != nil || result.Status != TranslationStatusSuccess {
+				t.Fatalf("RunTranslation = %q, %v", result.Status, err)
+			}
+			data, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			wantNote, wantSent := "NOTE Timed by Kim\n", []string{"Hello"}
+			if translateNotes {
+				wantNote, wantSent = "NOTE 번역 Timed by Kim\n", []string{"Hello", "Timed by Kim"}
+			}
+			if !strings.Contains(string(data), wantNote) || !strings.Contains(string(data), "번역 Hello") {
+				t.Fatalf("output lacks %q:\n%s", wantNote, data)
+			}
+			if !reflect.DeepEqual(sent, wantSent) {
+				t.Fatalf("sent lines = %q, want %q", sent, wantSent)
+			}
+		})
+	}
+}
+
+func TestRunTranslation_PolishModel(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string][]gemini.SegmentData)
+	respond := func(model, prefix string, tokens int) *stubTranslationClient {
+		return &stubTranslationClient{
+			translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+				mu.Lock()
+				calls[model] = append(calls[model], req.Target...)
+				mu.Unlock()
+				out := make([]gemini.TranslatedSegment, len(req.Target))
+				for i, seg := range req.Target {
+					if model == "strong" && seg.Lines[0] == "Bye" {
+						return nil, apperrors.BadRequest(errors.New("rejected"))
+					}
+					text := strings.Join(seg.Lines, " ")
+					if seg.Draft != nil {
+						text = strings.Join(seg.Draft, " ")
+					}
+					out[i] = gemini.TranslatedSegment{ID: seg.ID, Line1: prefix + text}
+				}
+				usage := gemini.UsageMetadata{PromptTokenCount: tokens, CandidatesTokenCount: tokens, TotalTokenCount: 2 * tokens}
+				return &gemini.ResponseData{Translations: out, Usage: usage}, nil
+			},
+		}
+	}
+	clients := map[string]*stubTranslationClient{
+		"fast":   respond("fast", "초안 ", 10),
+		"strong": respond("strong", "다듬은 ", 100),
+	}
+	prev := newGeminiClient
+	newGeminiClient = func(_ context.Context, _, model string, _ gemini.ClientOptions) (translationClient, error) {
+		return clients[model], nil
+	}
+	t.Cleanup(func() { newGeminiClient = prev })
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nBye\n\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	cfg := Config{
+		InputPath:   inPath,
+		OutputPath:  outPath,
+		APIKey:      "test",
+		Model:       "fast",
+		PolishModel: "strong",
+		ChunkSize:   1,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+	}
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil || result.Status != TranslationStatusSuccess {
+		t.Fatalf("RunTranslation = %q, %v", result.Status, err)
+	}
+
+	if len(calls["fast"]) != 2 {
+		t.Fatalf("first pass sent %d segments, want 2", len(calls["fast"]))
+	}
+	for _, seg := range calls["fast"] {
+		if seg.Draft != nil {
+			t.Fatalf("first pass sent draft %q", seg.Draft)
+		}
+	}
+	drafts := make(map[string][]string)
+	for _, seg := range calls["strong"] {
+		drafts[seg.Lines[0]] = seg.Draft
+	}
+	want := map[string][]string{"Hello": {"초안 Hello"}, "Bye": {"초안 Bye"}}
+	if !reflect.DeepEqual(drafts, want) {
+		t.Fatalf("polish drafts = %q, want %q", drafts, want)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	// The chunk the polish model rejected keeps its first-pass translation.
+	if !strings.Contains(string(data), "다듬은 초안 Hello") || !strings.Contains(string(data), "초안 Bye") || strings.Contains(string(data), "다듬은 초안 Bye") {
+		t.Fatalf("unexpected output:\n%s", data)
+	}
+
+	if result.PolishModel != "strong" {
+		t.Fatalf("PolishModel = %q, want strong", result.PolishModel)
+	}
+	if result.PolishUsage.PromptTokenCount != 100 {
+		t.Fatalf("PolishUsage = %+v, want one polished chunk", result.PolishUsage)
+	}
+	if result.Usage.PromptTokenCount != 2*10+100 || result.Usage.TotalTokenCount != 2*20+200 {
+		t.Fatalf("Usage = %+v, want both passes", result.Usage)
+	}
+}
+
+func TestRunTranslation_AllowSameLang(t *testing.T) {
+	calls := 0
+	withStubClient(t, &stubTranslationClient{
+		translate: func(gemini.RequestData) (*gemini.ResponseData, error) {
+			calls++
+			return nil, errors.New("unexpected translate call")
+		},
+	})
+
+	for _, allow := range []bool{false, true} {
+		t.Run(fmt.Sprintf("allow_%v", allow), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inPath := filepath.Join(tmpDir, "input.srt")
+			outPath := filepath.Join(tmpDir, "out.srt")
+			if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+			cfg := Config{
+				InputPath:     inPath,
+				OutputPath:    outPath,
+				APIKey:        "test",
+				ChunkSize:     10,
+				Concurrency:   1,
+				SourceLang:    "en",
+				TargetLang:    "en",
+				AllowSameLang: allow,
+			}
+			result, err := RunTranslation(context.Background(), cfg)
+			if !allow {
+				if err == nil || !strings.Contains(err.Error(), "must be different") {
+					t.Fatalf("expected same-language error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunTranslation failed: %v", err)
+			}
+			if result.Status != TranslationStatusSuccess || result.OutputPath != outPath {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			segments, err := srt.Load(outPath)
+			if err != nil {
+				t.Fatalf("load output: %v", err)
+			}
+			if len(segments) != 1 || segments[0].Lines[0] != "Hello" {
+				t.Fatalf("expected copied source text, got %+v", segments)
+			}
+		})
+	}
+	if calls != 0 {
+		t.Fatalf("expected no translate calls, got %d", calls)
+	}
+}
+
+func TestRunTranslation_AllowNoDialogue(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(gemini.RequestData) (*gemini.ResponseData, error) {
+			return nil, errors.New("unexpected translate call")
+		},
+	})
+
+	for _, allow := range []bool{false, true} {
+		t.Run(fmt.Sprintf("allow_%v", allow), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inPath := filepath.Join(tmpDir, "music.srt")
+			outPath := filepath.Join(tmpDir, "out.srt")
+			input := "1\n00:00:01,000 --> 00:00:01,200\n♪\n\n2\n00:00:05,000 --> 00:00:06,000\n♪ ♪\n"
+			if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+			cfg := Config{
+				InputPath:       inPath,
+				OutputPath:      outPath,
+				APIKey:          "test",
+				ChunkSize:       10,
+				Concurrency:     1,
+				SourceLang:      "ja",
+				TargetLang:      "ko",
+				AllowNoDialogue: allow,
+			}
+			result, err := RunTranslation(context.Background(), cfg)
+			if !allow {
+				if err == nil || !strings.Contains(err.Error(), "no dialogue text") {
+					t.Fatalf("expected no-dialogue error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunTranslation failed: %v", err)
+			}
+			if result.Status != TranslationStatusSuccess {
+				t.Fatalf("status = %q, want Success", result.Status)
+			}
+			segments, err := srt.Load(outPath)
+			if err != nil {
+				t.Fatalf("load output: %v", err)
+			}
+			if len(segments) != 2 || segments[0].Lines[0] != "♪" {
+				t.Fatalf("expected music cues copied through, got %+v", segments)
+			}
+			// Post-processing still applies the minimum duration.
+			if segments[0].EndTime != "00:00:01,800" {
+				t.Fatalf("expected timing correction, got end %s", segments[0].EndTime)
+			}
+		})
+	}
+}
+
+func TestRunTranslation_ChunkCacheResume(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	var requested []string
+	failWorld := true
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			requested = append(requested, seg.Lines[0])
+			if seg.Lines[0] == "World" && failWorld {
+				return nil, apperrors.BadRequest(errors.New("rejected"))
+			}
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "T-" + seg.Lines[0]}}}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     1,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		Overwrite:     true,
+		ChunkCache:    true,
+		NoPostprocess: true,
+	}
+
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("status = %q, want Partial Success", result.Status)
+	}
+	cacheDir := filepath.Join(tmpDir, "out_chunk_cache")
+	if _, err := os.Stat(cacheDir); err != nil {
+		t.Fatalf("expected chunk cache dir: %v", err)
+	}
+	log, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	if log.ChunkCacheDir != "out_chunk_cache" {
+		t.Fatalf("chunk_cache_dir = %q, want out_chunk_cache", log.ChunkCacheDir)
+	}
+
+	requested = nil
+	failWorld = false
+	result, err = RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if result.Status != TranslationStatusSuccess {
+		t.Fatalf("status = %q, want Success", result.Status)
+	}
+	if len(requested) != 1 || requested[0] != "World" {
+		t.Fatalf("expected only the uncached chunk to be requested, got %v", requested)
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Fatalf("expected chunk cache removed after success, got %v", err)
+	}
+}
+
+func TestRunTranslation_DumpFailedWritesFailedChunks(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nBig\nWorld\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nAgain\n\n4\n00:00:07,000 --> 00:00:08,000\nLater\n\n" +
+		"5\n00:00:09,000 --> 00:00:10,000\nBye\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				if seg.Lines[0] == "Big" || seg.Lines[0] == "Bye" {
+					return nil, apperrors.BadRequest(errors.New("rejected"))
+				}
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     2,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		Overwrite:     true,
+		NoPreprocess:  true,
+		NoPostprocess: true,
+		DumpFailed:    true,
+	}
+
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("status = %q, want Partial Success", result.Status)
+	}
+	data, err := os.ReadFile(recovery.FailedSourcePath(result.RecoveryLogPath))
+	if err != nil {
+		t.Fatalf("read dump: %v", err)
+	}
+	// Chunks 0 (segments 1-2) and 2 (segment 5) failed; chunk 1 did not.
+	want := "# Chunk 0\n\n1\nHello\n\n2\nBig\nWorld\n\n# Chunk 2\n\n5\nBye\n"
+	if string(data) != want {
+		t.Fatalf("dump = %q, want %q", data, want)
+	}
+}
+
+func TestRunTranslation_DumpIDMap(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	mapPath := filepath.Join(tmpDir, "idmap.json")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nこんにちは\n\n2\n00:00:03,000 --> 00:00:04,000\n（笑）\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nまたね\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     10,
+		Concurrency:   1,
+		SourceLang:    "ja",
+		TargetLang:    "ko",
+		NoPostprocess: true,
+		DumpIDMap:     mapPath,
+	}
+
+	if _, err := RunTranslation(context.Background(), cfg); err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	data, err := os.ReadFile(mapPath)
+	if err != nil {
+		t.Fatalf("read ID map: %v", err)
+	}
+	want := `{"version":1,"mapping":[{"internal_id":1,"original_id":1},{"internal_id":2,"original_id":3}]}`
+	if string(data) != want {
+		t.Fatalf("ID map = %s, want %s", data, want)
+	}
+}
+
+func TestRunTranslation_TMXExportAndImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	tmxPath := filepath.Join(tmpDir, "memory.tmx")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nこんにちは\n\n2\n00:00:03,000 --> 00:00:04,000\nまたね\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nこんにちは\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	var drafts [][]string
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				drafts = append(drafts, seg.Draft)
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:     inPath,
+		OutputPath:    filepath.Join(tmpDir, "out.srt"),
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     10,
+		Concurrency:   1,
+		SourceLang:    "ja",
+		TargetLang:    "ko",
+		NoPostprocess: true,
+		TMXPath:       tmxPath,
+	}
+	if _, err := RunTranslation(context.Background(), cfg); err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	f, err := os.Open(tmxPath)
+	if err != nil {
+		t.Fatalf("open TMX: %v", err)
+	}
+	units, err := tmx.Read(f, "ja", "ko")
+	f.Close()
+	if err != nil {
+		t.Fatalf("read TMX: %v", err)
+	}
+	want := []tmx.Unit{{Source: "こんにちは", Target: "T-こんにちは"}, {Source: "またね", Target: "T-またね"}}
+	if !reflect.DeepEqual(units, want) {
+		t.Fatalf("TMX units = %q, want %q", units, want)
+	}
+
+	drafts = nil
+	cfg.OutputPath = filepath.Join(tmpDir, "out2.srt")
+	cfg.TMXPath = ""
+	cfg.TMXImportPath = tmxPath
+	if _, err := RunTranslation(context.Background(), cfg); err != nil {
+		t.Fatalf("RunTranslation with TMX import failed: %v", err)
+	}
+	wantDrafts := [][]string{{"T-こんにちは"}, {"T-またね"}, {"T-こんにちは"}}
+	if !reflect.DeepEqual(drafts, wantDrafts) {
+		t.Fatalf("drafts = %q, want %q", drafts, wantDrafts)
+	}
+
+	cfg.OutputPath = filepath.Join(tmpDir, "out3.srt")
+	cfg.TMXImportPath = filepath.Join(tmpDir, "missing.tmx")
+	if _, err := RunTranslation(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error for a missing TMX file")
+	}
+}
+
+func TestRunTranslation_InPlaceKeepsBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "episode.srt")
+	original := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+	if err := os.WriteFile(inPath, []byte(original), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:     inPath,
+		OutputPath:    inPath,
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     10,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		NoPostprocess: true,
+	}
+
+	if _, err := RunTranslation(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "are the same") {
+		t.Fatalf("expected the same-file guard without in-place mode, got %v", err)
+	}
+
+	cfg.InPlace = true
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	backupPath := filepath.Join(tmpDir, "episode.orig.srt")
+	if result.BackupPath != backupPath || result.OutputPath != inPath {
+		t.Fatalf("result paths = backup %q, output %q", result.BackupPath, result.OutputPath)
+	}
+	backup, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatalf("read backup: %v", err)
+	}
+	if string(backup) != original {
+		t.Fatalf("backup = %q, want the untranslated original", backup)
+	}
+	translated, err := os.ReadFile(inPath)
+	if err != nil {
+		t.Fatalf("read input: %v", err)
+	}
+	if !strings.Contains(string(translated), "T-Hello") || !strings.Contains(string(translated), "T-World") {
+		t.Fatalf("input was not replaced with the translation: %q", translated)
+	}
+
+	// A second in-place run must not replace the backup of the original.
+	if _, err := RunTranslation(context.Background(), cfg); err == nil || !IsInputError(err) {
+		t.Fatalf("expected an input error for an existing backup, got %v", err)
+	}
+	if backup, _ := os.ReadFile(backupPath); string(backup) != original {
+		t.Fatalf("backup changed to %q", backup)
+	}
+}
+
+func TestRunTranslation_MaxCostStopsRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	var input strings.Builder
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(&input, "%d\n00:00:0%d,000 --> 00:00:0%d,500\nLine %d\n\n", i, i, i, i)
+	}
+	if err := os.WriteFile(inPath, []byte(input.String()), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	calls := 0
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			calls++
+			seg := req.Target[0]
+			// 1M prompt tokens on gemini-3-flash-preview = $0.50 per call.
+			return &gemini.ResponseData{
+				Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "T-" + seg.Lines[0]}},
+				Usage:        gemini.UsageMetadata{PromptTokenCount: 1_000_000, TotalTokenCount: 1_000_000},
+			}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		APIKey:        "test",
+		Model:         "gemini-3-flash-preview",
+		ChunkSize:     1,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		Overwrite:     true,
+		NoPostprocess: true,
+		MaxCost:       1.00,
+	}
+
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if !result.CostCapped {
+		t.Fatalf("expected run to be stopped by the cost cap")
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 API calls before the cap, got %d", calls)
+	}
+	if result.Status != TranslationStatusPartialSuccess || result.FailedChunks != 3 {
+		t.Fatalf("status = %q failed = %d, want Partial Success with 3 failed", result.Status, result.FailedChunks)
+	}
+	if result.OutputPath == "" || !result.PartialOutput {
+		t.Fatalf("expected partial output to be saved, got %+v", result)
+	}
+	log, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	if log.StatusReason != "cost_cap" {
+		t.Fatalf("status_reason = %q, want cost_cap", log.StatusReason)
+	}
+}
+
+func TestRunTranslation_TermMemoryCarriesAcrossFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	memoryPath := filepath.Join(tmpDir, "series_terms.json")
+	client := &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	}
+	withStubClient(t, client)
+
+	run := func(name, text string) {
+		t.Helper()
+		inPath := filepath.Join(tmpDir, name+".srt")
+		input := "1\n00:00:01,000 --> 00:00:02,000\n" + text + "\n"
+		if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+			t.Fatalf("write input: %v", err)
+		}
+		result, err := RunTranslation(context.Background(), Config{
+			InputPath:      inPath,
+			OutputPath:     filepath.Join(tmpDir, name+".out.srt"),
+			APIKey:         "test",
+			Model:          "m",
+			ChunkSize:      10,
+			Concurrency:    1,
+			SourceLang:     "en",
+			TargetLang:     "ko",
+			Overwrite:      true,
+			NoPostprocess:  true,
+			TermMemoryPath: memoryPath,
+		})
+		if err != nil || result.Status != TranslationStatusSuccess {
+			t.Fatalf("run %s: status %q err %v", name, result.Status, err)
+		}
+	}
+
+	run("ep1", "Magic Academy")
+	if strings.Contains(client.systemInstruction, "Magic Academy") {
+		t.Fatalf("first file should start with an empty term memory")
+	}
+	run("ep2", "Hello")
+	if !strings.Contains(client.systemInstruction, "- Magic Academy -> T-Magic Academy") {
+		t.Fatalf("expected episode 1 choice in episode 2 prompt, got:\n%s", client.systemInstruction)
+	}
+
+	info, err := os.Stat(memoryPath)
+	if err != nil {
+		t.Fatalf("stat term memory: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Fatalf("term memory perms = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestRunTranslation_KeepCueSettings(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "안녕"}}}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.vtt")
+	input := "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000 position:50% align:start\nHello\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		keep bool
+		ext  string
+		want bool
+	}{
+		{name: "vtt_kept", keep: true, ext: ".vtt", want: true},
+		{name: "vtt_default", keep: false, ext: ".vtt", want: false},
+		{name: "srt_output", keep: true, ext: ".srt", want: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := RunTranslation(context.Background(), Config{
+				InputPath:       inPath,
+				OutputPath:      filepath.Join(tmpDir, tc.name+tc.ext),
+				APIKey:          "test",
+				ChunkSize:       10,
+				Concurrency:     1,
+				SourceLang:      "en",
+				TargetLang:      "ko",
+				NoPostprocess:   true,
+				KeepCueSettings: tc.keep,
+			})
+			if err != nil || result.Status != TranslationStatusSuccess {
+				t.Fatalf("status %q err %v", result.Status, err)
+			}
+			data, err := os.ReadFile(result.OutputPath)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			content := string(data)
+			if got := strings.Contains(content, "align:start position:50%\n안녕"); got != tc.want {
+				t.Fatalf("cue settings kept = %v, want %v:\n%s", got, tc.want, content)
+			}
+		})
+	}
+}
+
+func TestRunTranslation_EmbedMetadata(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "안녕"}}}, nil
+		},
+	})
+	prevNow := provenanceNow
+	provenanceNow = func() time.Time { return time.Date(2026, 1, 30, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { provenanceNow = prevNow })
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	for _, ext := range []string{".vtt", ".srt"} {
+		t.Run(ext, func(t *testing.T) {
+			cfg := Config{
+				InputPath:     inPath,
+				OutputPath:    filepath.Join(tmpDir, "out"+ext),
+				APIKey:        "test",
+				Model:         "m",
+				ChunkSize:     10,
+				Concurrency:   1,
+				SourceLang:    "en",
+				TargetLang:    "ko",
+				NoPostprocess: true,
+				EmbedMetadata: true,
+			}
+			result, err := RunTranslation(context.Background(), cfg)
+			if err != nil || result.Status != TranslationStatusSuccess {
+				t.Fatalf("status %q err %v", result.Status, err)
+			}
+			data, err := os.ReadFile(result.OutputPath)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			content := string(data)
+			if ext == ".srt" {
+				if strings.Contains(content, "focst") {
+					t.Fatalf("SRT output must not carry metadata:\n%s", content)
+				}
+				return
+			}
+			for _, want := range []string{
+				"generated by: focst " + version.Version,
+				"model: m",
+				"source: en",
+				"target: ko",
+				"date: 2026-01-30T12:00:00Z",
+				"settings: " + cfg.provenanceSettings(cfg.planSettings("", "", "")).hash(),
+			} {
+				if !strings.Contains(content, want) {
+					t.Fatalf("expected %q in output:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestRunTranslation_PassesThroughNonTranslatable(t *testing.T) {
+	var requested []string
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				requested = append(requested, seg.Lines[0])
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nhttps://example.com\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\n123\n\n" +
+		"4\n00:00:07,000 --> 00:00:08,000\nWorld\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	for _, noSkip := range []bool{false, true} {
+		t.Run(fmt.Sprintf("no_skip_%v", noSkip), func(t *testing.T) {
+			requested = nil
+			outPath := filepath.Join(tmpDir, fmt.Sprintf("out_%v.srt", noSkip))
+			result, err := RunTranslation(context.Background(), Config{
+				InputPath:             inPath,
+				OutputPath:            outPath,
+				APIKey:                "test",
+				ChunkSize:             10,
+				Concurrency:           1,
+				SourceLang:            "en",
+				TargetLang:            "ko",
+				NoSkipNonTranslatable: noSkip,
+			})
+			if err != nil || result.Status != TranslationStatusSuccess {
+				t.Fatalf("status %q err %v", result.Status, err)
+			}
+
+			wantRequested := []string{"Hello", "World"}
+			wantOut := []string{"T-Hello", "https://example.com", "123", "T-World"}
+			if noSkip {
+				wantRequested = []string{"Hello", "https://example.com", "123", "World"}
+				wantOut = []string{"T-Hello", "T-https://example.com", "T-123", "T-World"}
+			}
+			if strings.Join(requested, "|") != strings.Join(wantRequested, "|") {
+				t.Fatalf("requested = %v, want %v", requested, wantRequested)
+			}
+			out, err := srt.Load(outPath)
+			if err != nil {
+				t.Fatalf("load output: %v", err)
+			}
+			var got []string
+			for _, seg := range out {
+				got = append(got, strings.Join(seg.Lines, " "))
+			}
+			if strings.Join(got, "|") != strings.Join(wantOut, "|") {
+				t.Fatalf("output = %v, want %v", got, wantOut)
+			}
+		})
+	}
+}
+
+func TestRunTranslation_Sample(t *testing.T) {
+	var requested []string
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				requested = append(requested, seg.Lines[0])
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	var input strings.Builder
+	for i := 1; i <= 6; i++ {
+		fmt.Fprintf(&input, "%d\n00:00:%02d,000 --> 00:00:%02d,500\nLine%d\n\n", i, i*2, i*2, i)
+	}
+	if err := os.WriteFile(inPath, []byte(input.String()), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	outPath := filepath.Join(tmpDir, "out.srt")
+	if err := os.WriteFile(outPath, []byte("keep me"), 0600); err != nil {
+		t.Fatalf("write existing output: %v", err)
+	}
+
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:   inPath,
+		OutputPath:  outPath,
+		APIKey:      "test",
+		ChunkSize:   10,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+		Sample:      2,
+	})
+	if err != nil || result.Status != TranslationStatusSuccess {
+		t.Fatalf("status %q err %v", result.Status, err)
+	}
+	samplePath := filepath.Join(tmpDir, "out.sample.srt")
+	if result.OutputPath != samplePath {
+		t.Fatalf("output path = %q, want %q", result.OutputPath, samplePath)
+	}
+	if strings.Join(requested, "|") != "Line1|Line2" {
+		t.Fatalf("requested = %v, want first two segments only", requested)
+	}
+	out, err := srt.Load(samplePath)
+	if err != nil {
+		t.Fatalf("load sample: %v", err)
+	}
+	if len(out) != 2 || out[0].Lines[0] != "T-Line1" || out[1].Lines[0] != "T-Line2" {
+		t.Fatalf("unexpected sample output: %+v", out)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil || string(data) != "keep me" {
+		t.Fatalf("real output changed: %q (err %v)", data, err)
+	}
+}
+
+func TestSampleOutputPath(t *testing.T) {
+	if got := SampleOutputPath(filepath.Join("dir", "movie.ko.srt")); got != filepath.Join("dir", "movie.ko.sample.srt") {
+		t.Fatalf("SampleOutputPath = %q", got)
+	}
+}
+
+func TestRunTranslation_OnEstimate(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	var input strings.Builder
+	for i := 1; i <= 4; i++ {
+		fmt.Fprintf(&input, "%d\n00:00:0%d,000 --> 00:00:0%d,500\nLine %d\n\n", i, i, i, i)
+	}
+	if err := os.WriteFile(inPath, []byte(input.String()), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			// 1M prompt tokens on gemini-3-flash-preview = $0.50 per call.
+			return &gemini.ResponseData{
+				Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "T-" + seg.Lines[0]}},
+				Usage:        gemini.UsageMetadata{PromptTokenCount: 1_000_000, TotalTokenCount: 1_000_000},
+			}, nil
+		},
+	})
+	var estimates []Estimate
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		APIKey:        "test",
+		Model:         "gemini-3-flash-preview",
+		ChunkSize:     1,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		Overwrite:     true,
+		NoPostprocess: true,
+		OnEstimate:    func(e Estimate) { estimates = append(estimates, e) },
+	})
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if len(estimates) != 4 {
+		t.Fatalf("got %d estimates, want one per chunk", len(estimates))
+	}
+	if got := estimates[0]; got.Completed != 1 || got.Scheduled != 4 || got.ProjectedCost != 2.00 {
+		t.Fatalf("first estimate = %+v, want 1/4 chunks and $2.00 projected", got)
+	}
+	if result.Throughput.Completed != 4 || result.Throughput.Usage.TotalTokenCount != 4_000_000 {
+		t.Fatalf("result throughput = %+v", result.Throughput)
+	}
+}
+
+func TestRunTranslation_ExplicitFormats(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.txt")
+	if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n\n"), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	outPath := filepath.Join(tmpDir, "output.sub")
+
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		InputFormat:   "srt",
+		OutputFormat:  "vtt",
+		APIKey:        "test",
+		Model:         "gemini-3-flash-preview",
+		ChunkSize:     10,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		Overwrite:     true,
+		NoPostprocess: true,
+	})
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if result.Status != TranslationStatusSuccess {
+		t.Fatalf("status = %q", result.Status)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "WEBVTT") || !strings.Contains(string(data), "T-Hello") {
+		t.Fatalf("expected WebVTT output with the translation, got:\n%s", data)
+	}
+
+	cfg := Config{APIKey: "test", ChunkSize: 1, Concurrency: 1, InputFormat: "docx"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid input format") {
+		t.Fatalf("Validate() = %v, want invalid input format", err)
+	}
+}
+
+func TestRunTranslation_InMemoryInput(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			return nil, fmt.Errorf("boom")
+		},
+	})
+	outPath := filepath.Join(t.TempDir(), "output.srt")
+
+	cfg := Config{
+		InputPath:            "https://example.com/in.srt",
+		InputData:            []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n\n"),
+		InputFormat:          "srt",
+		OutputPath:           outPath,
+		APIKey:               "test",
+		Model:                "gemini-3-flash-preview",
+		ChunkSize:            10,
+		Concurrency:          1,
+		SourceLang:           "en",
+		TargetLang:           "ko",
+		Overwrite:            true,
+		SavePartialOnFailure: true,
+	}
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if result.Status != TranslationStatusFailure || !result.PartialOutput {
+		t.Fatalf("status = %q, partial = %v", result.Status, result.PartialOutput)
+	}
+	if result.RecoveryLogPath != "" {
+		t.Fatalf("expected no recovery log for in-memory input, got %s", result.RecoveryLogPath)
+	}
+
+	cfg.InputFormat = ""
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "input format is required") {
+		t.Fatalf("Validate() = %v, want input format required", err)
+	}
+}
+
+func TestRunTranslation_InputErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := Config{
+		InputPath:   filepath.Join(tmpDir, "missing.srt"),
+		OutputPath:  filepath.Join(tmpDir, "out.srt"),
+		APIKey:      "test",
+		Model:       "gemini-3-flash-preview",
+		ChunkSize:   10,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+	}
+	if _, err := RunTranslation(context.Background(), cfg); !IsInputError(err) {
+		t.Fatalf("missing input: err = %v, want an InputError", err)
+	}
+	cfg.TargetLang, cfg.ImproveDrafts = "fr", true
+	if _, err := RunTranslation(context.Background(), cfg); !IsInputError(err) || !strings.Contains(err.Error(), "--improve") {
+		t.Fatalf("improve with shared script: err = %v, want an InputError", err)
+	}
+	cfg.ImproveDrafts = false
+	cfg.TargetLang = "xx"
+	if _, err := RunTranslation(context.Background(), cfg); !IsInputError(err) {
+		t.Fatalf("unsupported language: err = %v, want an InputError", err)
+	}
+	cfg.ChunkSize = 0
+	if _, err := RunTranslation(context.Background(), cfg); !IsInputError(err) || !strings.Contains(err.Error(), "invalid configuration") {
+		t.Fatalf("invalid config: err = %v, want an InputError", err)
+	}
+}
+
+func TestRunTranslation_ZeroDurationCues(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			resp := &gemini.ResponseData{}
+			for _, seg := range req.Target {
+				resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "안녕"})
+			}
+			return resp, nil
+		},
+	})
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "output.srt")
+	src := "1\n00:00:01,000 --> 00:00:01,000\nHi\n\n2\n00:00:01,300 --> 00:00:02,300\nBye\n\n"
+	if err := os.WriteFile(inPath, []byte(src), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:   inPath,
+		OutputPath:  outPath,
+		APIKey:      "test",
+		Model:       "gemini-3-flash-preview",
+		ChunkSize:   10,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+	})
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if result.Status != TranslationStatusSuccess || result.ZeroDurationCues != 1 {
+		t.Fatalf("status = %q, zero-duration cues = %d, want success and 1", result.Status, result.ZeroDurationCues)
+	}
+	out, err := srt.Load(outPath)
+	if err != nil {
+		t.Fatalf("load output: %v", err)
+	}
+	if out[0].EndTime != "00:00:01,295" || out[1].StartTime != "00:00:01,300" {
+		t.Fatalf("zero-duration cue timing = %s-%s, next starts %s", out[0].StartTime, out[0].EndTime, out[1].StartTime)
+	}
+}
+
+// hangingClient answers the first request and then blocks every later one
+// until its context is canceled, like a worker pool stuck on hung API calls.
+type hangingClient struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *hangingClient) Translate(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	c.mu.Lock()
+	c.calls++
+	first := c.calls == 1
+	c.mu.Unlock()
+	if first {
+		resp := &gemini.ResponseData{}
+		for _, seg := range req.Target {
+			resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "번역"})
+		}
+		return resp, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *hangingClient) SetSystemInstruction(string) {}
+
+func (c *hangingClient) Close() error { return nil }
+
+func TestRunTranslation_CancelOnStall(t *testing.T) {
+	prev := newGeminiClient
+	newGeminiClient = func(context.Context, string, string, gemini.ClientOptions) (translationClient, error) {
+		return &hangingClient{}, nil
+	}
+	t.Cleanup(func() { newGeminiClient = prev })
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	cfg := Config{
+		InputPath:   inPath,
+		OutputPath:  filepath.Join(tmpDir, "out.srt"),
+		APIKey:      "test",
+		ChunkSize:   1,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+		// Longer than the rate limiter's first tick, so the first chunk can finish.
+		StallTimeout:  time.Second,
+		CancelOnStall: true,
+	}
+
+	done := make(chan struct{})
+	var result TranslationResult
+	var err error
+	go func() {
+		result, err = RunTranslation(context.Background(), cfg)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("stall watchdog did not cancel the hung run")
+	}
+	if err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	if result.Status != TranslationStatusPartialSuccess || result.FailedChunks != 1 || result.RecoveryLogPath == "" {
+		t.Fatalf("expected partial success with the hung chunk left for repair, got %+v", result)
+	}
+}
+
+func TestStallWatchdog_WarnsAgainUntilProgress(t *testing.T) {
+	w := newStallWatchdog(20*time.Millisecond, nil)
+	defer w.stop()
+	onProgress := w.wrap(nil)
+
+	time.Sleep(70 * time.Millisecond)
+	if n := w.tripped(); n < 2 {
+		t.Fatalf("expected repeated stall warnings, got %d", n)
+	}
+
+	// Steady completions keep the watchdog from tripping.
+	onProgress(translator.TranslationProgress{State: translator.StateCompleted})
+	before := w.tripped()
+	for i := 0; i < 5; i++ {
+		onProgress(translator.TranslationProgress{State: translator.StateCompleted})
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := w.tripped(); n != before {
+		t.Fatalf("watchdog tripped despite completions: %d -> %d", before, n)
+	}
+}
+
+func TestRunTranslation_PriorityFirstStreamsPartialOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	outPath := filepath.Join(tmpDir, "output.srt")
+	partialPath := filepath.Join(tmpDir, "output.partial.srt")
+	var seenBeforeLast string
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			if seg.ID == 2 {
+				// Chunk 0 has been flushed by the time chunk 1 is sent.
+				data, _ := os.ReadFile(partialPath)
+				seenBeforeLast = string(data)
+			}
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "T-" + seg.Lines[0]}}}, nil
+		},
+	})
+
+	inPath := filepath.Join(tmpDir, "input.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	cfg := Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     1,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		PriorityFirst: true,
+	}
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil || result.Status != TranslationStatusSuccess {
+		t.Fatalf("RunTranslation = %q, %v", result.Status, err)
+	}
+	if !strings.Contains(seenBeforeLast, "T-Hello") || strings.Contains(seenBeforeLast, "World") {
+		t.Fatalf("incremental output before the last chunk = %q, want only the first chunk", seenBeforeLast)
+	}
+	if _, err := os.Stat(partialPath); !os.IsNotExist(err) {
+		t.Fatalf("incremental output should be removed once the output is saved, stat err=%v", err)
+	}
+}
+
+func TestRunTranslation_LargeRunNeedsConfirmation(t *testing.T) {
+	nonInteractive := prompt.Confirmer{IsInteractive: func() bool { return false }}
+	tests := []struct {
+		name   string
+		cfg    Config
+		yes    bool
+		wantOK bool
+	}{
+		{name: "max segments blocks without --yes", cfg: Config{MaxSegments: 1}},
+		{name: "cost threshold blocks without --yes", cfg: Config{ConfirmOverCost: 0.000001}},
+		{name: "--yes proceeds", cfg: Config{MaxSegments: 1}, yes: true, wantOK: true},
+		{name: "under the thresholds", cfg: Config{MaxSegments: 2, ConfirmOverCost: 100}, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			calls := 0
+			withStubClient(t, &stubTranslationClient{
+				translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+					calls++
+					seg := req.Target[0]
+					return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "T-" + seg.Lines[0]}}}, nil
+				},
+			})
+			inPath := filepath.Join(tmpDir, "input.srt")
+			input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+			if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+			cfg := tt.cfg
+			cfg.InputPath = inPath
+			cfg.OutputPath = filepath.Join(tmpDir, "output.srt")
+			cfg.APIKey = "test"
+			cfg.Model = "m"
+			cfg.ChunkSize = 1
+			cfg.Concurrency = 1
+			cfg.SourceLang = "en"
+			cfg.TargetLang = "ko"
+			cfg.OnConfirmLargeRun = func(reason string) (bool, error) {
+				return nonInteractive.ConfirmLargeRun(reason, tt.yes)
+			}
+
+			result, err := RunTranslation(context.Background(), cfg)
+			if tt.wantOK {
+				if err != nil || result.Status != TranslationStatusSuccess {
+					t.Fatalf("RunTranslation = %q, %v", result.Status, err)
+				}
+				return
+			}
+			if !IsInputError(err) {
+				t.Fatalf("expected input error, got %v", err)
+			}
+			if calls != 0 {
+				t.Fatalf("client called %d times before confirmation", calls)
+			}
+			if _, err := os.Stat(cfg.OutputPath); !os.IsNotExist(err) {
+				t.Fatalf("output written without confirmation, stat err=%v", err)
+			}
+		})
+	}
+}
+
+func TestRunTranslation_DropsEmptiedSegmentsAndKeepsPassthrough(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nSkip me.\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nBye\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	replies := map[string]string{"Hello": "。", "Bye": "再见"}
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: replies[seg.Lines[0]]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:          inPath,
+		OutputPath:         outPath,
+		APIKey:             "test",
+		Model:              "m",
+		ChunkSize:          10,
+		Concurrency:        1,
+		SourceLang:         "en",
+		TargetLang:         "zh-Hans",
+		NoPreprocess:       true,
+		NoTimingCorrection: true,
+		FilterRegex:        "^(Hello|Bye)$",
+		OnEmptied:          string(srt.EmptiedDrop),
+	}
+	if _, err := RunTranslation(context.Background(), cfg); err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	want := "\ufeff1\n00:00:03,000 --> 00:00:04,000\nSkip me.\n\n2\n00:00:05,000 --> 00:00:06,000\n再见\n"
+	if string(data) != want {
+		t.Fatalf("output = %q, want %q", data, want)
+	}
+}
+
+func TestRunTranslation_ExcludeIDsKeepsSegmentsVerbatim(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	excludedBlocks := "2\n00:00:03,000 --> 00:00:03,200\n이미 번역했어요.\n\n3\n00:00:05,000 --> 00:00:06,000\n- 네.\n- 아니요.\n\n"
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n" + excludedBlocks +
+		"4\n00:00:07,000 --> 00:00:08,000\nBye\n\n5\n00:00:09,000 --> 00:00:10,000\nThanks\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	var mu sync.Mutex
+	var sent []int
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			mu.Lock()
+			for _, seg := range append(req.ContextBefore, req.ContextAfter...) {
+				sent = append(sent, seg.ID)
+			}
+			for _, seg := range req.Target {
+				sent = append(sent, seg.ID)
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "번역 " + seg.Lines[0]})
+			}
+			mu.Unlock()
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+	cfg := Config{
+		InputPath:   inPath,
+		OutputPath:  outPath,
+		APIKey:      "test",
+		Model:       "m",
+		ChunkSize:   2,
+		ContextSize: 1,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+		ExcludeIDs:  "2-3",
+	}
+	if _, err := RunTranslation(context.Background(), cfg); err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+	for _, id := range sent {
+		if id == 2 || id == 3 {
+			t.Fatalf("excluded segment %d was sent to the model (sent %v)", id, sent)
+		}
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), "\n\n"+excludedBlocks) {
+		t.Fatalf("excluded segments changed:\n%s", data)
+	}
+	for _, want := range []string{"번역 Hello", "번역 Bye", "번역 Thanks"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output is missing %q:\n%s", want, data)
+		}
+	}
+}
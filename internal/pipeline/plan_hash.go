@@ -0,0 +1,144 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// planSettings are the settings that decide what the model is asked for each
+// chunk and which of its answers are kept: the models, the language pair, how
+// segments are grouped, the prompt, response validation, and the texts added
+// to the prompt (background, term memory, translation memory drafts), by
+// hash. Runtime settings (concurrency, rate limits, retries) and pre- and
+// post-processing are left out; provenanceSettings adds the latter.
+//
+// Settings added after the first version are omitted when unset so the hash
+// of runs that don't use them stays the same.
+type planSettings struct {
+	Model         string            `json:"model"`
+	SourceLang    string            `json:"source_lang"`
+	TargetLang    string            `json:"target_lang"`
+	ChunkSize     int               `json:"chunk_size"`
+	ContextSize   int               `json:"context_size"`
+	NoPromptCPL   bool              `json:"no_prompt_cpl"`
+	SingleLine    bool              `json:"single_line"`
+	Formality     string            `json:"formality"`
+	NarrativeTag  string            `json:"narrative_tag"`
+	ImproveDrafts bool              `json:"improve_drafts"`
+	Names         map[string]string `json:"names,omitempty"`
+	PolishModel   string            `json:"polish_model,omitempty"`
+
+	KeepDialogueDashes bool    `json:"keep_dialogue_dashes,omitempty"`
+	OnEmpty            string  `json:"on_empty,omitempty"`
+	AutoLinebreak      bool    `json:"auto_linebreak,omitempty"`
+	DedupRepeats       bool    `json:"dedup_repeats,omitempty"`
+	RetryOnLongLines   bool    `json:"retry_on_long_lines,omitempty"`
+	CPLMetric          string  `json:"cpl_metric,omitempty"`
+	CPLTolerance       float64 `json:"cpl_tolerance,omitempty"`
+	TranslateNotes     bool    `json:"translate_notes,omitempty"`
+
+	Background string `json:"background,omitempty"`
+	Terms      string `json:"terms,omitempty"`
+	Drafts     string `json:"drafts,omitempty"`
+}
+
+// PlanHash returns a stable fingerprint of the translation plan of cfg with
+// the background text, term memory entries, and translation memory drafts the
+// run sends (empty for none). Two configurations with the same hash send the
+// same requests for the same input and keep the same answers. Language
+// aliases hash like the code they resolve to.
+func PlanHash(cfg Config, background string, terms []translator.TermEntry, drafts map[string]string) string {
+	return cfg.planSettings(optionalBackgroundHash(background), termsHash(terms), draftsHash(drafts)).hash()
+}
+
+// planSettings returns the plan of c. backgroundSum, termsSum, and draftsSum
+// identify the texts the run adds to the prompt (see backgroundHash,
+// termsHash, and draftsHash).
+func (c Config) planSettings(backgroundSum, termsSum, draftsSum string) planSettings {
+	s := planSettings{
+		Model:              c.Model,
+		SourceLang:         languageCode(c.SourceLang),
+		TargetLang:         languageCode(c.TargetLang),
+		ChunkSize:          c.ChunkSize,
+		ContextSize:        c.ContextSize,
+		NoPromptCPL:        c.NoPromptCPL,
+		SingleLine:         c.SingleLine,
+		Formality:          formalitySetting(c.Formality),
+		NarrativeTag:       c.NarrativeTag,
+		ImproveDrafts:      c.ImproveDrafts,
+		Names:              c.NamesMapping,
+		PolishModel:        c.PolishModel,
+		KeepDialogueDashes: c.KeepDialogueDashes,
+		AutoLinebreak:      c.AutoLinebreak,
+		DedupRepeats:       c.DedupRepeats,
+		RetryOnLongLines:   c.RetryOnLongLines,
+		TranslateNotes:     c.TranslateNotes,
+		Background:         backgroundSum,
+		Terms:              termsSum,
+		Drafts:             draftsSum,
+	}
+	if policy, err := translator.ParseEmptyPolicy(c.OnEmpty); err == nil && policy != translator.EmptyPolicyFail {
+		s.OnEmpty = string(policy)
+	}
+	// The metric and tolerance only matter when responses are validated.
+	if c.RetryOnLongLines {
+		if metric, err := translator.ParseCPLMetric(c.CPLMetric); err == nil && metric != translator.CPLMetricGraphemes {
+			s.CPLMetric = string(metric)
+		}
+		s.CPLTolerance = c.CPLTolerance
+		if s.CPLTolerance == 0 {
+			s.CPLTolerance = translator.DefaultCPLTolerance
+		}
+	}
+	return s
+}
+
+// optionalBackgroundHash is backgroundHash, or "" without a background.
+func optionalBackgroundHash(background string) string {
+	if background == "" {
+		return ""
+	}
+	return backgroundHash(background)
+}
+
+// termsHash identifies the term memory entries a run sent in its prompt, or
+// returns "" when there were none.
+func termsHash(entries []translator.TermEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(entries)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// draftsHash identifies the translation memory drafts a run sent, or returns
+// "" when there were none.
+func draftsHash(drafts map[string]string) string {
+	if len(drafts) == 0 {
+		return ""
+	}
+	// encoding/json sorts map keys, so equal maps hash alike.
+	data, _ := json.Marshal(drafts)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// languageCode resolves a language ID or alias to its code, keeping unknown
+// IDs as they are.
+func languageCode(id string) string {
+	if lang, ok := language.GetLanguage(id); ok {
+		return lang.Code
+	}
+	return id
+}
+
+func (s planSettings) hash() string {
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
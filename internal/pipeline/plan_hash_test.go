@@ -0,0 +1,166 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+func planTestConfig() Config {
+	return Config{
+		Model:        "gemini-test",
+		SourceLang:   "ja",
+		TargetLang:   "ko",
+		ChunkSize:    100,
+		ContextSize:  5,
+		Concurrency:  7,
+		APIKey:       "k",
+		NamesMapping: map[string]string{"太郎": "타로", "花子": "하나코"},
+	}
+}
+
+func TestPlanHash_RelevantFields(t *testing.T) {
+	base := PlanHash(planTestConfig(), "", nil, nil)
+	if len(base) != 16 {
+		t.Fatalf("PlanHash = %q, want 16 hex characters", base)
+	}
+	if again := PlanHash(planTestConfig(), "", nil, nil); again != base {
+		t.Fatalf("PlanHash not stable: %q then %q", base, again)
+	}
+	changes := map[string]func(*Config){
+		"model":            func(c *Config) { c.Model = "gemini-other" },
+		"source":           func(c *Config) { c.SourceLang = "en" },
+		"target":           func(c *Config) { c.TargetLang = "zh-Hant" },
+		"chunk size":       func(c *Config) { c.ChunkSize = 50 },
+		"context size":     func(c *Config) { c.ContextSize = 0 },
+		"no prompt cpl":    func(c *Config) { c.NoPromptCPL = true },
+		"single line":      func(c *Config) { c.SingleLine = true },
+		"formality":        func(c *Config) { c.Formality = "formal" },
+		"narrative tag":    func(c *Config) { c.NarrativeTag = "forced" },
+		"improve drafts":   func(c *Config) { c.ImproveDrafts = true },
+		"names":            func(c *Config) { c.NamesMapping = map[string]string{"太郎": "타로"} },
+		"no names":         func(c *Config) { c.NamesMapping = nil },
+		"dialogue dashes":  func(c *Config) { c.KeepDialogueDashes = true },
+		"on empty":         func(c *Config) { c.OnEmpty = "keep-source" },
+		"auto linebreak":   func(c *Config) { c.AutoLinebreak = true },
+		"dedup repeats":    func(c *Config) { c.DedupRepeats = true },
+		"retry long lines": func(c *Config) { c.RetryOnLongLines = true },
+		"translate notes":  func(c *Config) { c.TranslateNotes = true },
+	}
+	for name, change := range changes {
+		cfg := planTestConfig()
+		change(&cfg)
+		if PlanHash(cfg, "", nil, nil) == base {
+			t.Errorf("changing %s did not change the plan hash", name)
+		}
+	}
+}
+
+func TestPlanHash_IrrelevantFields(t *testing.T) {
+	base := PlanHash(planTestConfig(), "", nil, nil)
+	changes := map[string]func(*Config){
+		"concurrency":      func(c *Config) { c.Concurrency = 2 },
+		"api key":          func(c *Config) { c.APIKey = "other" },
+		"paths":            func(c *Config) { c.InputPath, c.OutputPath = "a.srt", "b.srt" },
+		"ramp up":          func(c *Config) { c.RampUp = time.Second },
+		"unvalidated cpl":  func(c *Config) { c.CPLMetric, c.CPLTolerance = "width", 2 },
+		"default on empty": func(c *Config) { c.OnEmpty = "fail" },
+		"no postprocess":   func(c *Config) { c.NoPostprocess = true },
+		"compat profile":   func(c *Config) { c.CompatProfile = "legacy" },
+		"overwrite":        func(c *Config) { c.Overwrite = true },
+		"auto formality":   func(c *Config) { c.Formality = "auto" },
+		"names copy": func(c *Config) {
+			c.NamesMapping = map[string]string{"花子": "하나코", "太郎": "타로"}
+		},
+	}
+	for name, change := range changes {
+		cfg := planTestConfig()
+		change(&cfg)
+		if got := PlanHash(cfg, "", nil, nil); got != base {
+			t.Errorf("changing %s changed the plan hash: %q, want %q", name, got, base)
+		}
+	}
+}
+
+func TestPlanHash_Validation(t *testing.T) {
+	cfg := planTestConfig()
+	cfg.RetryOnLongLines = true
+	base := PlanHash(cfg, "", nil, nil)
+	cfg.CPLTolerance = 1.5
+	if got := PlanHash(cfg, "", nil, nil); got != base {
+		t.Fatalf("the default tolerance changed the plan hash: %q, want %q", got, base)
+	}
+	cfg.CPLTolerance = 1.2
+	if PlanHash(cfg, "", nil, nil) == base {
+		t.Fatal("changing the CPL tolerance did not change the plan hash")
+	}
+	cfg.CPLTolerance = 0
+	cfg.CPLMetric = "width"
+	if PlanHash(cfg, "", nil, nil) == base {
+		t.Fatal("changing the CPL metric did not change the plan hash")
+	}
+}
+
+func TestPlanHash_PromptTexts(t *testing.T) {
+	base := PlanHash(planTestConfig(), "", nil, nil)
+	withBackground := PlanHash(planTestConfig(), "A story about two siblings.", nil, nil)
+	if withBackground == base {
+		t.Fatal("adding a background did not change the plan hash")
+	}
+	if PlanHash(planTestConfig(), "A story about three siblings.", nil, nil) == withBackground {
+		t.Fatal("changing the background did not change the plan hash")
+	}
+	terms := []translator.TermEntry{{Source: "Captain", Target: "선장"}}
+	if PlanHash(planTestConfig(), "", terms, nil) == base {
+		t.Fatal("adding term memory entries did not change the plan hash")
+	}
+	if PlanHash(planTestConfig(), "", nil, map[string]string{"Hello": "안녕"}) == base {
+		t.Fatal("adding translation memory drafts did not change the plan hash")
+	}
+}
+
+func TestRepairConfig_MatchesPlanHash(t *testing.T) {
+	cfg := planTestConfig()
+	cfg.SourceLang, cfg.TargetLang = "zh", "en"
+	cfg.Formality = "informal"
+	cfg.SingleLine = true
+	cfg.PolishModel = "gemini-polish"
+	cfg.KeepDialogueDashes = true
+	cfg.RetryOnLongLines = true
+	cfg.CPLTolerance = 2
+	cfg.TranslateNotes = true
+	const background = "A story about two siblings."
+	terms := []translator.TermEntry{{Source: "Captain", Target: "Skipper"}}
+	log := &recovery.SessionLog{
+		Model:              cfg.Model,
+		SourceLang:         "zh-Hans",
+		TargetLang:         "en",
+		ChunkSize:          cfg.ChunkSize,
+		ContextSize:        cfg.ContextSize,
+		Concurrency:        3,
+		Formality:          cfg.Formality,
+		SingleLine:         true,
+		PolishModel:        cfg.PolishModel,
+		KeepDialogueDashes: true,
+		RetryOnLongLines:   true,
+		CPLTolerance:       2,
+		TranslateNotes:     true,
+		TermsHash:          termsHash(terms),
+	}
+	runtime := Config{APIKey: "other", Concurrency: 1}
+	want := PlanHash(cfg, background, terms, nil)
+	repairPlan := func(names map[string]string, background string) string {
+		return repairConfig(runtime, log, names).planSettings(optionalBackgroundHash(background), log.TermsHash, log.DraftsHash).hash()
+	}
+	if got := repairPlan(cfg.NamesMapping, background); got != want {
+		t.Fatalf("repair plan hash = %q, want %q", got, want)
+	}
+	if repairPlan(nil, background) == want {
+		t.Fatal("repair plan without the names mapping hashes like the original run")
+	}
+	if repairPlan(cfg.NamesMapping, "") == want {
+		t.Fatal("repair plan without the background hashes like the original run")
+	}
+}
@@ -0,0 +1,130 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/metadata"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// polishSegments runs the second pass of a run with Config.PolishModel. Every
+// chunk the first pass translated is sent again to the polish model, each
+// segment carrying its first-pass translation as a draft (see
+// Translator.SetDraftMemory). chunks and failed are the chunks of the first
+// pass and those that failed, numbered like the translator's (nil chunks
+// means all), and spans are the segment ranges the first pass recorded for
+// them. Failed chunks are not polished, and chunks that fail to polish
+// keep their first-pass lines. spent is the usage of the first pass, which
+// counts toward Config.MaxCost.
+func polishSegments(ctx context.Context, cfg Config, segments, translated []srt.Segment, selected []int, spans []chunker.Span, chunks, failed []int, srcLang, tgtLang language.Language, termMemory *translator.TermMemory, background string, spent gemini.UsageMetadata) ([]srt.Segment, gemini.UsageMetadata) {
+	positions := selected
+	if positions == nil {
+		positions = make([]int, len(segments))
+		for i := range positions {
+			positions[i] = i
+		}
+	}
+	if chunks == nil {
+		chunks = make([]int, len(spans))
+		for i := range chunks {
+			chunks[i] = i
+		}
+	}
+	skip := make(map[int]bool, len(failed))
+	for _, c := range failed {
+		skip[c] = true
+	}
+	var polish []int
+	drafts := make(map[string]string)
+	for _, c := range chunks {
+		if skip[c] {
+			continue
+		}
+		polish = append(polish, c)
+		for _, idx := range chunkPositions(positions, spans, c) {
+			drafts[strings.Join(segments[idx].Lines, "\n")] = strings.Join(translated[idx].Lines, "\n")
+		}
+	}
+	if len(polish) == 0 {
+		return translated, gemini.UsageMetadata{}
+	}
+
+	polishCfg := cfg
+	polishCfg.Model = cfg.PolishModel
+	gClient, err := newGeminiClient(ctx, cfg.APIKey, cfg.PolishModel, cfg.geminiClientOptions())
+	if err != nil {
+		logger.Warn("Failed to create polish client; keeping the first-pass translation", "model", cfg.PolishModel, "error", err)
+		return translated, gemini.UsageMetadata{}
+	}
+	defer gClient.Close()
+	// The chunk cache holds first-pass translations, so it is not used here.
+	tr, err := newTranslator(polishCfg, gClient, srcLang, tgtLang, nil, termMemory, drafts, background)
+	if err != nil {
+		logger.Warn("Failed to start polish pass; keeping the first-pass translation", "error", err)
+		return translated, gemini.UsageMetadata{}
+	}
+
+	var onProgress func(translator.TranslationProgress)
+	var guard *costGuard
+	if cfg.MaxCost > 0 {
+		remaining := cfg.MaxCost - metadata.EstimateGeminiCost(cfg.Model, spent.PromptTokenCount, spent.CandidatesTokenCount, spent.TotalTokenCount)
+		if remaining <= 0 {
+			logger.Warn("Cost cap reached before polishing; keeping the first-pass translation", "max_cost", cfg.MaxCost)
+			return translated, gemini.UsageMetadata{}
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		guard = newCostGuard(cfg.PolishModel, remaining, tr.GetUsage, cancel)
+		onProgress = guard.wrap(nil)
+	}
+
+	logger.Info("Starting polish pass", "model", cfg.PolishModel, "chunks", len(polish))
+	var polished []srt.Segment
+	var polishFailed []int
+	if selected != nil {
+		polished, polishFailed, err = tr.TranslateSubset(ctx, segments, selected, polish, onProgress)
+	} else {
+		polished, polishFailed, err = tr.TranslateChunks(ctx, segments, polish, onProgress)
+	}
+	if err != nil {
+		logger.Warn("Polish pass failed; keeping the first-pass translation", "error", err)
+		return translated, tr.GetUsage()
+	}
+	if guard != nil && guard.exceeded() {
+		logger.Warn("Polish pass stopped by cost cap", "max_cost", cfg.MaxCost)
+	}
+	if len(polishFailed) > 0 {
+		logger.Warn("Some chunks could not be polished; keeping their first-pass translation", "chunks", len(polishFailed))
+	}
+
+	for _, c := range polishFailed {
+		skip[c] = true
+	}
+	out := append([]srt.Segment(nil), translated...)
+	for _, c := range polish {
+		if skip[c] {
+			continue
+		}
+		for _, idx := range chunkPositions(positions, spans, c) {
+			// Only the lines: notes were translated after the first pass.
+			out[idx].Lines = polished[idx].Lines
+		}
+	}
+	return out, tr.GetUsage()
+}
+
+// chunkPositions returns the segment indices chunk c covers among positions.
+func chunkPositions(positions []int, spans []chunker.Span, c int) []int {
+	if c < 0 || c >= len(spans) {
+		return nil
+	}
+	start := min(spans[c].Offset, len(positions))
+	return positions[start:min(start+spans[c].Len, len(positions))]
+}
@@ -0,0 +1,50 @@
+package pipeline
+
+import (
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// SystemPrompt returns the system instruction a translation with opts would
+// send, including the names mapping, background information, and any term
+// memory, without creating an API client. A missing term memory file counts as empty.
+func SystemPrompt(opts Options) (string, error) {
+	cfg, _ := configFromOptions("", "", "", opts).Normalize()
+	srcLang, ok := language.GetLanguage(cfg.SourceLang)
+	if !ok {
+		return "", inputErrorf("unsupported source language: %s", cfg.SourceLang)
+	}
+	tgtLang, ok := language.GetLanguage(cfg.TargetLang)
+	if !ok {
+		return "", inputErrorf("unsupported target language: %s", cfg.TargetLang)
+	}
+	var termMemory *translator.TermMemory
+	if cfg.TermMemoryPath != "" {
+		var err error
+		termMemory, err = loadTermMemory(cfg.TermMemoryPath, srcLang.Code, tgtLang.Code)
+		if err != nil {
+			return "", err
+		}
+	}
+	var drafts map[string]string
+	if cfg.TMXImportPath != "" {
+		var err error
+		drafts, err = loadDraftMemory(cfg.TMXImportPath, srcLang.Code, tgtLang.Code)
+		if err != nil {
+			return "", err
+		}
+	}
+	var background string
+	if cfg.BackgroundPath != "" {
+		var err error
+		background, err = loadBackground(cfg.BackgroundPath)
+		if err != nil {
+			return "", err
+		}
+	}
+	tr, err := newTranslator(cfg, nil, srcLang, tgtLang, nil, termMemory, drafts, background)
+	if err != nil {
+		return "", err
+	}
+	return tr.SystemPrompt(), nil
+}
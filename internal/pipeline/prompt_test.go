@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSystemPrompt(t *testing.T) {
+	opts := DefaultOptions()
+	opts.SourceLang = "ja"
+	opts.TargetLang = "ko"
+	opts.NamesMapping = map[string]string{"太郎": "타로", "花子": "하나코"}
+
+	prompt, err := SystemPrompt(opts)
+	if err != nil {
+		t.Fatalf("SystemPrompt: %v", err)
+	}
+	section := "CRITICAL: The following character names MUST be translated as specified:\n- 太郎 -> 타로\n- 花子 -> 하나코\n"
+	if !strings.Contains(prompt, section) {
+		t.Fatalf("prompt missing sorted names section:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "characters or less") {
+		t.Fatalf("expected CPL constraints in the default prompt:\n%s", prompt)
+	}
+
+	opts.NoPromptCPL = true
+	prompt, err = SystemPrompt(opts)
+	if err != nil {
+		t.Fatalf("SystemPrompt(NoPromptCPL): %v", err)
+	}
+	if strings.Contains(prompt, "characters or less") {
+		t.Fatalf("expected no CPL constraints with NoPromptCPL:\n%s", prompt)
+	}
+
+	opts.TargetLang = "xx"
+	if _, err := SystemPrompt(opts); !IsInputError(err) {
+		t.Fatalf("expected input error for unsupported target, got %v", err)
+	}
+}
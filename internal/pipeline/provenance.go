@@ -0,0 +1,130 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
+	"github.com/oukeidos/focst/internal/version"
+)
+
+// provenanceNow is swapped in tests.
+var provenanceNow = time.Now
+
+// provenanceSettings are the output-affecting settings covered by the
+// settings hash: the translation plan plus pre- and post-processing. They
+// mirror the session log so repair reproduces the hash.
+type provenanceSettings struct {
+	planSettings
+	NoPreprocess       bool   `json:"no_preprocess"`
+	NoPostprocess      bool   `json:"no_postprocess"`
+	NoLangPreprocess   bool   `json:"no_lang_preprocess"`
+	NoLangPostprocess  bool   `json:"no_lang_postprocess"`
+	NoTimingCorrection bool   `json:"no_timing_correction"`
+	FilterRegex        string `json:"filter_regex"`
+	ForcedOnly         bool   `json:"forced_only"`
+
+	// Single preprocessing steps are omitted when unset so the hash of runs
+	// that don't use them stays the same.
+	NoBracketRemoval    bool `json:"no_bracket_removal,omitempty"`
+	NoAngleStrip        bool `json:"no_angle_strip,omitempty"`
+	NoMeaninglessFilter bool `json:"no_meaningless_filter,omitempty"`
+	// NormalizeQuotes, OnEmptied, ExcludeIDs, and PostprocessLang are omitted
+	// when unset for the same reason.
+	NormalizeQuotes bool   `json:"normalize_quotes,omitempty"`
+	OnEmptied       string `json:"on_emptied,omitempty"`
+	ExcludeIDs      string `json:"exclude_ids,omitempty"`
+	PostprocessLang string `json:"postprocess_lang,omitempty"`
+}
+
+// provenanceSettings returns the settings hash input of a run of c with the
+// given plan.
+func (c Config) provenanceSettings(plan planSettings) provenanceSettings {
+	return provenanceSettings{
+		planSettings:        plan,
+		NoPreprocess:        c.NoPreprocess,
+		NoPostprocess:       c.NoPostprocess,
+		NoLangPreprocess:    c.NoLangPreprocess,
+		NoLangPostprocess:   c.NoLangPostprocess,
+		NoTimingCorrection:  c.NoTimingCorrection,
+		FilterRegex:         c.FilterRegex,
+		ForcedOnly:          c.ForcedOnly,
+		NoBracketRemoval:    c.NoBracketRemoval,
+		NoAngleStrip:        c.NoAngleStrip,
+		NoMeaninglessFilter: c.NoMeaninglessFilter,
+		NormalizeQuotes:     c.NormalizeQuotes,
+		OnEmptied:           emptiedSetting(c.OnEmptied),
+		ExcludeIDs:          c.ExcludeIDs,
+		PostprocessLang:     c.postprocessLangCode(),
+	}
+}
+
+// sessionProvenanceSettings returns the settings hash input of a repair of
+// the run that wrote log, with the plan repair translates with.
+func sessionProvenanceSettings(log *recovery.SessionLog, plan planSettings) provenanceSettings {
+	return provenanceSettings{
+		planSettings:        plan,
+		NoPreprocess:        log.NoPreprocess,
+		NoPostprocess:       log.NoPostprocess,
+		NoLangPreprocess:    log.NoLangPreprocess,
+		NoLangPostprocess:   log.NoLangPostprocess,
+		NoTimingCorrection:  log.NoTimingCorrection,
+		FilterRegex:         log.FilterRegex,
+		ForcedOnly:          log.ForcedOnly,
+		NoBracketRemoval:    log.NoBracketRemoval,
+		NoAngleStrip:        log.NoAngleStrip,
+		NoMeaninglessFilter: log.NoMeaninglessFilter,
+		NormalizeQuotes:     log.NormalizeQuotes,
+		OnEmptied:           emptiedSetting(log.OnEmptied),
+		ExcludeIDs:          log.ExcludeIDs,
+		PostprocessLang:     log.PostprocessLang,
+	}
+}
+
+// emptiedSetting maps "keep" to "" so it hashes like an unset OnEmptied.
+func emptiedSetting(policy string) string {
+	if policy == string(srt.EmptiedKeep) {
+		return ""
+	}
+	return policy
+}
+
+// formalitySetting maps "auto" to "" so it hashes like an unset Formality.
+func formalitySetting(formality string) string {
+	if formality == string(translator.FormalityAuto) {
+		return ""
+	}
+	return formality
+}
+
+// hash returns a short, stable fingerprint of the settings.
+func (s provenanceSettings) hash() string {
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// saveOptions builds the srt save options for format, embedding provenance
+// when enabled and the output format can carry comments.
+func saveOptions(embed bool, outputPath, format string, s provenanceSettings) srt.SaveOptions {
+	if !embed {
+		return srt.SaveOptions{Format: format}
+	}
+	if !srt.SupportsProvenance(outputPath, format) {
+		logger.Info("Metadata not embedded: output format has no comment syntax", "path", outputPath)
+		return srt.SaveOptions{Format: format}
+	}
+	return srt.SaveOptions{Format: format, Provenance: &srt.Provenance{
+		Tool:         "focst " + version.Version,
+		Model:        s.Model,
+		SourceLang:   s.SourceLang,
+		TargetLang:   s.TargetLang,
+		Date:         provenanceNow().UTC().Format(time.RFC3339),
+		SettingsHash: s.hash(),
+	}}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/oukeidos/focst/internal/files"
 	"github.com/oukeidos/focst/internal/gemini"
@@ -19,21 +21,27 @@ import (
 type RepairResult struct {
 	Model string
 	Usage gemini.UsageMetadata
+	// FailedChunks is the number of chunks still failed after a repair that
+	// ran to completion; RunRepair then also returns an error.
+	FailedChunks int
 }
 
 // RunRepair executes the session repair pipeline.
 func RunRepair(ctx context.Context, cfg Config) (RepairResult, error) {
 	// 1. Validation & Load Log
 	if cfg.LogPath == "" {
-		return RepairResult{}, fmt.Errorf("log file path is required for repair")
+		return RepairResult{}, inputErrorf("log file path is required for repair")
 	}
 
 	logFile, origHash, err := recovery.LoadSessionLogWithHash(cfg.LogPath)
 	if err != nil {
-		return RepairResult{}, fmt.Errorf("failed to load recovery log: %w", err)
+		return RepairResult{}, inputErrorf("failed to load recovery log: %w", err)
 	}
 	if err := logFile.Validate(); err != nil {
-		return RepairResult{}, fmt.Errorf("invalid recovery log: %w", err)
+		return RepairResult{}, inputErrorf("invalid recovery log: %w", err)
+	}
+	if len(logFile.FailedChunks) == 0 {
+		return RepairResult{}, inputErrorf("recovery log records a successful run with no failed chunks; nothing to repair")
 	}
 	runtimeLog, err := resolveRuntimeSessionLog(cfg.LogPath, logFile)
 	if err != nil {
@@ -44,7 +52,10 @@ func RunRepair(ctx context.Context, cfg Config) (RepairResult, error) {
 	resolvedOutputPath := recovery.ResolveOutputPath(cfg.LogPath, logFile.OutputPath)
 
 	if err := cfg.ValidateRepairRuntime(); err != nil {
-		return RepairResult{}, fmt.Errorf("invalid configuration: %w", err)
+		return RepairResult{}, inputErrorf("invalid configuration: %w", err)
+	}
+	if err := checkLogAge(cfg, logFile); err != nil {
+		return RepairResult{}, err
 	}
 	if err := files.RejectSymlinkPath(resolvedOutputPath); err != nil {
 		return RepairResult{}, err
@@ -53,31 +64,32 @@ func RunRepair(ctx context.Context, cfg Config) (RepairResult, error) {
 		return RepairResult{}, err
 	}
 
-	segments, err := srt.Load(runtimeLog.InputPath)
+	segments, err := srt.LoadWithOptions(runtimeLog.InputPath, srt.LoadOptions{Format: runtimeLog.InputFormat, Encoding: runtimeLog.InputEncoding, Language: runtimeLog.SourceLang})
 	if err != nil {
-		return RepairResult{}, fmt.Errorf("failed to load subtitle file: %w", err)
+		return RepairResult{}, inputErrorf("failed to load subtitle file: %w", err)
 	}
 	if err := srt.Validate(segments); err != nil {
-		return RepairResult{}, fmt.Errorf("invalid subtitle file: %w", err)
+		return RepairResult{}, inputErrorf("invalid subtitle file: %w", err)
 	}
 	inputHash, err := recovery.HashFileHex(runtimeLog.InputPath)
 	if err != nil {
 		return RepairResult{}, fmt.Errorf("failed to compute input hash: %w", err)
 	}
 	if inputHash != logFile.InputHash {
-		return RepairResult{}, fmt.Errorf("input file content mismatch: expected %s, got %s", logFile.InputHash, inputHash)
+		return RepairResult{}, inputErrorf("input file content mismatch: expected %s, got %s", logFile.InputHash, inputHash)
 	}
 	if !logFile.NoPreprocess {
-		segments = srt.PreprocessForPathWithOptions(segments, logFile.SourceLang, runtimeLog.InputPath, !logFile.NoLangPreprocess)
+		segments, _ = srt.PreprocessSteps(segments, logFile.SourceLang, runtimeLog.InputPath, logFile.PreprocessOptions())
 	}
 	segmentsChecksum := srt.SegmentsChecksumHex(segments)
 	if segmentsChecksum != logFile.SegmentsChecksum {
-		return RepairResult{}, fmt.Errorf("segment checksum mismatch: expected %s, got %s", logFile.SegmentsChecksum, segmentsChecksum)
+		return RepairResult{}, inputErrorf("segment checksum mismatch: expected %s, got %s", logFile.SegmentsChecksum, segmentsChecksum)
 	}
 
 	// 2. Setup Client & Translator
 	// Use model from log, but allow API key from config (runtime)
-	gClient, err := gemini.NewClient(ctx, cfg.APIKey, logFile.Model)
+	logGeminiEndpoint(cfg.GeminiEndpoint)
+	gClient, err := newGeminiClient(ctx, cfg.APIKey, logFile.Model, cfg.geminiClientOptions())
 	if err != nil {
 		return RepairResult{}, fmt.Errorf("failed to create Gemini client: %w", err)
 	}
@@ -86,48 +98,130 @@ func RunRepair(ctx context.Context, cfg Config) (RepairResult, error) {
 	srcLang, _ := language.GetLanguage(runtimeLog.SourceLang)
 	tgtLang, _ := language.GetLanguage(runtimeLog.TargetLang)
 
-	tr, err := translator.NewTranslator(gClient, runtimeLog.ChunkSize, runtimeLog.ContextSize, runtimeLog.Concurrency, cfg.RetryOnLongLines, srcLang, tgtLang)
-	if err != nil {
-		return RepairResult{}, fmt.Errorf("failed to initialize translator: %w", err)
-	}
-	tr.SetPromptCPL(!runtimeLog.NoPromptCPL)
+	var nameMapping map[string]string
 	if runtimeLog.NamesPath != "" {
-		nameMapping, err := names.LoadMappingFile(runtimeLog.NamesPath, runtimeLog.SourceLang, runtimeLog.TargetLang)
+		nameMapping, err = names.LoadMappingFile(runtimeLog.NamesPath, runtimeLog.SourceLang, runtimeLog.TargetLang)
+		if err != nil {
+			return RepairResult{}, inputErrorf("failed to load names mapping: %w", err)
+		}
+	}
+	repairCfg := repairConfig(cfg, &runtimeLog, nameMapping)
+	var background string
+	if runtimeLog.BackgroundPath != "" {
+		background, err = loadBackground(runtimeLog.BackgroundPath)
+		if err != nil {
+			return RepairResult{}, err
+		}
+		if backgroundHash(background) != runtimeLog.BackgroundHash {
+			return RepairResult{}, inputErrorf("background file changed since the original run: %s", runtimeLog.BackgroundPath)
+		}
+		logger.Info("Background information loaded", "path", runtimeLog.BackgroundPath)
+	}
+	// The settings come from the log; the names and background are what this
+	// repair loaded. The term memory and drafts of the original run are not
+	// sent again, so their logged hashes stand in for them.
+	plan := repairCfg.planSettings(optionalBackgroundHash(background), logFile.TermsHash, logFile.DraftsHash)
+	if logFile.PlanHash != "" {
+		if sum := plan.hash(); sum != logFile.PlanHash {
+			logger.Warn("Translation plan differs from the original run (e.g. a changed names file); repaired chunks may not match the rest of the output",
+				"logged_plan", logFile.PlanHash, "plan", sum)
+		}
+	}
+	var termMemory *translator.TermMemory
+	if len(runtimeLog.Terms) > 0 {
+		termMemory = translator.NewTermMemory(translator.DefaultTermMemoryLimit, runtimeLog.Terms)
+		logger.Info("Reusing phrase choices from the original run", "count", len(runtimeLog.Terms))
+	}
+	var cache translator.ChunkCache
+	var chunkCache *recovery.FileChunkCache
+	if runtimeLog.ChunkCacheDir != "" {
+		chunkCache, err = recovery.NewFileChunkCache(runtimeLog.ChunkCacheDir, logFile.Model)
 		if err != nil {
-			return RepairResult{}, fmt.Errorf("failed to load names mapping: %w", err)
+			return RepairResult{}, fmt.Errorf("failed to open chunk cache: %w", err)
 		}
-		tr.SetNamesMapping(nameMapping)
-		logger.Info("Loaded character name mapping", "count", len(nameMapping), "path", runtimeLog.NamesPath)
+		cache = chunkCache
+		logger.Info("Chunk cache enabled", "dir", runtimeLog.ChunkCacheDir)
+	}
+
+	tr, err := newTranslator(repairCfg, gClient, srcLang, tgtLang, cache, termMemory, nil, background)
+	if err != nil {
+		return RepairResult{}, err
 	}
 
 	// 3. Repair
 	logger.Info("Starting repair", "model", runtimeLog.Model, "failed_chunks", len(runtimeLog.FailedChunks))
-	translated, newFailed, err := recovery.Repair(ctx, tr, &runtimeLog, resolvedOutputPath, cfg.ForceRepair, cfg.OnProgress)
+	onRepairProgress := cfg.OnRepairProgress
+	if onRepairProgress == nil && cfg.OnProgress != nil {
+		onRepairProgress = func(p recovery.RepairProgress) { cfg.OnProgress(p.TranslationProgress) }
+	}
+	translated, newFailed, err := recovery.Repair(ctx, tr, &runtimeLog, resolvedOutputPath, cfg.repairOutputPolicy(), onRepairProgress)
 	if err != nil {
 		return RepairResult{}, fmt.Errorf("repair failed: %w", err)
 	}
 
 	// 4. Handle Results
+	usage := tr.GetUsage()
 	if len(newFailed) == 0 {
 		status := "Success"
 		logger.Info("Repair finished", "status", status)
 
+		if repairCfg.TranslateNotes && ctx.Err() == nil {
+			// Repaired chunks come back with their source notes; notes the
+			// existing output already holds translated are left alone.
+			notesUsage := translateNotes(ctx, repairCfg, gClient, srcLang, tgtLang, background, translated, func(i int) bool {
+				return slices.Equal(translated[i].Notes, segments[i].Notes)
+			})
+			usage.PromptTokenCount += notesUsage.PromptTokenCount
+			usage.CandidatesTokenCount += notesUsage.CandidatesTokenCount
+			usage.TotalTokenCount += notesUsage.TotalTokenCount
+		}
+
 		outSegments := translated
 		if !logFile.NoPostprocess {
 			logger.Info("Performing post-processing")
-			outSegments = srt.PostprocessWithOptions(outSegments, tgtLang.Code, tgtLang.DefaultCPS, !logFile.NoLangPostprocess)
+			outSegments = srt.PostprocessWithOptions(outSegments, tgtLang.Code, tgtLang.DefaultCPS, srt.PostprocessOptions{
+				NoLangRules:        logFile.NoLangPostprocess,
+				NoTimingCorrection: logFile.NoTimingCorrection,
+				NormalizeQuotes:    logFile.NormalizeQuotes,
+				OnEmptied:          srt.EmptiedPolicy(logFile.OnEmptied),
+				RulesLang:          logFile.PostprocessLang,
+			})
+			selected, err := runtimeLog.SelectedSegments(segments)
+			if err != nil {
+				return RepairResult{}, err
+			}
+			restorePassthroughLines(outSegments, segments, selected)
+			if logFile.ExcludeIDs != "" {
+				ranges, _ := srt.ParseIDRanges(logFile.ExcludeIDs) // validated with the session log
+				restoreExcluded(outSegments, segments, ranges)
+			}
 		} else {
 			logger.Info("Post-processing skipped")
 		}
 
+		if cfg.BackupOutput {
+			if err := backupOutput(resolvedOutputPath); err != nil {
+				return RepairResult{}, err
+			}
+		}
+
 		// Use resolved output path
 		logger.Info("Saving results to output file", "path", resolvedOutputPath)
-		if err := srt.Save(resolvedOutputPath, outSegments); err != nil {
+		saveOpts := saveOptions(logFile.EmbedMetadata, resolvedOutputPath, logFile.OutputFormat, sessionProvenanceSettings(logFile, plan))
+		saveOpts.Verify = true // repair overwrites the previous output; never replace it with unparsable data
+		saveOpts.CueSettings = logFile.KeepCueSettings
+		saveOpts.Compat = srt.CompatProfile(logFile.CompatProfile)
+		if err := srt.SaveWithOptions(resolvedOutputPath, outSegments, saveOpts); err != nil {
 			return RepairResult{}, fmt.Errorf("failed to save output file: %w", err)
 		}
 		logger.Info("Saved results", "path", resolvedOutputPath)
+		if chunkCache != nil {
+			if err := chunkCache.Remove(); err != nil {
+				logger.Warn("Failed to remove chunk cache", "dir", runtimeLog.ChunkCacheDir, "error", err)
+			}
+		}
 
-		// Clean up log file on success
+		// Clean up log file only after the output is safely saved
 		if currentHash, err := recovery.HashFile(cfg.LogPath); err != nil {
 			logger.Warn("Failed to read session log for verification", "path", cfg.LogPath, "error", err)
 		} else if currentHash != origHash {
@@ -135,6 +229,7 @@ func RunRepair(ctx context.Context, cfg Config) (RepairResult, error) {
 		} else if err := os.Remove(cfg.LogPath); err != nil {
 			logger.Warn("Failed to remove session log after success", "path", cfg.LogPath, "error", err)
 		}
+		removeFailedSource(cfg.LogPath)
 	} else {
 		status := recovery.CalculateStatus(len(newFailed), logFile.TotalChunks)
 		logger.Info("Repair finished", "status", status)
@@ -146,27 +241,117 @@ func RunRepair(ctx context.Context, cfg Config) (RepairResult, error) {
 		} else {
 			logger.Warn("Partial repair - session log updated", "path", cfg.LogPath)
 		}
-		return RepairResult{Model: runtimeLog.Model, Usage: tr.GetUsage()}, fmt.Errorf("repair finished with %d failed chunks", len(newFailed))
+		if cfg.DumpFailed {
+			if selected, err := runtimeLog.SelectedSegments(segments); err == nil {
+				dumpFailedSource(cfg.LogPath, segments, selected, tr.ChunkSpans(), newFailed)
+			}
+		} else {
+			removeFailedSource(cfg.LogPath)
+		}
+		return RepairResult{Model: runtimeLog.Model, Usage: usage, FailedChunks: len(newFailed)}, fmt.Errorf("repair finished with %d failed chunks", len(newFailed))
 	}
 
-	return RepairResult{Model: runtimeLog.Model, Usage: tr.GetUsage()}, nil
+	return RepairResult{Model: runtimeLog.Model, Usage: usage}, nil
+}
+
+// checkLogAge refuses a session log older than cfg.RepairMaxAge unless
+// cfg.ForceStaleRepair is set, in which case it only warns.
+func checkLogAge(cfg Config, logFile *recovery.SessionLog) error {
+	if cfg.RepairMaxAge <= 0 {
+		return nil
+	}
+	age, err := logFile.Age(cfg.LogPath, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to determine recovery log age: %w", err)
+	}
+	if age <= cfg.RepairMaxAge {
+		return nil
+	}
+	age = age.Round(time.Minute)
+	if cfg.ForceStaleRepair {
+		logger.Warn("Repairing a stale recovery log; the model or input may have changed since the run", "age", age, "max_age", cfg.RepairMaxAge)
+		return nil
+	}
+	return inputErrorf("recovery log is %s old, older than the %s limit; the model or input may have changed since the run (use --force to repair anyway)", age, cfg.RepairMaxAge)
+}
+
+// backupOutput copies an existing output file to path+".bak" so a worse
+// repair result never destroys the previous output. A missing output is not an error.
+func backupOutput(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat output for backup: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read output for backup: %w", err)
+	}
+	backupPath := path + ".bak"
+	if err := files.AtomicWrite(backupPath, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to back up output: %w", err)
+	}
+	logger.Info("Backed up existing output", "path", backupPath)
+	return nil
+}
+
+// repairConfig returns cfg with the translation settings of the run that
+// wrote log, so repair builds its translator through newTranslator exactly as
+// that run did. Runtime settings (rate limits, retries, timeouts) stay those
+// of cfg.
+func repairConfig(cfg Config, log *recovery.SessionLog, names map[string]string) Config {
+	c := cfg
+	c.Model = log.Model
+	c.SourceLang = log.SourceLang
+	c.TargetLang = log.TargetLang
+	c.ChunkSize = log.ChunkSize
+	c.ContextSize = log.ContextSize
+	c.Concurrency = log.Concurrency
+	c.NoPromptCPL = log.NoPromptCPL
+	c.CPLMetric = log.CPLMetric
+	c.RetryOnLongLines = cfg.RetryOnLongLines || log.RetryOnLongLines
+	c.CPLTolerance = log.CPLTolerance
+	c.KeepDialogueDashes = log.KeepDialogueDashes
+	c.DedupRepeats = log.DedupRepeats
+	c.ImproveDrafts = log.ImproveDrafts
+	c.OnEmpty = log.OnEmpty
+	c.Formality = log.Formality
+	c.NarrativeTag = log.NarrativeTag
+	c.PolishModel = log.PolishModel
+	c.SingleLine = log.SingleLine
+	c.AutoLinebreak = log.AutoLinebreak
+	c.TranslateNotes = log.TranslateNotes
+	c.NamesMapping = names
+	return c
 }
 
 func resolveRuntimeSessionLog(logPath string, logFile *recovery.SessionLog) (recovery.SessionLog, error) {
 	runtimeLog := *logFile
 	resolvedInputPath := recovery.ResolveInputPath(logPath, logFile.InputPath)
 	if _, err := os.Stat(resolvedInputPath); err != nil {
-		return recovery.SessionLog{}, fmt.Errorf("invalid recovery log: input file not found: %s", logFile.InputPath)
+		return recovery.SessionLog{}, inputErrorf("invalid recovery log: input file not found: %s", logFile.InputPath)
 	}
 	runtimeLog.InputPath = resolvedInputPath
 
 	if logFile.NamesPath != "" {
 		resolvedNamesPath := recovery.ResolveInputPath(logPath, logFile.NamesPath)
 		if _, err := os.Stat(resolvedNamesPath); err != nil {
-			return recovery.SessionLog{}, fmt.Errorf("invalid recovery log: names_path not found: %s", logFile.NamesPath)
+			return recovery.SessionLog{}, inputErrorf("invalid recovery log: names_path not found: %s", logFile.NamesPath)
 		}
 		runtimeLog.NamesPath = resolvedNamesPath
 	}
+	if logFile.BackgroundPath != "" {
+		resolvedBackgroundPath := recovery.ResolveInputPath(logPath, logFile.BackgroundPath)
+		if _, err := os.Stat(resolvedBackgroundPath); err != nil {
+			return recovery.SessionLog{}, inputErrorf("invalid recovery log: background_path not found: %s", logFile.BackgroundPath)
+		}
+		runtimeLog.BackgroundPath = resolvedBackgroundPath
+	}
+	if logFile.ChunkCacheDir != "" {
+		runtimeLog.ChunkCacheDir = recovery.ResolveOutputPath(logPath, logFile.ChunkCacheDir)
+	}
 
 	return runtimeLog, nil
 }
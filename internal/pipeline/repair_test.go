@@ -2,13 +2,19 @@ package pipeline
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/gemini"
 	"github.com/oukeidos/focst/internal/recovery"
 	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
 )
 
 func writeSessionLog(t *testing.T, dir string, log *recovery.SessionLog) string {
@@ -178,6 +184,104 @@ func TestRunRepair_ResolvesRelativeInputPathFromLogDir(t *testing.T) {
 	}
 }
 
+func TestRunRepair_BackupOutput(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "안녕"}}}, nil
+		},
+	})
+
+	for _, backup := range []bool{false, true} {
+		t.Run(fmt.Sprintf("backup_%v", backup), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inputPath := filepath.Join(tmpDir, "input.srt")
+			if err := os.WriteFile(inputPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+				t.Fatalf("failed to create input file: %v", err)
+			}
+			outputPath := filepath.Join(tmpDir, "output.srt")
+			original := "1\n00:00:01,000 --> 00:00:02,000\nprevious output\n"
+			if err := os.WriteFile(outputPath, []byte(original), 0600); err != nil {
+				t.Fatalf("failed to create output file: %v", err)
+			}
+			logPath := writeSessionLog(t, tmpDir, buildRecoveryLog(t, inputPath, "output.srt", true))
+
+			cfg := Config{LogPath: logPath, APIKey: "test", ForceRepair: true, BackupOutput: backup, NoPostprocess: true}
+			if _, err := RunRepair(context.Background(), cfg); err != nil {
+				t.Fatalf("RunRepair failed: %v", err)
+			}
+
+			out, err := os.ReadFile(outputPath)
+			if err != nil || !strings.Contains(string(out), "안녕") {
+				t.Fatalf("output not repaired: %q, err=%v", out, err)
+			}
+			if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+				t.Fatalf("session log should be removed after a successful save, stat err=%v", err)
+			}
+			bak, err := os.ReadFile(outputPath + ".bak")
+			if !backup {
+				if !os.IsNotExist(err) {
+					t.Fatalf("unexpected backup without BackupOutput, err=%v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("backup not created: %v", err)
+			}
+			if string(bak) != original {
+				t.Fatalf("backup = %q, want original %q", bak, original)
+			}
+		})
+	}
+}
+
+func TestRunRepair_MaxAge(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "안녕"}}}, nil
+		},
+	})
+
+	tests := []struct {
+		name    string
+		age     time.Duration
+		force   bool
+		wantErr bool
+	}{
+		{name: "fresh log", age: time.Hour},
+		{name: "stale log refused", age: 10 * 24 * time.Hour, wantErr: true},
+		{name: "stale log forced", age: 10 * 24 * time.Hour, force: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inputPath := filepath.Join(tmpDir, "input.srt")
+			if err := os.WriteFile(inputPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+				t.Fatalf("failed to create input file: %v", err)
+			}
+			log := buildRecoveryLog(t, inputPath, "output.srt", true)
+			log.CreatedAt = time.Now().Add(-tt.age)
+			logPath := writeSessionLog(t, tmpDir, log)
+
+			cfg := Config{LogPath: logPath, APIKey: "test", ForceRepair: true, NoPostprocess: true, RepairMaxAge: 7 * 24 * time.Hour, ForceStaleRepair: tt.force}
+			_, err := RunRepair(context.Background(), cfg)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("RunRepair failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !IsInputError(err) || !strings.Contains(err.Error(), "older than") {
+				t.Fatalf("expected stale log input error, got %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(tmpDir, "output.srt")); !os.IsNotExist(err) {
+				t.Fatalf("stale log should be refused before any output is written, stat err=%v", err)
+			}
+		})
+	}
+}
+
 func TestResolveRuntimeSessionLog_DoesNotMutateOriginalPaths(t *testing.T) {
 	tmpDir := t.TempDir()
 	inputPath := filepath.Join(tmpDir, "input.srt")
@@ -214,3 +318,466 @@ func TestResolveRuntimeSessionLog_DoesNotMutateOriginalPaths(t *testing.T) {
 		t.Fatalf("original names path was mutated: %q", logFile.NamesPath)
 	}
 }
+
+func TestRunRepair_ReusesTermsFromOriginalRun(t *testing.T) {
+	failHello := true
+	client := &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				if failHello && seg.Lines[0] == "Hello" {
+					return nil, apperrors.BadRequest(fmt.Errorf("rejected"))
+				}
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	}
+	withStubClient(t, client)
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nMagic Academy\n\n2\n00:00:03,000 --> 00:00:04,000\nHello\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:     inPath,
+		OutputPath:    filepath.Join(tmpDir, "output.srt"),
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     1,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ko",
+		Overwrite:     true,
+		NoPostprocess: true,
+	})
+	if err != nil || result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("RunTranslation: status %q err %v", result.Status, err)
+	}
+	logFile, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	want := []translator.TermEntry{{Source: "Magic Academy", Target: "T-Magic Academy"}}
+	if !reflect.DeepEqual(logFile.Terms, want) {
+		t.Fatalf("session log terms = %+v, want %+v", logFile.Terms, want)
+	}
+
+	failHello = false
+	if _, err := RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test", NoPostprocess: true}); err != nil {
+		t.Fatalf("RunRepair failed: %v", err)
+	}
+	if !strings.Contains(client.systemInstruction, "- Magic Academy -> T-Magic Academy") {
+		t.Fatalf("expected carried term in repair prompt, got:\n%s", client.systemInstruction)
+	}
+}
+
+func TestRunTranslation_KeepLogOnSuccess(t *testing.T) {
+	client := &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	}
+	withStubClient(t, client)
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	cfg := Config{
+		InputPath:   inPath,
+		OutputPath:  filepath.Join(tmpDir, "output.srt"),
+		APIKey:      "test",
+		Model:       "m",
+		ChunkSize:   1,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+		Overwrite:   true,
+	}
+
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil || result.Status != TranslationStatusSuccess || result.RecoveryLogPath != "" {
+		t.Fatalf("without --keep-log: status %q log %q err %v", result.Status, result.RecoveryLogPath, err)
+	}
+
+	cfg.KeepLog = true
+	result, err = RunTranslation(context.Background(), cfg)
+	if err != nil || result.Status != TranslationStatusSuccess || result.RecoveryLogPath == "" {
+		t.Fatalf("with --keep-log: status %q log %q err %v", result.Status, result.RecoveryLogPath, err)
+	}
+	logFile, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	if err := logFile.Validate(); err != nil {
+		t.Fatalf("kept log is invalid: %v", err)
+	}
+	if logFile.Status != string(TranslationStatusSuccess) || len(logFile.FailedChunks) != 0 || logFile.TotalChunks != 2 {
+		t.Fatalf("unexpected kept log: status %q failed %v total %d", logFile.Status, logFile.FailedChunks, logFile.TotalChunks)
+	}
+
+	_, err = RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test"})
+	if !IsInputError(err) || !strings.Contains(err.Error(), "nothing to repair") {
+		t.Fatalf("RunRepair on success log: err = %v, want nothing to repair", err)
+	}
+}
+
+func TestRunRepair_ReusesBackground(t *testing.T) {
+	failHello := true
+	client := &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				if failHello && seg.Lines[0] == "Hello" {
+					return nil, apperrors.BadRequest(fmt.Errorf("rejected"))
+				}
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	}
+	withStubClient(t, client)
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	backgroundPath := filepath.Join(tmpDir, "background.txt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nBye\n\n2\n00:00:03,000 --> 00:00:04,000\nHello\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	if err := os.WriteFile(backgroundPath, []byte("Mina runs a bakery.\n"), 0600); err != nil {
+		t.Fatalf("write background: %v", err)
+	}
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:      inPath,
+		OutputPath:     filepath.Join(tmpDir, "output.srt"),
+		APIKey:         "test",
+		Model:          "m",
+		ChunkSize:      1,
+		Concurrency:    1,
+		SourceLang:     "en",
+		TargetLang:     "ko",
+		Overwrite:      true,
+		NoPostprocess:  true,
+		BackgroundPath: backgroundPath,
+	})
+	if err != nil || result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("RunTranslation: status %q err %v", result.Status, err)
+	}
+	logFile, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	if logFile.BackgroundPath != "background.txt" || logFile.BackgroundHash != backgroundHash("Mina runs a bakery.\n") {
+		t.Fatalf("session log background = %q %q", logFile.BackgroundPath, logFile.BackgroundHash)
+	}
+
+	failHello = false
+	client.systemInstruction = ""
+	if _, err := RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test", NoPostprocess: true}); err != nil {
+		t.Fatalf("RunRepair failed: %v", err)
+	}
+	if !strings.Contains(client.systemInstruction, "Mina runs a bakery.") {
+		t.Fatalf("expected background in repair prompt, got:\n%s", client.systemInstruction)
+	}
+}
+
+func TestRunRepair_RejectsChangedBackground(t *testing.T) {
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			return nil, apperrors.BadRequest(fmt.Errorf("rejected"))
+		},
+	})
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	backgroundPath := filepath.Join(tmpDir, "background.txt")
+	if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	if err := os.WriteFile(backgroundPath, []byte("Before."), 0600); err != nil {
+		t.Fatalf("write background: %v", err)
+	}
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:      inPath,
+		OutputPath:     filepath.Join(tmpDir, "output.srt"),
+		APIKey:         "test",
+		Model:          "m",
+		ChunkSize:      1,
+		Concurrency:    1,
+		SourceLang:     "en",
+		TargetLang:     "ko",
+		BackgroundPath: backgroundPath,
+	})
+	if err != nil || result.RecoveryLogPath == "" {
+		t.Fatalf("RunTranslation: log %q err %v", result.RecoveryLogPath, err)
+	}
+	if err := os.WriteFile(backgroundPath, []byte("After."), 0600); err != nil {
+		t.Fatalf("rewrite background: %v", err)
+	}
+	_, err = RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test"})
+	if err == nil || !strings.Contains(err.Error(), "background file changed") {
+		t.Fatalf("RunRepair error = %v, want background change", err)
+	}
+}
+
+func TestRunRepair_KeepsPassthroughVerbatim(t *testing.T) {
+	failBye := true
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				if failBye && seg.Lines[0] == "Bye" {
+					return nil, apperrors.BadRequest(fmt.Errorf("rejected"))
+				}
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: map[string]string{"Hello": "你好", "Bye": "再见"}[seg.Lines[0]]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nSkip me.\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nBye\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:          inPath,
+		OutputPath:         outPath,
+		APIKey:             "test",
+		Model:              "m",
+		ChunkSize:          1,
+		Concurrency:        1,
+		SourceLang:         "en",
+		TargetLang:         "zh-Hans",
+		NoPreprocess:       true,
+		NoTimingCorrection: true,
+		FilterRegex:        "^(Hello|Bye)$",
+	})
+	if err != nil || result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("RunTranslation: status %q err %v", result.Status, err)
+	}
+
+	failBye = false
+	if _, err := RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test"}); err != nil {
+		t.Fatalf("RunRepair failed: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	want := "\ufeff1\n00:00:01,000 --> 00:00:02,000\n你好\n\n2\n00:00:03,000 --> 00:00:04,000\nSkip me.\n\n3\n00:00:05,000 --> 00:00:06,000\n再见\n"
+	if string(data) != want {
+		t.Fatalf("output = %q, want %q", data, want)
+	}
+}
+
+func TestRunRepair_UsesLoggedCPLMetric(t *testing.T) {
+	failBye := true
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				if failBye && seg.Lines[0] == "Bye" {
+					return nil, apperrors.BadRequest(fmt.Errorf("rejected"))
+				}
+				// 20 graphemes but only 20 width units: over the Japanese CPL
+				// in graphemes, within it in width.
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "ABCDEFGHIJ KLMNOPQRS"})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nBye\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:     inPath,
+		OutputPath:    outPath,
+		APIKey:        "test",
+		Model:         "m",
+		ChunkSize:     1,
+		Concurrency:   1,
+		SourceLang:    "en",
+		TargetLang:    "ja",
+		NoPostprocess: true,
+		CPLMetric:     string(translator.CPLMetricWidth),
+		AutoLinebreak: true,
+	})
+	if err != nil || result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("RunTranslation: status %q err %v", result.Status, err)
+	}
+	logFile, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	if logFile.CPLMetric != string(translator.CPLMetricWidth) {
+		t.Fatalf("session log cpl_metric = %q, want width", logFile.CPLMetric)
+	}
+
+	failBye = false
+	if _, err := RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test"}); err != nil {
+		t.Fatalf("RunRepair failed: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if got := strings.Count(string(data), "ABCDEFGHIJ KLMNOPQRS\n"); got != 2 {
+		t.Fatalf("want both cues kept on one line under the width metric, got:\n%s", data)
+	}
+}
+
+func TestRunRepair_UsesLoggedCPLTolerance(t *testing.T) {
+	// 25 graphemes: within a 2.0 tolerance of the Japanese CPL (26), over the
+	// default 1.5 (19.5).
+	const long = "ABCDEFGHIJKLMNOPQRSTUVWXY"
+	failBye := true
+	calls := make(map[string]int)
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				if failBye && seg.Lines[0] == "Bye" {
+					return nil, apperrors.BadRequest(fmt.Errorf("rejected"))
+				}
+				calls[seg.Lines[0]]++
+				line := long
+				if calls[seg.Lines[0]] > 1 {
+					line = "short"
+				}
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: line})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nBye\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:        inPath,
+		OutputPath:       outPath,
+		APIKey:           "test",
+		Model:            "m",
+		ChunkSize:        1,
+		Concurrency:      1,
+		SourceLang:       "en",
+		TargetLang:       "ja",
+		NoPostprocess:    true,
+		NoJitter:         true,
+		RetryOnLongLines: true,
+		CPLTolerance:     2.0,
+	})
+	if err != nil || result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("RunTranslation: status %q err %v", result.Status, err)
+	}
+	logFile, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	if !logFile.RetryOnLongLines || logFile.CPLTolerance != 2.0 {
+		t.Fatalf("session log retry_on_long_lines = %v, cpl_tolerance = %v", logFile.RetryOnLongLines, logFile.CPLTolerance)
+	}
+
+	failBye = false
+	if _, err := RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test", NoJitter: true}); err != nil {
+		t.Fatalf("RunRepair failed: %v", err)
+	}
+	if calls["Bye"] != 1 {
+		t.Fatalf("repaired chunk sent %d times, want 1", calls["Bye"])
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if got := strings.Count(string(data), long+"\n"); got != 2 {
+		t.Fatalf("want the logged tolerance to accept both cues, got:\n%s", data)
+	}
+}
+
+func TestRunRepair_TranslatesNotesOfRepairedChunks(t *testing.T) {
+	failBye := true
+	var sent []string
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				if failBye && seg.Lines[0] == "Bye" {
+					return nil, apperrors.BadRequest(fmt.Errorf("rejected"))
+				}
+				sent = append(sent, seg.Lines...)
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + strings.Join(seg.Lines, " ")})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.vtt")
+	outPath := filepath.Join(tmpDir, "out.vtt")
+	input := "WEBVTT\n\nNOTE First note\n\n1\n00:00:01.000 --> 00:00:02.000\nHello\n\nNOTE Second note\n\n2\n00:00:03.000 --> 00:00:04.000\nBye\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	result, err := RunTranslation(context.Background(), Config{
+		InputPath:      inPath,
+		OutputPath:     outPath,
+		APIKey:         "test",
+		Model:          "m",
+		ChunkSize:      1,
+		Concurrency:    1,
+		SourceLang:     "en",
+		TargetLang:     "ko",
+		NoPostprocess:  true,
+		TranslateNotes: true,
+	})
+	if err != nil || result.Status != TranslationStatusPartialSuccess {
+		t.Fatalf("RunTranslation: status %q err %v", result.Status, err)
+	}
+	logFile, err := recovery.LoadSessionLog(result.RecoveryLogPath)
+	if err != nil {
+		t.Fatalf("load session log: %v", err)
+	}
+	if !logFile.TranslateNotes {
+		t.Fatal("session log does not record translate_notes")
+	}
+
+	failBye = false
+	sent = nil
+	if _, err := RunRepair(context.Background(), Config{LogPath: result.RecoveryLogPath, APIKey: "test", NoPostprocess: true}); err != nil {
+		t.Fatalf("RunRepair failed: %v", err)
+	}
+	if want := []string{"Bye", "Second note"}; !reflect.DeepEqual(sent, want) {
+		t.Fatalf("repair sent %q, want %q", sent, want)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	for _, want := range []string{"NOTE T-First note\n", "NOTE T-Second note\n", "T-Bye\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("output lacks %q:\n%s", want, data)
+		}
+	}
+}
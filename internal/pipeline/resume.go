@@ -0,0 +1,172 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+const resumeTokenVersion = 1
+
+// ResumeToken is a lightweight snapshot of a translation in progress: the
+// settings it runs with and every chunk completed so far. Unlike the session
+// log, which is written when a run ends, it is saved after each completed
+// chunk, so the work survives a crash.
+//
+// ResumeToken implements translator.ChunkCache. Resuming runs the same input
+// and Options again with the token set as Config.Resume: chunks the token
+// holds are reused, keyed by their content and settings, and only the
+// remaining chunks are sent to the API.
+type ResumeToken struct {
+	Version    int    `json:"version"`
+	InputPath  string `json:"input_path"`
+	OutputPath string `json:"output_path"`
+	InputHash  string `json:"input_hash"`
+	// Options are the settings of the original run. They hold no secrets;
+	// the API key is resolved again when resuming.
+	Options   Options                  `json:"options"`
+	Chunks    map[string][]srt.Segment `json:"chunks"`
+	UpdatedAt time.Time                `json:"updated_at"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// NewResumeToken starts an empty token at path for translating inputPath to
+// outputPath with opts, and saves it.
+func NewResumeToken(path, inputPath, outputPath string, opts Options) (*ResumeToken, error) {
+	hash, err := recovery.HashFileHex(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash input: %w", err)
+	}
+	t := &ResumeToken{
+		Version:    resumeTokenVersion,
+		InputPath:  inputPath,
+		OutputPath: outputPath,
+		InputHash:  hash,
+		Options:    opts,
+		Chunks:     make(map[string][]srt.Segment),
+		path:       path,
+	}
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LoadResumeToken reads the token at path. It does not check the input; see
+// CheckInput.
+func LoadResumeToken(path string) (*ResumeToken, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t ResumeToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse resume token: %w", err)
+	}
+	if t.Version != resumeTokenVersion {
+		return nil, fmt.Errorf("unsupported resume token version %d", t.Version)
+	}
+	if t.Chunks == nil {
+		t.Chunks = make(map[string][]srt.Segment)
+	}
+	t.path = path
+	return &t, nil
+}
+
+// CheckInput returns an error when the input file is gone or changed since
+// the token was started, in which case its chunks no longer apply.
+func (t *ResumeToken) CheckInput() error {
+	hash, err := recovery.HashFileHex(t.InputPath)
+	if err != nil {
+		return fmt.Errorf("input file is unavailable: %w", err)
+	}
+	if hash != t.InputHash {
+		return fmt.Errorf("input file changed since the session started")
+	}
+	return nil
+}
+
+// CompletedChunks returns the number of chunks saved so far.
+func (t *ResumeToken) CompletedChunks() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.Chunks)
+}
+
+// Load returns the saved translation of a completed chunk.
+func (t *ResumeToken) Load(key string) ([]srt.Segment, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	segments, ok := t.Chunks[key]
+	return segments, ok
+}
+
+// Store records a completed chunk and saves the token.
+func (t *ResumeToken) Store(key string, segments []srt.Segment) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Chunks[key] = segments
+	return t.saveLocked()
+}
+
+// Remove deletes the token file once its run has ended.
+func (t *ResumeToken) Remove() error {
+	if err := os.Remove(t.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (t *ResumeToken) save() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.saveLocked()
+}
+
+func (t *ResumeToken) saveLocked() error {
+	if err := files.RejectSymlinkPath(t.path); err != nil {
+		return err
+	}
+	t.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return files.AtomicWrite(t.path, data, 0600)
+}
+
+// chunkCaches loads a chunk from the first cache holding it, copying it into
+// the caches before that one, and stores completed chunks in all of them.
+type chunkCaches []translator.ChunkCache
+
+func (c chunkCaches) Load(key string) ([]srt.Segment, bool) {
+	for i, cache := range c {
+		if segments, ok := cache.Load(key); ok {
+			for _, earlier := range c[:i] {
+				_ = earlier.Store(key, segments)
+			}
+			return segments, true
+		}
+	}
+	return nil, false
+}
+
+func (c chunkCaches) Store(key string, segments []srt.Segment) error {
+	var errs []error
+	for _, cache := range c {
+		if err := cache.Store(key, segments); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
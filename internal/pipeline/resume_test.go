@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/gemini"
+)
+
+func TestResumeToken_ResumeTranslatesOnlyRemainingChunks(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "out.srt")
+	tokenPath := filepath.Join(tmpDir, "resume.json")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nBye\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	opts := DefaultOptions()
+	opts.Model = "m"
+	opts.ChunkSize = 1
+	opts.Concurrency = 1
+	opts.SourceLang = "en"
+	opts.TargetLang = "ko"
+	opts.NoPreprocess = true
+	opts.NoPostprocess = true
+
+	var requested []string
+	failWorld := true
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			seg := req.Target[0]
+			requested = append(requested, seg.Lines[0])
+			if failWorld && seg.Lines[0] == "World" {
+				return nil, apperrors.BadRequest(errors.New("rejected"))
+			}
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: seg.ID, Line1: "T-" + seg.Lines[0]}}}, nil
+		},
+	})
+
+	token, err := NewResumeToken(tokenPath, inPath, outPath, opts)
+	if err != nil {
+		t.Fatalf("NewResumeToken failed: %v", err)
+	}
+	cfg := configFromOptions(inPath, outPath, "test", opts)
+	cfg.Resume = token
+	if _, err := RunTranslation(context.Background(), cfg); err != nil {
+		t.Fatalf("RunTranslation failed: %v", err)
+	}
+
+	// A relaunch reads the token back from disk.
+	loaded, err := LoadResumeToken(tokenPath)
+	if err != nil {
+		t.Fatalf("LoadResumeToken failed: %v", err)
+	}
+	if err := loaded.CheckInput(); err != nil {
+		t.Fatalf("CheckInput failed: %v", err)
+	}
+	if got := loaded.CompletedChunks(); got != 2 {
+		t.Fatalf("CompletedChunks = %d, want 2", got)
+	}
+
+	requested = nil
+	failWorld = false
+	cfg = configFromOptions(loaded.InputPath, loaded.OutputPath, "test", loaded.Options)
+	cfg.Overwrite = true
+	cfg.Resume = loaded
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("resumed RunTranslation failed: %v", err)
+	}
+	if result.Status != TranslationStatusSuccess {
+		t.Fatalf("status = %q, want Success", result.Status)
+	}
+	if len(requested) != 1 || requested[0] != "World" {
+		t.Fatalf("resumed run requested %v, want only the unfinished chunk", requested)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	want := "\ufeff1\n00:00:01,000 --> 00:00:02,000\nT-Hello\n\n2\n00:00:03,000 --> 00:00:04,000\nT-World\n\n3\n00:00:05,000 --> 00:00:06,000\nT-Bye\n"
+	if string(data) != want {
+		t.Fatalf("output = %q, want %q", data, want)
+	}
+}
+
+func TestResumeToken_CheckInputDetectsChange(t *testing.T) {
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	token, err := NewResumeToken(filepath.Join(tmpDir, "resume.json"), inPath, filepath.Join(tmpDir, "out.srt"), DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewResumeToken failed: %v", err)
+	}
+	if err := os.WriteFile(inPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nChanged\n"), 0600); err != nil {
+		t.Fatalf("rewrite input: %v", err)
+	}
+	if err := token.CheckInput(); err == nil {
+		t.Fatal("expected CheckInput to reject a changed input")
+	}
+}
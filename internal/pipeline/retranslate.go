@@ -0,0 +1,233 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/recovery"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// Review retains the segments behind a successful translation so individual
+// cues can be re-translated afterwards without re-running the whole file.
+type Review struct {
+	// Source holds the (preprocessed) source segments that were translated.
+	Source []srt.Segment
+	// Translated holds the translations before post-processing, aligned with Source.
+	Translated []srt.Segment
+	// Selected lists the Source indices that were sent for translation, or nil
+	// when every segment was. Other segments passed through unchanged.
+	Selected   []int
+	OutputPath string
+	SourceLang string
+	TargetLang string
+	// ChunkSize is the chunk size of the original run; chunk indices depend on it.
+	ChunkSize int
+	// Spans are the chunk ranges the original run recorded, over Selected
+	// positions when Selected is non-nil. When nil they are recomputed from
+	// ChunkSize.
+	Spans []chunker.Span
+}
+
+// chunkSpans returns r.Spans, or the spans the chunker produces for the
+// reviewed segments when none were recorded.
+func (r *Review) chunkSpans() []chunker.Span {
+	if r.Spans != nil {
+		return r.Spans
+	}
+	work := r.Source
+	if r.Selected != nil {
+		work = make([]srt.Segment, 0, len(r.Selected))
+		for _, idx := range r.Selected {
+			if idx >= 0 && idx < len(r.Source) {
+				work = append(work, r.Source[idx])
+			}
+		}
+	}
+	return chunker.Spans(chunker.SplitIntoChunks(work, r.ChunkSize, 0))
+}
+
+// ChunksForSegments maps Source indices to the ascending, de-duplicated chunk
+// indices that contain them, using the numbering TranslateChunks and
+// TranslateSubset expect. Indices of passthrough or out-of-range segments are ignored.
+func (r *Review) ChunksForSegments(indices []int) []int {
+	if r.ChunkSize <= 0 {
+		return nil
+	}
+	position := make(map[int]int, len(r.Selected))
+	for pos, idx := range r.Selected {
+		position[idx] = pos
+	}
+	chunkOf := make(map[int]int)
+	for c, span := range r.chunkSpans() {
+		for pos := span.Offset; pos < span.Offset+span.Len; pos++ {
+			chunkOf[pos] = c
+		}
+	}
+	seen := make(map[int]bool)
+	var chunks []int
+	for _, idx := range indices {
+		if idx < 0 || idx >= len(r.Source) {
+			continue
+		}
+		pos := idx
+		if r.Selected != nil {
+			p, ok := position[idx]
+			if !ok {
+				continue
+			}
+			pos = p
+		}
+		chunk, ok := chunkOf[pos]
+		if !ok {
+			continue
+		}
+		if !seen[chunk] {
+			seen[chunk] = true
+			chunks = append(chunks, chunk)
+		}
+	}
+	sort.Ints(chunks)
+	return chunks
+}
+
+// chunkSegments returns the Source indices covered by chunk.
+func (r *Review) chunkSegments(chunk int) []int {
+	total := len(r.Source)
+	if r.Selected != nil {
+		total = len(r.Selected)
+	}
+	spans := r.chunkSpans()
+	if chunk < 0 || chunk >= len(spans) {
+		return nil
+	}
+	start := spans[chunk].Offset
+	end := min(start+spans[chunk].Len, total)
+	var out []int
+	for pos := start; pos < end; pos++ {
+		if r.Selected != nil {
+			out = append(out, r.Selected[pos])
+		} else {
+			out = append(out, pos)
+		}
+	}
+	return out
+}
+
+// RunRetranslation re-translates the chunks that contain the given Source
+// indices and rewrites r.OutputPath with the updated translations. cfg supplies
+// the API key, model, and translation settings; chunk size and languages come
+// from r so chunk numbering matches the original run. Successfully re-translated
+// chunks replace their entries in r.Translated; failed chunks keep the previous
+// translation, so the result is never partial output.
+func RunRetranslation(ctx context.Context, cfg Config, r *Review, indices []int) (TranslationResult, error) {
+	if r == nil {
+		return TranslationResult{}, fmt.Errorf("no translation to review")
+	}
+	cfg.ChunkSize = r.ChunkSize
+	cfg.SourceLang = r.SourceLang
+	cfg.TargetLang = r.TargetLang
+	var notes []string
+	cfg, notes = cfg.Normalize()
+	for _, note := range notes {
+		logger.Warn("Config normalized", "detail", note)
+	}
+	if err := cfg.Validate(); err != nil {
+		return TranslationResult{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if len(r.Translated) != len(r.Source) {
+		return TranslationResult{}, fmt.Errorf("review size mismatch: %d source segments, %d translated", len(r.Source), len(r.Translated))
+	}
+	chunks := r.ChunksForSegments(indices)
+	if len(chunks) == 0 {
+		return TranslationResult{}, fmt.Errorf("no translated segments selected")
+	}
+
+	srcLang, ok := language.GetLanguage(r.SourceLang)
+	if !ok {
+		return TranslationResult{}, fmt.Errorf("unsupported source language: %s", r.SourceLang)
+	}
+	tgtLang, ok := language.GetLanguage(r.TargetLang)
+	if !ok {
+		return TranslationResult{}, fmt.Errorf("unsupported target language: %s", r.TargetLang)
+	}
+
+	var background string
+	if cfg.BackgroundPath != "" {
+		var err error
+		if background, err = loadBackground(cfg.BackgroundPath); err != nil {
+			return TranslationResult{}, err
+		}
+	}
+
+	logger.Info("Re-translating selected segments", "segments", len(indices), "chunks", len(chunks))
+	translated, failed, _, usage, throughput, costCapped, err := translateSegments(ctx, cfg, r.Source, r.Selected, chunks, srcLang, tgtLang, nil, nil, nil, background)
+	if err != nil {
+		return TranslationResult{Usage: usage, Throughput: throughput}, err
+	}
+
+	failedSet := make(map[int]bool, len(failed))
+	for _, c := range failed {
+		failedSet[c] = true
+	}
+	for _, c := range chunks {
+		if failedSet[c] {
+			continue
+		}
+		for _, idx := range r.chunkSegments(c) {
+			r.Translated[idx] = translated[idx]
+		}
+	}
+
+	status := translationStatusFromRecovery(recovery.CalculateStatus(len(failed), len(chunks)))
+	result := TranslationResult{
+		Status:       status,
+		Usage:        usage,
+		FailedChunks: len(failed),
+		TotalChunks:  len(chunks),
+		CostCapped:   costCapped,
+		Throughput:   throughput,
+		Review:       r,
+	}
+	logger.Info("Re-translation finished", "status", status)
+	if status == TranslationStatusFailure {
+		return result, nil
+	}
+
+	outSegments := cloneSegments(r.Translated)
+	if !cfg.NoPostprocess {
+		outSegments = srt.PostprocessWithOptions(outSegments, tgtLang.Code, tgtLang.DefaultCPS, srt.PostprocessOptions{
+			NoLangRules:        cfg.NoLangPostprocess,
+			NoTimingCorrection: cfg.NoTimingCorrection,
+			NormalizeQuotes:    cfg.NormalizeQuotes,
+			OnEmptied:          srt.EmptiedPolicy(cfg.OnEmptied),
+			RulesLang:          cfg.postprocessLangCode(),
+		})
+		restorePassthroughLines(outSegments, r.Source, r.Selected)
+		restoreExcluded(outSegments, r.Source, cfg.excludeRanges())
+	}
+	saveOpts := saveOptions(cfg.EmbedMetadata, r.OutputPath, cfg.OutputFormat, cfg.provenanceSettings(cfg.planSettings(optionalBackgroundHash(background), "", "")))
+	saveOpts.CueSettings = cfg.keepCueSettings(r.OutputPath)
+	saveOpts.Compat = srt.CompatProfile(cfg.CompatProfile)
+	if err := srt.SaveWithOptions(r.OutputPath, outSegments, saveOpts); err != nil {
+		return result, fmt.Errorf("failed to save output file: %w", err)
+	}
+	result.OutputPath = r.OutputPath
+	logger.Info("Saved results", "path", r.OutputPath)
+	return result, nil
+}
+
+// cloneSegments copies segments and their lines so in-place edits to the copy
+// leave the original untouched.
+func cloneSegments(segments []srt.Segment) []srt.Segment {
+	out := make([]srt.Segment, len(segments))
+	for i, seg := range segments {
+		seg.Lines = append([]string(nil), seg.Lines...)
+		out[i] = seg
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestReviewChunksForSegments(t *testing.T) {
+	source := make([]srt.Segment, 7)
+	cases := []struct {
+		name     string
+		selected []int
+		spans    []chunker.Span
+		indices  []int
+		want     []int
+	}{
+		{name: "all_segments", indices: []int{5, 0, 1, 6}, want: []int{0, 2, 3}},
+		{name: "out_of_range", indices: []int{-1, 7}, want: nil},
+		{name: "subset_numbering", selected: []int{1, 3, 4, 6}, indices: []int{6, 3}, want: []int{0, 1}},
+		{name: "passthrough_ignored", selected: []int{1, 3, 4, 6}, indices: []int{0, 2, 5}, want: nil},
+		{name: "recorded_spans", spans: []chunker.Span{{Offset: 0, Len: 1}, {Offset: 1, Len: 4}, {Offset: 5, Len: 2}}, indices: []int{4, 0, 5}, want: []int{0, 1, 2}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Review{Source: source, Selected: tc.selected, ChunkSize: 2, Spans: tc.spans}
+			if got := r.ChunksForSegments(tc.indices); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("ChunksForSegments(%v) = %v, want %v", tc.indices, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunRetranslation_UpdatesSelectedChunks(t *testing.T) {
+	prefix := "A-"
+	var requested []string
+	withStubClient(t, &stubTranslationClient{
+		translate: func(req gemini.RequestData) (*gemini.ResponseData, error) {
+			var out []gemini.TranslatedSegment
+			for _, seg := range req.Target {
+				requested = append(requested, seg.Lines[0])
+				out = append(out, gemini.TranslatedSegment{ID: seg.ID, Line1: prefix + seg.Lines[0]})
+			}
+			return &gemini.ResponseData{Translations: out}, nil
+		},
+	})
+
+	tmpDir := t.TempDir()
+	inPath := filepath.Join(tmpDir, "input.srt")
+	outPath := filepath.Join(tmpDir, "output.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\none\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\ntwo\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nthree\n\n" +
+		"4\n00:00:07,000 --> 00:00:08,000\nfour\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	cfg := Config{
+		InputPath:   inPath,
+		OutputPath:  outPath,
+		APIKey:      "test",
+		ChunkSize:   2,
+		Concurrency: 1,
+		SourceLang:  "en",
+		TargetLang:  "ko",
+	}
+	result, err := RunTranslation(context.Background(), cfg)
+	if err != nil || result.Status != TranslationStatusSuccess {
+		t.Fatalf("status %q err %v", result.Status, err)
+	}
+	if result.Review == nil {
+		t.Fatalf("expected review data after a successful run")
+	}
+
+	prefix = "B-"
+	requested = nil
+	retry, err := RunRetranslation(context.Background(), cfg, result.Review, []int{2})
+	if err != nil || retry.Status != TranslationStatusSuccess {
+		t.Fatalf("retranslate status %q err %v", retry.Status, err)
+	}
+	if strings.Join(requested, "|") != "three|four" {
+		t.Fatalf("requested = %v, want the second chunk only", requested)
+	}
+
+	out, err := srt.Load(outPath)
+	if err != nil {
+		t.Fatalf("load output: %v", err)
+	}
+	var got []string
+	for _, seg := range out {
+		got = append(got, strings.Join(seg.Lines, " "))
+	}
+	want := []string{"A-one", "A-two", "B-three", "B-four"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("output = %v, want %v", got, want)
+	}
+	if line := result.Review.Translated[2].Lines[0]; line != "B-three" {
+		t.Fatalf("review not updated: %q", line)
+	}
+}
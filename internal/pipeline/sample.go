@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// SampleOutputPath returns where a sample run writes its output: the output
+// path with ".sample" before the extension (movie.ko.srt -> movie.ko.sample.srt),
+// so a sample never replaces the real translation.
+func SampleOutputPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".sample" + ext
+}
+
+// sampleChunks returns the chunk indices covering the first n segments and the
+// number of translatable segments among them. When selected is non-nil, chunks
+// are numbered over the selected subset, matching TranslateSubset.
+func sampleChunks(n int, segments []srt.Segment, chunkSize int, selected []int) ([]int, int) {
+	work := segments
+	count := min(n, len(segments))
+	if selected != nil {
+		work = make([]srt.Segment, len(selected))
+		count = 0
+		for k, idx := range selected {
+			work[k] = segments[idx]
+			if idx < n {
+				count++
+			}
+		}
+	}
+	var chunks []int
+	for i, span := range chunker.Spans(chunker.SplitIntoChunks(work, chunkSize, 0)) {
+		if span.Offset < count {
+			chunks = append(chunks, i)
+		}
+	}
+	return chunks, count
+}
+
+// truncateSample cuts a sample run down to its first n segments, dropping
+// selected indices that fall outside them.
+func truncateSample(n int, segments, translated []srt.Segment, selected []int) ([]srt.Segment, []srt.Segment, []int) {
+	if n >= len(segments) {
+		return segments, translated, selected
+	}
+	if selected != nil {
+		kept := make([]int, 0, len(selected))
+		for _, idx := range selected {
+			if idx < n {
+				kept = append(kept, idx)
+			}
+		}
+		selected = kept
+	}
+	return segments[:n], translated[:n], selected
+}
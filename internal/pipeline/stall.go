@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+// stallWatchdog warns, and optionally cancels the run, when no chunk has
+// completed for a whole timeout, e.g. because every worker is stuck on a hung
+// API call. Each completed chunk restarts the timer.
+type stallWatchdog struct {
+	timeout time.Duration
+	// cancel is nil when a stall only logs a warning.
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+	trips   int
+}
+
+func newStallWatchdog(timeout time.Duration, cancel context.CancelFunc) *stallWatchdog {
+	w := &stallWatchdog{timeout: timeout, cancel: cancel}
+	w.mu.Lock()
+	w.timer = time.AfterFunc(timeout, w.trip)
+	w.mu.Unlock()
+	return w
+}
+
+// wrap returns a progress callback that restarts the timer on every completed
+// chunk before forwarding the event to next.
+func (w *stallWatchdog) wrap(next func(translator.TranslationProgress)) func(translator.TranslationProgress) {
+	return func(p translator.TranslationProgress) {
+		if p.State == translator.StateCompleted {
+			w.mu.Lock()
+			if !w.stopped {
+				w.timer.Reset(w.timeout)
+			}
+			w.mu.Unlock()
+		}
+		if next != nil {
+			next(p)
+		}
+	}
+}
+
+func (w *stallWatchdog) trip() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return
+	}
+	w.trips++
+	if w.cancel != nil {
+		logger.Warn("No chunk completed within the stall timeout; canceling the run", "stall_timeout", w.timeout)
+		w.stopped = true
+		w.cancel()
+		return
+	}
+	logger.Warn("No chunk completed within the stall timeout", "stall_timeout", w.timeout, "stalls", w.trips)
+	w.timer.Reset(w.timeout)
+}
+
+// stop disarms the watchdog once the run is over.
+func (w *stallWatchdog) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped = true
+	w.timer.Stop()
+}
+
+// tripped reports how many times the timeout elapsed without a completion.
+func (w *stallWatchdog) tripped() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.trips
+}
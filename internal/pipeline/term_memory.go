@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
+)
+
+const termMemoryFileVersion = 1
+
+// termMemoryFile is the on-disk form of --term-memory. Entries are kept per
+// language pair so one file can serve several series or directions.
+type termMemoryFile struct {
+	Version int                               `json:"version"`
+	Pairs   map[string][]translator.TermEntry `json:"pairs"`
+}
+
+func termMemoryPairKey(srcCode, tgtCode string) string {
+	return srcCode + "->" + tgtCode
+}
+
+func readTermMemoryFile(path string) (termMemoryFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return termMemoryFile{Version: termMemoryFileVersion, Pairs: map[string][]translator.TermEntry{}}, nil
+	}
+	if err != nil {
+		return termMemoryFile{}, fmt.Errorf("failed to read term memory: %w", err)
+	}
+	var f termMemoryFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return termMemoryFile{}, fmt.Errorf("failed to parse term memory %s: %w", path, err)
+	}
+	if f.Version != termMemoryFileVersion {
+		return termMemoryFile{}, fmt.Errorf("unsupported term memory version %d in %s", f.Version, path)
+	}
+	if f.Pairs == nil {
+		f.Pairs = map[string][]translator.TermEntry{}
+	}
+	return f, nil
+}
+
+// loadTermMemory returns the remembered pairs for srcCode->tgtCode. A missing
+// file yields an empty memory.
+func loadTermMemory(path, srcCode, tgtCode string) (*translator.TermMemory, error) {
+	f, err := readTermMemoryFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return translator.NewTermMemory(translator.DefaultTermMemoryLimit, f.Pairs[termMemoryPairKey(srcCode, tgtCode)]), nil
+}
+
+// saveTermMemory writes memory back for srcCode->tgtCode, keeping other pairs.
+func saveTermMemory(path, srcCode, tgtCode string, memory *translator.TermMemory) error {
+	f, err := readTermMemoryFile(path)
+	if err != nil {
+		return err
+	}
+	f.Pairs[termMemoryPairKey(srcCode, tgtCode)] = memory.Entries()
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode term memory: %w", err)
+	}
+	if err := files.AtomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save term memory: %w", err)
+	}
+	return nil
+}
+
+// carriedTerms collects short phrase choices from the chunks that succeeded
+// for the recovery log, so that repaired chunks reuse them.
+func carriedTerms(source, translated []srt.Segment) []translator.TermEntry {
+	memory := translator.NewTermMemory(translator.DefaultTermMemoryLimit, nil)
+	memory.RecordSegments(source, translated)
+	return memory.Entries()
+}
@@ -0,0 +1,71 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/tmx"
+)
+
+// loadDraftMemory reads the srcCode->tgtCode units of the TMX file at path
+// as a draft memory for Translator.SetDraftMemory. Later units win over
+// earlier ones with the same source.
+func loadDraftMemory(path, srcCode, tgtCode string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, inputErrorf("failed to open TMX file: %w", err)
+	}
+	defer f.Close()
+	units, err := tmx.Read(f, srcCode, tgtCode)
+	if err != nil {
+		return nil, inputErrorf("%s: %w", path, err)
+	}
+	memory := make(map[string]string, len(units))
+	for _, u := range units {
+		memory[u.Source] = u.Target
+	}
+	return memory, nil
+}
+
+// tmxUnits pairs each translated segment with its source, skipping
+// passthrough segments (only selected ones count when selected is non-nil),
+// empty ones, and repeats of a source already paired.
+func tmxUnits(source, translated []srt.Segment, selected []int) []tmx.Unit {
+	indices := selected
+	if indices == nil {
+		indices = make([]int, len(source))
+		for i := range source {
+			indices[i] = i
+		}
+	}
+	seen := make(map[string]bool, len(indices))
+	units := make([]tmx.Unit, 0, len(indices))
+	for _, idx := range indices {
+		if idx >= len(translated) {
+			continue
+		}
+		src := strings.TrimSpace(strings.Join(source[idx].Lines, "\n"))
+		tgt := strings.TrimSpace(strings.Join(translated[idx].Lines, "\n"))
+		if src == "" || tgt == "" || seen[src] {
+			continue
+		}
+		seen[src] = true
+		units = append(units, tmx.Unit{Source: src, Target: tgt})
+	}
+	return units
+}
+
+func saveTMX(path, srcCode, tgtCode string, units []tmx.Unit) error {
+	var buf bytes.Buffer
+	if err := tmx.Write(&buf, srcCode, tgtCode, units); err != nil {
+		return err
+	}
+	if err := files.AtomicWrite(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to save TMX file: %w", err)
+	}
+	return nil
+}
@@ -1,16 +1,19 @@
 package pipeline
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/oukeidos/focst/internal/chunker"
 	"github.com/oukeidos/focst/internal/files"
 	"github.com/oukeidos/focst/internal/gemini"
 	"github.com/oukeidos/focst/internal/language"
@@ -20,6 +23,17 @@ import (
 	"github.com/oukeidos/focst/internal/translator"
 )
 
+// translationClient is the subset of the Gemini client used by the pipeline.
+type translationClient interface {
+	gemini.Translator
+	Close() error
+}
+
+// newGeminiClient is swapped in tests to avoid real API calls.
+var newGeminiClient = func(ctx context.Context, apiKey, model string, opts gemini.ClientOptions) (translationClient, error) {
+	return gemini.NewClient(ctx, apiKey, model, opts)
+}
+
 // RunTranslation executes the full translation pipeline.
 func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error) {
 	var notes []string
@@ -28,32 +42,54 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 		logger.Warn("Config normalized", "detail", note)
 	}
 	if err := cfg.Validate(); err != nil {
-		return TranslationResult{}, fmt.Errorf("invalid configuration: %w", err)
+		return TranslationResult{}, inputErrorf("invalid configuration: %w", err)
+	}
+	if cfg.Sample > 0 && cfg.InPlace {
+		return TranslationResult{}, inputErrorf("in-place mode cannot be combined with a sample run")
+	}
+	if cfg.Sample > 0 {
+		cfg.OutputPath = SampleOutputPath(cfg.OutputPath)
+		logger.Info("Sample run: translating the first segments only", "segments", cfg.Sample, "output", cfg.OutputPath)
 	}
 
 	// 1. Validation & Setup
-	absIn, err := filepath.Abs(cfg.InputPath)
-	if err != nil {
-		return TranslationResult{}, fmt.Errorf("failed to resolve input path: %w", err)
-	}
 	absOut, err := filepath.Abs(cfg.OutputPath)
 	if err != nil {
 		return TranslationResult{}, fmt.Errorf("failed to resolve output path: %w", err)
 	}
-	if absIn == absOut {
-		return TranslationResult{}, fmt.Errorf("input and output files are the same (%s)", absIn)
-	}
-	if inInfo, err := os.Stat(absIn); err == nil {
-		if outInfo, err := os.Stat(absOut); err == nil {
-			if os.SameFile(inInfo, outInfo) {
-				return TranslationResult{}, fmt.Errorf("input and output files are the same (%s)", absIn)
+	// In-memory (remote) input has no local file to compare with the output.
+	remoteInput := cfg.InputData != nil
+	var absIn string
+	if !remoteInput {
+		absIn, err = filepath.Abs(cfg.InputPath)
+		if err != nil {
+			return TranslationResult{}, fmt.Errorf("failed to resolve input path: %w", err)
+		}
+		sameFile := absIn == absOut
+		if inInfo, err := os.Stat(absIn); err == nil {
+			if outInfo, err := os.Stat(absOut); err == nil {
+				sameFile = sameFile || os.SameFile(inInfo, outInfo)
+			} else if !os.IsNotExist(err) {
+				return TranslationResult{}, fmt.Errorf("failed to stat output path: %w", err)
 			}
 		} else if !os.IsNotExist(err) {
-			return TranslationResult{}, fmt.Errorf("failed to stat output path: %w", err)
+			return TranslationResult{}, fmt.Errorf("failed to stat input path: %w", err)
+		}
+		// In-place mode replaces the input on purpose, after backing it up.
+		if sameFile && !cfg.InPlace {
+			return TranslationResult{}, inputErrorf("input and output files are the same (%s)", absIn)
+		}
+		if cfg.InPlace {
+			if !sameFile {
+				return TranslationResult{}, inputErrorf("in-place mode writes to the input path, not %s", cfg.OutputPath)
+			}
+			if err := checkInPlaceBackup(absIn); err != nil {
+				return TranslationResult{}, err
+			}
 		}
-	} else if !os.IsNotExist(err) {
-		return TranslationResult{}, fmt.Errorf("failed to stat input path: %w", err)
 	}
+	// logInputPath is the input a recovery log points at.
+	logInputPath := absIn
 	if err := files.RejectSymlinkPath(cfg.OutputPath); err != nil {
 		return TranslationResult{}, err
 	}
@@ -62,12 +98,15 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 			return TranslationResult{}, err
 		}
 	}
+	if err := ensureOutputDir(cfg.OutputPath, cfg.MakeOutputDir); err != nil {
+		return TranslationResult{}, err
+	}
 
-	shouldOverwrite := cfg.Overwrite
+	shouldOverwrite := cfg.Overwrite || cfg.InPlace
 	outputExists := false
 	if _, err := os.Stat(cfg.OutputPath); err == nil {
 		outputExists = true
-		if cfg.OnConfirmOverwrite != nil {
+		if cfg.OnConfirmOverwrite != nil && !cfg.InPlace {
 			shouldOverwrite = cfg.OnConfirmOverwrite(cfg.OutputPath)
 		}
 		if !shouldOverwrite {
@@ -79,29 +118,65 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 
 	srcLang, ok := language.GetLanguage(cfg.SourceLang)
 	if !ok {
-		return TranslationResult{}, fmt.Errorf("unsupported source language: %s", cfg.SourceLang)
+		return TranslationResult{}, inputErrorf("unsupported source language: %s", cfg.SourceLang)
 	}
 	tgtLang, ok := language.GetLanguage(cfg.TargetLang)
 	if !ok {
-		return TranslationResult{}, fmt.Errorf("unsupported target language: %s", cfg.TargetLang)
+		return TranslationResult{}, inputErrorf("unsupported target language: %s", cfg.TargetLang)
+	}
+	if cfg.TargetLang != tgtLang.Code {
+		logger.Warn("Target language alias resolved", "requested", cfg.TargetLang, "resolved", tgtLang.Code, "name", tgtLang.Name)
+	}
+	if srcScript, tgtScript := language.ChineseScript(srcLang.Code), language.ChineseScript(tgtLang.Code); srcScript != "" && tgtScript != "" && srcScript != tgtScript {
+		logger.Warn("Source and target are different Chinese scripts; output will be converted to the target script",
+			"source", srcLang.Code, "target", tgtLang.Code)
 	}
-	if srcLang.Code == tgtLang.Code {
-		return TranslationResult{}, fmt.Errorf("source and target languages must be different (%s)", srcLang.Code)
+	sameLang := srcLang.Code == tgtLang.Code
+	if sameLang && !cfg.AllowSameLang {
+		return TranslationResult{}, inputErrorf("source and target languages must be different (%s)", srcLang.Code)
+	}
+	if cfg.ImproveDrafts && !sameLang && !language.DistinctScripts(srcLang.Code, tgtLang.Code) {
+		return TranslationResult{}, inputErrorf("--improve needs source and target languages written in different scripts (%s and %s share one)", srcLang.Code, tgtLang.Code)
+	}
+	if size, changed := cfg.resolveChunkSize(srcLang.Code, tgtLang.Code); changed {
+		logger.Info("Using suggested chunk size for language pair", "chunk_size", size, "default", cfg.ChunkSize,
+			"source", srcLang.Code, "target", tgtLang.Code)
+		cfg.ChunkSize = size
+	}
+	if cfg.Sample > 0 && cfg.Sample < cfg.ChunkSize {
+		// A smaller chunk keeps the sample from translating far past its end.
+		cfg.ChunkSize = cfg.Sample
 	}
 
 	// 2. Load and Preprocess
-	segments, err := srt.Load(cfg.InputPath)
+	var segments []srt.Segment
+	loadOpts := srt.LoadOptions{Format: cfg.InputFormat, Encoding: cfg.InputEncoding, Language: srcLang.Code}
+	if remoteInput {
+		segments, err = srt.LoadReaderWithOptions(bytes.NewReader(cfg.InputData), loadOpts)
+	} else {
+		segments, err = srt.LoadWithOptions(cfg.InputPath, loadOpts)
+	}
 	if err != nil {
-		return TranslationResult{}, fmt.Errorf("failed to load subtitle file: %w", err)
+		return TranslationResult{}, inputErrorf("failed to load subtitle file: %w", err)
 	}
-	if err := srt.Validate(segments); err != nil {
-		return TranslationResult{}, fmt.Errorf("invalid subtitle file: %w", err)
+	if err := srt.ValidateWithOptions(segments, srt.ValidateOptions{RequireDialogue: !cfg.AllowNoDialogue}); err != nil {
+		return TranslationResult{}, inputErrorf("invalid subtitle file: %w", err)
 	}
 	logger.Info("Loaded and validated subtitles", "count", len(segments), "path", cfg.InputPath)
+	zeroDuration := srt.ZeroDurationSegments(segments)
+	if len(zeroDuration) > 0 {
+		logger.Warn("Source has zero-duration cues (end equals start); timing correction extends them",
+			"count", len(zeroDuration), "first_id", zeroDuration[0])
+	}
+	noDialogue := !srt.HasDialogue(segments)
+	copyThrough := sameLang || noDialogue
 
-	if !cfg.NoPreprocess {
-		var idMap []srt.IDMap
-		segments, idMap = srt.PreprocessForPathWithMappingOptions(segments, srcLang.Code, cfg.InputPath, !cfg.NoLangPreprocess)
+	var idMap []srt.IDMap
+	if noDialogue {
+		// Preprocessing would drop symbol-only cues; keep them for copy-through.
+		logger.Info("Preprocessing skipped (no dialogue text)")
+	} else if !cfg.NoPreprocess {
+		segments, idMap = srt.PreprocessSteps(segments, srcLang.Code, cfg.InputPath, cfg.preprocessOptions())
 		logger.Info("Preprocessing complete", "count", len(segments))
 		if cfg.LogPath != "" && len(idMap) > 0 {
 			if err := writeIDMap(cfg.LogPath, idMap); err != nil {
@@ -111,45 +186,240 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 	} else {
 		logger.Info("Preprocessing skipped")
 	}
+	if cfg.DumpIDMap != "" {
+		if idMap == nil {
+			idMap = srt.IdentityIDMap(segments)
+		}
+		if _, err := saveIDMap(cfg.DumpIDMap, idMap); err != nil {
+			return TranslationResult{}, fmt.Errorf("failed to write segment ID mapping: %w", err)
+		}
+		logger.Info("Segment ID mapping saved", "path", cfg.DumpIDMap, "mapping_count", len(idMap))
+	}
 
-	// 3. Initialize Client & Translator
-	gClient, err := gemini.NewClient(ctx, cfg.APIKey, cfg.Model)
-	if err != nil {
-		return TranslationResult{}, fmt.Errorf("failed to create Gemini client: %w", err)
+	var selected []int
+	if cfg.HasSegmentFilter() && !copyThrough {
+		var pattern *regexp.Regexp
+		if cfg.FilterRegex != "" {
+			pattern = regexp.MustCompile(cfg.FilterRegex) // validated by cfg.Validate
+		}
+		selected = srt.SelectSegments(segments, pattern, cfg.ForcedOnly)
+		if len(selected) == 0 {
+			return TranslationResult{}, fmt.Errorf("no segments matched the segment filter")
+		}
+		logger.Info("Segment filter applied", "selected", len(selected), "passthrough", len(segments)-len(selected))
+	}
+	if ranges := cfg.excludeRanges(); ranges != nil && !copyThrough {
+		before := len(segments)
+		if selected != nil {
+			before = len(selected)
+		}
+		selected = srt.ExcludeIDs(segments, selected, ranges)
+		if selected != nil {
+			if len(selected) == 0 {
+				return TranslationResult{}, fmt.Errorf("every segment is excluded from translation")
+			}
+			logger.Info("Excluded segments passed through", "count", before-len(selected))
+		}
+	}
+	skipNonTranslatable := !cfg.NoSkipNonTranslatable && !copyThrough
+	allNonTranslatable := false
+	if skipNonTranslatable {
+		before := len(segments)
+		if selected != nil {
+			before = len(selected)
+		}
+		selected = srt.ExcludeNonTranslatable(segments, selected)
+		if selected != nil && len(selected) < before {
+			logger.Info("Non-translatable segments passed through", "count", before-len(selected))
+		}
+		if selected != nil && len(selected) == 0 {
+			allNonTranslatable = true
+			copyThrough = true
+		}
 	}
-	defer gClient.Close()
 
-	tr, err := translator.NewTranslator(gClient, cfg.ChunkSize, cfg.ContextSize, cfg.Concurrency, cfg.RetryOnLongLines, srcLang, tgtLang)
-	if err != nil {
-		return TranslationResult{}, fmt.Errorf("failed to initialize translator: %w", err)
+	translatable := len(segments)
+	if selected != nil {
+		translatable = len(selected)
 	}
-	tr.SetPromptCPL(!cfg.NoPromptCPL)
-	if len(cfg.NamesMapping) > 0 {
-		tr.SetNamesMapping(cfg.NamesMapping)
-		logger.Info("Loaded character name mapping", "count", len(cfg.NamesMapping))
+	var sampled []int
+	if cfg.Sample > 0 && !copyThrough {
+		var count int
+		sampled, count = sampleChunks(cfg.Sample, segments, cfg.ChunkSize, selected)
+		if count == 0 {
+			return TranslationResult{}, fmt.Errorf("no translatable segments in the first %d segments", cfg.Sample)
+		}
+		translatable = count
+	}
+	if !copyThrough {
+		ok, err := confirmLargeRun(cfg, segments, selected, translatable)
+		if err != nil {
+			return TranslationResult{}, err
+		}
+		if !ok {
+			logger.Info("Large run aborted by user", "segments", translatable)
+			return TranslationResult{Status: TranslationStatusSkipped}, nil
+		}
 	}
 
-	// 4. Translate
-	logger.Info("Starting translation", "model", cfg.Model)
-	translated, failed, err := tr.TranslateSRT(ctx, segments, cfg.OnProgress)
-	if err != nil {
-		return TranslationResult{Usage: tr.GetUsage()}, fmt.Errorf("fatal translation error: %w", err)
+	// 3-4. Initialize Client & Translator, then Translate
+	var translated []srt.Segment
+	var failed []int
+	var spans []chunker.Span
+	var usage, polishUsage gemini.UsageMetadata
+	var costCapped bool
+	var throughput translator.Throughput
+	var termMemory *translator.TermMemory
+	var promptTerms []translator.TermEntry
+	var drafts map[string]string
+	var chunkCache *recovery.FileChunkCache
+	var background string
+	if copyThrough {
+		if sameLang {
+			logger.Warn("Source and target languages match; copying subtitles without translation", "lang", srcLang.Code)
+		} else if allNonTranslatable {
+			logger.Warn("Only numbers, URLs, or codes found; copying subtitles without translation")
+		} else {
+			logger.Warn("No dialogue text found; copying subtitles without translation")
+		}
+		translated = append([]srt.Segment(nil), segments...)
+		translatable = 0
+	} else {
+		if cfg.ChunkCache {
+			chunkCache, err = recovery.NewFileChunkCache(recovery.ChunkCacheDir(absOut), cfg.Model)
+			if err != nil {
+				return TranslationResult{}, fmt.Errorf("failed to open chunk cache: %w", err)
+			}
+			logger.Info("Chunk cache enabled", "dir", chunkCache.Dir())
+		}
+		if cfg.TermMemoryPath != "" {
+			termMemory, err = loadTermMemory(cfg.TermMemoryPath, srcLang.Code, tgtLang.Code)
+			if err != nil {
+				return TranslationResult{}, err
+			}
+			// Recording this run's choices later must not change the plan.
+			promptTerms = termMemory.Entries()
+			logger.Info("Term memory loaded", "path", cfg.TermMemoryPath, "entries", len(promptTerms))
+		}
+		if cfg.TMXImportPath != "" {
+			drafts, err = loadDraftMemory(cfg.TMXImportPath, srcLang.Code, tgtLang.Code)
+			if err != nil {
+				return TranslationResult{}, err
+			}
+			logger.Info("Translation memory loaded", "path", cfg.TMXImportPath, "units", len(drafts))
+		}
+		if cfg.BackgroundPath != "" {
+			background, err = loadBackground(cfg.BackgroundPath)
+			if err != nil {
+				return TranslationResult{}, err
+			}
+			logger.Info("Background information loaded", "path", cfg.BackgroundPath, "bytes", len(background))
+		}
+		var cache translator.ChunkCache
+		if chunkCache != nil {
+			cache = chunkCache
+		}
+		if cfg.Resume != nil {
+			if cache != nil {
+				cache = chunkCaches{cfg.Resume, cache}
+			} else {
+				cache = cfg.Resume
+			}
+			logger.Info("Resume token enabled", "completed_chunks", cfg.Resume.CompletedChunks())
+		}
+		translated, failed, spans, usage, throughput, costCapped, err = translateSegments(ctx, cfg, segments, selected, sampled, srcLang, tgtLang, cache, termMemory, drafts, background)
+		if err != nil {
+			return TranslationResult{Usage: usage, Throughput: throughput}, err
+		}
+		if cfg.PolishModel != "" && !costCapped && ctx.Err() == nil {
+			translated, polishUsage = polishSegments(ctx, cfg, segments, translated, selected, spans, sampled, failed, srcLang, tgtLang, termMemory, background, usage)
+			usage.PromptTokenCount += polishUsage.PromptTokenCount
+			usage.CandidatesTokenCount += polishUsage.CandidatesTokenCount
+			usage.TotalTokenCount += polishUsage.TotalTokenCount
+		}
 	}
+	if cfg.Sample > 0 {
+		segments, translated, selected = truncateSample(cfg.Sample, segments, translated, selected)
+	}
+	plan := cfg.planSettings(optionalBackgroundHash(background), termsHash(promptTerms), draftsHash(drafts))
 
 	// 5. Handle Results
-	totalChunks := (len(segments) + cfg.ChunkSize - 1) / cfg.ChunkSize
+	totalChunks := len(spans)
+	if sampled != nil {
+		totalChunks = len(sampled)
+	}
 	status := translationStatusFromRecovery(recovery.CalculateStatus(len(failed), totalChunks))
 	result := TranslationResult{
-		Status:       status,
-		Usage:        tr.GetUsage(),
-		FailedChunks: len(failed),
-		TotalChunks:  totalChunks,
+		Status:           status,
+		Usage:            usage,
+		PolishUsage:      polishUsage,
+		FailedChunks:     len(failed),
+		TotalChunks:      totalChunks,
+		ChunkSize:        cfg.ChunkSize,
+		ContextSize:      cfg.ContextSize,
+		CostCapped:       costCapped,
+		Throughput:       throughput,
+		ZeroDurationCues: len(zeroDuration),
+	}
+	if !copyThrough {
+		result.PolishModel = cfg.PolishModel
 	}
 	logger.Info("Translation finished", "status", status)
+	if len(failed) > 0 {
+		report := buildFailureReport(segments, selected, spans, failed, throughput.Failures)
+		result.Failures = &report
+		for _, e := range report.Chunks {
+			logger.Warn("Failed chunk", "chunk", e.Chunk, "first_id", e.FirstID, "last_id", e.LastID, "kind", e.Kind, "action", e.Action)
+		}
+		if report.NeedsConfigFix() {
+			logger.Warn("Some chunks were refused by the API; fix the API key or request settings before running repair")
+		}
+	}
+	if cfg.FailureReportPath != "" && !copyThrough {
+		report := FailureReport{Chunks: []FailureEntry{}}
+		if result.Failures != nil {
+			report = *result.Failures
+		}
+		if err := saveFailureReport(cfg.FailureReportPath, report); err != nil {
+			logger.Warn("Failed to write failure report", "path", cfg.FailureReportPath, "error", err)
+		} else {
+			logger.Info("Failure report saved", "path", cfg.FailureReportPath, "count", len(report.Chunks))
+		}
+	}
+	if status == TranslationStatusSuccess && !copyThrough && len(cfg.NamesMapping) > 0 {
+		report := checkGlossary(segments, translated, selected, cfg.NamesMapping)
+		result.Glossary = &report
+		logger.Info("Glossary adherence", "summary", report.Summary())
+		for _, e := range report.Entries {
+			if e.Honored < e.Occurrences {
+				logger.Warn("Glossary mapping missed", "source", e.Source, "target", e.Target, "missed", e.Occurrences-e.Honored, "occurrences", e.Occurrences)
+			}
+		}
+		if cfg.GlossaryReportPath != "" {
+			if err := saveGlossaryReport(cfg.GlossaryReportPath, report); err != nil {
+				logger.Warn("Failed to write glossary report", "path", cfg.GlossaryReportPath, "error", err)
+			} else {
+				logger.Info("Glossary report saved", "path", cfg.GlossaryReportPath)
+			}
+		}
+	}
+	if status == TranslationStatusSuccess && !copyThrough && cfg.TMXPath != "" {
+		units := tmxUnits(segments, translated, selected)
+		if err := saveTMX(cfg.TMXPath, srcLang.Code, tgtLang.Code, units); err != nil {
+			logger.Warn("Failed to write TMX file", "path", cfg.TMXPath, "error", err)
+		} else {
+			logger.Info("Translation memory exported", "path", cfg.TMXPath, "units", len(units))
+		}
+	}
 	canceled := ctx.Err() != nil
+	if costCapped {
+		logger.Warn("Translation stopped by cost cap", "max_cost", cfg.MaxCost, "failed_chunks", len(failed), "total_chunks", totalChunks)
+	}
 
 	effectiveOutputPath := cfg.OutputPath
-	if status == TranslationStatusSuccess || status == TranslationStatusPartialSuccess {
+	keepCues := cfg.keepCueSettings(effectiveOutputPath)
+	savePartialFailure := status == TranslationStatusFailure && (cfg.SavePartialOnFailure || costCapped)
+	if status == TranslationStatusSuccess || status == TranslationStatusPartialSuccess || savePartialFailure {
 		if !(outputExists && shouldOverwrite) {
 			safePath, changed, err := files.SafePath(cfg.OutputPath)
 			if err != nil {
@@ -161,11 +431,41 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 			}
 		}
 
+		if termMemory != nil && cfg.Sample == 0 {
+			// A sample is a trial run; keep it out of the series memory.
+			termMemory.RecordSegments(segments, translated)
+			if err := saveTermMemory(cfg.TermMemoryPath, srcLang.Code, tgtLang.Code, termMemory); err != nil {
+				logger.Warn("Failed to update term memory", "path", cfg.TermMemoryPath, "error", err)
+			}
+		}
+
+		var review *Review
+		if status == TranslationStatusSuccess && !copyThrough {
+			// Post-processing edits segments in place; keep the raw translations.
+			review = &Review{
+				Source:     segments,
+				Translated: cloneSegments(translated),
+				Selected:   selected,
+				SourceLang: srcLang.Code,
+				TargetLang: tgtLang.Code,
+				ChunkSize:  cfg.ChunkSize,
+				Spans:      spans,
+			}
+		}
+
 		outSegments := translated
 		if status == TranslationStatusSuccess {
 			if !cfg.NoPostprocess {
 				logger.Info("Performing post-processing")
-				outSegments = srt.PostprocessWithOptions(outSegments, tgtLang.Code, tgtLang.DefaultCPS, !cfg.NoLangPostprocess)
+				outSegments = srt.PostprocessWithOptions(outSegments, tgtLang.Code, tgtLang.DefaultCPS, srt.PostprocessOptions{
+					NoLangRules:        cfg.NoLangPostprocess,
+					NoTimingCorrection: cfg.NoTimingCorrection,
+					NormalizeQuotes:    cfg.NormalizeQuotes,
+					OnEmptied:          srt.EmptiedPolicy(cfg.OnEmptied),
+					RulesLang:          cfg.postprocessLangCode(),
+				})
+				restorePassthroughLines(outSegments, segments, selected)
+				restoreExcluded(outSegments, segments, cfg.excludeRanges())
 			} else {
 				logger.Info("Post-processing skipped")
 			}
@@ -173,22 +473,66 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 			logger.Info("Skipping post-processing for partial output")
 		}
 
-		if err := srt.Save(effectiveOutputPath, outSegments); err != nil {
+		if cfg.InPlace {
+			backup, err := backupInPlaceInput(absIn)
+			if err != nil {
+				return result, fmt.Errorf("failed to back up input before writing in place: %w", err)
+			}
+			logger.Info("Original input backed up", "path", backup)
+			result.BackupPath = backup
+			// The input now holds the translation; repair reads the backup.
+			logInputPath = backup
+		}
+		saveOpts := saveOptions(cfg.EmbedMetadata, effectiveOutputPath, cfg.OutputFormat, cfg.provenanceSettings(plan))
+		saveOpts.CueSettings = keepCues
+		saveOpts.Compat = srt.CompatProfile(cfg.CompatProfile)
+		saveOpts.Verify = cfg.InPlace // never replace the input with unparsable data
+		if err := srt.SaveWithOptions(effectiveOutputPath, outSegments, saveOpts); err != nil {
 			return result, fmt.Errorf("failed to save output file: %w", err)
 		}
 		result.OutputPath = effectiveOutputPath
-		logger.Info("Saved results", "path", effectiveOutputPath)
+		result.PartialOutput = status != TranslationStatusSuccess
+		if cfg.PriorityFirst {
+			removeIncrementalOutput(cfg.OutputPath)
+		}
+		if review != nil {
+			review.OutputPath = effectiveOutputPath
+			result.Review = review
+		}
+		if savePartialFailure {
+			logger.Warn("Saved partial output despite failure (failed chunks keep source text)", "path", effectiveOutputPath)
+		} else {
+			logger.Info("Saved results", "path", effectiveOutputPath)
+		}
+		if chunkCache != nil && status == TranslationStatusSuccess {
+			if err := chunkCache.Remove(); err != nil {
+				logger.Warn("Failed to remove chunk cache", "dir", chunkCache.Dir(), "error", err)
+			}
+		}
 	}
 
-	if status == TranslationStatusPartialSuccess || status == TranslationStatusFailure {
-		inputHash, err := recovery.HashFileHex(absIn)
+	if cfg.Sample > 0 && (status == TranslationStatusPartialSuccess || status == TranslationStatusFailure) {
+		logger.Warn("Sample run incomplete; no recovery log written", "failed_chunks", len(failed), "total_chunks", totalChunks)
+		return result, nil
+	}
+	if remoteInput && (status == TranslationStatusPartialSuccess || status == TranslationStatusFailure) {
+		logger.Warn("Remote input; no recovery log written (download the input to repair)", "failed_chunks", len(failed), "total_chunks", totalChunks)
+		return result, nil
+	}
+	keepSuccessLog := cfg.KeepLog && status == TranslationStatusSuccess
+	if keepSuccessLog && (copyThrough || cfg.Sample > 0 || remoteInput) {
+		logger.Warn("Session log not kept for sample, remote-input, or copy-through runs")
+		keepSuccessLog = false
+	}
+	if status == TranslationStatusPartialSuccess || status == TranslationStatusFailure || keepSuccessLog {
+		inputHash, err := recovery.HashFileHex(logInputPath)
 		if err != nil {
 			return result, fmt.Errorf("failed to compute input hash for recovery log: %w", err)
 		}
 		segmentsChecksum := srt.SegmentsChecksumHex(segments)
 		logPath := recovery.GenerateRecoveryPath(effectiveOutputPath)
 
-		relativeInputPath, err := recovery.ToRelativeInputPath(logPath, absIn)
+		relativeInputPath, err := recovery.ToRelativeInputPath(logPath, logInputPath)
 		if err != nil {
 			return result, fmt.Errorf("failed to convert input path to relative: %w", err)
 		}
@@ -207,39 +551,106 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 			}
 		}
 
+		relativeBackgroundPath, backgroundSum := "", ""
+		if background != "" {
+			relativeBackgroundPath, err = recovery.ToRelativeInputPath(logPath, cfg.BackgroundPath)
+			if err != nil {
+				return result, fmt.Errorf("failed to convert background path to relative: %w", err)
+			}
+			backgroundSum = backgroundHash(background)
+		}
+
+		relativeCacheDir := ""
+		// A successful run has already removed its cache.
+		if chunkCache != nil && status != TranslationStatusSuccess {
+			relativeCacheDir, err = recovery.ToRelativeOutputPath(logPath, chunkCache.Dir())
+			if err != nil {
+				logger.Warn("Chunk cache is outside the recovery log directory; repair will not reuse it", "dir", chunkCache.Dir())
+				relativeCacheDir = ""
+			}
+		}
+
 		session := &recovery.SessionLog{
-			LogVersion:        recovery.CurrentLogVersion,
-			InputPath:         relativeInputPath,
-			OutputPath:        relativeOutputPath,
-			InputHash:         inputHash,
-			SegmentsChecksum:  segmentsChecksum,
-			Model:             cfg.Model,
-			NamesPath:         relativeNamesPath,
-			ChunkSize:         cfg.ChunkSize,
-			ContextSize:       cfg.ContextSize,
-			Concurrency:       cfg.Concurrency,
-			NoPreprocess:      cfg.NoPreprocess,
-			NoPostprocess:     cfg.NoPostprocess,
-			NoLangPreprocess:  cfg.NoLangPreprocess,
-			NoLangPostprocess: cfg.NoLangPostprocess,
-			NoPromptCPL:       cfg.NoPromptCPL,
-			SourceLang:        srcLang.Code,
-			TargetLang:        tgtLang.Code,
-			FailedChunks:      failed,
-			TotalChunks:       totalChunks,
-			Status:            string(status),
-		}
-		if canceled {
+			LogVersion:          recovery.CurrentLogVersion,
+			InputPath:           relativeInputPath,
+			OutputPath:          relativeOutputPath,
+			InputHash:           inputHash,
+			SegmentsChecksum:    segmentsChecksum,
+			Model:               cfg.Model,
+			PolishModel:         cfg.PolishModel,
+			NamesPath:           relativeNamesPath,
+			BackgroundPath:      relativeBackgroundPath,
+			BackgroundHash:      backgroundSum,
+			ChunkSize:           cfg.ChunkSize,
+			ContextSize:         cfg.ContextSize,
+			Concurrency:         cfg.Concurrency,
+			NoPreprocess:        cfg.NoPreprocess,
+			NoPostprocess:       cfg.NoPostprocess,
+			NoLangPreprocess:    cfg.NoLangPreprocess,
+			NoBracketRemoval:    cfg.NoBracketRemoval,
+			NoAngleStrip:        cfg.NoAngleStrip,
+			NoMeaninglessFilter: cfg.NoMeaninglessFilter,
+			NoLangPostprocess:   cfg.NoLangPostprocess,
+			NoPromptCPL:         cfg.NoPromptCPL,
+			SourceLang:          srcLang.Code,
+			TargetLang:          tgtLang.Code,
+			FailedChunks:        failed,
+			TotalChunks:         totalChunks,
+			Status:              string(status),
+			FilterRegex:         cfg.FilterRegex,
+			ForcedOnly:          cfg.ForcedOnly,
+			ExcludeIDs:          cfg.ExcludeIDs,
+			ChunkCacheDir:       relativeCacheDir,
+			NoTimingCorrection:  cfg.NoTimingCorrection,
+			NormalizeQuotes:     cfg.NormalizeQuotes,
+			OnEmptied:           cfg.OnEmptied,
+			PostprocessLang:     cfg.postprocessLangCode(),
+			CompatProfile:       cfg.CompatProfile,
+			EmbedMetadata:       cfg.EmbedMetadata,
+			KeepCueSettings:     keepCues,
+			KeepDialogueDashes:  cfg.KeepDialogueDashes,
+			OnEmpty:             cfg.OnEmpty,
+			Formality:           cfg.Formality,
+			NarrativeTag:        cfg.NarrativeTag,
+			CPLMetric:           cfg.CPLMetric,
+			TranslateNotes:      cfg.TranslateNotes,
+			RetryOnLongLines:    cfg.RetryOnLongLines,
+			CPLTolerance:        cfg.CPLTolerance,
+			SingleLine:          cfg.SingleLine,
+			AutoLinebreak:       cfg.AutoLinebreak,
+			DedupRepeats:        cfg.DedupRepeats,
+			ImproveDrafts:       cfg.ImproveDrafts,
+			SkipNonTranslatable: skipNonTranslatable,
+			InputFormat:         cfg.InputFormat,
+			InputEncoding:       cfg.InputEncoding,
+			OutputFormat:        cfg.OutputFormat,
+			Terms:               carriedTerms(segments, translated),
+			CreatedAt:           time.Now().UTC(),
+			PlanHash:            plan.hash(),
+			TermsHash:           plan.Terms,
+			DraftsHash:          plan.Drafts,
+		}
+		if costCapped {
+			session.StatusReason = "cost_cap"
+		} else if canceled {
 			session.StatusReason = "canceled"
 		}
 		if err := recovery.SaveSessionLog(logPath, session); err != nil {
 			logger.Error("Failed to save recovery log", "error", err)
 		} else {
-			if status == TranslationStatusPartialSuccess {
+			switch status {
+			case TranslationStatusSuccess:
+				logger.Info("Session log kept", "path", logPath)
+			case TranslationStatusPartialSuccess:
 				logger.Warn("Partial success - recovery log saved")
-			} else {
+			default:
 				logger.Error("Translation failed - recovery log saved")
 			}
+			if cfg.DumpFailed && len(failed) > 0 {
+				dumpFailedSource(logPath, segments, selected, spans, failed)
+			} else {
+				removeFailedSource(logPath)
+			}
 		}
 		result.RecoveryLogPath = logPath
 		return result, nil
@@ -248,27 +659,189 @@ func RunTranslation(ctx context.Context, cfg Config) (TranslationResult, error)
 	return result, nil
 }
 
+// translateSegments creates the Gemini client and translator and translates
+// segments (or only the selected subset when selected is non-nil). A non-nil
+// chunks limits the run to those chunk indices, numbered over the subset when
+// selected is non-nil; untouched chunks come back as their source text. A non-nil
+// cache makes completed chunks persist and be reused across runs, and a non-nil
+// termMemory adds remembered phrase choices to the prompt. drafts, keyed by
+// source text, are sent as drafts of matching segments. The returned bool
+// reports whether cfg.MaxCost stopped the run early.
+func translateSegments(ctx context.Context, cfg Config, segments []srt.Segment, selected, chunks []int, srcLang, tgtLang language.Language, cache translator.ChunkCache, termMemory *translator.TermMemory, drafts map[string]string, background string) ([]srt.Segment, []int, []chunker.Span, gemini.UsageMetadata, translator.Throughput, bool, error) {
+	logGeminiEndpoint(cfg.GeminiEndpoint)
+	gClient, err := newGeminiClient(ctx, cfg.APIKey, cfg.Model, cfg.geminiClientOptions())
+	if err != nil {
+		return nil, nil, nil, gemini.UsageMetadata{}, translator.Throughput{}, false, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	defer gClient.Close()
+
+	tr, err := newTranslator(cfg, gClient, srcLang, tgtLang, cache, termMemory, drafts, background)
+	if err != nil {
+		return nil, nil, nil, gemini.UsageMetadata{}, translator.Throughput{}, false, err
+	}
+
+	onProgress := cfg.OnProgress
+	if cfg.OnEstimate != nil {
+		onProgress = wrapEstimate(cfg.Model, tr.Throughput, cfg.OnEstimate, onProgress)
+	}
+	var guard *costGuard
+	if cfg.MaxCost > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		guard = newCostGuard(cfg.Model, cfg.MaxCost, tr.GetUsage, cancel)
+		onProgress = guard.wrap(onProgress)
+		logger.Info("Cost cap enabled", "max_cost", cfg.MaxCost)
+	}
+	if cfg.StallTimeout > 0 {
+		var cancelOnStall context.CancelFunc
+		if cfg.CancelOnStall {
+			ctx, cancelOnStall = context.WithCancel(ctx)
+			defer cancelOnStall()
+		}
+		watchdog := newStallWatchdog(cfg.StallTimeout, cancelOnStall)
+		defer watchdog.stop()
+		onProgress = watchdog.wrap(onProgress)
+		logger.Info("Stall watchdog enabled", "stall_timeout", cfg.StallTimeout, "cancel", cfg.CancelOnStall)
+	}
+
+	if cfg.PriorityFirst {
+		if out := newIncrementalOutput(cfg, segments, selected); out != nil {
+			tr.SetPriorityFirst(out.flush)
+			logger.Info("Streaming finished chunks in order", "path", out.path)
+		}
+	}
+
+	logger.Info("Starting translation", "model", cfg.Model, "chunk_size", cfg.ChunkSize, "ctx_size", cfg.ContextSize)
+	var translated []srt.Segment
+	var failed []int
+	if selected != nil {
+		translated, failed, err = tr.TranslateSubset(ctx, segments, selected, chunks, onProgress)
+	} else if chunks != nil {
+		translated, failed, err = tr.TranslateChunks(ctx, segments, chunks, onProgress)
+	} else {
+		translated, failed, err = tr.TranslateSRT(ctx, segments, onProgress)
+	}
+	costCapped := guard != nil && guard.exceeded()
+	if err != nil {
+		return nil, nil, nil, tr.GetUsage(), tr.Throughput(), costCapped, fmt.Errorf("fatal translation error: %w", err)
+	}
+	usage := tr.GetUsage()
+	if cfg.TranslateNotes && ctx.Err() == nil {
+		notesUsage := translateNotes(ctx, cfg, gClient, srcLang, tgtLang, background, translated, nil)
+		usage.PromptTokenCount += notesUsage.PromptTokenCount
+		usage.CandidatesTokenCount += notesUsage.CandidatesTokenCount
+		usage.TotalTokenCount += notesUsage.TotalTokenCount
+	}
+	return translated, failed, tr.ChunkSpans(), usage, tr.Throughput(), costCapped, nil
+}
+
+// newTranslator creates a translator for cfg with every setting that shapes
+// its requests, including the system prompt.
+func newTranslator(cfg Config, client translationClient, srcLang, tgtLang language.Language, cache translator.ChunkCache, termMemory *translator.TermMemory, drafts map[string]string, background string) (*translator.Translator, error) {
+	tr, err := translator.NewTranslator(client, cfg.ChunkSize, cfg.ContextSize, cfg.Concurrency, cfg.RetryOnLongLines, srcLang, tgtLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize translator: %w", err)
+	}
+	tr.SetPromptCPL(!cfg.NoPromptCPL)
+	tr.SetRampUp(cfg.RampUp)
+	tr.SetJitterMax(cfg.retryJitter())
+	tr.SetRelaxSafety(cfg.RelaxSafety)
+	tr.SetMaxSegmentsPerMinute(cfg.MaxSegmentsPerMinute)
+	tr.SetPreserveDialogueDashes(cfg.KeepDialogueDashes)
+	tr.SetDedupRepeats(cfg.DedupRepeats)
+	tr.SetImproveDrafts(cfg.ImproveDrafts)
+	tr.SetFailFast(cfg.FailFast)
+	if metric, err := translator.ParseCPLMetric(cfg.CPLMetric); err == nil {
+		tr.SetCPLMetric(metric)
+	}
+	if cfg.CPLTolerance > 0 {
+		tr.SetCPLTolerance(cfg.CPLTolerance)
+	}
+	if policy, err := translator.ParseEmptyPolicy(cfg.OnEmpty); err == nil {
+		tr.SetEmptyPolicy(policy)
+	}
+	if formality, err := translator.ParseFormality(cfg.Formality); err == nil {
+		tr.SetFormality(formality)
+	}
+	if tag, err := srt.ParseNarrativeTag(cfg.NarrativeTag); err == nil {
+		tr.SetNarrative(tag)
+	}
+	tr.SetSingleLine(cfg.SingleLine)
+	tr.SetAutoLinebreak(cfg.AutoLinebreak)
+	if len(cfg.NamesMapping) > 0 {
+		tr.SetNamesMapping(cfg.NamesMapping)
+		logger.Info("Loaded character name mapping", "count", len(cfg.NamesMapping))
+	}
+	if cache != nil {
+		tr.SetChunkCache(cache)
+	}
+	if termMemory != nil {
+		tr.SetTermMemory(termMemory)
+	}
+	if len(drafts) > 0 {
+		tr.SetDraftMemory(drafts)
+	}
+	if background != "" {
+		tr.SetBackground(background)
+	}
+	return tr, nil
+}
+
+// restorePassthroughLines undoes target-language text cleanup on segments that
+// were not selected for translation, so they keep their source text verbatim.
+// Segments are matched by ID, since post-processing may drop emptied segments.
+func restorePassthroughLines(out, source []srt.Segment, selected []int) {
+	if selected == nil {
+		return
+	}
+	isSelected := make(map[int]bool, len(selected))
+	for _, idx := range selected {
+		isSelected[idx] = true
+	}
+	passthrough := make(map[int][]string, len(source)-len(selected))
+	for i, seg := range source {
+		if !isSelected[i] {
+			passthrough[seg.ID] = seg.Lines
+		}
+	}
+	for i := range out {
+		if lines, ok := passthrough[out[i].ID]; ok {
+			out[i].Lines = lines
+		}
+	}
+}
+
+// restoreExcluded puts the segments excluded by ID back as they were before
+// post-processing, timing included, so hand-made cues stay byte-identical.
+func restoreExcluded(out, source []srt.Segment, ranges []srt.IDRange) {
+	if ranges == nil {
+		return
+	}
+	excluded := make(map[int]srt.Segment)
+	for _, seg := range source {
+		if srt.ContainsID(ranges, seg.ID) {
+			excluded[seg.ID] = seg
+		}
+	}
+	for i := range out {
+		if seg, ok := excluded[out[i].ID]; ok {
+			out[i] = seg
+		}
+	}
+}
+
 func writeIDMap(logPath string, mapping []srt.IDMap) error {
 	dir := filepath.Dir(logPath)
 	base := strings.TrimSuffix(filepath.Base(logPath), filepath.Ext(logPath))
 	id := uuid.NewString()
 	mapPath := filepath.Join(dir, fmt.Sprintf("%s_idmap_%s.json", base, id))
 
-	data, err := json.Marshal(struct {
-		Version int         `json:"version"`
-		Mapping []srt.IDMap `json:"mapping"`
-	}{
-		Version: 1,
-		Mapping: mapping,
-	})
+	data, err := saveIDMap(mapPath, mapping)
 	if err != nil {
 		return err
 	}
 
-	if err := files.AtomicWrite(mapPath, data, 0600); err != nil {
-		return err
-	}
-
 	sum := sha256.Sum256(data)
 	logger.Info("Segment ID mapping saved",
 		"event", "segment_id_map",
@@ -278,3 +851,43 @@ func writeIDMap(logPath string, mapping []srt.IDMap) error {
 	)
 	return nil
 }
+
+// saveIDMap writes mapping as an ID map document (see srt.MarshalIDMap) and
+// returns the bytes written.
+func saveIDMap(path string, mapping []srt.IDMap) ([]byte, error) {
+	data, err := srt.MarshalIDMap(mapping)
+	if err != nil {
+		return nil, err
+	}
+	if err := files.RejectSymlinkPath(path); err != nil {
+		return nil, err
+	}
+	if err := files.AtomicWrite(path, data, 0600); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ensureOutputDir checks that the directory of outputPath exists before any
+// request is paid for, so a mistyped path fails at once rather than when the
+// finished translation is saved. With create, a missing directory is made.
+func ensureOutputDir(outputPath string, create bool) error {
+	dir := filepath.Dir(outputPath)
+	info, err := os.Stat(dir)
+	switch {
+	case err == nil:
+		if !info.IsDir() {
+			return inputErrorf("output directory is not a directory: %s", dir)
+		}
+		return nil
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to stat output directory: %w", err)
+	case !create:
+		return inputErrorf("output directory does not exist: %s (use --mkdir to create it)", dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	logger.Info("Created output directory", "path", dir)
+	return nil
+}
@@ -1,6 +1,9 @@
 package pipeline
 
-import "github.com/oukeidos/focst/internal/gemini"
+import (
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/translator"
+)
 
 // TranslationStatus is the terminal state of a translation run.
 type TranslationStatus string
@@ -17,9 +20,36 @@ type TranslationResult struct {
 	Status          TranslationStatus
 	RecoveryLogPath string
 	OutputPath      string
-	Usage           gemini.UsageMetadata
-	FailedChunks    int
-	TotalChunks     int
+	// BackupPath is where an in-place run kept the original input.
+	BackupPath   string
+	Usage        gemini.UsageMetadata
+	FailedChunks int
+	TotalChunks  int
+	// ChunkSize and ContextSize are the values the run used, after
+	// Normalize and the language pair's suggested chunk size.
+	ChunkSize   int
+	ContextSize int
+	// PartialOutput is true when the saved output still contains source text for failed chunks.
+	PartialOutput bool
+	// CostCapped is true when the run was stopped early by Config.MaxCost.
+	CostCapped bool
+	// PolishModel is Config.PolishModel when a polish pass ran, and
+	// PolishUsage that pass's share of Usage, which covers both passes.
+	PolishModel string
+	PolishUsage gemini.UsageMetadata
+	// Throughput is the final timing and token rate of the translated chunks.
+	Throughput translator.Throughput
+	// Glossary reports names-mapping adherence for a successful run with a
+	// mapping; nil otherwise.
+	Glossary *GlossaryReport
+	// Failures says why each failed chunk failed; nil when nothing failed.
+	Failures *FailureReport
+	// ZeroDurationCues counts source cues whose end equals their start. They
+	// pass validation but suggest a damaged source file.
+	ZeroDurationCues int
+	// Review retains the segments behind a successful run for RunRetranslation.
+	// It is nil when the run did not fully succeed or copied subtitles through.
+	Review *Review
 }
 
 func translationStatusFromRecovery(status string) TranslationStatus {
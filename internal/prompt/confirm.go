@@ -35,8 +35,24 @@ func (c Confirmer) ConfirmOverwrite(path string, force bool) (bool, error) {
 	if c.IsInteractive == nil || !c.IsInteractive() {
 		return false, fmt.Errorf("non-interactive stdin: use -y to overwrite existing output")
 	}
+	return c.ask(fmt.Sprintf("Warning: Output file %s already exists. Overwrite? (y/n): ", path))
+}
+
+// ConfirmLargeRun asks whether to go ahead with a run that exceeds a size or
+// cost threshold; reason says which.
+func (c Confirmer) ConfirmLargeRun(reason string, force bool) (bool, error) {
+	if force {
+		return true, nil
+	}
+	if c.IsInteractive == nil || !c.IsInteractive() {
+		return false, fmt.Errorf("non-interactive stdin: use -y to proceed")
+	}
+	return c.ask(fmt.Sprintf("Warning: %s. Continue? (y/n): ", reason))
+}
+
+func (c Confirmer) ask(question string) (bool, error) {
 	if c.Out != nil {
-		fmt.Fprintf(c.Out, "Warning: Output file %s already exists. Overwrite? (y/n): ", path)
+		fmt.Fprint(c.Out, question)
 	}
 	reader := bufio.NewReader(c.In)
 	response, err := reader.ReadString('\n')
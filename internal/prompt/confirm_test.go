@@ -63,3 +63,39 @@ func TestConfirmOverwrite_Interactive(t *testing.T) {
 		}
 	})
 }
+
+func TestConfirmLargeRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		force       bool
+		want        bool
+		wantErr     bool
+	}{
+		{name: "non-interactive", input: "y\n", wantErr: true},
+		{name: "non-interactive forced", input: "n\n", force: true, want: true},
+		{name: "interactive yes", input: "y\n", interactive: true, want: true},
+		{name: "interactive no", input: "n\n", interactive: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			c := Confirmer{
+				In:            bytes.NewBufferString(tt.input),
+				Out:           &out,
+				IsInteractive: func() bool { return tt.interactive },
+			}
+			ok, err := c.ConfirmLargeRun("12000 segments exceed the limit of 5000", tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.want {
+				t.Fatalf("ok = %v, want %v", ok, tt.want)
+			}
+			if tt.interactive && !bytes.Contains(out.Bytes(), []byte("12000 segments")) {
+				t.Fatalf("prompt %q does not mention the reason", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package recovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+const chunkCacheVersion = 1
+
+// FileChunkCache stores completed chunk translations as JSON files in a directory.
+// It implements translator.ChunkCache. Entries are namespaced (e.g. by model) so
+// translations from different settings never collide.
+type FileChunkCache struct {
+	dir       string
+	namespace string
+}
+
+type chunkCacheEntry struct {
+	Version  int             `json:"version"`
+	Segments []cachedSegment `json:"segments"`
+}
+
+type cachedSegment struct {
+	ID           int               `json:"id"`
+	StartTime    string            `json:"start_time"`
+	EndTime      string            `json:"end_time"`
+	Lines        []string          `json:"lines"`
+	Forced       bool              `json:"forced,omitempty"`
+	CueSettings  string            `json:"cue_settings,omitempty"`
+	OverrideTags []srt.OverrideTag `json:"override_tags,omitempty"`
+	Notes        []string          `json:"notes,omitempty"`
+}
+
+// ChunkCacheDir returns the chunk cache directory for an output path:
+// [dir]/[basename]_chunk_cache next to the output and its recovery log.
+func ChunkCacheDir(outputPath string) string {
+	dir := filepath.Dir(outputPath)
+	base := strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
+	return filepath.Join(dir, fmt.Sprintf("%s_chunk_cache", base))
+}
+
+// NewFileChunkCache creates (if needed) the cache directory and returns a cache for it.
+func NewFileChunkCache(dir, namespace string) (*FileChunkCache, error) {
+	if err := files.RejectSymlinkPath(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create chunk cache directory: %w", err)
+	}
+	return &FileChunkCache{dir: dir, namespace: namespace}, nil
+}
+
+// Dir returns the cache directory.
+func (c *FileChunkCache) Dir() string {
+	return c.dir
+}
+
+func (c *FileChunkCache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(c.namespace + "\n" + key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Load returns the cached segments for key, if present and readable.
+func (c *FileChunkCache) Load(key string) ([]srt.Segment, bool) {
+	data, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry chunkCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Version != chunkCacheVersion {
+		return nil, false
+	}
+	segments := make([]srt.Segment, len(entry.Segments))
+	for i, s := range entry.Segments {
+		segments[i] = srt.Segment{ID: s.ID, StartTime: s.StartTime, EndTime: s.EndTime, Lines: s.Lines, Forced: s.Forced, CueSettings: s.CueSettings, OverrideTags: s.OverrideTags, Notes: s.Notes}
+	}
+	return segments, true
+}
+
+// Store writes the translated segments for key atomically.
+func (c *FileChunkCache) Store(key string, segments []srt.Segment) error {
+	entry := chunkCacheEntry{Version: chunkCacheVersion, Segments: make([]cachedSegment, len(segments))}
+	for i, s := range segments {
+		entry.Segments[i] = cachedSegment{ID: s.ID, StartTime: s.StartTime, EndTime: s.EndTime, Lines: s.Lines, Forced: s.Forced, CueSettings: s.CueSettings, OverrideTags: s.OverrideTags, Notes: s.Notes}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return files.AtomicWrite(c.entryPath(key), data, 0600)
+}
+
+// Remove deletes the cache directory and all entries.
+func (c *FileChunkCache) Remove() error {
+	return os.RemoveAll(c.dir)
+}
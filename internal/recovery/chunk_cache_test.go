@@ -0,0 +1,67 @@
+package recovery
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestChunkCacheDir(t *testing.T) {
+	got := ChunkCacheDir(filepath.Join("dir", "movie.ko.srt"))
+	want := filepath.Join("dir", "movie.ko_chunk_cache")
+	if got != want {
+		t.Fatalf("ChunkCacheDir() = %q, want %q", got, want)
+	}
+}
+
+func TestFileChunkCache_RoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out_chunk_cache")
+	cache, err := NewFileChunkCache(dir, "model-a")
+	if err != nil {
+		t.Fatalf("NewFileChunkCache failed: %v", err)
+	}
+	segments := []srt.Segment{{ID: 3, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"안녕", "세상"}}}
+	if _, ok := cache.Load("k1"); ok {
+		t.Fatalf("expected miss before store")
+	}
+	if err := cache.Store("k1", segments); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	got, ok := cache.Load("k1")
+	if !ok || !reflect.DeepEqual(got, segments) {
+		t.Fatalf("Load() = (%+v, %v), want (%+v, true)", got, ok, segments)
+	}
+
+	other, err := NewFileChunkCache(dir, "model-b")
+	if err != nil {
+		t.Fatalf("NewFileChunkCache failed: %v", err)
+	}
+	if _, ok := other.Load("k1"); ok {
+		t.Fatalf("expected namespaces to isolate entries")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			t.Fatalf("Info failed: %v", err)
+		}
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+			t.Fatalf("expected owner-only permissions, got %v", info.Mode().Perm())
+		}
+	}
+
+	if err := cache.Remove(); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected cache dir removed, got %v", err)
+	}
+}
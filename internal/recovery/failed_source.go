@@ -0,0 +1,49 @@
+package recovery
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/files"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// FailedSourcePath returns the companion file for a session log's failed
+// chunk source text, e.g. "out_recovery.failed.txt" for "out_recovery.json".
+func FailedSourcePath(logPath string) string {
+	return strings.TrimSuffix(logPath, filepath.Ext(logPath)) + ".failed.txt"
+}
+
+// WriteFailedSource writes the source text of the failed chunks of work, the
+// segments the run split into chunks at spans (see Translator.ChunkSpans), to
+// path for inspection or manual translation. Each chunk starts with a "# Chunk N"
+// header; each segment is its ID followed by its lines, and segments are
+// separated by blank lines. The file holds dialogue, so it is written with
+// the session log's permissions.
+func WriteFailedSource(path string, work []srt.Segment, spans []chunker.Span, failed []int) error {
+	var b strings.Builder
+	for _, chunk := range failed {
+		if chunk < 0 || chunk >= len(spans) {
+			continue
+		}
+		start := spans[chunk].Offset
+		end := start + spans[chunk].Len
+		if start >= len(work) || end > len(work) {
+			return fmt.Errorf("chunk %d span %d-%d is outside the %d segments", chunk, start, end, len(work))
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# Chunk %d\n", chunk)
+		for _, seg := range work[start:end] {
+			fmt.Fprintf(&b, "\n%d\n", seg.ID)
+			for _, line := range seg.Lines {
+				b.WriteString(line)
+				b.WriteString("\n")
+			}
+		}
+	}
+	return files.AtomicWrite(path, []byte(b.String()), 0600)
+}
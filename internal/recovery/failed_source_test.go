@@ -0,0 +1,36 @@
+package recovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestWriteFailedSource_OnlyFailedChunks(t *testing.T) {
+	work := []srt.Segment{
+		{ID: 1, Lines: []string{"first"}},
+		{ID: 2, Lines: []string{"second"}},
+		{ID: 3, Lines: []string{"third", "line two"}},
+		{ID: 4, Lines: []string{"fourth"}},
+		{ID: 5, Lines: []string{"fifth"}},
+	}
+	path := filepath.Join(t.TempDir(), "out_recovery.failed.txt")
+	spans := []chunker.Span{{Offset: 0, Len: 1}, {Offset: 1, Len: 3}, {Offset: 4, Len: 1}}
+	if err := WriteFailedSource(path, work, spans, []int{1, 2}); err != nil {
+		t.Fatalf("WriteFailedSource: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read dump: %v", err)
+	}
+	want := "# Chunk 1\n\n2\nsecond\n\n3\nthird\nline two\n\n4\nfourth\n\n# Chunk 2\n\n5\nfifth\n"
+	if string(data) != want {
+		t.Fatalf("dump = %q, want %q", data, want)
+	}
+	if got := FailedSourcePath("/tmp/out_recovery.json"); got != "/tmp/out_recovery.failed.txt" {
+		t.Fatalf("FailedSourcePath = %q", got)
+	}
+}
@@ -8,22 +8,30 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oukeidos/focst/internal/files"
 	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/oukeidos/focst/internal/translator"
 )
 
 // SessionLog stores the state of a translation session for later repair.
 type SessionLog struct {
-	LogVersion        int    `json:"log_version"`
-	InputPath         string `json:"input_path"`
-	OutputPath        string `json:"output_path"`
-	InputHash         string `json:"input_hash"`
-	SegmentsChecksum  string `json:"segments_checksum"`
-	Model             string `json:"model"`
-	NamesPath         string `json:"names_path,omitempty"`
+	LogVersion       int    `json:"log_version"`
+	InputPath        string `json:"input_path"`
+	OutputPath       string `json:"output_path"`
+	InputHash        string `json:"input_hash"`
+	SegmentsChecksum string `json:"segments_checksum"`
+	Model            string `json:"model"`
+	NamesPath        string `json:"names_path,omitempty"`
+	// BackgroundPath is the background information file of the original run,
+	// and BackgroundHash the hash of the text it sent, so repair sends the same.
+	BackgroundPath    string `json:"background_path,omitempty"`
+	BackgroundHash    string `json:"background_hash,omitempty"`
 	ChunkSize         int    `json:"chunk_size"`
 	ContextSize       int    `json:"context_size"`
 	Concurrency       int    `json:"concurrency"`
@@ -38,16 +46,136 @@ type SessionLog struct {
 	TotalChunks       int    `json:"total_chunks"`
 	Status            string `json:"status"` // "Success", "Partial Success", "Failure"
 	StatusReason      string `json:"status_reason,omitempty"`
+	// FilterRegex and ForcedOnly record the segment selection of the original run;
+	// chunk indices then refer to the selected subset (see srt.SelectSegments).
+	FilterRegex string `json:"filter_regex,omitempty"`
+	ForcedOnly  bool   `json:"forced_only,omitempty"`
+	// ExcludeIDs records the segment IDs the original run kept verbatim
+	// (see srt.ParseIDRanges); they are left out of the selected subset.
+	ExcludeIDs string `json:"exclude_ids,omitempty"`
+	// ChunkCacheDir is the relative directory holding completed chunk translations
+	// (see FileChunkCache); repair reuses and extends it.
+	ChunkCacheDir string `json:"chunk_cache_dir,omitempty"`
+	// NoTimingCorrection keeps source timing during post-processing.
+	NoTimingCorrection bool `json:"no_timing_correction,omitempty"`
+	// NormalizeQuotes converts straight quotes during post-processing.
+	NormalizeQuotes bool `json:"normalize_quotes,omitempty"`
+	// OnEmptied is the policy for segments emptied by punctuation cleanup.
+	OnEmptied string `json:"on_emptied,omitempty"`
+	// PostprocessLang is the language whose punctuation rules post-processing
+	// ran, when it differs from the target.
+	PostprocessLang string `json:"postprocess_lang,omitempty"`
+	// EmbedMetadata writes a provenance comment block into the repaired output.
+	EmbedMetadata bool `json:"embed_metadata,omitempty"`
+	// KeepCueSettings writes source WebVTT cue settings into the repaired output.
+	KeepCueSettings bool `json:"keep_cue_settings,omitempty"`
+	// CompatProfile is the player compatibility profile of the output.
+	CompatProfile string `json:"compat_profile,omitempty"`
+	// KeepDialogueDashes re-applies source dialogue dashes to repaired chunks.
+	KeepDialogueDashes bool `json:"keep_dialogue_dashes,omitempty"`
+	// OnEmpty is the empty translation policy used for repaired chunks.
+	OnEmpty string `json:"on_empty,omitempty"`
+	// Formality is the form of address ("formal" or "informal") the original
+	// run asked for; empty means auto.
+	Formality string `json:"formality,omitempty"`
+	// NarrativeTag selects the segments the original run translated as
+	// on-screen text (see srt.NarrativeTag).
+	NarrativeTag string `json:"narrative_tag,omitempty"`
+	// CPLMetric is how the original run measured line length ("graphemes" or
+	// "width"); empty means graphemes.
+	CPLMetric string `json:"cpl_metric,omitempty"`
+	// RetryOnLongLines makes repair retry responses with over-long lines, as
+	// the original run did.
+	RetryOnLongLines bool `json:"retry_on_long_lines,omitempty"`
+	// CPLTolerance is the multiple of the target CPL the original run accepted
+	// with RetryOnLongLines; zero means translator.DefaultCPLTolerance.
+	CPLTolerance float64 `json:"cpl_tolerance,omitempty"`
+	// TranslateNotes translates the WebVTT NOTE blocks of repaired segments.
+	TranslateNotes bool `json:"translate_notes,omitempty"`
+	// SingleLine limits repaired segments to one line.
+	SingleLine bool `json:"single_line,omitempty"`
+	// AutoLinebreak splits over-long one-line repaired segments in two.
+	AutoLinebreak bool `json:"auto_linebreak,omitempty"`
+	// DedupRepeats translates repeated source lines once in repaired chunks.
+	DedupRepeats bool `json:"dedup_repeats,omitempty"`
+	// ImproveDrafts sends existing target-language lines as drafts in
+	// repaired chunks.
+	ImproveDrafts bool `json:"improve_drafts,omitempty"`
+	// PolishModel is the model of the original run's polish pass. Repair
+	// translates failed chunks with Model only.
+	PolishModel string `json:"polish_model,omitempty"`
+	// SkipNonTranslatable records that numbers, URLs, and codes were excluded from
+	// the selection (see srt.ExcludeNonTranslatable), shifting chunk indices.
+	SkipNonTranslatable bool `json:"skip_non_translatable,omitempty"`
+	// InputFormat and OutputFormat record formats forced regardless of file
+	// extension (see srt.LoadOptions); empty means inferred from the extension.
+	InputFormat  string `json:"input_format,omitempty"`
+	OutputFormat string `json:"output_format,omitempty"`
+	// InputEncoding records a text encoding forced for the input; empty
+	// means detected.
+	InputEncoding string `json:"input_encoding,omitempty"`
+	// NoBracketRemoval, NoAngleStrip, and NoMeaninglessFilter record the
+	// Japanese preprocessing steps the original run skipped.
+	NoBracketRemoval    bool `json:"no_bracket_removal,omitempty"`
+	NoAngleStrip        bool `json:"no_angle_strip,omitempty"`
+	NoMeaninglessFilter bool `json:"no_meaningless_filter,omitempty"`
+	// Terms holds short phrase choices from the chunks that succeeded (log
+	// version 5). Repair adds them to the prompt so repaired chunks match
+	// their neighbors.
+	Terms []translator.TermEntry `json:"terms,omitempty"`
+	// CreatedAt is when the run that wrote the log finished (log version 6).
+	// Repair uses it to refuse stale logs (see SessionLog.Age).
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// PlanHash fingerprints the translation plan of the run (model, languages,
+	// chunking, prompt and validation settings, names mapping, background,
+	// term memory, and translation memory drafts; log version 7). Repair warns
+	// when the names or background it loads make the plan hash differently.
+	PlanHash string `json:"plan_hash,omitempty"`
+	// TermsHash and DraftsHash identify the term memory entries and the
+	// translation memory drafts the run sent, so repair can rebuild PlanHash
+	// without them.
+	TermsHash  string `json:"terms_hash,omitempty"`
+	DraftsHash string `json:"drafts_hash,omitempty"`
 }
 
-const CurrentLogVersion = 4
+const CurrentLogVersion = 7
+
+// MinLogVersion is the oldest log version repair accepts. Version 4 logs
+// lack Terms, version 4 and 5 logs lack CreatedAt, and logs before version 7
+// lack PlanHash.
+const MinLogVersion = 4
+
+// PreprocessOptions returns the preprocessing steps the original run used,
+// so repair reproduces its segments.
+func (log *SessionLog) PreprocessOptions() srt.PreprocessOptions {
+	return srt.PreprocessOptions{
+		NoLangRules:         log.NoLangPreprocess,
+		NoBracketRemoval:    log.NoBracketRemoval,
+		NoAngleStrip:        log.NoAngleStrip,
+		NoMeaninglessFilter: log.NoMeaninglessFilter,
+	}
+}
+
+// Age returns how old the log at path is at now: since CreatedAt, or since
+// the file's modification time for logs written before version 6.
+func (log *SessionLog) Age(path string, now time.Time) (time.Duration, error) {
+	created := log.CreatedAt
+	if created.IsZero() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		created = info.ModTime()
+	}
+	return now.Sub(created), nil
+}
 
 // Validate checks if the session log is consistent and safe to resume.
 func (log *SessionLog) Validate() error {
 	if log.LogVersion == 0 {
 		log.LogVersion = CurrentLogVersion
 	}
-	if log.LogVersion != CurrentLogVersion {
+	if log.LogVersion < MinLogVersion || log.LogVersion > CurrentLogVersion {
 		return fmt.Errorf("unsupported log_version: %d", log.LogVersion)
 	}
 	if log.InputPath == "" {
@@ -97,7 +225,9 @@ func (log *SessionLog) Validate() error {
 	if log.TotalChunks <= 0 {
 		return fmt.Errorf("invalid total_chunks: %d", log.TotalChunks)
 	}
-	if len(log.FailedChunks) == 0 {
+	// Success logs are kept only on request (--keep-log) and list no failures;
+	// repair rejects them separately.
+	if len(log.FailedChunks) == 0 && log.Status != "Success" {
 		return fmt.Errorf("failed_chunks list is empty")
 	}
 	for _, idx := range log.FailedChunks {
@@ -117,12 +247,64 @@ func (log *SessionLog) Validate() error {
 	if log.Status == "" {
 		return fmt.Errorf("session status is empty")
 	}
-	if log.StatusReason != "" && log.StatusReason != "canceled" {
+	if log.StatusReason != "" && log.StatusReason != "canceled" && log.StatusReason != "cost_cap" {
 		return fmt.Errorf("invalid status_reason: %s", log.StatusReason)
 	}
+	if log.ChunkCacheDir != "" {
+		if filepath.IsAbs(log.ChunkCacheDir) {
+			return fmt.Errorf("chunk_cache_dir must be relative, not absolute: %s", log.ChunkCacheDir)
+		}
+		if strings.HasPrefix(filepath.Clean(log.ChunkCacheDir), "..") {
+			return fmt.Errorf("chunk_cache_dir cannot traverse parent directories: %s", log.ChunkCacheDir)
+		}
+	}
+	if log.FilterRegex != "" {
+		if _, err := regexp.Compile(log.FilterRegex); err != nil {
+			return fmt.Errorf("invalid filter_regex: %v", err)
+		}
+	}
+	if log.ExcludeIDs != "" {
+		if _, err := srt.ParseIDRanges(log.ExcludeIDs); err != nil {
+			return fmt.Errorf("invalid exclude_ids: %v", err)
+		}
+	}
+	if _, err := srt.ParseCompatProfile(log.CompatProfile); err != nil {
+		return fmt.Errorf("invalid compat_profile: %v", err)
+	}
+	if log.PostprocessLang != "" && !srt.HasPunctuationCleanup(log.PostprocessLang) {
+		return fmt.Errorf("invalid postprocess_lang: %s", log.PostprocessLang)
+	}
 	return nil
 }
 
+// SelectedSegments applies the session's segment selection and exclusions to
+// the preprocessed segments. It returns nil when the original run translated every segment.
+func (log *SessionLog) SelectedSegments(segments []srt.Segment) ([]int, error) {
+	var selected []int
+	if log.FilterRegex != "" || log.ForcedOnly {
+		var pattern *regexp.Regexp
+		if log.FilterRegex != "" {
+			re, err := regexp.Compile(log.FilterRegex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter_regex: %v", err)
+			}
+			pattern = re
+		}
+		selected = srt.SelectSegments(segments, pattern, log.ForcedOnly)
+	}
+	if log.ExcludeIDs != "" {
+		ranges, err := srt.ParseIDRanges(log.ExcludeIDs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_ids: %v", err)
+		}
+		selected = srt.ExcludeIDs(segments, selected, ranges)
+	}
+	if log.SkipNonTranslatable {
+		selected = srt.ExcludeNonTranslatable(segments, selected)
+	}
+	return selected, nil
+}
+
 // SaveSessionLog saves the session state to a JSON file.
 func SaveSessionLog(path string, log *SessionLog) error {
 	if log.LogVersion == 0 {
@@ -3,9 +3,13 @@ package recovery
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/translator"
 )
 
 func TestSaveSessionLog_Permissions(t *testing.T) {
@@ -76,6 +80,69 @@ func TestSaveSessionLog_Exclusive(t *testing.T) {
 	}
 }
 
+func TestSessionLog_TermsRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "terms_recovery.json")
+	terms := []translator.TermEntry{{Source: "Magic Academy", Target: "마법 학원"}, {Source: "Yes, master", Target: "네, 주인님"}}
+	if err := SaveSessionLog(path, &SessionLog{InputPath: "test.srt", Terms: terms}); err != nil {
+		t.Fatalf("SaveSessionLog failed: %v", err)
+	}
+	loaded, err := LoadSessionLog(path)
+	if err != nil {
+		t.Fatalf("LoadSessionLog failed: %v", err)
+	}
+	if loaded.LogVersion != CurrentLogVersion || !reflect.DeepEqual(loaded.Terms, terms) {
+		t.Fatalf("round trip = version %d, terms %+v; want %d, %+v", loaded.LogVersion, loaded.Terms, CurrentLogVersion, terms)
+	}
+
+	oldPath := filepath.Join(tmpDir, "v4_recovery.json")
+	if err := os.WriteFile(oldPath, []byte(`{"log_version": 4, "input_path": "test.srt"}`), 0600); err != nil {
+		t.Fatalf("write v4 log: %v", err)
+	}
+	old, err := LoadSessionLog(oldPath)
+	if err != nil {
+		t.Fatalf("LoadSessionLog(v4) failed: %v", err)
+	}
+	if old.LogVersion != 4 || old.Terms != nil {
+		t.Fatalf("v4 log = version %d, terms %+v", old.LogVersion, old.Terms)
+	}
+}
+
+func TestSessionLog_Age(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Now()
+
+	path := filepath.Join(tmpDir, "v6_recovery.json")
+	created := now.Add(-48 * time.Hour)
+	if err := SaveSessionLog(path, &SessionLog{InputPath: "test.srt", CreatedAt: created}); err != nil {
+		t.Fatalf("SaveSessionLog failed: %v", err)
+	}
+	loaded, err := LoadSessionLog(path)
+	if err != nil {
+		t.Fatalf("LoadSessionLog failed: %v", err)
+	}
+	// The explicit timestamp wins over the fresh file mtime.
+	if age, err := loaded.Age(path, now); err != nil || age.Round(time.Second) != 48*time.Hour {
+		t.Fatalf("Age = %s, %v; want 48h", age, err)
+	}
+
+	// Logs before version 6 fall back to the file's modification time.
+	oldPath := filepath.Join(tmpDir, "v5_recovery.json")
+	if err := os.WriteFile(oldPath, []byte(`{"log_version": 5, "input_path": "test.srt"}`), 0600); err != nil {
+		t.Fatalf("write v5 log: %v", err)
+	}
+	if err := os.Chtimes(oldPath, now.Add(-72*time.Hour), now.Add(-72*time.Hour)); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	old, err := LoadSessionLog(oldPath)
+	if err != nil {
+		t.Fatalf("LoadSessionLog(v5) failed: %v", err)
+	}
+	if age, err := old.Age(oldPath, now); err != nil || age.Round(time.Second) != 72*time.Hour {
+		t.Fatalf("v5 Age = %s, %v; want 72h", age, err)
+	}
+}
+
 func TestGenerateRecoveryPath(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -153,6 +220,18 @@ func TestSessionLog_Validate_Security(t *testing.T) {
 		}
 	})
 
+	t.Run("Empty FailedChunks only for Success", func(t *testing.T) {
+		log := *validLog
+		log.FailedChunks = nil
+		if err := log.Validate(); err == nil || !strings.Contains(err.Error(), "failed_chunks list is empty") {
+			t.Errorf("expected error for failure log without failed chunks, got: %v", err)
+		}
+		log.Status = "Success"
+		if err := log.Validate(); err != nil {
+			t.Errorf("expected kept success log to pass, got error: %v", err)
+		}
+	})
+
 	t.Run("Empty OutputPath", func(t *testing.T) {
 		log := *validLog
 		log.OutputPath = ""
@@ -187,7 +266,23 @@ func TestSessionLog_Validate_Security(t *testing.T) {
 
 	t.Run("Old log version is rejected", func(t *testing.T) {
 		log := *validLog
-		log.LogVersion = CurrentLogVersion - 1
+		log.LogVersion = MinLogVersion - 1
+		if err := log.Validate(); err == nil || !strings.Contains(err.Error(), "unsupported log_version") {
+			t.Errorf("expected unsupported log_version error, got: %v", err)
+		}
+	})
+
+	t.Run("Previous log version without terms is accepted", func(t *testing.T) {
+		log := *validLog
+		log.LogVersion = MinLogVersion
+		if err := log.Validate(); err != nil {
+			t.Errorf("expected version %d log to validate, got: %v", MinLogVersion, err)
+		}
+	})
+
+	t.Run("Newer log version is rejected", func(t *testing.T) {
+		log := *validLog
+		log.LogVersion = CurrentLogVersion + 1
 		if err := log.Validate(); err == nil || !strings.Contains(err.Error(), "unsupported log_version") {
 			t.Errorf("expected unsupported log_version error, got: %v", err)
 		}
@@ -3,53 +3,105 @@ package recovery
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/oukeidos/focst/internal/chunker"
 	"github.com/oukeidos/focst/internal/srt"
 	"github.com/oukeidos/focst/internal/translator"
 )
 
+// RepairProgress extends a chunk progress event with repair-wide counts.
+// ChunkIndex is the original chunk index from the session log.
+type RepairProgress struct {
+	translator.TranslationProgress
+	Repaired int // chunks repaired so far in this run
+	Targets  int // chunks scheduled for repair in this run
+}
+
+// OutputPolicy says how Repair treats the output the original run saved.
+type OutputPolicy int
+
+const (
+	// OutputReuse keeps the existing output for chunks that passed and fails
+	// when it cannot be reused.
+	OutputReuse OutputPolicy = iota
+	// OutputForce re-translates every chunk when the existing output cannot
+	// be reused.
+	OutputForce
+	// OutputMerge makes the existing output the authoritative base: it must
+	// hold exactly one segment per source segment, and only the failed chunk
+	// ranges are overwritten. Every other range keeps the output's text, even
+	// where the chunk cache holds a different translation. A mismatch is
+	// always an error.
+	OutputMerge
+	// OutputRestoreCache is OutputReuse, except that passed chunks the
+	// original run left in its chunk cache are restored from the cache,
+	// replacing the output's text for them (including any edits).
+	OutputRestoreCache
+)
+
 // Repair function resumes translation for failed chunks.
 // resolvedOutputPath should be the absolute path resolved from the log file location.
-func Repair(ctx context.Context, tr *translator.Translator, log *SessionLog, resolvedOutputPath string, forceRepair bool, onProgress func(translator.TranslationProgress)) ([]srt.Segment, []int, error) {
+func Repair(ctx context.Context, tr *translator.Translator, log *SessionLog, resolvedOutputPath string, policy OutputPolicy, onProgress func(RepairProgress)) ([]srt.Segment, []int, error) {
 	// 1. Load input SRT
-	segments, err := srt.Load(log.InputPath)
+	segments, err := srt.LoadWithOptions(log.InputPath, srt.LoadOptions{Format: log.InputFormat, Encoding: log.InputEncoding, Language: log.SourceLang})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load input subtitles: %w", err)
 	}
 
 	// Preprocess to match the state during the first run
 	if !log.NoPreprocess {
-		segments = srt.PreprocessForPathWithOptions(segments, log.SourceLang, log.InputPath, !log.NoLangPreprocess)
+		segments, _ = srt.PreprocessSteps(segments, log.SourceLang, log.InputPath, log.PreprocessOptions())
+	}
+
+	selected, err := log.SelectedSegments(segments)
+	if err != nil {
+		return nil, nil, err
+	}
+	work := segments
+	if selected != nil {
+		work = make([]srt.Segment, len(selected))
+		for k, idx := range selected {
+			work[k] = segments[idx]
+		}
 	}
 
 	// 2. Load current output SRT (partial success) to preserve previous translations.
 	results := make([]srt.Segment, len(segments))
 	copy(results, segments)
 
-	currentOutput, parseErr := srt.Load(resolvedOutputPath)
-	outputReason := ""
-	if parseErr != nil {
-		outputReason = fmt.Sprintf("output parse failed: %v", parseErr)
-	} else if len(currentOutput) != len(segments) {
-		outputReason = fmt.Sprintf("segment count mismatch: expected %d, got %d", len(segments), len(currentOutput))
-	} else {
+	currentOutput, outputErr := loadOutputBase(resolvedOutputPath, log.OutputFormat, len(segments))
+	if outputErr == nil {
 		copy(results, currentOutput)
+		if policy == OutputRestoreCache {
+			restoreCachedChunks(tr, work, selected, log.ChunkSize, log.FailedChunks, results)
+		}
 	}
 
 	// 3. Translate only failed chunks
 	targetChunks := log.FailedChunks
-	if outputReason != "" {
-		if !forceRepair {
-			return nil, nil, fmt.Errorf("existing output could not be reused (%s). Use --force-repair to ignore existing output and re-translate", outputReason)
+	if outputErr != nil {
+		switch policy {
+		case OutputMerge:
+			return nil, nil, fmt.Errorf("existing output cannot be used as the merge base (%w)", outputErr)
+		case OutputReuse, OutputRestoreCache:
+			return nil, nil, fmt.Errorf("existing output could not be reused (%w). Use --force-repair to ignore existing output and re-translate", outputErr)
 		}
-		totalChunks := (len(segments) + log.ChunkSize - 1) / log.ChunkSize
+		totalChunks := len(chunker.SplitIntoChunks(work, log.ChunkSize, 0))
 		targetChunks = make([]int, totalChunks)
 		for i := 0; i < totalChunks; i++ {
 			targetChunks[i] = i
 		}
 	}
 
-	translated, newFailedChunks, err := tr.TranslateChunks(ctx, segments, targetChunks, onProgress)
+	chunkProgress := repairProgressAdapter(len(targetChunks), onProgress)
+	var translated []srt.Segment
+	var newFailedChunks []int
+	if selected != nil {
+		translated, newFailedChunks, err = tr.TranslateSubset(ctx, segments, selected, targetChunks, chunkProgress)
+	} else {
+		translated, newFailedChunks, err = tr.TranslateChunks(ctx, segments, targetChunks, chunkProgress)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -70,17 +122,84 @@ func Repair(ctx context.Context, tr *translator.Translator, log *SessionLog, res
 		}
 	}
 
-	// Merge newly succeeded segments into our 'results'
+	// Merge newly succeeded segments into our 'results'.
+	// Chunk positions index the selected subset when a segment filter is active.
+	spans := tr.ChunkSpans()
 	for chunkIdx := range newlySucceeded {
-		startIdx := chunkIdx * log.ChunkSize
-		endIdx := startIdx + log.ChunkSize
-		if endIdx > len(segments) {
-			endIdx = len(segments)
+		if chunkIdx < 0 || chunkIdx >= len(spans) {
+			continue
 		}
-		for i := startIdx; i < endIdx; i++ {
-			results[i] = translated[i]
+		span := spans[chunkIdx]
+		for i := span.Offset; i < span.Offset+span.Len; i++ {
+			pos := i
+			if selected != nil {
+				pos = selected[i]
+			}
+			results[pos] = translated[pos]
 		}
 	}
 
 	return results, newFailedChunks, nil
 }
+
+// restoreCachedChunks overwrites the chunks of work that did not fail with
+// their cached translations, where the translator's chunk cache holds them.
+// results is indexed like the full segment list. Notes are kept from results:
+// the cache holds them untranslated.
+func restoreCachedChunks(tr *translator.Translator, work []srt.Segment, selected []int, chunkSize int, failed []int, results []srt.Segment) {
+	spans := chunker.Spans(chunker.SplitIntoChunks(work, chunkSize, 0))
+	skip := make(map[int]bool, len(failed))
+	for _, idx := range failed {
+		skip[idx] = true
+	}
+	var passed []int
+	for i := range spans {
+		if !skip[i] {
+			passed = append(passed, i)
+		}
+	}
+	for chunkIdx, cached := range tr.CachedChunks(work, passed) {
+		span := spans[chunkIdx]
+		for k := 0; k < span.Len && k < len(cached); k++ {
+			pos := span.Offset + k
+			if selected != nil {
+				pos = selected[pos]
+			}
+			seg := cached[k]
+			seg.Notes = results[pos].Notes
+			results[pos] = seg
+		}
+	}
+}
+
+// loadOutputBase loads the existing output and checks that it has one
+// segment per source segment, so chunk ranges line up with the source.
+func loadOutputBase(path, format string, want int) ([]srt.Segment, error) {
+	output, err := srt.LoadWithOptions(path, srt.LoadOptions{Format: format})
+	if err != nil {
+		return nil, fmt.Errorf("output parse failed: %w", err)
+	}
+	if len(output) != want {
+		return nil, fmt.Errorf("segment count mismatch: expected %d, got %d", want, len(output))
+	}
+	return output, nil
+}
+
+// repairProgressAdapter converts per-chunk translator events into RepairProgress
+// with a running repaired count. Events may arrive from concurrent workers.
+func repairProgressAdapter(targets int, onProgress func(RepairProgress)) func(translator.TranslationProgress) {
+	if onProgress == nil {
+		return nil
+	}
+	var mu sync.Mutex
+	repaired := 0
+	return func(p translator.TranslationProgress) {
+		mu.Lock()
+		if p.State == translator.StateCompleted {
+			repaired++
+		}
+		event := RepairProgress{TranslationProgress: p, Repaired: repaired, Targets: targets}
+		onProgress(event)
+		mu.Unlock()
+	}
+}
@@ -3,11 +3,13 @@ package recovery
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/oukeidos/focst/internal/gemini"
 	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
 	"github.com/oukeidos/focst/internal/translator"
 )
 
@@ -72,7 +74,7 @@ func TestRepairToggles(t *testing.T) {
 			ChunkSize:    10,
 		}
 
-		results, _, err := Repair(ctx, tr, log, tmpOut.Name(), true, nil)
+		results, _, err := Repair(ctx, tr, log, tmpOut.Name(), OutputForce, nil)
 		if err != nil {
 			t.Fatalf("Repair failed: %v", err)
 		}
@@ -102,7 +104,7 @@ func TestRepairToggles(t *testing.T) {
 			ChunkSize:    10,
 		}
 
-		results, _, err := Repair(ctx, tr, log, tmpOut.Name(), true, nil)
+		results, _, err := Repair(ctx, tr, log, tmpOut.Name(), OutputForce, nil)
 		if err != nil {
 			t.Fatalf("Repair failed: %v", err)
 		}
@@ -129,9 +131,274 @@ func TestRepairToggles(t *testing.T) {
 			ChunkSize:    10,
 		}
 
-		_, _, err := Repair(ctx, tr, log, tmpOut.Name(), false, nil)
+		_, _, err := Repair(ctx, tr, log, tmpOut.Name(), OutputReuse, nil)
 		if err == nil || !strings.Contains(err.Error(), "existing output could not be reused") {
 			t.Fatalf("expected output reuse error, got: %v", err)
 		}
 	})
 }
+
+func TestRepair_SegmentFilter(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.srt")
+	outPath := filepath.Join(dir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nSIGN Exit\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nBye\n\n" +
+		"4\n00:00:07,000 --> 00:00:08,000\nSIGN Shop\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Existing output: first selected chunk done, second failed (still source).
+	output := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\n출구\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nBye\n\n" +
+		"4\n00:00:07,000 --> 00:00:08,000\nSIGN Shop\n"
+	if err := os.WriteFile(outPath, []byte(output), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := translator.NewTranslator(&mockGemini{}, 1, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	log := &SessionLog{
+		LogVersion:   CurrentLogVersion,
+		InputPath:    inPath,
+		OutputPath:   outPath,
+		NoPreprocess: true,
+		SourceLang:   "en",
+		TargetLang:   "ko",
+		FailedChunks: []int{1},
+		TotalChunks:  2,
+		ChunkSize:    1,
+		FilterRegex:  "^SIGN",
+	}
+
+	results, failed, err := Repair(context.Background(), tr, log, outPath, OutputReuse, nil)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("expected no failed chunks, got %v", failed)
+	}
+	want := []string{"Hello", "출구", "Bye", "번역됨: SIGN Shop"}
+	for i, w := range want {
+		if got := results[i].Lines[0]; got != w {
+			t.Fatalf("segment %d = %q, want %q", i+1, got, w)
+		}
+	}
+}
+
+func TestRepair_ProgressPerFailedChunk(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.srt")
+	outPath := filepath.Join(dir, "out.srt")
+	input := "1\n00:00:01,000 --> 00:00:02,000\nOne\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nTwo\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nThree\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(outPath, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := translator.NewTranslator(&mockGemini{}, 1, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	log := &SessionLog{
+		LogVersion:   CurrentLogVersion,
+		InputPath:    inPath,
+		OutputPath:   outPath,
+		NoPreprocess: true,
+		SourceLang:   "en",
+		TargetLang:   "ko",
+		FailedChunks: []int{0, 2},
+		TotalChunks:  3,
+		ChunkSize:    1,
+	}
+
+	var completed []RepairProgress
+	_, failed, err := Repair(context.Background(), tr, log, outPath, OutputReuse, func(p RepairProgress) {
+		if p.State == translator.StateCompleted {
+			completed = append(completed, p)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("expected no failed chunks, got %v", failed)
+	}
+	if len(completed) != 2 {
+		t.Fatalf("expected 2 completed events, got %d", len(completed))
+	}
+	wantChunks := []int{0, 2}
+	for i, p := range completed {
+		if p.ChunkIndex != wantChunks[i] {
+			t.Fatalf("event %d chunk = %d, want %d", i, p.ChunkIndex, wantChunks[i])
+		}
+		if p.Repaired != i+1 || p.Targets != 2 {
+			t.Fatalf("event %d = %d of %d, want %d of 2", i, p.Repaired, p.Targets, i+1)
+		}
+	}
+}
+
+func TestRepair_MergeOutput(t *testing.T) {
+	input := "1\n00:00:01,000 --> 00:00:02,000\nOne\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nTwo\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nThree\n"
+	tests := []struct {
+		name    string
+		output  string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "matching output is the base",
+			// Chunk 1 failed and still holds source text; the others were
+			// translated (and hand-edited) and must survive unchanged.
+			output: "1\n00:00:01,000 --> 00:00:02,000\n하나 (수정)\n\n" +
+				"2\n00:00:03,000 --> 00:00:04,000\nTwo\n\n" +
+				"3\n00:00:05,000 --> 00:00:06,000\n셋\n",
+			want: []string{"하나 (수정)", "번역됨: Two", "셋"},
+		},
+		{
+			name: "segment count mismatch",
+			output: "1\n00:00:01,000 --> 00:00:02,000\n하나\n\n" +
+				"2\n00:00:03,000 --> 00:00:04,000\nTwo\n",
+			wantErr: "segment count mismatch: expected 3, got 2",
+		},
+		{
+			name:    "missing output",
+			wantErr: "output parse failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			inPath := filepath.Join(dir, "in.srt")
+			outPath := filepath.Join(dir, "out.srt")
+			if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if tt.output != "" {
+				if err := os.WriteFile(outPath, []byte(tt.output), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			src, _ := language.GetLanguage("en")
+			tgt, _ := language.GetLanguage("ko")
+			tr, err := translator.NewTranslator(&mockGemini{}, 1, 0, 1, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			log := &SessionLog{
+				LogVersion:   CurrentLogVersion,
+				InputPath:    inPath,
+				OutputPath:   outPath,
+				NoPreprocess: true,
+				SourceLang:   "en",
+				TargetLang:   "ko",
+				FailedChunks: []int{1},
+				TotalChunks:  3,
+				ChunkSize:    1,
+			}
+
+			results, failed, err := Repair(context.Background(), tr, log, outPath, OutputMerge, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), "merge base") || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected merge base error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || len(failed) != 0 {
+				t.Fatalf("Repair = failed %v, err %v", failed, err)
+			}
+			for i, w := range tt.want {
+				if got := results[i].Lines[0]; got != w {
+					t.Fatalf("segment %d = %q, want %q", i+1, got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestRepair_CachedChunksByPolicy(t *testing.T) {
+	input := "1\n00:00:01,000 --> 00:00:02,000\nOne\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nTwo\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nThree\n"
+	// The output was edited after the run, so it disagrees with the chunk
+	// cache on the chunks that passed.
+	output := "1\n00:00:01,000 --> 00:00:02,000\n하나 (수정)\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nTwo\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\n셋\n"
+	tests := []struct {
+		name   string
+		policy OutputPolicy
+		want   []string
+	}{
+		{name: "reuse keeps the output", policy: OutputReuse, want: []string{"하나 (수정)", "번역됨: Two", "셋"}},
+		{name: "merge keeps the output", policy: OutputMerge, want: []string{"하나 (수정)", "번역됨: Two", "셋"}},
+		{name: "restore takes cached chunks", policy: OutputRestoreCache, want: []string{"번역됨: One", "번역됨: Two", "번역됨: Three"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			inPath := filepath.Join(dir, "in.srt")
+			outPath := filepath.Join(dir, "out.srt")
+			if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(outPath, []byte(output), 0600); err != nil {
+				t.Fatal(err)
+			}
+			cache, err := NewFileChunkCache(filepath.Join(dir, "cache"), "m")
+			if err != nil {
+				t.Fatalf("NewFileChunkCache failed: %v", err)
+			}
+			src, _ := language.GetLanguage("en")
+			tgt, _ := language.GetLanguage("ko")
+			tr, err := translator.NewTranslator(&mockGemini{}, 1, 0, 1, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			tr.SetChunkCache(cache)
+			// Fill the cache for the chunks that passed in the original run.
+			segments, err := srt.Load(inPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := tr.TranslateChunks(context.Background(), segments, []int{0, 2}, nil); err != nil {
+				t.Fatalf("TranslateChunks failed: %v", err)
+			}
+			log := &SessionLog{
+				LogVersion:   CurrentLogVersion,
+				InputPath:    inPath,
+				OutputPath:   outPath,
+				NoPreprocess: true,
+				SourceLang:   "en",
+				TargetLang:   "ko",
+				FailedChunks: []int{1},
+				TotalChunks:  3,
+				ChunkSize:    1,
+			}
+
+			results, failed, err := Repair(context.Background(), tr, log, outPath, tt.policy, nil)
+			if err != nil || len(failed) != 0 {
+				t.Fatalf("Repair = failed %v, err %v", failed, err)
+			}
+			for i, w := range tt.want {
+				if got := results[i].Lines[0]; got != w {
+					t.Fatalf("segment %d = %q, want %q", i+1, got, w)
+				}
+			}
+		})
+	}
+}
@@ -67,3 +67,23 @@ func TestSave_DirectoryError(t *testing.T) {
 		t.Errorf("Expected error for invalid directory path, got nil")
 	}
 }
+
+func TestSaveWithOptions_VerifyRoundTrip(t *testing.T) {
+	segments := []Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"Hello"}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"World"}},
+	}
+	for _, ext := range []string{".srt", ".vtt", ".ass", ".ttml"} {
+		path := filepath.Join(t.TempDir(), "out"+ext)
+		if err := SaveWithOptions(path, segments, SaveOptions{Verify: true}); err != nil {
+			t.Fatalf("verified save (%s) failed: %v", ext, err)
+		}
+	}
+
+	if err := verifyRoundTrip(".srt", []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 2); err == nil {
+		t.Fatalf("expected segment count mismatch to fail verification")
+	}
+	if err := verifyRoundTrip(".vtt", []byte("not a webvtt file"), 1); err == nil {
+		t.Fatalf("expected unparsable output to fail verification")
+	}
+}
@@ -0,0 +1,78 @@
+package srt
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	urlLinePattern  = regexp.MustCompile(`(?i)^(https?://|www\.)\S+$`)
+	codeLinePattern = regexp.MustCompile(`^[A-Z0-9]+(?:[-_./][A-Z0-9]+)*$`)
+)
+
+// IsNonTranslatable reports whether every line of seg is text the model should
+// not translate: a pure number (e.g. "123", "1:23", "2024-01-01"), a URL, or an
+// all-caps product code mixing letters and digits (e.g. "XJ-900").
+// Segments without text are not classified as non-translatable.
+func IsNonTranslatable(seg Segment) bool {
+	found := false
+	for _, line := range seg.Lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !isNumericLine(line) && !urlLinePattern.MatchString(line) && !isCodeLine(line) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// ExcludeNonTranslatable removes non-translatable segments from selected
+// (every segment when selected is nil), keeping ascending order. It returns
+// selected unchanged when nothing is excluded.
+func ExcludeNonTranslatable(segments []Segment, selected []int) []int {
+	var candidates []int
+	if selected == nil {
+		candidates = make([]int, len(segments))
+		for i := range segments {
+			candidates[i] = i
+		}
+	} else {
+		candidates = selected
+	}
+	kept := make([]int, 0, len(candidates))
+	for _, idx := range candidates {
+		if idx < len(segments) && IsNonTranslatable(segments[idx]) {
+			continue
+		}
+		kept = append(kept, idx)
+	}
+	if len(kept) == len(candidates) {
+		return selected
+	}
+	return kept
+}
+
+func isNumericLine(line string) bool {
+	digits := false
+	for _, r := range line {
+		switch {
+		case unicode.IsDigit(r):
+			digits = true
+		case strings.ContainsRune(" .,:;/-+%#()", r):
+		default:
+			return false
+		}
+	}
+	return digits
+}
+
+func isCodeLine(line string) bool {
+	if !codeLinePattern.MatchString(line) {
+		return false
+	}
+	return strings.IndexFunc(line, unicode.IsDigit) >= 0 && strings.IndexFunc(line, unicode.IsLetter) >= 0
+}
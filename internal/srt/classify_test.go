@@ -0,0 +1,56 @@
+package srt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsNonTranslatable(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  bool
+	}{
+		{name: "number", lines: []string{"123"}, want: true},
+		{name: "timecode", lines: []string{"1:23"}, want: true},
+		{name: "date", lines: []string{"2024-01-01"}, want: true},
+		{name: "url", lines: []string{"https://example.com/path?q=1"}, want: true},
+		{name: "www url", lines: []string{"www.example.co.jp"}, want: true},
+		{name: "product code", lines: []string{"XJ-900"}, want: true},
+		{name: "url and number lines", lines: []string{"https://example.com", "42"}, want: true},
+		{name: "sentence", lines: []string{"こんにちは"}, want: false},
+		{name: "sentence with number", lines: []string{"123 people came"}, want: false},
+		{name: "sentence with url", lines: []string{"Visit https://example.com"}, want: false},
+		{name: "all-caps word", lines: []string{"HELP"}, want: false},
+		{name: "mixed lines", lines: []string{"123", "はい"}, want: false},
+		{name: "empty", lines: nil, want: false},
+		{name: "symbols only", lines: []string{"♪"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNonTranslatable(Segment{Lines: tt.lines}); got != tt.want {
+				t.Fatalf("IsNonTranslatable(%q) = %v, want %v", tt.lines, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeNonTranslatable(t *testing.T) {
+	segments := []Segment{
+		{Lines: []string{"はい"}},
+		{Lines: []string{"123"}},
+		{Lines: []string{"https://example.com"}},
+		{Lines: []string{"いいえ"}},
+	}
+
+	if got := ExcludeNonTranslatable(segments, nil); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Fatalf("ExcludeNonTranslatable(all) = %v, want [0 3]", got)
+	}
+	if got := ExcludeNonTranslatable(segments, []int{1, 3}); !reflect.DeepEqual(got, []int{3}) {
+		t.Fatalf("ExcludeNonTranslatable([1 3]) = %v, want [3]", got)
+	}
+	if got := ExcludeNonTranslatable(segments[:1], nil); got != nil {
+		t.Fatalf("expected nil when nothing is excluded, got %v", got)
+	}
+}
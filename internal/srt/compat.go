@@ -0,0 +1,80 @@
+package srt
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// CompatProfile selects byte-level formatting quirks applied to SRT and
+// WebVTT output after serialization, for players that are strict about a
+// layout the serializer normalizes. Other formats are written unchanged.
+type CompatProfile string
+
+const (
+	// CompatNone keeps the serializer's output: LF line endings, a UTF-8 BOM
+	// on SRT, and no blank line after the last cue.
+	CompatNone CompatProfile = ""
+	// CompatWindows uses CRLF line endings.
+	CompatWindows CompatProfile = "windows"
+	// CompatLegacy suits older hardware players and TVs: no BOM, CRLF line
+	// endings, and a blank line after the last cue, which some of them need
+	// to show it.
+	CompatLegacy CompatProfile = "legacy"
+)
+
+// CompatProfileNamesLabel lists the names accepted by ParseCompatProfile.
+const CompatProfileNamesLabel = "windows, legacy"
+
+// quirk rewrites serialized output. Quirks see LF line endings unless an
+// earlier quirk in the same profile converted them.
+type quirk func([]byte) []byte
+
+// compatQuirks maps each profile and output extension to its quirks, in the
+// order they are applied.
+var compatQuirks = map[CompatProfile]map[string][]quirk{
+	CompatWindows: {
+		".srt": {crlfLineEndings},
+		".vtt": {crlfLineEndings},
+	},
+	CompatLegacy: {
+		".srt": {stripBOM, trailingBlankLine, crlfLineEndings},
+		".vtt": {stripBOM, trailingBlankLine, crlfLineEndings},
+	},
+}
+
+// ParseCompatProfile validates a profile name. An empty string selects
+// CompatNone.
+func ParseCompatProfile(s string) (CompatProfile, error) {
+	p := CompatProfile(s)
+	if p == CompatNone {
+		return CompatNone, nil
+	}
+	if _, ok := compatQuirks[p]; !ok {
+		return "", fmt.Errorf("invalid compatibility profile %q (want one of: %s)", s, CompatProfileNamesLabel)
+	}
+	return p, nil
+}
+
+// applyCompat runs the quirks of profile for the output extension ext.
+func applyCompat(data []byte, ext string, profile CompatProfile) []byte {
+	for _, q := range compatQuirks[profile][ext] {
+		data = q(data)
+	}
+	return data
+}
+
+var utf8BOM = []byte("\ufeff")
+
+func stripBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, utf8BOM)
+}
+
+// trailingBlankLine ends the output with exactly one blank line.
+func trailingBlankLine(data []byte) []byte {
+	return append(bytes.TrimRight(data, "\n"), "\n\n"...)
+}
+
+func crlfLineEndings(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+}
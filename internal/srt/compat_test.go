@@ -0,0 +1,91 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveWithOptions_CompatProfile(t *testing.T) {
+	segments := []Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"a", "b"}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"c"}},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		profile CompatProfile
+		want    string
+	}{
+		{
+			name: "srt default",
+			file: "out.srt",
+			want: "\ufeff1\n00:00:01,000 --> 00:00:02,000\na\nb\n\n2\n00:00:03,000 --> 00:00:04,000\nc\n",
+		},
+		{
+			name:    "srt windows",
+			file:    "out.srt",
+			profile: CompatWindows,
+			want:    "\ufeff1\r\n00:00:01,000 --> 00:00:02,000\r\na\r\nb\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nc\r\n",
+		},
+		{
+			name:    "srt legacy",
+			file:    "out.srt",
+			profile: CompatLegacy,
+			want:    "1\r\n00:00:01,000 --> 00:00:02,000\r\na\r\nb\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nc\r\n\r\n",
+		},
+		{
+			name:    "vtt legacy",
+			file:    "out.vtt",
+			profile: CompatLegacy,
+			want:    "WEBVTT\r\n\r\n1\r\n00:00:01.000 --> 00:00:02.000\r\na\r\nb\r\n\r\n2\r\n00:00:03.000 --> 00:00:04.000\r\nc\r\n\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := SaveWithOptions(path, segments, SaveOptions{Compat: tt.profile, Verify: true}); err != nil {
+				t.Fatalf("SaveWithOptions: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("output = %q\nwant   %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveWithOptions_CompatProfileLeavesOtherFormats(t *testing.T) {
+	segments := []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"a"}}}
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.ass")
+	legacy := filepath.Join(dir, "legacy.ass")
+	if err := Save(plain, segments); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := SaveWithOptions(legacy, segments, SaveOptions{Compat: CompatLegacy}); err != nil {
+		t.Fatalf("SaveWithOptions: %v", err)
+	}
+	want, _ := os.ReadFile(plain)
+	got, _ := os.ReadFile(legacy)
+	if string(got) != string(want) {
+		t.Fatalf("ASS output changed by the compat profile:\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestParseCompatProfile(t *testing.T) {
+	for _, name := range []string{"", "windows", "legacy"} {
+		if _, err := ParseCompatProfile(name); err != nil {
+			t.Errorf("ParseCompatProfile(%q): %v", name, err)
+		}
+	}
+	if _, err := ParseCompatProfile("roku"); err == nil {
+		t.Error("ParseCompatProfile accepted an unknown profile")
+	}
+	if err := SaveWithOptions(filepath.Join(t.TempDir(), "out.srt"), nil, SaveOptions{Compat: "roku"}); err == nil {
+		t.Error("SaveWithOptions accepted an unknown profile")
+	}
+}
@@ -0,0 +1,68 @@
+package srt
+
+import (
+	"strings"
+
+	"github.com/asticode/go-astisub"
+)
+
+// vttCueSettings renders the cue settings astisub parsed for a WebVTT item in
+// the order its writer emits them. Regions are not carried over because they
+// reference a header block that Save does not reproduce.
+func vttCueSettings(item *astisub.Item) string {
+	if item == nil || item.InlineStyle == nil {
+		return ""
+	}
+	style := item.InlineStyle
+	var settings []string
+	if style.WebVTTAlign != "" {
+		settings = append(settings, "align:"+style.WebVTTAlign)
+	}
+	if style.WebVTTLine != "" {
+		settings = append(settings, "line:"+style.WebVTTLine)
+	}
+	if style.WebVTTPosition != nil {
+		settings = append(settings, "position:"+style.WebVTTPosition.String())
+	}
+	if style.WebVTTSize != "" {
+		settings = append(settings, "size:"+style.WebVTTSize)
+	}
+	if style.WebVTTVertical != "" {
+		settings = append(settings, "vertical:"+style.WebVTTVertical)
+	}
+	return strings.Join(settings, " ")
+}
+
+// applyCueSettings copies each segment's CueSettings onto the matching item so
+// the WebVTT writer emits them on the timing line. Unknown keys are dropped.
+func applyCueSettings(subs *astisub.Subtitles, segments []Segment) {
+	for i, seg := range segments {
+		if i >= len(subs.Items) || seg.CueSettings == "" {
+			continue
+		}
+		style := &astisub.StyleAttributes{}
+		for _, setting := range strings.Fields(seg.CueSettings) {
+			key, value, ok := strings.Cut(setting, ":")
+			if !ok || value == "" {
+				continue
+			}
+			switch key {
+			case "align":
+				style.WebVTTAlign = value
+			case "line":
+				style.WebVTTLine = value
+			case "position":
+				position := &astisub.WebVTTPosition{XPosition: value}
+				if x, align, found := strings.Cut(value, ","); found {
+					position = &astisub.WebVTTPosition{XPosition: x, Alignment: align}
+				}
+				style.WebVTTPosition = position
+			case "size":
+				style.WebVTTSize = value
+			case "vertical":
+				style.WebVTTVertical = value
+			}
+		}
+		subs.Items[i].InlineStyle = style
+	}
+}
@@ -0,0 +1,87 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCueSettings_VTTRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.vtt")
+	input := "WEBVTT\n\n" +
+		"1\n00:00:01.000 --> 00:00:02.000 align:start line:0 position:50%\nHello\n\n" +
+		"2\n00:00:03.000 --> 00:00:04.000\nWorld\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	segments, err := Load(inPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := segments[0].CueSettings; got != "align:start line:0 position:50%" {
+		t.Fatalf("CueSettings = %q", got)
+	}
+	if segments[1].CueSettings != "" {
+		t.Fatalf("unexpected settings on plain cue: %q", segments[1].CueSettings)
+	}
+
+	// Translated text keeps the source cue's placement.
+	segments[0].Lines = []string{"안녕하세요"}
+	segments[1].Lines = []string{"세계"}
+
+	tests := []struct {
+		name string
+		opts SaveOptions
+		want bool
+	}{
+		{name: "preserved", opts: SaveOptions{CueSettings: true, Verify: true}, want: true},
+		{name: "dropped_by_default", opts: SaveOptions{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(dir, tt.name+".vtt")
+			if err := SaveWithOptions(outPath, segments, tt.opts); err != nil {
+				t.Fatalf("SaveWithOptions failed: %v", err)
+			}
+			data, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			content := string(data)
+			timing := "00:00:01.000 --> 00:00:02.000 align:start line:0 position:50%\n안녕하세요"
+			if strings.Contains(content, timing) != tt.want {
+				t.Fatalf("cue settings present = %v, want %v:\n%s", !tt.want, tt.want, content)
+			}
+
+			reloaded, err := Load(outPath)
+			if err != nil {
+				t.Fatalf("reloading output failed: %v", err)
+			}
+			wantSettings := ""
+			if tt.want {
+				wantSettings = "align:start line:0 position:50%"
+			}
+			if reloaded[0].CueSettings != wantSettings || reloaded[1].CueSettings != "" {
+				t.Fatalf("reloaded settings = %q, %q", reloaded[0].CueSettings, reloaded[1].CueSettings)
+			}
+		})
+	}
+}
+
+func TestCueSettings_IgnoredForOtherFormats(t *testing.T) {
+	segments := []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"Hi"}, CueSettings: "position:50%"}}
+	path := filepath.Join(t.TempDir(), "out.srt")
+	if err := SaveWithOptions(path, segments, SaveOptions{CueSettings: true}); err != nil {
+		t.Fatalf("SaveWithOptions failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if strings.Contains(string(data), "position") {
+		t.Fatalf("SRT output should not carry cue settings:\n%s", data)
+	}
+}
@@ -0,0 +1,192 @@
+package srt
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	xunicode "golang.org/x/text/encoding/unicode"
+)
+
+// EncodingNamesLabel lists the canonical names accepted by ParseEncoding.
+const EncodingNamesLabel = "utf-8, utf-16le, utf-16be, shift-jis, euc-jp, cp949, gb18030, big5, windows-1252"
+
+// textEncodings maps each canonical encoding name to its decoder.
+var textEncodings = map[string]encoding.Encoding{
+	"utf-8":        xunicode.UTF8,
+	"utf-16le":     xunicode.UTF16(xunicode.LittleEndian, xunicode.UseBOM),
+	"utf-16be":     xunicode.UTF16(xunicode.BigEndian, xunicode.UseBOM),
+	"shift-jis":    japanese.ShiftJIS,
+	"euc-jp":       japanese.EUCJP,
+	"cp949":        korean.EUCKR,
+	"gb18030":      simplifiedchinese.GB18030,
+	"big5":         traditionalchinese.Big5,
+	"windows-1252": charmap.Windows1252,
+}
+
+// encodingAliases maps other common names to the canonical ones.
+var encodingAliases = map[string]string{
+	"utf8":        "utf-8",
+	"utf-16":      "utf-16le",
+	"sjis":        "shift-jis",
+	"shift_jis":   "shift-jis",
+	"cp932":       "shift-jis",
+	"windows-31j": "shift-jis",
+	"eucjp":       "euc-jp",
+	"euc-kr":      "cp949",
+	"euckr":       "cp949",
+	"uhc":         "cp949",
+	"gbk":         "gb18030",
+	"gb2312":      "gb18030",
+	"cp936":       "gb18030",
+	"cp950":       "big5",
+	"cp1252":      "windows-1252",
+	"latin1":      "windows-1252",
+	"iso-8859-1":  "windows-1252",
+}
+
+// ParseEncoding normalizes an encoding name such as "Shift_JIS" or "EUC-KR"
+// to its canonical form. Empty input and "auto" return "", meaning the
+// encoding is detected.
+func ParseEncoding(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "auto" {
+		return "", nil
+	}
+	if canonical, ok := encodingAliases[name]; ok {
+		name = canonical
+	}
+	if _, ok := textEncodings[name]; !ok {
+		return "", fmt.Errorf("unsupported text encoding %q (supported: %s)", name, EncodingNamesLabel)
+	}
+	return name, nil
+}
+
+// legacyEncodings are the detection candidates for input that is not UTF-8,
+// in tie-breaking order.
+var legacyEncodings = []string{"shift-jis", "cp949", "gb18030", "big5", "euc-jp", "windows-1252"}
+
+// languageEncodings lists the legacy encodings usual for a source language;
+// detection tries them first.
+var languageEncodings = map[string][]string{
+	"ja":      {"shift-jis", "euc-jp"},
+	"ko":      {"cp949"},
+	"zh":      {"gb18030"},
+	"zh-Hans": {"gb18030"},
+	"zh-Hant": {"big5"},
+}
+
+// isTextFormat reports whether ext is a plain-text subtitle format that can be
+// transcoded. STL is binary, and TTML declares its own encoding.
+func isTextFormat(ext string) bool {
+	switch ext {
+	case ".srt", ".vtt", ".ass", ".ssa":
+		return true
+	default:
+		return false
+	}
+}
+
+// DecodeText converts subtitle text to UTF-8 and returns it with the name of
+// the encoding it was read as. A forced name (see ParseEncoding) is used as
+// is. Otherwise a byte order mark decides, then valid UTF-8 is kept, and
+// anything else is read with the legacy encoding that decodes it most
+// plausibly, trying those usual for lang first.
+func DecodeText(data []byte, name, lang string) ([]byte, string, error) {
+	name, err := ParseEncoding(name)
+	if err != nil {
+		return nil, "", err
+	}
+	if name == "" {
+		name = detectEncoding(data, lang)
+	}
+	if name == "utf-8" {
+		return bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), name, nil
+	}
+	out, err := textEncodings[name].NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode input as %s: %w", name, err)
+	}
+	return out, name, nil
+}
+
+func detectEncoding(data []byte, lang string) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xef\xbb\xbf")):
+		return "utf-8"
+	case bytes.HasPrefix(data, []byte("\xff\xfe")):
+		return "utf-16le"
+	case bytes.HasPrefix(data, []byte("\xfe\xff")):
+		return "utf-16be"
+	case utf8.Valid(data):
+		return "utf-8"
+	}
+
+	candidates := append([]string(nil), languageEncodings[lang]...)
+	for _, name := range legacyEncodings {
+		if !slices.Contains(candidates, name) {
+			candidates = append(candidates, name)
+		}
+	}
+	best := ""
+	bestInvalid, bestCoverage := 0, -1.0
+	for _, name := range candidates {
+		decoded, err := textEncodings[name].NewDecoder().Bytes(data)
+		if err != nil {
+			continue
+		}
+		invalid, coverage := decodingPlausibility(data, decoded, name)
+		if best == "" || invalid < bestInvalid || (invalid == bestInvalid && coverage > bestCoverage) {
+			best, bestInvalid, bestCoverage = name, invalid, coverage
+		}
+	}
+	if best == "" {
+		return "windows-1252"
+	}
+	return best
+}
+
+// decodingPlausibility counts the replacement characters in decoded and the
+// share of data's non-ASCII bytes that decoded into the script the encoding
+// is meant for (e.g. kana and kanji for Shift-JIS). Reading text with the
+// wrong legacy encoding typically yields replacement characters or, like
+// half-width katakana or accented Latin letters, runes outside that script.
+func decodingPlausibility(data, decoded []byte, name string) (int, float64) {
+	nonASCII := 0
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			nonASCII++
+		}
+	}
+	invalid, covered := 0, 0
+	for _, r := range string(decoded) {
+		switch {
+		case r == utf8.RuneError:
+			invalid++
+		case r < utf8.RuneSelf:
+		case name == "windows-1252":
+			if unicode.IsLetter(r) || unicode.IsPunct(r) {
+				covered++
+			}
+		case unicode.Is(unicode.Han, r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff01 && r <= 0xff5e):
+			covered += 2
+		case (name == "shift-jis" || name == "euc-jp") && (unicode.Is(unicode.Hiragana, r) || (r >= 0x30a0 && r <= 0x30ff)):
+			covered += 2
+		case name == "cp949" && unicode.Is(unicode.Hangul, r) && (r < 0xffa0 || r > 0xffdc):
+			covered += 2
+		}
+	}
+	if nonASCII == 0 {
+		return invalid, 1
+	}
+	return invalid, min(float64(covered)/float64(nonASCII), 1)
+}
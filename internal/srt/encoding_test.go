@@ -0,0 +1,80 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Sample bytes of the same text in legacy encodings.
+const (
+	cp949Hello    = "\xbe\xc8\xb3\xe7\xc7\xcf\xbc\xbc\xbf\xe4\x2e\x20\xb9\xdd\xb0\xa9\xbd\xc0\xb4\xcf\xb4\xd9"         // 안녕하세요. 반갑습니다
+	shiftJISHello = "\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd\x81\x41\x8c\xb3\x8b\x43\x82\xc5\x82\xb7\x82\xa9\x81\x48" // こんにちは、元気ですか？
+	gb18030Hello  = "\xc4\xe3\xba\xc3\xa3\xac\xba\xdc\xb8\xdf\xd0\xcb\xbc\xfb\xb5\xbd\xc4\xe3"                         // 你好，很高兴见到你
+	cp1252Cafe    = "\x43\x61\x66\xe9\x20\x64\xe9\x6a\xe0\x20\x76\x75"                                                 // Café déjà vu
+)
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		encoding string
+		lang     string
+		want     string
+		wantName string
+	}{
+		{name: "cp949 detected", data: cp949Hello, want: "안녕하세요. 반갑습니다", wantName: "cp949"},
+		{name: "shift-jis detected", data: shiftJISHello, want: "こんにちは、元気ですか？", wantName: "shift-jis"},
+		{name: "gb18030 with language hint", data: gb18030Hello, lang: "zh-Hans", want: "你好，很高兴见到你", wantName: "gb18030"},
+		{name: "windows-1252 detected", data: cp1252Cafe, want: "Café déjà vu", wantName: "windows-1252"},
+		{name: "forced by alias", data: cp949Hello, encoding: "EUC-KR", want: "안녕하세요. 반갑습니다", wantName: "cp949"},
+		{name: "utf-8 kept", data: "안녕", want: "안녕", wantName: "utf-8"},
+		{name: "utf-8 BOM stripped", data: "\xef\xbb\xbfhi", want: "hi", wantName: "utf-8"},
+		{name: "utf-16 BOM", data: "\xff\xfeh\x00i\x00", want: "hi", wantName: "utf-16le"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, name, err := DecodeText([]byte(tt.data), tt.encoding, tt.lang)
+			if err != nil {
+				t.Fatalf("DecodeText: %v", err)
+			}
+			if string(got) != tt.want || name != tt.wantName {
+				t.Fatalf("DecodeText = %q as %s, want %q as %s", got, name, tt.want, tt.wantName)
+			}
+		})
+	}
+	if _, _, err := DecodeText([]byte("x"), "ebcdic", ""); err == nil {
+		t.Fatalf("expected error for unsupported encoding")
+	}
+}
+
+func TestLoad_TranscodesLegacyEncodings(t *testing.T) {
+	dir := t.TempDir()
+	for name, text := range map[string]string{"ko.srt": cp949Hello, "ja.srt": shiftJISHello} {
+		path := filepath.Join(dir, name)
+		content := "1\r\n00:00:01,000 --> 00:00:02,000\r\n" + text + "\r\n"
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("write input: %v", err)
+		}
+	}
+	tests := []struct {
+		file string
+		opts LoadOptions
+		want string
+	}{
+		{file: "ko.srt", want: "안녕하세요. 반갑습니다"},
+		{file: "ja.srt", want: "こんにちは、元気ですか？"},
+		{file: "ja.srt", opts: LoadOptions{Format: "srt", Language: "ja"}, want: "こんにちは、元気ですか？"},
+		{file: "ko.srt", opts: LoadOptions{Encoding: "cp949"}, want: "안녕하세요. 반갑습니다"},
+	}
+	for _, tt := range tests {
+		segments, err := LoadWithOptions(filepath.Join(dir, tt.file), tt.opts)
+		if err != nil {
+			t.Fatalf("LoadWithOptions(%s): %v", tt.file, err)
+		}
+		if len(segments) != 1 || !reflect.DeepEqual(segments[0].Lines, []string{tt.want}) {
+			t.Fatalf("LoadWithOptions(%s) = %+v, want %q", tt.file, segments, tt.want)
+		}
+	}
+}
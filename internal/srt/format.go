@@ -0,0 +1,135 @@
+package srt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asticode/go-astisub"
+	"github.com/oukeidos/focst/internal/logger"
+)
+
+// formatNames maps each explicit format name to the extension it stands for.
+var formatNames = map[string]string{
+	"srt":  ".srt",
+	"vtt":  ".vtt",
+	"ass":  ".ass",
+	"ssa":  ".ssa",
+	"ttml": ".ttml",
+	"stl":  ".stl",
+}
+
+// FormatNamesLabel lists the names accepted by ParseFormat.
+const FormatNamesLabel = "srt, vtt, ass, ssa, ttml, stl"
+
+// ParseFormat normalizes an explicit format name such as "SRT" or ".vtt" to
+// its canonical form ("srt", "vtt", ...). Empty input returns "", meaning the
+// format is inferred from the file extension.
+func ParseFormat(name string) (string, error) {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), ".")
+	if name == "" {
+		return "", nil
+	}
+	if _, ok := formatNames[name]; !ok {
+		return "", fmt.Errorf("unsupported subtitle format %q (supported: %s)", name, FormatNamesLabel)
+	}
+	return name, nil
+}
+
+// FormatExt returns the extension whose format applies to path: the explicit
+// format when one is given, otherwise path's own (lower-cased) extension.
+func FormatExt(path, format string) string {
+	if ext, ok := formatNames[strings.ToLower(format)]; ok {
+		return ext
+	}
+	return strings.ToLower(filepath.Ext(path))
+}
+
+// LoadOptions controls how LoadWithOptions reads a file.
+type LoadOptions struct {
+	// Format forces the parser ("srt", "vtt", ...) regardless of the file
+	// extension. Empty detects the format from the extension.
+	Format string
+	// Encoding forces the text encoding of the input ("shift-jis", "cp949",
+	// ...; see ParseEncoding). Empty detects it (see DecodeText). Text
+	// formats are converted to UTF-8 before parsing.
+	Encoding string
+	// Language is the source language code, which tells encoding detection
+	// which legacy encodings to try first.
+	Language string
+}
+
+// LoadWithOptions is Load with an optional explicit format and encoding.
+func LoadWithOptions(path string, opts LoadOptions) ([]Segment, error) {
+	if _, err := ParseFormat(opts.Format); err != nil {
+		return nil, err
+	}
+	if _, err := ParseEncoding(opts.Encoding); err != nil {
+		return nil, err
+	}
+	ext := FormatExt(path, opts.Format)
+	if opts.Format == "" && !isTextFormat(ext) {
+		subs, err := astisub.OpenFile(path)
+		if err != nil {
+			return nil, err
+		}
+		segments := fromAstisub(subs)
+		addFormatFields(ext, subs, segments)
+		return segments, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return loadText(ext, data, opts)
+}
+
+// LoadReader reads subtitles in the given format ("srt", "vtt", ...) from r,
+// e.g. an input fetched over HTTP. The format is required since there is no
+// file extension to infer it from.
+func LoadReader(r io.Reader, format string) ([]Segment, error) {
+	return LoadReaderWithOptions(r, LoadOptions{Format: format})
+}
+
+// LoadReaderWithOptions is LoadReader with an optional explicit encoding.
+func LoadReaderWithOptions(r io.Reader, opts LoadOptions) ([]Segment, error) {
+	format, err := ParseFormat(opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		return nil, fmt.Errorf("subtitle format is required")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadText(formatNames[format], data, opts)
+}
+
+// loadText converts text formats to UTF-8 before parsing them.
+func loadText(ext string, data []byte, opts LoadOptions) ([]Segment, error) {
+	if isTextFormat(ext) {
+		decoded, name, err := DecodeText(data, opts.Encoding, opts.Language)
+		if err != nil {
+			return nil, err
+		}
+		if name != "utf-8" {
+			logger.Info("Converted subtitle input to UTF-8", "encoding", name)
+		}
+		data = decoded
+	}
+	return loadData(ext, data)
+}
+
+func loadData(ext string, data []byte) ([]Segment, error) {
+	subs, err := decodeSubtitles(ext, data)
+	if err != nil {
+		return nil, err
+	}
+	segments := fromAstisub(subs)
+	addFormatFields(ext, subs, segments)
+	return segments, nil
+}
@@ -0,0 +1,73 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": "", "SRT": "srt", ".vtt": "vtt", " ass ": "ass"} {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("txt"); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
+
+func TestLoadSaveWithExplicitFormat(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "subs.txt")
+	content := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n\n"
+	if err := os.WriteFile(inPath, []byte(content), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	if _, err := Load(inPath); err == nil {
+		t.Fatalf("expected Load to reject an unknown extension without a format")
+	}
+	segments, err := LoadWithOptions(inPath, LoadOptions{Format: "srt"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions: %v", err)
+	}
+	if len(segments) != 2 || segments[1].Lines[0] != "World" {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+
+	outPath := filepath.Join(dir, "subs")
+	if err := SaveWithOptions(outPath, segments, SaveOptions{Format: "vtt", Verify: true}); err != nil {
+		t.Fatalf("SaveWithOptions: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "WEBVTT") {
+		t.Fatalf("expected WebVTT output, got:\n%s", data)
+	}
+	reloaded, err := LoadWithOptions(outPath, LoadOptions{Format: "vtt"})
+	if err != nil || len(reloaded) != 2 {
+		t.Fatalf("reload = %d segments, %v", len(reloaded), err)
+	}
+
+	if err := SaveWithOptions(outPath, segments, SaveOptions{Format: "docx"}); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
+
+func TestLoadReader(t *testing.T) {
+	content := "WEBVTT\n\n00:00:01.000 --> 00:00:02.000 align:start\nHello\n"
+	segments, err := LoadReader(strings.NewReader(content), "vtt")
+	if err != nil {
+		t.Fatalf("LoadReader: %v", err)
+	}
+	if len(segments) != 1 || segments[0].Lines[0] != "Hello" || segments[0].CueSettings != "align:start" {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	if _, err := LoadReader(strings.NewReader(content), ""); err == nil {
+		t.Fatalf("expected error without a format")
+	}
+}
@@ -0,0 +1,31 @@
+package srt
+
+import "encoding/json"
+
+// IDMapVersion is the version of the ID map document MarshalIDMap writes.
+const IDMapVersion = 1
+
+// IdentityIDMap maps every segment's ID to itself, for segments that were
+// not preprocessed and so kept their original IDs.
+func IdentityIDMap(segments []Segment) []IDMap {
+	mapping := make([]IDMap, len(segments))
+	for i, seg := range segments {
+		mapping[i] = IDMap{InternalID: seg.ID, OriginalID: seg.ID}
+	}
+	return mapping
+}
+
+// MarshalIDMap encodes mapping as an ID map document:
+// {"version":1,"mapping":[{"internal_id":1,"original_id":3},...]}.
+func MarshalIDMap(mapping []IDMap) ([]byte, error) {
+	if mapping == nil {
+		mapping = []IDMap{}
+	}
+	return json.Marshal(struct {
+		Version int     `json:"version"`
+		Mapping []IDMap `json:"mapping"`
+	}{
+		Version: IDMapVersion,
+		Mapping: mapping,
+	})
+}
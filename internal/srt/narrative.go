@@ -0,0 +1,61 @@
+package srt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NarrativeTag selects which segments are on-screen text (signs, notes,
+// captions) rather than dialogue, so they can be translated in a literal
+// register.
+type NarrativeTag string
+
+const (
+	// NarrativeForced tags SSA/ASS events whose style marks them forced.
+	NarrativeForced NarrativeTag = "forced"
+	// NarrativePositioned tags SSA/ASS events placed with \pos or \move, as
+	// typesetting for signs usually is.
+	NarrativePositioned NarrativeTag = "positioned"
+	// NarrativeBracketed tags segments whose whole text is in brackets or
+	// parentheses, e.g. "[Sign: Exit]".
+	NarrativeBracketed NarrativeTag = "bracketed"
+)
+
+// ParseNarrativeTag validates a tag name. An empty string disables tagging.
+func ParseNarrativeTag(s string) (NarrativeTag, error) {
+	switch tag := NarrativeTag(s); tag {
+	case "", NarrativeForced, NarrativePositioned, NarrativeBracketed:
+		return tag, nil
+	default:
+		return "", fmt.Errorf("invalid narrative tag %q (want %q, %q, or %q)", s, NarrativeForced, NarrativePositioned, NarrativeBracketed)
+	}
+}
+
+// narrativeBrackets pairs the opening and closing brackets NarrativeBracketed
+// accepts.
+var narrativeBrackets = map[rune]rune{'[': ']', '(': ')', '（': '）', '【': '】'}
+
+// Matches reports whether seg is on-screen text under tag. The empty tag
+// matches nothing.
+func (tag NarrativeTag) Matches(seg Segment) bool {
+	switch tag {
+	case NarrativeForced:
+		return seg.Forced
+	case NarrativePositioned:
+		for _, t := range seg.OverrideTags {
+			if strings.Contains(t.Block, `\pos(`) || strings.Contains(t.Block, `\move(`) {
+				return true
+			}
+		}
+		return false
+	case NarrativeBracketed:
+		text := []rune(strings.TrimSpace(strings.Join(seg.Lines, "\n")))
+		if len(text) < 2 {
+			return false
+		}
+		closing, ok := narrativeBrackets[text[0]]
+		return ok && text[len(text)-1] == closing && strings.Count(string(text), string(text[0])) == 1
+	default:
+		return false
+	}
+}
@@ -0,0 +1,34 @@
+package srt
+
+import "testing"
+
+func TestNarrativeTag_Matches(t *testing.T) {
+	dialogue := Segment{Lines: []string{"Where are you going?"}}
+	tests := []struct {
+		name string
+		tag  NarrativeTag
+		seg  Segment
+		want bool
+	}{
+		{name: "forced style", tag: NarrativeForced, seg: Segment{Lines: []string{"EXIT"}, Forced: true}, want: true},
+		{name: "forced dialogue", tag: NarrativeForced, seg: dialogue},
+		{name: "positioned sign", tag: NarrativePositioned, seg: Segment{Lines: []string{"EXIT"}, OverrideTags: []OverrideTag{{Block: `{\an8\pos(320,50)}`}}}, want: true},
+		{name: "moving sign", tag: NarrativePositioned, seg: Segment{Lines: []string{"EXIT"}, OverrideTags: []OverrideTag{{Block: `{\move(0,0,10,10)}`}}}, want: true},
+		{name: "italic dialogue", tag: NarrativePositioned, seg: Segment{Lines: []string{"Hi"}, OverrideTags: []OverrideTag{{Block: `{\i1}`}}}},
+		{name: "bracketed note", tag: NarrativeBracketed, seg: Segment{Lines: []string{"[Sign: Exit]"}}, want: true},
+		{name: "full-width brackets", tag: NarrativeBracketed, seg: Segment{Lines: []string{"【出口】"}}, want: true},
+		{name: "two bracketed parts", tag: NarrativeBracketed, seg: Segment{Lines: []string{"(sighs) Fine. (leaves)"}}},
+		{name: "dialogue", tag: NarrativeBracketed, seg: dialogue},
+		{name: "disabled", seg: Segment{Lines: []string{"[Sign]"}, Forced: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tag.Matches(tt.seg); got != tt.want {
+				t.Fatalf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := ParseNarrativeTag("signs"); err == nil {
+		t.Fatalf("expected error for unknown narrative tag")
+	}
+}
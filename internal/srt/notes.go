@@ -0,0 +1,52 @@
+package srt
+
+import (
+	"strings"
+
+	"github.com/asticode/go-astisub"
+)
+
+// provenanceNoteKeys are the line prefixes of an embedded provenance block
+// (see Provenance.commentLines), in the order they are written.
+var provenanceNoteKeys = []string{"generated by: ", "model: ", "source: ", "target: ", "date: ", "settings: "}
+
+// vttNotes returns the lines of the NOTE block astisub attached to a WebVTT
+// item, without a provenance block a previous run embedded, so translating
+// an output again does not pile up stale provenance.
+func vttNotes(item *astisub.Item) []string {
+	if item == nil || len(item.Comments) == 0 {
+		return nil
+	}
+	lines := item.Comments
+	if strings.HasPrefix(lines[0], provenanceNoteKeys[0]) {
+		next := 0
+		for len(lines) > 0 && next < len(provenanceNoteKeys) {
+			if strings.HasPrefix(lines[0], provenanceNoteKeys[next]) {
+				lines = lines[1:]
+			}
+			next++
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string(nil), lines...)
+}
+
+// applyNotes writes each segment's Notes as the NOTE block before its cue.
+// "-->" is removed because it would end the block early.
+func applyNotes(subs *astisub.Subtitles, segments []Segment) {
+	for i, seg := range segments {
+		if i >= len(subs.Items) || len(seg.Notes) == 0 {
+			continue
+		}
+		comments := make([]string, 0, len(seg.Notes))
+		for _, line := range seg.Notes {
+			line = strings.TrimSpace(strings.ReplaceAll(line, "-->", ""))
+			if line != "" {
+				comments = append(comments, line)
+			}
+		}
+		subs.Items[i].Comments = comments
+	}
+}
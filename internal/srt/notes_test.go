@@ -0,0 +1,77 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNotes_VTTRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.vtt")
+	input := "WEBVTT\n\n" +
+		"NOTE Translator: check the pun\nin the next line\n\n" +
+		"1\n00:00:01.000 --> 00:00:02.000\nHello\n\n" +
+		"2\n00:00:03.000 --> 00:00:04.000\nWorld\n"
+	if err := os.WriteFile(inPath, []byte(input), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	segments, err := Load(inPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := []string{"Translator: check the pun", "in the next line"}
+	if !reflect.DeepEqual(segments[0].Notes, want) || segments[1].Notes != nil {
+		t.Fatalf("Notes = %q, %q", segments[0].Notes, segments[1].Notes)
+	}
+
+	segments[0].Lines = []string{"안녕하세요"}
+	outPath := filepath.Join(dir, "out.vtt")
+	if err := SaveWithOptions(outPath, segments, SaveOptions{Verify: true}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(outPath)
+	if !strings.Contains(string(data), "NOTE Translator: check the pun\nin the next line\n\n1\n") {
+		t.Fatalf("NOTE block not kept before its cue:\n%s", data)
+	}
+
+	srtPath := filepath.Join(dir, "out.srt")
+	if err := Save(srtPath, segments); err != nil {
+		t.Fatalf("Save SRT failed: %v", err)
+	}
+	if data, _ := os.ReadFile(srtPath); strings.Contains(string(data), "Translator") {
+		t.Fatalf("SRT output carries the note:\n%s", data)
+	}
+}
+
+func TestNotes_ProvenanceNotCarriedOver(t *testing.T) {
+	dir := t.TempDir()
+	segments := []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"Hi"}, Notes: []string{"Keep this"}}}
+	path := filepath.Join(dir, "out.vtt")
+	prov := &Provenance{Tool: "focst test", Model: "m", SourceLang: "en", TargetLang: "ko", SettingsHash: "abc"}
+	if err := SaveWithOptions(path, segments, SaveOptions{Provenance: prov}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded[0].Notes, []string{"Keep this"}) {
+		t.Fatalf("Notes = %q, want only the original note", loaded[0].Notes)
+	}
+}
+
+func TestApplyNotes_RemovesCueArrow(t *testing.T) {
+	segments := []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"Hi"}, Notes: []string{"a --> b", "   "}}}
+	subs, err := toAstisub(segments, false)
+	if err != nil {
+		t.Fatalf("toAstisub failed: %v", err)
+	}
+	applyNotes(subs, segments)
+	if got := subs.Items[0].Comments; !reflect.DeepEqual(got, []string{"a  b"}) {
+		t.Fatalf("Comments = %q", got)
+	}
+}
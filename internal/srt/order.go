@@ -0,0 +1,39 @@
+package srt
+
+import (
+	"sort"
+	"time"
+)
+
+// Renumber sets segment IDs to 1..n in slice order.
+func Renumber(segments []Segment) {
+	for i := range segments {
+		segments[i].ID = i + 1
+	}
+}
+
+// SortByStart stably sorts segments by start time, so cues starting at the
+// same time keep their order. Segments with an unparsable start time move to
+// the end, also in their original order. IDs are left unchanged; call
+// Renumber afterwards for sequential IDs.
+func SortByStart(segments []Segment) {
+	type keyed struct {
+		start time.Duration
+		valid bool
+		seg   Segment
+	}
+	items := make([]keyed, len(segments))
+	for i, seg := range segments {
+		start, err := ParseTimestamp(seg.StartTime)
+		items[i] = keyed{start: start, valid: err == nil, seg: seg}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].valid != items[j].valid {
+			return items[i].valid
+		}
+		return items[i].valid && items[i].start < items[j].start
+	})
+	for i := range items {
+		segments[i] = items[i].seg
+	}
+}
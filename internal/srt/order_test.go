@@ -0,0 +1,35 @@
+package srt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortByStartAndRenumber(t *testing.T) {
+	segments := []Segment{
+		{ID: 7, StartTime: "00:00:05,000", EndTime: "00:00:06,000", Lines: []string{"third"}},
+		{ID: 3, StartTime: "bad", EndTime: "00:00:01,000", Lines: []string{"invalid"}},
+		{ID: 5, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"first"}},
+		{ID: 9, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"second a"}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:03,500", Lines: []string{"second b"}},
+	}
+
+	SortByStart(segments)
+	Renumber(segments)
+
+	var got []string
+	var ids []int
+	for _, seg := range segments {
+		got = append(got, seg.Lines[0])
+		ids = append(ids, seg.ID)
+	}
+	if want := []string{"first", "second a", "second b", "third", "invalid"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order = %q, want %q", got, want)
+	}
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("IDs = %v, want %v", ids, want)
+	}
+	if segments[2].EndTime != "00:00:03,500" {
+		t.Fatalf("segment fields moved without their cue: %+v", segments[2])
+	}
+}
@@ -0,0 +1,148 @@
+package srt
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/asticode/go-astisub"
+)
+
+// OverrideTag is an ASS/SSA override block such as "{\i1}" or "{\pos(10,20)}"
+// and where it sat in its segment's visible text.
+type OverrideTag struct {
+	Block string `json:"block"`
+	// Pos is the block's offset into the segment's visible text (all lines,
+	// without separators) as a fraction of its length in runes: 0 is the
+	// start of the first line, 1 the end of the last.
+	Pos float64 `json:"pos"`
+}
+
+var overrideBlockRegexp = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ExtractOverrideTags removes every {...} override block from lines and
+// returns the visible text with the blocks and their relative positions.
+func ExtractOverrideTags(lines []string) ([]string, []OverrideTag) {
+	text := make([]string, len(lines))
+	type found struct {
+		block  string
+		offset int
+	}
+	var blocks []found
+	total := 0
+	for i, line := range lines {
+		var b strings.Builder
+		last := 0
+		for _, idx := range overrideBlockRegexp.FindAllStringIndex(line, -1) {
+			b.WriteString(line[last:idx[0]])
+			blocks = append(blocks, found{block: line[idx[0]:idx[1]], offset: total + utf8.RuneCountInString(b.String())})
+			last = idx[1]
+		}
+		b.WriteString(line[last:])
+		text[i] = b.String()
+		total += utf8.RuneCountInString(text[i])
+	}
+	if len(blocks) == 0 {
+		return text, nil
+	}
+	tags := make([]OverrideTag, len(blocks))
+	for i, f := range blocks {
+		tags[i] = OverrideTag{Block: f.block}
+		if total > 0 {
+			tags[i].Pos = float64(f.offset) / float64(total)
+		}
+	}
+	return text, tags
+}
+
+// InsertOverrideTags puts tags back into lines at the same relative positions,
+// moved to the nearest word boundary when the line has spaces. A position on
+// a line break goes to the start of the next line.
+func InsertOverrideTags(lines []string, tags []OverrideTag) []string {
+	if len(tags) == 0 || len(lines) == 0 {
+		return lines
+	}
+	runes := make([][]rune, len(lines))
+	total := 0
+	for i, line := range lines {
+		runes[i] = []rune(line)
+		total += len(runes[i])
+	}
+
+	// inserts[i][j] holds the blocks that go before rune j of line i.
+	inserts := make([]map[int]string, len(lines))
+	for _, tag := range tags {
+		offset := int(math.Round(tag.Pos * float64(total)))
+		line := 0
+		for line < len(lines)-1 && offset >= len(runes[line]) {
+			offset -= len(runes[line])
+			line++
+		}
+		offset = snapToWordBoundary(runes[line], min(offset, len(runes[line])))
+		if inserts[line] == nil {
+			inserts[line] = map[int]string{}
+		}
+		inserts[line][offset] += tag.Block
+	}
+
+	out := make([]string, len(lines))
+	for i, r := range runes {
+		if inserts[i] == nil {
+			out[i] = lines[i]
+			continue
+		}
+		var b strings.Builder
+		for j := 0; j <= len(r); j++ {
+			b.WriteString(inserts[i][j])
+			if j < len(r) {
+				b.WriteRune(r[j])
+			}
+		}
+		out[i] = b.String()
+	}
+	return out
+}
+
+// snapToWordBoundary returns the word boundary in line nearest to offset,
+// or offset itself when line has no spaces (e.g. CJK text).
+func snapToWordBoundary(line []rune, offset int) int {
+	isBoundary := func(i int) bool {
+		return i == 0 || i == len(line) || unicode.IsSpace(line[i-1]) != unicode.IsSpace(line[i])
+	}
+	if !strings.ContainsFunc(string(line), unicode.IsSpace) {
+		return offset
+	}
+	for d := 0; d <= len(line); d++ {
+		if offset-d >= 0 && isBoundary(offset-d) {
+			return offset - d
+		}
+		if offset+d <= len(line) && isBoundary(offset+d) {
+			return offset + d
+		}
+	}
+	return offset
+}
+
+// ssaOverrideTags rebuilds an SSA/ASS item's raw lines, which astisub splits
+// into override blocks and text, and returns the blocks.
+func ssaOverrideTags(item *astisub.Item) []OverrideTag {
+	raw := make([]string, len(item.Lines))
+	for i, l := range item.Lines {
+		var b strings.Builder
+		for _, li := range l.Items {
+			if li.InlineStyle != nil {
+				b.WriteString(li.InlineStyle.SSAEffect)
+			}
+			b.WriteString(li.Text)
+		}
+		raw[i] = b.String()
+	}
+	_, tags := ExtractOverrideTags(raw)
+	return tags
+}
+
+func isSSAExt(ext string) bool {
+	return ext == ".ass" || ext == ".ssa"
+}
@@ -0,0 +1,97 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOverrideTags_ExtractAndInsert(t *testing.T) {
+	text, tags := ExtractOverrideTags([]string{`{\i1}Hello{\i0} there`})
+	if !reflect.DeepEqual(text, []string{"Hello there"}) {
+		t.Fatalf("visible text = %q", text)
+	}
+	if len(tags) != 2 || tags[0].Block != `{\i1}` || tags[0].Pos != 0 || tags[1].Block != `{\i0}` {
+		t.Fatalf("tags = %+v", tags)
+	}
+
+	tests := []struct {
+		name  string
+		lines []string
+		tags  []OverrideTag
+		want  []string
+	}{
+		{name: "round trip", lines: text, tags: tags, want: []string{`{\i1}Hello{\i0} there`}},
+		{name: "snapped to word boundary", lines: []string{"Bonjour à tous"}, tags: tags, want: []string{`{\i1}Bonjour{\i0} à tous`}},
+		{name: "no spaces", lines: []string{"こんにちは皆さん"}, tags: tags, want: []string{`{\i1}こんにち{\i0}は皆さん`}},
+		{name: "line break goes to next line", lines: []string{"ab", "cd"}, tags: []OverrideTag{{Block: `{\c&H00FFFF&}`, Pos: 0.5}}, want: []string{"ab", `{\c&H00FFFF&}cd`}},
+		{name: "end of text", lines: []string{"ab", "cd"}, tags: []OverrideTag{{Block: `{\r}`, Pos: 1}}, want: []string{"ab", `cd{\r}`}},
+		{name: "no tags", lines: []string{"plain"}, want: []string{"plain"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InsertOverrideTags(tt.lines, tt.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("InsertOverrideTags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverrideTags_ASSRoundTrip(t *testing.T) {
+	content := `[Script Info]
+ScriptType: v4.00+
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\i1}Hello{\i0} there
+Dialogue: 0,0:00:03.00,0:00:04.00,Default,,0,0,0,,{\pos(320,50)\fad(200,200)}Exit
+`
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.ass")
+	if err := os.WriteFile(inPath, []byte(content), 0600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	segments, err := Load(inPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	// Only the visible text is translated.
+	if !reflect.DeepEqual(segments[0].Lines, []string{"Hello there"}) || !reflect.DeepEqual(segments[1].Lines, []string{"Exit"}) {
+		t.Fatalf("lines = %q, %q", segments[0].Lines, segments[1].Lines)
+	}
+	segments[0].Lines = []string{"안녕 거기"}
+	segments[1].Lines = []string{"출구"}
+
+	assPath := filepath.Join(dir, "out.ass")
+	if err := SaveWithOptions(assPath, segments, SaveOptions{Verify: true}); err != nil {
+		t.Fatalf("save ass: %v", err)
+	}
+	data, err := os.ReadFile(assPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	for _, want := range []string{`{\i1}안녕{\i0} 거기`, `{\pos(320,50)\fad(200,200)}출구`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("ASS output missing %q:\n%s", want, data)
+		}
+	}
+
+	// Other formats have no override syntax and get the text only.
+	srtPath := filepath.Join(dir, "out.srt")
+	if err := Save(srtPath, segments); err != nil {
+		t.Fatalf("save srt: %v", err)
+	}
+	data, err = os.ReadFile(srtPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if strings.Contains(string(data), "{") {
+		t.Fatalf("SRT output kept override tags:\n%s", data)
+	}
+}
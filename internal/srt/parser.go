@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/asticode/go-astisub"
 	"github.com/oukeidos/focst/internal/files"
@@ -19,21 +19,55 @@ type Segment struct {
 	StartTime string // Format: 00:00:00,000 (standardized for internal use)
 	EndTime   string
 	Lines     []string
+	Forced    bool // SSA/ASS event whose style name contains "forced"
+	// CueSettings holds a WebVTT cue's settings (e.g. "align:start position:50%").
+	// Load fills it for .vtt input only; SaveOptions.CueSettings writes it back.
+	CueSettings string
+	// OverrideTags holds the SSA/ASS override blocks (e.g. "{\i1}") removed
+	// from Lines. Load fills it for .ass/.ssa input only; saving to ASS/SSA
+	// puts them back at the same relative positions in the current Lines.
+	OverrideTags []OverrideTag
+	// Notes holds the lines of the WebVTT NOTE block before the cue. Load
+	// fills it for .vtt input only, and saving to WebVTT writes it back.
+	Notes []string
 }
 
 // Load reads subtitles from a file and returns them as a slice of Segment.
-// It automatically detects the format based on the file extension or content.
+// It automatically detects the format based on the file extension or content,
+// and converts text formats in a legacy encoding (Shift-JIS, CP949, ...) to
+// UTF-8 (see DecodeText).
 func Load(path string) ([]Segment, error) {
-	subs, err := astisub.OpenFile(path)
-	if err != nil {
-		return nil, err
+	return LoadWithOptions(path, LoadOptions{})
+}
+
+// addFormatFields fills the segment fields only some input formats carry.
+func addFormatFields(ext string, subs *astisub.Subtitles, segments []Segment) {
+	for i, item := range subs.Items {
+		switch {
+		case ext == ".vtt":
+			segments[i].CueSettings = vttCueSettings(item)
+			segments[i].Notes = vttNotes(item)
+		case isSSAExt(ext):
+			segments[i].OverrideTags = ssaOverrideTags(item)
+		}
 	}
-	return fromAstisub(subs), nil
+}
+
+// ValidateOptions adds optional checks to ValidateWithOptions.
+type ValidateOptions struct {
+	// RequireDialogue rejects files whose text has no letters or digits
+	// (e.g. only "♪" music cues), not just whitespace-only files.
+	RequireDialogue bool
 }
 
 // Validate checks if the segments are valid for translation.
 // It returns an error if there are no segments, no text, or invalid timestamps.
 func Validate(segments []Segment) error {
+	return ValidateWithOptions(segments, ValidateOptions{})
+}
+
+// ValidateWithOptions is Validate with optional stricter checks.
+func ValidateWithOptions(segments []Segment, opts ValidateOptions) error {
 	if len(segments) == 0 {
 		return fmt.Errorf("no subtitles found in file")
 	}
@@ -67,10 +101,43 @@ func Validate(segments []Segment) error {
 	if !hasText {
 		return fmt.Errorf("file contains subtitles but no dialogue text")
 	}
+	if opts.RequireDialogue && !HasDialogue(segments) {
+		return fmt.Errorf("file contains subtitles but no dialogue text")
+	}
 
 	return nil
 }
 
+// ZeroDurationSegments returns the IDs of segments whose end time equals their
+// start time. Validate accepts them, and timing correction extends them to
+// MinCueDuration, but they usually point to a broken source file.
+func ZeroDurationSegments(segments []Segment) []int {
+	var ids []int
+	for _, seg := range segments {
+		start, err1 := ParseTimestamp(seg.StartTime)
+		end, err2 := ParseTimestamp(seg.EndTime)
+		if err1 == nil && err2 == nil && end == start {
+			ids = append(ids, seg.ID)
+		}
+	}
+	return ids
+}
+
+// HasDialogue reports whether any segment line contains a letter or digit.
+// Whitespace- or symbol-only files (e.g. "♪" music cues) have no dialogue.
+func HasDialogue(segments []Segment) bool {
+	for _, seg := range segments {
+		for _, line := range seg.Lines {
+			for _, r := range line {
+				if unicode.IsLetter(r) || unicode.IsNumber(r) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 // fromAstisub converts astisub.Subtitles to our internal Segment slice.
 func fromAstisub(subs *astisub.Subtitles) []Segment {
 	segments := make([]Segment, 0, len(subs.Items))
@@ -85,11 +152,21 @@ func fromAstisub(subs *astisub.Subtitles) []Segment {
 			StartTime: formatDuration(item.StartAt),
 			EndTime:   formatDuration(item.EndAt),
 			Lines:     lines,
+			Forced:    isForcedItem(item),
 		})
 	}
 	return segments
 }
 
+// isForcedItem reports whether an SSA/ASS event uses a "forced" style
+// (e.g. "Forced", "Signs-Forced").
+func isForcedItem(item *astisub.Item) bool {
+	if item == nil || item.Style == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(item.Style.ID), "forced")
+}
+
 func formatDuration(d time.Duration) string {
 	h := d / time.Hour
 	d -= h * time.Hour
@@ -103,12 +180,52 @@ func formatDuration(d time.Duration) string {
 }
 
 // Save writes segments to a file, determining the format by file extension.
+// Use SaveWithOptions to force a format.
 func Save(path string, segments []Segment) error {
-	subs, err := toAstisub(segments)
+	return SaveWithOptions(path, segments, SaveOptions{})
+}
+
+// SaveOptions controls optional output written by SaveWithOptions.
+type SaveOptions struct {
+	// Provenance, when set, is embedded as a WebVTT NOTE block or ASS/SSA
+	// [Script Info] comments. Other formats have no comment syntax and skip it.
+	Provenance *Provenance
+	// Verify parses the written temp file back before it replaces path, so an
+	// invalid serialization never overwrites an existing output.
+	Verify bool
+	// CueSettings writes each segment's CueSettings back onto its cue when
+	// saving WebVTT. Other formats ignore it.
+	CueSettings bool
+	// Format forces the serializer ("srt", "vtt", ...) regardless of the file
+	// extension. Empty picks the format from the extension.
+	Format string
+	// Compat applies a profile's formatting quirks to SRT and WebVTT output
+	// (see CompatProfile).
+	Compat CompatProfile
+}
+
+// SaveWithOptions is Save with optional embedded metadata and verification.
+func SaveWithOptions(path string, segments []Segment, opts SaveOptions) error {
+	if _, err := ParseFormat(opts.Format); err != nil {
+		return err
+	}
+	if _, err := ParseCompatProfile(string(opts.Compat)); err != nil {
+		return err
+	}
+	ext := FormatExt(path, opts.Format)
+	subs, err := toAstisub(segments, isSSAExt(ext))
 	if err != nil {
 		return err
 	}
-	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".vtt" {
+		applyNotes(subs, segments)
+	}
+	if opts.Provenance != nil {
+		embedProvenance(subs, ext, *opts.Provenance)
+	}
+	if opts.CueSettings && ext == ".vtt" {
+		applyCueSettings(subs, segments)
+	}
 
 	var buf bytes.Buffer
 	var writeErr error
@@ -138,8 +255,68 @@ func Save(path string, segments []Segment) error {
 	if writeErr != nil {
 		return fmt.Errorf("failed to write to buffer: %w", writeErr)
 	}
+	content := applyCompat(buf.Bytes(), ext, opts.Compat)
+
+	if !opts.Verify {
+		return files.AtomicWrite(path, content, 0600)
+	}
+	return files.AtomicWriteVerified(path, content, 0600, func(data []byte) error {
+		return verifyRoundTrip(ext, data, len(segments))
+	})
+}
+
+// verifyRoundTrip checks that serialized output parses back into want segments.
+func verifyRoundTrip(ext string, data []byte, want int) error {
+	segments, err := decode(ext, data)
+	if err != nil {
+		return fmt.Errorf("serialized output does not parse: %w", err)
+	}
+	if len(segments) != want {
+		return fmt.Errorf("serialized output has %d segments, want %d", len(segments), want)
+	}
+	return nil
+}
 
-	return files.AtomicWrite(path, buf.Bytes(), 0600)
+// decode parses data in the format implied by ext, mirroring Save's choice.
+func decode(ext string, data []byte) ([]Segment, error) {
+	subs, err := decodeSubtitles(ext, data)
+	if err != nil {
+		return nil, err
+	}
+	return fromAstisub(subs), nil
+}
+
+func decodeSubtitles(ext string, data []byte) (*astisub.Subtitles, error) {
+	r := bytes.NewReader(data)
+	switch ext {
+	case ".vtt":
+		return astisub.ReadFromWebVTT(r)
+	case ".ssa", ".ass":
+		return astisub.ReadFromSSA(r)
+	case ".ttml":
+		return astisub.ReadFromTTML(r)
+	case ".stl":
+		return astisub.ReadFromSTL(r, astisub.STLOptions{})
+	default:
+		return astisub.ReadFromSRT(r)
+	}
+}
+
+// embedProvenance attaches the provenance comment block for ext's format.
+func embedProvenance(subs *astisub.Subtitles, ext string, p Provenance) {
+	lines := p.commentLines()
+	if len(lines) == 0 {
+		return
+	}
+	switch ext {
+	case ".vtt":
+		// The writer emits an item's comments as a NOTE block before its cue.
+		if len(subs.Items) > 0 {
+			subs.Items[0].Comments = append(lines, subs.Items[0].Comments...)
+		}
+	case ".ssa", ".ass":
+		subs.Metadata.Comments = append(subs.Metadata.Comments, lines...)
+	}
 }
 
 // fixASSStylesSection replaces the library-generated Styles section with standard ASS format.
@@ -213,7 +390,9 @@ func defaultASSStyle() *astisub.Style {
 	}
 }
 
-func toAstisub(segments []Segment) (*astisub.Subtitles, error) {
+// toAstisub converts segments for writing. overrideTags re-inserts each
+// segment's OverrideTags into its lines (SSA/ASS output only).
+func toAstisub(segments []Segment, overrideTags bool) (*astisub.Subtitles, error) {
 	subs := astisub.NewSubtitles()
 	// Initialize Metadata to prevent nil pointer dereference in WriteToSSA
 	subs.Metadata = &astisub.Metadata{SSAScriptType: "v4.00+"}
@@ -234,7 +413,11 @@ func toAstisub(segments []Segment) (*astisub.Subtitles, error) {
 			StartAt: start,
 			EndAt:   end,
 		}
-		for _, l := range seg.Lines {
+		lines := seg.Lines
+		if overrideTags {
+			lines = InsertOverrideTags(lines, seg.OverrideTags)
+		}
+		for _, l := range lines {
 			item.Lines = append(item.Lines, astisub.Line{
 				Items: []astisub.LineItem{{Text: l}},
 			})
@@ -320,7 +503,7 @@ func Parse(r io.Reader) ([]Segment, error) {
 
 // Deprecated: use Save instead.
 func Generate(w io.Writer, segments []Segment) error {
-	subs, err := toAstisub(segments)
+	subs, err := toAstisub(segments, false)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,20 @@ package srt
 
 import "testing"
 
+func TestValidateWithOptions_RequireDialogue(t *testing.T) {
+	music := []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"♪~"}}}
+	if err := Validate(music); err != nil {
+		t.Fatalf("expected music-only file to pass default validation, got %v", err)
+	}
+	if err := ValidateWithOptions(music, ValidateOptions{RequireDialogue: true}); err == nil {
+		t.Fatalf("expected music-only file to fail with RequireDialogue")
+	}
+	blank := []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{" "}}}
+	if err := ValidateWithOptions(blank, ValidateOptions{}); err == nil {
+		t.Fatalf("expected whitespace-only file to fail without RequireDialogue")
+	}
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -7,12 +7,15 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/oukeidos/focst/internal/files"
 )
 
 // GenerateOutputPath creates an output path with a language-specific suffix and handles collisions.
+// The base name is passed through files.SanitizeFilename so the result is a
+// name the OS accepts even when the input's was not.
 func GenerateOutputPath(inputPath string, targetLang string) string {
 	ext := filepath.Ext(inputPath)
-	base := strings.TrimSuffix(inputPath, ext)
+	base := filepath.Join(filepath.Dir(inputPath), files.SanitizeFilename(strings.TrimSuffix(filepath.Base(inputPath), ext)))
 
 	// Normalize targetLang (e.g., zh-Hans -> zh) for suffix if desired,
 	// but using the full code is often safer/more explicit.
@@ -1,6 +1,7 @@
 package srt
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
@@ -24,38 +25,153 @@ var (
 	multiSpaceRegex = regexp.MustCompile(`\s+`)
 )
 
+const (
+	// MinCueDuration is the shortest duration timing correction leaves a cue
+	// with, including cues whose source end equals their start.
+	MinCueDuration = 800 * time.Millisecond
+	// cueGap is the space timing correction keeps before the next cue.
+	cueGap = 5 * time.Millisecond
+)
+
+// PostprocessOptions selects which post-processing steps run. The zero value runs all of them.
+type PostprocessOptions struct {
+	// NoLangRules skips language-specific punctuation cleanup.
+	NoLangRules bool
+	// NoTimingCorrection keeps the source timing untouched.
+	NoTimingCorrection bool
+	// NormalizeQuotes replaces straight quotes with the target language's
+	// quotation marks (e.g. “ ” for English, « » for French). It is a
+	// language rule, so NoLangRules skips it too.
+	NormalizeQuotes bool
+	// OnEmptied decides what happens to a segment whose lines are all removed
+	// by language-specific cleanup. Empty means EmptiedKeep.
+	OnEmptied EmptiedPolicy
+	// RulesLang selects the language whose punctuation cleanup and quote
+	// style run, independent of the target language (e.g. Traditional Chinese
+	// punctuation for a Simplified Chinese target). Empty uses the target.
+	RulesLang string
+}
+
+// EmptiedPolicy decides what post-processing does with a segment that
+// language-specific cleanup leaves without lines, such as a translation that
+// was only punctuation.
+type EmptiedPolicy string
+
+const (
+	// EmptiedKeep keeps the segment's text as it was before cleanup (the default).
+	EmptiedKeep EmptiedPolicy = "keep"
+	// EmptiedDrop removes the segment from the output.
+	EmptiedDrop EmptiedPolicy = "drop"
+)
+
+// ParseEmptiedPolicy validates a policy name. An empty string selects keep.
+func ParseEmptiedPolicy(s string) (EmptiedPolicy, error) {
+	switch EmptiedPolicy(s) {
+	case "", EmptiedKeep:
+		return EmptiedKeep, nil
+	case EmptiedDrop:
+		return EmptiedDrop, nil
+	default:
+		return "", fmt.Errorf("invalid emptied segment policy %q (want %q or %q)", s, EmptiedKeep, EmptiedDrop)
+	}
+}
+
 // Postprocess performs punctuation cleanup and timing correction.
 func Postprocess(segments []Segment, targetLangCode string, targetCPS int) []Segment {
-	return PostprocessWithOptions(segments, targetLangCode, targetCPS, true)
+	return PostprocessWithOptions(segments, targetLangCode, targetCPS, PostprocessOptions{})
 }
 
-// PostprocessWithOptions performs timing correction and optional language-specific cleanup.
-func PostprocessWithOptions(segments []Segment, targetLangCode string, targetCPS int, applyLangRules bool) []Segment {
+// PostprocessWithOptions performs language-specific cleanup and timing correction,
+// each of which can be skipped independently.
+func PostprocessWithOptions(segments []Segment, targetLangCode string, targetCPS int, opts PostprocessOptions) []Segment {
 	// 1. Punctuation Cleanup
-	if applyLangRules {
-		if targetLangCode == "ko" {
-			for i := range segments {
-				segments[i] = cleanPunctuation(segments[i])
-			}
-		} else if targetLangCode == "ja" {
-			for i := range segments {
-				segments[i] = cleanJapanesePunctuation(segments[i])
-			}
-		} else if targetLangCode == "zh-Hant" {
+	if !opts.NoLangRules {
+		before := make([][]string, len(segments))
+		for i := range segments {
+			before[i] = segments[i].Lines
+		}
+		rulesLang := targetLangCode
+		if opts.RulesLang != "" {
+			rulesLang = opts.RulesLang
+		}
+		if clean := punctuationCleaner(rulesLang); clean != nil {
 			for i := range segments {
-				segments[i] = cleanTraditionalChinesePunctuation(segments[i])
+				segments[i] = clean(segments[i])
 			}
-		} else if targetLangCode == "zh" || targetLangCode == "zh-Hans" {
+		}
+		if opts.NormalizeQuotes {
 			for i := range segments {
-				segments[i] = cleanSimplifiedChinesePunctuation(segments[i])
+				segments[i] = normalizeQuotes(segments[i], rulesLang)
 			}
 		}
+		segments = handleEmptied(segments, before, opts.OnEmptied)
 	}
 
 	// 2. Timing Correction
+	if opts.NoTimingCorrection {
+		return segments
+	}
 	return correctTiming(segments, targetCPS)
 }
 
+// punctuationCleaner returns the punctuation cleanup for langCode, or nil when
+// the language has none.
+func punctuationCleaner(langCode string) func(Segment) Segment {
+	switch langCode {
+	case "ko":
+		return cleanPunctuation
+	case "ja":
+		return cleanJapanesePunctuation
+	case "zh-Hant":
+		return cleanTraditionalChinesePunctuation
+	case "zh", "zh-Hans":
+		return cleanSimplifiedChinesePunctuation
+	default:
+		return nil
+	}
+}
+
+// HasPunctuationCleanup reports whether post-processing has punctuation
+// cleanup rules for langCode.
+func HasPunctuationCleanup(langCode string) bool {
+	return punctuationCleaner(langCode) != nil
+}
+
+// handleEmptied applies policy to the segments that had text before cleanup
+// and have no lines after it, so no output cue is blank.
+func handleEmptied(segments []Segment, before [][]string, policy EmptiedPolicy) []Segment {
+	kept := segments[:0]
+	emptied := 0
+	for i, seg := range segments {
+		if len(seg.Lines) > 0 || !hasText(before[i]) {
+			kept = append(kept, seg)
+			continue
+		}
+		emptied++
+		if policy == EmptiedDrop {
+			continue
+		}
+		seg.Lines = before[i]
+		kept = append(kept, seg)
+	}
+	if emptied > 0 {
+		if policy == "" {
+			policy = EmptiedKeep
+		}
+		logger.Warn("Punctuation cleanup emptied segments", "count", emptied, "policy", string(policy))
+	}
+	return kept
+}
+
+func hasText(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func cleanPunctuation(seg Segment) Segment {
 	newLines := make([]string, 0, len(seg.Lines))
 	for _, line := range seg.Lines {
@@ -430,7 +546,7 @@ func correctTiming(segments []Segment, targetCPS int) []Segment {
 
 	invalidTimingCount := 0
 
-	// Step 1: Readability & Duration (Min 0.8s)
+	// Step 1: Readability & Duration (MinCueDuration)
 	for i := range segments {
 		start, err1 := ParseTimestamp(segments[i].StartTime)
 		end, err2 := ParseTimestamp(segments[i].EndTime)
@@ -439,7 +555,10 @@ func correctTiming(segments []Segment, targetCPS int) []Segment {
 			continue // Skip segments with invalid timestamps to avoid corruption
 		}
 
-		duration := end.Seconds() - start.Seconds()
+		// Durations stay integral so untouched cues keep their exact end time.
+		// Zero-duration cues (end == start) get at least MinCueDuration below;
+		// the overlap step then pulls the end back before the next cue.
+		duration := end - start
 
 		// Calculate total characters (grapheme clusters including spaces)
 		totalChars := 0
@@ -447,19 +566,18 @@ func correctTiming(segments []Segment, targetCPS int) []Segment {
 			totalChars += uniseg.GraphemeClusterCount(line)
 		}
 
-		// Ensure min duration 0.8s
-		if duration < 0.8 {
-			duration = 0.8
+		// Ensure min duration
+		if duration < MinCueDuration {
+			duration = MinCueDuration
 		}
 
 		// Apply target CPS
-		reqDuration := float64(totalChars) / float64(targetCPS)
+		reqDuration := time.Duration(float64(totalChars) / float64(targetCPS) * float64(time.Second))
 		if duration < reqDuration {
 			duration = reqDuration
 		}
 
-		newEnd := start + time.Duration(duration*float64(time.Second))
-		segments[i].EndTime = FormatTimestamp(newEnd)
+		segments[i].EndTime = FormatTimestamp(start + duration)
 	}
 
 	// Step 2: Overlap Prevention (5ms gap)
@@ -474,8 +592,7 @@ func correctTiming(segments []Segment, targetCPS int) []Segment {
 
 		// If end of current is > next start - 5ms, adjust current end.
 		// Skip adjustment if it would create a negative duration.
-		gap := 5 * time.Millisecond
-		targetEnd := nextStart - gap
+		targetEnd := nextStart - cueGap
 		if currEnd > targetEnd {
 			currStart, err3 := ParseTimestamp(segments[i].StartTime)
 			if err3 != nil {
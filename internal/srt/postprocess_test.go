@@ -246,6 +246,44 @@ func TestPostprocess(t *testing.T) {
 	}
 }
 
+func TestPostprocessWithOptions_Combinations(t *testing.T) {
+	input := func() []Segment {
+		return []Segment{
+			{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:01,500", Lines: []string{"안녕하세요. 반갑습니다..."}},
+		}
+	}
+	const (
+		cleaned      = "안녕하세요, 반갑습니다…"
+		original     = "안녕하세요. 반갑습니다..."
+		correctedEnd = "00:00:02,083" // 13 chars / 12 cps
+		rawEnd       = "00:00:02,250" // uncleaned text is longer
+		originalEnd  = "00:00:01,500"
+	)
+
+	tests := []struct {
+		name      string
+		opts      PostprocessOptions
+		wantLine  string
+		wantEndTS string
+	}{
+		{"all", PostprocessOptions{}, cleaned, correctedEnd},
+		{"punctuation_only", PostprocessOptions{NoTimingCorrection: true}, cleaned, originalEnd},
+		{"timing_only", PostprocessOptions{NoLangRules: true}, original, rawEnd},
+		{"neither", PostprocessOptions{NoLangRules: true, NoTimingCorrection: true}, original, originalEnd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PostprocessWithOptions(input(), "ko", 12, tt.opts)
+			if got[0].Lines[0] != tt.wantLine {
+				t.Errorf("line = %q, want %q", got[0].Lines[0], tt.wantLine)
+			}
+			if got[0].EndTime != tt.wantEndTS {
+				t.Errorf("end = %s, want %s", got[0].EndTime, tt.wantEndTS)
+			}
+		})
+	}
+}
+
 func TestCorrectTiming(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -292,6 +330,28 @@ func TestCorrectTiming(t *testing.T) {
 				{ID: 2, StartTime: "00:00:02,001", EndTime: "00:00:03,000", Lines: []string{"Second"}},
 			},
 		},
+		{
+			name: "Zero duration expanded to minimum",
+			segments: []Segment{
+				{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:01,000", Lines: []string{"Hi"}},
+				{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"Next"}},
+			},
+			expected: []Segment{
+				{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:01,800", Lines: []string{"Hi"}},
+				{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"Next"}},
+			},
+		},
+		{
+			name: "Zero duration stops before next cue",
+			segments: []Segment{
+				{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:01,000", Lines: []string{"Hi"}},
+				{ID: 2, StartTime: "00:00:01,300", EndTime: "00:00:02,300", Lines: []string{"Next"}},
+			},
+			expected: []Segment{
+				{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:01,295", Lines: []string{"Hi"}},
+				{ID: 2, StartTime: "00:00:01,300", EndTime: "00:00:02,300", Lines: []string{"Next"}},
+			},
+		},
 		{
 			name: "Grapheme Cluster CPS",
 			segments: []Segment{
@@ -314,3 +374,83 @@ func TestCorrectTiming(t *testing.T) {
 		})
 	}
 }
+
+func TestZeroDurationSegments(t *testing.T) {
+	segments := []Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:01,000"},
+		{ID: 2, StartTime: "00:00:02,000", EndTime: "00:00:03,000"},
+		{ID: 3, StartTime: "00:00:04,500", EndTime: "00:00:04,500"},
+		{ID: 4, StartTime: "bad", EndTime: "bad"},
+	}
+	if got := ZeroDurationSegments(segments); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Fatalf("ZeroDurationSegments = %v, want [1 3]", got)
+	}
+}
+
+func TestPostprocessWithOptions_EmptiedSegments(t *testing.T) {
+	cases := []struct {
+		lang string
+		line string
+	}{
+		{"ko", "."},
+		{"ja", "。"},
+		{"zh-Hant", "。"},
+		{"zh-Hans", "。"},
+	}
+	input := func(line string) []Segment {
+		return []Segment{
+			{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{line}},
+			{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"OK"}},
+		}
+	}
+	for _, tc := range cases {
+		t.Run(tc.lang, func(t *testing.T) {
+			kept := PostprocessWithOptions(input(tc.line), tc.lang, 12, PostprocessOptions{NoTimingCorrection: true})
+			if len(kept) != 2 || !reflect.DeepEqual(kept[0].Lines, []string{tc.line}) {
+				t.Fatalf("keep policy = %+v, want the emptied segment with its text before cleanup", kept)
+			}
+			dropped := PostprocessWithOptions(input(tc.line), tc.lang, 12, PostprocessOptions{NoTimingCorrection: true, OnEmptied: EmptiedDrop})
+			if len(dropped) != 1 || dropped[0].ID != 2 {
+				t.Fatalf("drop policy = %+v, want only segment 2", dropped)
+			}
+		})
+	}
+}
+
+func TestParseEmptiedPolicy(t *testing.T) {
+	if p, err := ParseEmptiedPolicy(""); err != nil || p != EmptiedKeep {
+		t.Fatalf(`ParseEmptiedPolicy("") = %q, %v`, p, err)
+	}
+	if p, err := ParseEmptiedPolicy("drop"); err != nil || p != EmptiedDrop {
+		t.Fatalf(`ParseEmptiedPolicy("drop") = %q, %v`, p, err)
+	}
+	if _, err := ParseEmptiedPolicy("blank"); err == nil {
+		t.Fatal("expected an error for an unknown policy")
+	}
+}
+
+func TestPostprocessWithOptions_RulesLang(t *testing.T) {
+	input := func() []Segment {
+		return []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:03,000", Lines: []string{"你好,世界."}}}
+	}
+	got := PostprocessWithOptions(input(), "zh-Hans", 11, PostprocessOptions{NoTimingCorrection: true})
+	if got[0].Lines[0] != "你好 世界" {
+		t.Fatalf("Simplified rules = %q", got[0].Lines[0])
+	}
+	got = PostprocessWithOptions(input(), "zh-Hans", 11, PostprocessOptions{RulesLang: "zh-Hant", NoTimingCorrection: true})
+	if got[0].Lines[0] != "你好，世界" {
+		t.Fatalf("zh-Hans target with zh-Hant rules = %q, want Traditional punctuation", got[0].Lines[0])
+	}
+	got = PostprocessWithOptions(input(), "en", 11, PostprocessOptions{RulesLang: "zh-Hant", NoTimingCorrection: true})
+	if got[0].Lines[0] != "你好，世界" {
+		t.Fatalf("en target with zh-Hant rules = %q, want Traditional punctuation", got[0].Lines[0])
+	}
+}
+
+func TestHasPunctuationCleanup(t *testing.T) {
+	for lang, want := range map[string]bool{"ko": true, "ja": true, "zh": true, "zh-Hans": true, "zh-Hant": true, "en": false, "fr": false} {
+		if got := HasPunctuationCleanup(lang); got != want {
+			t.Errorf("HasPunctuationCleanup(%q) = %v, want %v", lang, got, want)
+		}
+	}
+}
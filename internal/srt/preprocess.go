@@ -48,13 +48,41 @@ func PreprocessWithMapping(segments []Segment, sourceLangCode string) ([]Segment
 // For WebVTT inputs, consecutive segments with identical start/end timestamps are
 // merged into one segment in appearance order before other preprocessing rules.
 func PreprocessForPathWithMappingOptions(segments []Segment, sourceLangCode, sourcePath string, applyLangRules bool) ([]Segment, []IDMap) {
-	normalized := normalizeBySourcePath(segments, sourcePath)
-	return PreprocessWithMappingOptions(normalized, sourceLangCode, applyLangRules)
+	return PreprocessSteps(segments, sourceLangCode, sourcePath, PreprocessOptions{NoLangRules: !applyLangRules})
 }
 
 // PreprocessWithMappingOptions performs preprocessing and returns ID mappings.
 // Language-specific rules can be disabled with applyLangRules=false.
 func PreprocessWithMappingOptions(segments []Segment, sourceLangCode string, applyLangRules bool) ([]Segment, []IDMap) {
+	return PreprocessSteps(segments, sourceLangCode, "", PreprocessOptions{NoLangRules: !applyLangRules})
+}
+
+// PreprocessOptions selects the language-specific preprocessing steps, which
+// only apply to Japanese ("ja") sources. The zero value runs them all.
+type PreprocessOptions struct {
+	// NoLangRules skips every language-specific step.
+	NoLangRules bool
+	// NoBracketRemoval keeps text within (), [], （）, and ［］.
+	NoBracketRemoval bool
+	// NoAngleStrip keeps "<" and ">" characters.
+	NoAngleStrip bool
+	// NoMeaninglessFilter keeps segments left with only symbols.
+	NoMeaninglessFilter bool
+}
+
+// PreprocessSteps performs preprocessing with the steps opts selects and
+// returns ID mappings. Every segment's lines are trimmed and segments left
+// without text are dropped. A non-empty sourcePath applies its
+// format-specific normalization first (see PreprocessForPathWithMappingOptions).
+func PreprocessSteps(segments []Segment, sourceLangCode, sourcePath string, opts PreprocessOptions) ([]Segment, []IDMap) {
+	if sourcePath != "" {
+		segments = normalizeBySourcePath(segments, sourcePath)
+	}
+	langRules := !opts.NoLangRules && sourceLangCode == "ja"
+	removeBrackets := langRules && !opts.NoBracketRemoval
+	stripAngles := langRules && !opts.NoAngleStrip
+	filterMeaningless := langRules && !opts.NoMeaninglessFilter
+
 	var cleaned []Segment
 	var originalIDs []int
 
@@ -62,10 +90,11 @@ func PreprocessWithMappingOptions(segments []Segment, sourceLangCode string, app
 		newLines := make([]string, 0, len(seg.Lines))
 		for _, line := range seg.Lines {
 			cleanedLine := line
-			if applyLangRules && sourceLangCode == "ja" {
-				cleanedLine = parenRegex.ReplaceAllString(line, "")
-				cleanedLine = strings.ReplaceAll(cleanedLine, "<", "")
-				cleanedLine = strings.ReplaceAll(cleanedLine, ">", "")
+			if removeBrackets {
+				cleanedLine = removeBracketedText(cleanedLine)
+			}
+			if stripAngles {
+				cleanedLine = stripAngleBrackets(cleanedLine)
 			}
 			cleanedLine = strings.TrimSpace(cleanedLine)
 			if cleanedLine != "" {
@@ -78,8 +107,8 @@ func PreprocessWithMappingOptions(segments []Segment, sourceLangCode string, app
 			continue
 		}
 
-		// Check if the remaining text is just symbols/punctuation (Japanese only)
-		if applyLangRules && sourceLangCode == "ja" && isMeaningless(newLines) {
+		// Check if the remaining text is just symbols/punctuation
+		if filterMeaningless && isMeaningless(newLines) {
 			continue
 		}
 
@@ -88,10 +117,7 @@ func PreprocessWithMappingOptions(segments []Segment, sourceLangCode string, app
 		cleaned = append(cleaned, seg)
 	}
 
-	// Re-reindex
-	for i := range cleaned {
-		cleaned[i].ID = i + 1
-	}
+	Renumber(cleaned)
 
 	mapping := make([]IDMap, 0, len(cleaned))
 	for i := range cleaned {
@@ -104,6 +130,17 @@ func PreprocessWithMappingOptions(segments []Segment, sourceLangCode string, app
 	return cleaned, mapping
 }
 
+// removeBracketedText drops text within (), [], （）, and ［］, e.g. speaker
+// labels and sound descriptions.
+func removeBracketedText(line string) string {
+	return parenRegex.ReplaceAllString(line, "")
+}
+
+// stripAngleBrackets removes "<" and ">" characters.
+func stripAngleBrackets(line string) string {
+	return strings.NewReplacer("<", "", ">", "").Replace(line)
+}
+
 func isMeaningless(lines []string) bool {
 	for _, line := range lines {
 		for _, r := range line {
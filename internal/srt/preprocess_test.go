@@ -95,6 +95,63 @@ func TestPreprocess(t *testing.T) {
 	}
 }
 
+func TestPreprocessSteps_EachStepIndependently(t *testing.T) {
+	segments := []Segment{
+		{ID: 1, Lines: []string{"(Note) <Hello>"}},
+		{ID: 2, Lines: []string{"!!!"}},
+		{ID: 3, Lines: []string{"[SFX]"}},
+	}
+	tests := []struct {
+		name     string
+		opts     PreprocessOptions
+		expected []Segment
+	}{
+		{
+			name:     "all steps",
+			expected: []Segment{{ID: 1, Lines: []string{"Hello"}}},
+		},
+		{
+			name: "no bracket removal",
+			opts: PreprocessOptions{NoBracketRemoval: true},
+			expected: []Segment{
+				{ID: 1, Lines: []string{"(Note) Hello"}},
+				{ID: 2, Lines: []string{"[SFX]"}},
+			},
+		},
+		{
+			name:     "no angle strip",
+			opts:     PreprocessOptions{NoAngleStrip: true},
+			expected: []Segment{{ID: 1, Lines: []string{"<Hello>"}}},
+		},
+		{
+			name: "no meaningless filter",
+			opts: PreprocessOptions{NoMeaninglessFilter: true},
+			expected: []Segment{
+				{ID: 1, Lines: []string{"Hello"}},
+				{ID: 2, Lines: []string{"!!!"}},
+			},
+		},
+		{
+			name: "no language rules overrides the steps",
+			opts: PreprocessOptions{NoLangRules: true},
+			expected: []Segment{
+				{ID: 1, Lines: []string{"(Note) <Hello>"}},
+				{ID: 2, Lines: []string{"!!!"}},
+				{ID: 3, Lines: []string{"[SFX]"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned, _ := PreprocessSteps(segments, "ja", "", tt.opts)
+			if !reflect.DeepEqual(cleaned, tt.expected) {
+				t.Errorf("PreprocessSteps() = %+v, want %+v", cleaned, tt.expected)
+			}
+		})
+	}
+}
+
 func TestPreprocess_FullwidthBracketsNotAppliedForNonJapanese(t *testing.T) {
 	segments := []Segment{
 		{ID: 1, Lines: []string{"Hello（world）", "［Action］ Good morning"}},
@@ -0,0 +1,48 @@
+package srt
+
+import "strings"
+
+// Provenance records how an output file was produced. SaveWithOptions writes
+// it as a comment block for formats with comment syntax, so players ignore it.
+type Provenance struct {
+	Tool         string // e.g. "focst 0.1.4"
+	Model        string
+	SourceLang   string
+	TargetLang   string
+	Date         string // RFC3339, UTC
+	SettingsHash string
+}
+
+// SupportsProvenance reports whether the output format at path (or the
+// explicit format, when set) can carry a provenance comment block. SRT has
+// none: a fake cue would be shown on screen.
+func SupportsProvenance(path, format string) bool {
+	switch FormatExt(path, format) {
+	case ".vtt", ".ass", ".ssa":
+		return true
+	default:
+		return false
+	}
+}
+
+// commentLines renders the provenance as "key: value" lines, omitting empty
+// fields. "-->" is removed because it would end a WebVTT NOTE block early.
+func (p Provenance) commentLines() []string {
+	fields := []struct{ key, value string }{
+		{"generated by", p.Tool},
+		{"model", p.Model},
+		{"source", p.SourceLang},
+		{"target", p.TargetLang},
+		{"date", p.Date},
+		{"settings", p.SettingsHash},
+	}
+	var lines []string
+	for _, f := range fields {
+		value := strings.TrimSpace(strings.NewReplacer("-->", "", "\r", " ", "\n", " ").Replace(f.value))
+		if value == "" {
+			continue
+		}
+		lines = append(lines, f.key+": "+value)
+	}
+	return lines
+}
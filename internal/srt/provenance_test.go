@@ -0,0 +1,76 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveWithOptions_ProvenanceRoundTrip(t *testing.T) {
+	segments := []Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"안녕하세요"}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"반갑습니다"}},
+	}
+	prov := &Provenance{
+		Tool:         "focst 0.1.4",
+		Model:        "gemini-3-flash-preview",
+		SourceLang:   "ja",
+		TargetLang:   "ko",
+		Date:         "2026-01-30T12:00:00Z",
+		SettingsHash: "0123456789ab",
+	}
+
+	tests := []struct {
+		ext  string
+		want string // marker expected in the file; empty means not embedded
+	}{
+		{ext: ".vtt", want: "NOTE generated by: focst 0.1.4"},
+		{ext: ".ass", want: "; generated by: focst 0.1.4"},
+		{ext: ".ssa", want: "; model: gemini-3-flash-preview"},
+		{ext: ".srt", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out"+tt.ext)
+			if err := SaveWithOptions(path, segments, SaveOptions{Provenance: prov}); err != nil {
+				t.Fatalf("SaveWithOptions failed: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			content := string(data)
+			if tt.want != "" && !strings.Contains(content, tt.want) {
+				t.Fatalf("expected %q in output:\n%s", tt.want, content)
+			}
+			if tt.want == "" && strings.Contains(content, "focst") {
+				t.Fatalf("expected no metadata in %s output:\n%s", tt.ext, content)
+			}
+			if SupportsProvenance(path, "") != (tt.want != "") {
+				t.Fatalf("SupportsProvenance(%q) = %v", path, SupportsProvenance(path, ""))
+			}
+
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("reloading output with metadata failed: %v", err)
+			}
+			if len(loaded) != len(segments) {
+				t.Fatalf("reloaded %d segments, want %d", len(loaded), len(segments))
+			}
+			for i := range segments {
+				if strings.Join(loaded[i].Lines, "\n") != strings.Join(segments[i].Lines, "\n") {
+					t.Fatalf("segment %d lines = %v, want %v", i, loaded[i].Lines, segments[i].Lines)
+				}
+			}
+		})
+	}
+}
+
+func TestProvenance_CommentLinesSanitized(t *testing.T) {
+	lines := Provenance{Tool: "focst", Model: "a-->b\nc"}.commentLines()
+	if len(lines) != 2 || lines[1] != "model: ab c" {
+		t.Fatalf("unexpected comment lines: %q", lines)
+	}
+}
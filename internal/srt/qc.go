@@ -0,0 +1,152 @@
+package srt
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rivo/uniseg"
+)
+
+// QCOptions sets the limits CheckQuality measures segments against.
+type QCOptions struct {
+	// MaxCPS is the reading-speed limit in characters per second.
+	MaxCPS float64
+	// MaxCPL is the line-length limit in characters (grapheme clusters).
+	MaxCPL int
+}
+
+// QCDistribution summarizes reading speeds across segments.
+type QCDistribution struct {
+	Min    float64
+	Mean   float64
+	Median float64
+	P95    float64
+	Max    float64
+}
+
+// QCReport holds the reading-speed, line-length, and timing statistics of a
+// subtitle file. Segment lists hold subtitle IDs.
+type QCReport struct {
+	Segments int
+	// Measured counts segments with text and a positive duration; only these
+	// contribute to CPS.
+	Measured int
+	// InvalidTiming lists segments with unparsable timestamps or an end that
+	// is not after the start.
+	InvalidTiming []int
+	CPS           QCDistribution
+	OverCPS       []int
+	// LongLines lists segments with at least one line over MaxCPL.
+	LongLines     []int
+	MaxLineLength int
+	MinDuration   time.Duration
+	MaxDuration   time.Duration
+	// MinGap is the smallest gap between consecutive segments; negative when
+	// they overlap.
+	MinGap   time.Duration
+	Overlaps []int
+}
+
+// Violations counts segments breaking the CPS limit plus segments breaking
+// the CPL limit.
+func (r QCReport) Violations() int {
+	return len(r.OverCPS) + len(r.LongLines)
+}
+
+// CheckQuality measures segments against opts without modifying them.
+// Characters are counted as grapheme clusters, as in post-processing timing.
+func CheckQuality(segments []Segment, opts QCOptions) QCReport {
+	report := QCReport{Segments: len(segments)}
+	var speeds []float64
+	haveDuration, haveGap := false, false
+	var prevEnd time.Duration
+	prevValid := false
+
+	for _, seg := range segments {
+		chars := 0
+		longLine := false
+		for _, line := range seg.Lines {
+			n := uniseg.GraphemeClusterCount(line)
+			chars += n
+			if n > report.MaxLineLength {
+				report.MaxLineLength = n
+			}
+			if opts.MaxCPL > 0 && n > opts.MaxCPL {
+				longLine = true
+			}
+		}
+		if longLine {
+			report.LongLines = append(report.LongLines, seg.ID)
+		}
+
+		start, err1 := ParseTimestamp(seg.StartTime)
+		end, err2 := ParseTimestamp(seg.EndTime)
+		if err1 != nil || err2 != nil || end <= start {
+			report.InvalidTiming = append(report.InvalidTiming, seg.ID)
+			prevValid = false
+			continue
+		}
+
+		duration := end - start
+		if !haveDuration || duration < report.MinDuration {
+			report.MinDuration = duration
+		}
+		if !haveDuration || duration > report.MaxDuration {
+			report.MaxDuration = duration
+		}
+		haveDuration = true
+
+		if prevValid {
+			gap := start - prevEnd
+			if !haveGap || gap < report.MinGap {
+				report.MinGap = gap
+			}
+			haveGap = true
+			if gap < 0 {
+				report.Overlaps = append(report.Overlaps, seg.ID)
+			}
+		}
+		prevEnd, prevValid = end, true
+
+		if chars == 0 {
+			continue
+		}
+		cps := float64(chars) / duration.Seconds()
+		speeds = append(speeds, cps)
+		if opts.MaxCPS > 0 && cps > opts.MaxCPS {
+			report.OverCPS = append(report.OverCPS, seg.ID)
+		}
+	}
+
+	report.Measured = len(speeds)
+	report.CPS = distribution(speeds)
+	return report
+}
+
+func distribution(values []float64) QCDistribution {
+	if len(values) == 0 {
+		return QCDistribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	return QCDistribution{
+		Min:    sorted[0],
+		Mean:   sum / float64(len(sorted)),
+		Median: percentile(sorted, 50),
+		P95:    percentile(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p int) float64 {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
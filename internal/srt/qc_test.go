@@ -0,0 +1,56 @@
+package srt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckQuality(t *testing.T) {
+	segments := []Segment{
+		// 10 chars over 1s: 10 CPS.
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"Hello you!"}},
+		// 20 chars over 0.5s: 40 CPS, and the line is over 15 CPL.
+		{ID: 2, StartTime: "00:00:02,500", EndTime: "00:00:03,000", Lines: []string{"Twenty characters!!!"}},
+		// Overlaps segment 2; 4 graphemes over 2s: 2 CPS.
+		{ID: 3, StartTime: "00:00:02,900", EndTime: "00:00:04,900", Lines: []string{"안녕하세"}},
+		{ID: 4, StartTime: "00:00:06,000", EndTime: "00:00:05,000", Lines: []string{"Backwards"}},
+		// No text: timing counts, CPS does not.
+		{ID: 5, StartTime: "00:00:07,000", EndTime: "00:00:10,000", Lines: nil},
+	}
+
+	report := CheckQuality(segments, QCOptions{MaxCPS: 17, MaxCPL: 15})
+
+	if report.Segments != 5 || report.Measured != 3 {
+		t.Fatalf("Segments/Measured = %d/%d, want 5/3", report.Segments, report.Measured)
+	}
+	if !reflect.DeepEqual(report.OverCPS, []int{2}) {
+		t.Errorf("OverCPS = %v, want [2]", report.OverCPS)
+	}
+	if !reflect.DeepEqual(report.LongLines, []int{2}) || report.MaxLineLength != 20 {
+		t.Errorf("LongLines = %v, MaxLineLength = %d", report.LongLines, report.MaxLineLength)
+	}
+	if !reflect.DeepEqual(report.InvalidTiming, []int{4}) {
+		t.Errorf("InvalidTiming = %v, want [4]", report.InvalidTiming)
+	}
+	if !reflect.DeepEqual(report.Overlaps, []int{3}) || report.MinGap != -100*time.Millisecond {
+		t.Errorf("Overlaps = %v, MinGap = %v", report.Overlaps, report.MinGap)
+	}
+	if report.MinDuration != 500*time.Millisecond || report.MaxDuration != 3*time.Second {
+		t.Errorf("durations = %v..%v", report.MinDuration, report.MaxDuration)
+	}
+	want := QCDistribution{Min: 2, Mean: 52.0 / 3, Median: 10, P95: 40, Max: 40}
+	if report.CPS != want {
+		t.Errorf("CPS = %+v, want %+v", report.CPS, want)
+	}
+	if report.Violations() != 2 {
+		t.Errorf("Violations = %d, want 2", report.Violations())
+	}
+}
+
+func TestCheckQuality_Empty(t *testing.T) {
+	report := CheckQuality(nil, QCOptions{MaxCPS: 17, MaxCPL: 42})
+	if report.Measured != 0 || report.CPS != (QCDistribution{}) || report.Violations() != 0 {
+		t.Fatalf("unexpected report for empty input: %+v", report)
+	}
+}
@@ -0,0 +1,114 @@
+package srt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// quoteStyle holds the conventional quotation marks of a language. Empty
+// single marks leave straight single quotes as they are.
+type quoteStyle struct {
+	open, close             string
+	singleOpen, singleClose string
+	// spaced puts a no-break space inside the marks, as French does.
+	spaced bool
+}
+
+var quoteStyles = map[string]quoteStyle{
+	"en": {open: "“", close: "”", singleOpen: "‘", singleClose: "’"},
+	"de": {open: "„", close: "“", singleOpen: "‚", singleClose: "‘"},
+	"fr": {open: "«", close: "»", spaced: true},
+	"es": {open: "«", close: "»"},
+	"it": {open: "«", close: "»"},
+	"ru": {open: "«", close: "»"},
+	"pl": {open: "„", close: "”"},
+	"pt": {open: "“", close: "”", singleOpen: "‘", singleClose: "’"},
+}
+
+// normalizeQuotes replaces straight quotes in seg with the quotation marks of
+// langCode. Languages without a known style are left alone.
+func normalizeQuotes(seg Segment, langCode string) Segment {
+	style, ok := quoteStyles[langCode]
+	if !ok {
+		return seg
+	}
+	lines := make([]string, len(seg.Lines))
+	for i, line := range seg.Lines {
+		lines[i] = style.apply(line)
+	}
+	seg.Lines = lines
+	return seg
+}
+
+// apply converts the straight quotes of one line. A quote opens at the start
+// of the line or after a space or an opening bracket and closes otherwise.
+// Single quotes between letters are apostrophes (don't, l'homme) and stay, and
+// the other single quotes are converted only in open/close pairs so a lone
+// elision like "runnin'" is kept.
+func (s quoteStyle) apply(line string) string {
+	if !strings.ContainsAny(line, `"'`) {
+		return line
+	}
+	runes := []rune(line)
+	out := make([]string, len(runes))
+	for i, r := range runes {
+		out[i] = string(r)
+	}
+	pendingSingle := -1
+	for i, r := range runes {
+		switch r {
+		case '"':
+			if quoteOpens(runes, i) {
+				out[i] = s.open
+				if s.spaced {
+					out[i] += "\u00a0"
+				}
+			} else {
+				out[i] = s.close
+				if s.spaced {
+					out[i] = "\u00a0" + out[i]
+					trimSpacesBefore(out, runes, i)
+				}
+			}
+		case '\'':
+			if s.singleOpen == "" || isApostrophe(runes, i) {
+				continue
+			}
+			if quoteOpens(runes, i) {
+				pendingSingle = i
+			} else if pendingSingle >= 0 {
+				out[pendingSingle] = s.singleOpen
+				out[i] = s.singleClose
+				pendingSingle = -1
+			}
+		}
+	}
+	return strings.Join(out, "")
+}
+
+func quoteOpens(runes []rune, i int) bool {
+	if i+1 >= len(runes) || unicode.IsSpace(runes[i+1]) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	prev := runes[i-1]
+	return unicode.IsSpace(prev) || strings.ContainsRune("([{<>}—–-", prev)
+}
+
+func isApostrophe(runes []rune, i int) bool {
+	return i > 0 && i+1 < len(runes) && isWordRune(runes[i-1]) && isWordRune(runes[i+1])
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// trimSpacesBefore drops the spaces preceding the closing mark at i, which
+// carries its own no-break space.
+func trimSpacesBefore(out []string, runes []rune, i int) {
+	for j := i - 1; j >= 0 && runes[j] == ' '; j-- {
+		out[j] = ""
+	}
+}
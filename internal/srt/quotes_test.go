@@ -0,0 +1,50 @@
+package srt
+
+import "testing"
+
+func TestNormalizeQuotes(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		line string
+		want string
+	}{
+		{"english double", "en", `She said "hello" to me.`, "She said “hello” to me."},
+		{"english apostrophes kept", "en", `I don't know, it's "fine".`, "I don't know, it's “fine”."},
+		{"english single pair", "en", `He said 'wait' and left.`, "He said ‘wait’ and left."},
+		{"english lone elision kept", "en", `Keep runnin' now.`, "Keep runnin' now."},
+		{"english after dash", "en", `-"Go!"`, "-“Go!”"},
+		{"french guillemets", "fr", `Il a dit "bonjour".`, "Il a dit «\u00a0bonjour\u00a0»."},
+		{"french elision kept", "fr", `"C'est l'homme."`, "«\u00a0C'est l'homme.\u00a0»"},
+		{"french space before close", "fr", `"Oui "`, "«\u00a0Oui\u00a0»"},
+		{"german", "de", `Er sagte "Hallo".`, "Er sagte „Hallo“."},
+		{"unknown language", "ko", `"안녕"`, `"안녕"`},
+		{"no quotes", "en", "Plain line", "Plain line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeQuotes(Segment{Lines: []string{tt.line}}, tt.lang)
+			if got.Lines[0] != tt.want {
+				t.Errorf("got %q, want %q", got.Lines[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestPostprocessWithOptions_NormalizeQuotes(t *testing.T) {
+	input := func() []Segment {
+		return []Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:03,000", Lines: []string{`"Don't."`}}}
+	}
+	got := PostprocessWithOptions(input(), "en", 12, PostprocessOptions{NormalizeQuotes: true, NoTimingCorrection: true})
+	if got[0].Lines[0] != "“Don't.”" {
+		t.Errorf("normalized = %q", got[0].Lines[0])
+	}
+	got = PostprocessWithOptions(input(), "en", 12, PostprocessOptions{NoTimingCorrection: true})
+	if got[0].Lines[0] != `"Don't."` {
+		t.Errorf("without NormalizeQuotes = %q, want it unchanged", got[0].Lines[0])
+	}
+	got = PostprocessWithOptions(input(), "en", 12, PostprocessOptions{NormalizeQuotes: true, NoLangRules: true, NoTimingCorrection: true})
+	if got[0].Lines[0] != `"Don't."` {
+		t.Errorf("with NoLangRules = %q, want it unchanged", got[0].Lines[0])
+	}
+}
@@ -0,0 +1,95 @@
+package srt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SelectSegments returns the indices (ascending) of segments chosen for translation.
+// A segment is selected when its lines joined by "\n" match pattern and, if
+// forcedOnly is set, it is flagged Forced. A nil pattern matches every segment.
+func SelectSegments(segments []Segment, pattern *regexp.Regexp, forcedOnly bool) []int {
+	selected := make([]int, 0, len(segments))
+	for i, seg := range segments {
+		if forcedOnly && !seg.Forced {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(strings.Join(seg.Lines, "\n")) {
+			continue
+		}
+		selected = append(selected, i)
+	}
+	return selected
+}
+
+// IDRange is an inclusive range of segment IDs.
+type IDRange struct {
+	First, Last int
+}
+
+// ParseIDRanges parses a comma-separated list of segment IDs and inclusive
+// ranges, e.g. "10-20,45".
+func ParseIDRanges(s string) ([]IDRange, error) {
+	var ranges []IDRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("invalid segment ID list %q: empty entry", s)
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		lo, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil || lo < 1 {
+			return nil, fmt.Errorf("invalid segment ID %q: must be a positive integer", part)
+		}
+		hi := lo
+		if isRange {
+			hi, err = strconv.Atoi(strings.TrimSpace(last))
+			if err != nil || hi < 1 {
+				return nil, fmt.Errorf("invalid segment ID %q: must be a positive integer", part)
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("invalid segment ID range %q: end is before start", part)
+			}
+		}
+		ranges = append(ranges, IDRange{First: lo, Last: hi})
+	}
+	return ranges, nil
+}
+
+// ContainsID reports whether id falls in any of ranges.
+func ContainsID(ranges []IDRange, id int) bool {
+	for _, r := range ranges {
+		if id >= r.First && id <= r.Last {
+			return true
+		}
+	}
+	return false
+}
+
+// ExcludeIDs removes segments whose ID falls in ranges from selected (every
+// segment when selected is nil), keeping ascending order. It returns selected
+// unchanged when nothing is excluded.
+func ExcludeIDs(segments []Segment, selected []int, ranges []IDRange) []int {
+	var candidates []int
+	if selected == nil {
+		candidates = make([]int, len(segments))
+		for i := range segments {
+			candidates[i] = i
+		}
+	} else {
+		candidates = selected
+	}
+	kept := make([]int, 0, len(candidates))
+	for _, idx := range candidates {
+		if idx < len(segments) && ContainsID(ranges, segments[idx].ID) {
+			continue
+		}
+		kept = append(kept, idx)
+	}
+	if len(kept) == len(candidates) {
+		return selected
+	}
+	return kept
+}
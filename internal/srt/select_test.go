@@ -0,0 +1,100 @@
+package srt
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSelectSegments(t *testing.T) {
+	segments := []Segment{
+		{ID: 1, Lines: []string{"Hello"}},
+		{ID: 2, Lines: []string{"[SIGN] Exit"}, Forced: true},
+		{ID: 3, Lines: []string{"Bye", "[SIGN] Shop"}},
+		{ID: 4, Lines: []string{"Thanks"}, Forced: true},
+	}
+
+	tests := []struct {
+		name       string
+		pattern    *regexp.Regexp
+		forcedOnly bool
+		want       []int
+	}{
+		{name: "no filter selects all", want: []int{0, 1, 2, 3}},
+		{name: "regex across lines", pattern: regexp.MustCompile(`(?m)^\[SIGN\]`), want: []int{1, 2}},
+		{name: "forced only", forcedOnly: true, want: []int{1, 3}},
+		{name: "regex and forced", pattern: regexp.MustCompile(`SIGN`), forcedOnly: true, want: []int{1}},
+		{name: "no match", pattern: regexp.MustCompile(`nothing`), want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SelectSegments(segments, tt.pattern, tt.forcedOnly)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("SelectSegments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_ASSForcedStyle(t *testing.T) {
+	content := `[Script Info]
+ScriptType: v4.00+
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+Style: Signs-Forced,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,8,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,Hello
+Dialogue: 0,0:00:03.00,0:00:04.00,Signs-Forced,,0,0,0,,Exit
+`
+	path := filepath.Join(t.TempDir(), "forced.ass")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write ass: %v", err)
+	}
+	segments, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(segments))
+	}
+	if segments[0].Forced || !segments[1].Forced {
+		t.Fatalf("unexpected forced flags: %v, %v", segments[0].Forced, segments[1].Forced)
+	}
+}
+
+func TestParseIDRanges(t *testing.T) {
+	got, err := ParseIDRanges("10-20, 45,3 - 4")
+	if err != nil {
+		t.Fatalf("ParseIDRanges: %v", err)
+	}
+	want := []IDRange{{10, 20}, {45, 45}, {3, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseIDRanges = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"", "1,,2", "a", "0", "5-3", "2-", "-2"} {
+		if _, err := ParseIDRanges(bad); err == nil {
+			t.Errorf("ParseIDRanges(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestExcludeIDs(t *testing.T) {
+	segments := []Segment{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	ranges := []IDRange{{2, 3}, {5, 5}}
+	if got := ExcludeIDs(segments, nil, ranges); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Errorf("ExcludeIDs(nil) = %v, want [0 3]", got)
+	}
+	if got := ExcludeIDs(segments, []int{1, 3}, ranges); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("ExcludeIDs([1 3]) = %v, want [3]", got)
+	}
+	if got := ExcludeIDs(segments, nil, []IDRange{{9, 10}}); got != nil {
+		t.Errorf("ExcludeIDs without matches = %v, want nil", got)
+	}
+}
@@ -0,0 +1,103 @@
+package srt
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Part is one piece of a split subtitle file. Its segments are copies,
+// renumbered from 1, with their original timings.
+type Part struct {
+	// Offset is where the part begins on the original timeline: the start of
+	// its window for duration splits, the first cue's start for count splits.
+	Offset   time.Duration
+	Segments []Segment
+}
+
+// SplitByCount cuts segments into parts of at most n segments each, in order.
+func SplitByCount(segments []Segment, n int) ([]Part, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("segment count must be positive, got %d", n)
+	}
+	var parts []Part
+	for start := 0; start < len(segments); start += n {
+		end := min(start+n, len(segments))
+		part := newPart(segments[start:end])
+		if offset, err := ParseTimestamp(part.Segments[0].StartTime); err == nil {
+			part.Offset = offset
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// SplitByDuration cuts the timeline into windows of length d and puts each
+// segment in the window its start time falls in; a cue that runs past a
+// window's end stays whole. Windows without cues produce no part. Segments
+// must be in start-time order (see SortByStart).
+func SplitByDuration(segments []Segment, d time.Duration) ([]Part, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("split duration must be positive, got %s", d)
+	}
+	var parts []Part
+	first, window := 0, time.Duration(-1)
+	for i, seg := range segments {
+		start, err := ParseTimestamp(seg.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("segment %d: %w", seg.ID, err)
+		}
+		w := start / d
+		if w < window {
+			return nil, fmt.Errorf("segment %d starts before the previous segment; sort segments by start time first", seg.ID)
+		}
+		if w != window {
+			if i > first {
+				parts = append(parts, windowPart(segments[first:i], window*d))
+			}
+			first, window = i, w
+		}
+	}
+	if len(segments) > first {
+		parts = append(parts, windowPart(segments[first:], window*d))
+	}
+	return parts, nil
+}
+
+func windowPart(segments []Segment, offset time.Duration) Part {
+	part := newPart(segments)
+	part.Offset = offset
+	return part
+}
+
+func newPart(segments []Segment) Part {
+	copied := append([]Segment(nil), segments...)
+	Renumber(copied)
+	return Part{Segments: copied}
+}
+
+// Shift moves every segment's start and end time by delta. Times that would
+// fall before zero are clamped to zero.
+func Shift(segments []Segment, delta time.Duration) error {
+	for i := range segments {
+		start, err := ParseTimestamp(segments[i].StartTime)
+		if err != nil {
+			return fmt.Errorf("segment %d: %w", segments[i].ID, err)
+		}
+		end, err := ParseTimestamp(segments[i].EndTime)
+		if err != nil {
+			return fmt.Errorf("segment %d: %w", segments[i].ID, err)
+		}
+		segments[i].StartTime = FormatTimestamp(start + delta)
+		segments[i].EndTime = FormatTimestamp(end + delta)
+	}
+	return nil
+}
+
+// PartPath returns the path of part n (1-based) of path, e.g. "in.part1.srt"
+// for "in.srt".
+func PartPath(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.part%d%s", strings.TrimSuffix(path, ext), n, ext)
+}
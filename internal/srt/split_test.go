@@ -0,0 +1,100 @@
+package srt
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func splitFixture() []Segment {
+	starts := []string{"00:00:01,000", "00:00:05,000", "00:00:12,000", "00:00:29,500", "00:00:31,000"}
+	segments := make([]Segment, len(starts))
+	for i, start := range starts {
+		at, _ := ParseTimestamp(start)
+		segments[i] = Segment{ID: i + 1, StartTime: start, EndTime: FormatTimestamp(at + 2*time.Second), Lines: []string{fmt.Sprintf("line %d", i+1)}}
+	}
+	return segments
+}
+
+func collectLines(t *testing.T, parts []Part) []string {
+	t.Helper()
+	var lines []string
+	for _, part := range parts {
+		for i, seg := range part.Segments {
+			if seg.ID != i+1 {
+				t.Fatalf("part segment %d has ID %d, want %d", i, seg.ID, i+1)
+			}
+			lines = append(lines, seg.Lines...)
+		}
+	}
+	return lines
+}
+
+func TestSplitByCount(t *testing.T) {
+	segments := splitFixture()
+	parts, err := SplitByCount(segments, 2)
+	if err != nil {
+		t.Fatalf("SplitByCount: %v", err)
+	}
+	if len(parts) != 3 || len(parts[2].Segments) != 1 {
+		t.Fatalf("expected parts of 2, 2, 1 segments, got %d parts", len(parts))
+	}
+	if got := fmt.Sprint(collectLines(t, parts)); got != "[line 1 line 2 line 3 line 4 line 5]" {
+		t.Fatalf("segments not preserved: %s", got)
+	}
+	if parts[1].Offset != 12*time.Second {
+		t.Errorf("part 2 offset = %v, want 12s", parts[1].Offset)
+	}
+	if segments[2].ID != 3 {
+		t.Errorf("SplitByCount renumbered the input")
+	}
+	if _, err := SplitByCount(segments, 0); err == nil {
+		t.Errorf("expected error for zero count")
+	}
+}
+
+func TestSplitByDuration(t *testing.T) {
+	segments := splitFixture()
+	parts, err := SplitByDuration(segments, 10*time.Second)
+	if err != nil {
+		t.Fatalf("SplitByDuration: %v", err)
+	}
+	// Windows 0-10s, 10-20s, and 20-30s hold cues; 30-40s holds the last one.
+	wantOffsets := []time.Duration{0, 10 * time.Second, 20 * time.Second, 30 * time.Second}
+	if len(parts) != len(wantOffsets) {
+		t.Fatalf("got %d parts, want %d", len(parts), len(wantOffsets))
+	}
+	for i, want := range wantOffsets {
+		if parts[i].Offset != want {
+			t.Errorf("part %d offset = %v, want %v", i+1, parts[i].Offset, want)
+		}
+	}
+	if got := fmt.Sprint(collectLines(t, parts)); got != "[line 1 line 2 line 3 line 4 line 5]" {
+		t.Fatalf("segments not preserved: %s", got)
+	}
+	// The 29.5s cue ends past its window but stays whole.
+	if parts[2].Segments[0].EndTime != "00:00:31,500" {
+		t.Errorf("cue crossing a boundary changed: %+v", parts[2].Segments[0])
+	}
+
+	if err := Shift(parts[2].Segments, -parts[2].Offset); err != nil {
+		t.Fatalf("Shift: %v", err)
+	}
+	if seg := parts[2].Segments[0]; seg.StartTime != "00:00:09,500" || seg.EndTime != "00:00:11,500" {
+		t.Errorf("rebased cue = %s --> %s, want 00:00:09,500 --> 00:00:11,500", seg.StartTime, seg.EndTime)
+	}
+	if segments[3].StartTime != "00:00:29,500" {
+		t.Errorf("Shift on a part modified the input: %+v", segments[3])
+	}
+
+	unsorted := []Segment{segments[2], segments[0]}
+	if _, err := SplitByDuration(unsorted, 10*time.Second); err == nil {
+		t.Errorf("expected error for segments out of order")
+	}
+}
+
+func TestPartPath(t *testing.T) {
+	if got := PartPath("/tmp/in.srt", 2); got != "/tmp/in.part2.srt" {
+		t.Fatalf("PartPath = %q", got)
+	}
+}
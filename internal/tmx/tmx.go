@@ -0,0 +1,119 @@
+// Package tmx reads and writes translation memories in TMX 1.4, the exchange
+// format of CAT tools.
+package tmx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/oukeidos/focst/internal/version"
+)
+
+// Unit is one translation unit: a source segment and its translation.
+// Multi-line subtitles keep their lines separated by "\n".
+type Unit struct {
+	Source string
+	Target string
+}
+
+type document struct {
+	XMLName xml.Name `xml:"tmx"`
+	Version string   `xml:"version,attr"`
+	Header  header   `xml:"header"`
+	Units   []tu     `xml:"body>tu"`
+}
+
+type header struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+type tu struct {
+	Variants []tuv `xml:"tuv"`
+}
+
+type tuv struct {
+	Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	// LegacyLang reads the unprefixed lang attribute of TMX 1.1 files. It
+	// also matches xml:lang, so Lang takes precedence.
+	LegacyLang string `xml:"lang,attr,omitempty"`
+	Seg        string `xml:"seg"`
+}
+
+func (v tuv) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.LegacyLang
+}
+
+// Write encodes units as a TMX 1.4 document whose variants are tagged with
+// srcLang and tgtLang (e.g. "ja" and "ko").
+func Write(w io.Writer, srcLang, tgtLang string, units []Unit) error {
+	doc := document{
+		Version: "1.4",
+		Header: header{
+			CreationTool:        "focst",
+			CreationToolVersion: version.Version,
+			SegType:             "block",
+			OTMF:                "focst",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+		},
+		Units: make([]tu, len(units)),
+	}
+	for i, u := range units {
+		doc.Units[i] = tu{Variants: []tuv{{Lang: srcLang, Seg: u.Source}, {Lang: tgtLang, Seg: u.Target}}}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode TMX: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Read decodes a TMX document and returns the units that have a variant in
+// both srcLang and tgtLang. Language tags match case-insensitively, and a
+// regional tag matches its base language, so "ja-JP" matches "ja". Inline
+// elements inside a segment, such as <ph> placeholders, are skipped.
+func Read(r io.Reader, srcLang, tgtLang string) ([]Unit, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse TMX: %w", err)
+	}
+	var units []Unit
+	for _, u := range doc.Units {
+		var src, tgt string
+		for _, v := range u.Variants {
+			switch {
+			case src == "" && langMatches(v.lang(), srcLang):
+				src = v.Seg
+			case tgt == "" && langMatches(v.lang(), tgtLang):
+				tgt = v.Seg
+			}
+		}
+		if strings.TrimSpace(src) != "" && strings.TrimSpace(tgt) != "" {
+			units = append(units, Unit{Source: src, Target: tgt})
+		}
+	}
+	return units, nil
+}
+
+// langMatches reports whether the TMX language tag tag denotes lang.
+func langMatches(tag, lang string) bool {
+	tag, lang = strings.ToLower(tag), strings.ToLower(lang)
+	return tag == lang || strings.HasPrefix(tag, lang+"-")
+}
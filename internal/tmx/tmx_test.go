@@ -0,0 +1,111 @@
+package tmx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrite_ValidTMX14(t *testing.T) {
+	var buf bytes.Buffer
+	units := []Unit{{Source: "こんにちは", Target: "안녕하세요"}}
+	if err := Write(&buf, "ja", "ko", units); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Fatalf("missing XML declaration:\n%s", out)
+	}
+
+	// Walk the document with a strict decoder and check the elements and
+	// attributes TMX 1.4 requires.
+	dec := xml.NewDecoder(strings.NewReader(out))
+	dec.Strict = true
+	var path []string
+	seen := map[string]map[string]string{}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("not well-formed XML: %v\n%s", err, out)
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			path = append(path, el.Name.Local)
+			attrs := map[string]string{}
+			for _, a := range el.Attr {
+				name := a.Name.Local
+				if a.Name.Space == "http://www.w3.org/XML/1998/namespace" {
+					name = "xml:" + name
+				}
+				attrs[name] = a.Value
+			}
+			seen[strings.Join(path, "/")] = attrs
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+	if seen["tmx"]["version"] != "1.4" {
+		t.Fatalf("tmx version = %q, want 1.4", seen["tmx"]["version"])
+	}
+	for _, attr := range []string{"creationtool", "creationtoolversion", "segtype", "o-tmf", "adminlang", "srclang", "datatype"} {
+		if seen["tmx/header"][attr] == "" {
+			t.Fatalf("header lacks required attribute %q: %v", attr, seen["tmx/header"])
+		}
+	}
+	if seen["tmx/header"]["srclang"] != "ja" {
+		t.Fatalf("srclang = %q, want ja", seen["tmx/header"]["srclang"])
+	}
+	if _, ok := seen["tmx/body/tu/tuv/seg"]; !ok {
+		t.Fatalf("missing tmx/body/tu/tuv/seg:\n%s", out)
+	}
+	if !strings.Contains(out, `xml:lang="ko"`) {
+		t.Fatalf("variants must use xml:lang:\n%s", out)
+	}
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	units := []Unit{
+		{Source: "こんにちは", Target: "안녕하세요"},
+		{Source: "一行目\n二行目", Target: "첫 줄\n둘째 줄"},
+		{Source: "A & B <x>", Target: "A & B <x> \"q\""},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "ja", "ko", units); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	got, err := Read(&buf, "ja", "ko")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, units) {
+		t.Fatalf("round trip = %q, want %q", got, units)
+	}
+}
+
+func TestRead_MatchesLanguageTags(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<tmx version="1.4"><header creationtool="x" creationtoolversion="1" segtype="sentence" o-tmf="x" adminlang="en" srclang="JA-JP" datatype="plaintext"/>
+<body>
+<tu><tuv xml:lang="ko-KR"><seg>안녕</seg></tuv><tuv xml:lang="JA-JP"><seg>やあ</seg></tuv></tu>
+<tu><tuv lang="ja"><seg>さようなら</seg></tuv><tuv lang="ko"><seg>잘 가<ph x="1"/>요</seg></tuv></tu>
+<tu><tuv xml:lang="ja"><seg>英語だけ</seg></tuv><tuv xml:lang="en"><seg>English only</seg></tuv></tu>
+</body></tmx>`
+	got, err := Read(strings.NewReader(doc), "ja", "ko")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	want := []Unit{{Source: "やあ", Target: "안녕"}, {Source: "さようなら", Target: "잘 가요"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Read = %q, want %q", got, want)
+	}
+
+	if _, err := Read(strings.NewReader("<tmx><body>"), "ja", "ko"); err == nil {
+		t.Fatal("expected an error for malformed TMX")
+	}
+}
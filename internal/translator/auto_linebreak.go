@@ -0,0 +1,104 @@
+package translator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+)
+
+// clausePunct ends a clause; a line break right after one reads naturally.
+const clausePunct = ",.;:!?…，、。；：！？"
+
+// SetAutoLinebreak splits a line1 longer than the target CPL into two lines
+// when the model left line2 empty. It runs before the CPL check, so a line
+// that fits once split is not retried. Single-line mode takes precedence.
+func (t *Translator) SetAutoLinebreak(enabled bool) {
+	t.autoLinebreak = enabled
+}
+
+// lineBudget returns the target CPL under the active metric, without the
+// validation tolerance.
+func (t *Translator) lineBudget() int {
+	if t.usesWidth() {
+		return t.tgtLang.DefaultCPL * 2
+	}
+	return t.tgtLang.DefaultCPL
+}
+
+// breakResponseLines splits each over-long one-line translation in resp.
+func (t *Translator) breakResponseLines(resp *gemini.ResponseData) {
+	limit := t.lineBudget()
+	for i, tr := range resp.Translations {
+		if strings.TrimSpace(tr.Line2) != "" || strings.Contains(tr.Line1, "\n") || t.lineLength(tr.Line1) <= limit {
+			continue
+		}
+		if line1, line2, ok := t.breakLine(tr.Line1, limit); ok {
+			resp.Translations[i].Line1 = line1
+			resp.Translations[i].Line2 = line2
+		}
+	}
+}
+
+// breakLine splits line in two at the best break point. Breaks fall on spaces,
+// and for targets without word spaces (Chinese, Japanese, Thai, ...) also
+// right after clause punctuation. Splits whose lines both fit limit win,
+// preferring a break after clause punctuation and then the most balanced
+// split; when none fits, the most balanced split is used. It reports false
+// when line has no break point.
+func (t *Translator) breakLine(line string, limit int) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	punctBreaks := !language.UsesWordSpaces(t.tgtLang.Code)
+
+	var best [2]string
+	found, bestFits, bestClause, bestLonger := false, false, false, 0
+	for i, r := range line {
+		end := i + utf8.RuneLen(r)
+		var left, right string
+		switch {
+		case unicode.IsSpace(r):
+			left, right = line[:i], line[end:]
+		case punctBreaks && strings.ContainsRune(clausePunct, r):
+			left, right = line[:end], line[end:]
+		default:
+			continue
+		}
+		left, right = strings.TrimSpace(left), strings.TrimSpace(right)
+		if !hasWordRune(left) || !hasWordRune(right) {
+			continue
+		}
+		if first, _ := utf8.DecodeRuneInString(right); strings.ContainsRune(clausePunct, first) {
+			continue
+		}
+		last, _ := utf8.DecodeLastRuneInString(left)
+		clause := strings.ContainsRune(clausePunct, last)
+		longer := max(t.lineLength(left), t.lineLength(right))
+		fits := longer <= limit
+
+		better := !found
+		switch {
+		case better:
+		case fits != bestFits:
+			better = fits
+		case fits && clause != bestClause:
+			better = clause
+		default:
+			better = longer < bestLonger
+		}
+		if better {
+			best = [2]string{left, right}
+			found, bestFits, bestClause, bestLonger = true, fits, clause, longer
+		}
+	}
+	return best[0], best[1], found
+}
+
+func hasWordRune(s string) bool {
+	return strings.IndexFunc(s, isWordRune) >= 0
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
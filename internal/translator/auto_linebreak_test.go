@@ -0,0 +1,86 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestTranslator_BreakResponseLines(t *testing.T) {
+	tests := []struct {
+		name         string
+		lang         string
+		line1, line2 string
+		want1, want2 string
+	}{
+		{"english at cpl", "en", "We have to leave right now before they see", "", "We have to leave right now before they see", ""},
+		{"english over cpl", "en", "We have to leave right now before they see.", "", "We have to leave right", "now before they see."},
+		{"english clause", "en", "We have to leave right now, before they see us.", "", "We have to leave right now,", "before they see us."},
+		{"english line2 kept", "en", "We have to leave right now, before they see us.", "Go.", "We have to leave right now, before they see us.", "Go."},
+		{"chinese at cpl", "zh-Hans", "我们必须马上离开这里他们会回来了", "", "我们必须马上离开这里他们会回来了", ""},
+		{"chinese punctuation", "zh-Hans", "快点走吧，他们马上就要回来了，别等了", "", "快点走吧，", "他们马上就要回来了，别等了"},
+		{"chinese space", "zh-Hans", "我们必须马上离开这里 否则他们很快就会回来", "", "我们必须马上离开这里", "否则他们很快就会回来"},
+		{"chinese no break point", "zh-Hans", "我们必须马上离开这里否则他们会回来了", "", "我们必须马上离开这里否则他们会回来了", ""},
+		{"dialogue dash not split off", "en", "- We have to leave right now before they see.", "", "- We have to leave right", "now before they see."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tgt, _ := language.GetLanguage(tt.lang)
+			tr := &Translator{tgtLang: tgt, autoLinebreak: true}
+			resp := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: 1, Line1: tt.line1, Line2: tt.line2}}}
+			tr.breakResponseLines(resp)
+			got := resp.Translations[0]
+			if got.Line1 != tt.want1 || got.Line2 != tt.want2 {
+				t.Fatalf("got %q / %q, want %q / %q", got.Line1, got.Line2, tt.want1, tt.want2)
+			}
+		})
+	}
+}
+
+func TestTranslator_AutoLinebreakPassesCPLCheck(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	long := "We have to leave right now before they see us and call every guard in the building."
+	calls := 0
+	client := &gemini.MockClient{
+		TranslateFunc: func(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			calls++
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: 1, Line1: long}}}, nil
+		},
+	}
+	src, _ := language.GetLanguage("ja")
+	tgt, _ := language.GetLanguage("en")
+	tr, err := NewTranslator(client, 1, 0, 1, true, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetAutoLinebreak(true)
+
+	translated, failed, err := tr.TranslateSRT(context.Background(), []srt.Segment{{ID: 1, Lines: []string{"今すぐ出ないと"}}}, nil)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("TranslateSRT = failed %v, err %v", failed, err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 (the split line should pass the CPL check)", calls)
+	}
+	lines := translated[0].Lines
+	if len(lines) != 2 || strings.Join(lines, " ") != long {
+		t.Fatalf("lines = %q, want %q split in two", lines, long)
+	}
+	for _, line := range lines {
+		if len(line) > tgt.DefaultCPL {
+			t.Fatalf("line %q is longer than %d", line, tgt.DefaultCPL)
+		}
+	}
+}
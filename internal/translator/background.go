@@ -0,0 +1,17 @@
+package translator
+
+import "strings"
+
+// SetBackground sets user-supplied background information about the content
+// (a synopsis, character notes) that is added to the system prompt. It is
+// reference material only; the model is told not to translate it.
+func (t *Translator) SetBackground(text string) {
+	t.background = strings.TrimSpace(text)
+}
+
+func (t *Translator) backgroundPromptSection() string {
+	return "\n\nBACKGROUND INFORMATION: The following notes describe the content being translated. " +
+		"Use them only to understand the context (who is speaking, relationships, setting, terminology); " +
+		"do not translate them or add anything from them to your output.\n" +
+		"<background>\n" + t.background + "\n</background>\n"
+}
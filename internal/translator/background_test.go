@@ -0,0 +1,26 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+)
+
+func TestTranslator_BackgroundInSystemPrompt(t *testing.T) {
+	src, _ := language.GetLanguage("ja")
+	tgt, _ := language.GetLanguage("en")
+	tr, err := NewTranslator(&gemini.MockClient{}, 10, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	if prompt := tr.SystemPrompt(); strings.Contains(prompt, "BACKGROUND INFORMATION") {
+		t.Fatalf("prompt has a background section without background:\n%s", prompt)
+	}
+	tr.SetBackground("  Kenji is Aiko's older brother.\n")
+	prompt := tr.SystemPrompt()
+	if !strings.Contains(prompt, "BACKGROUND INFORMATION") || !strings.Contains(prompt, "<background>\nKenji is Aiko's older brother.\n</background>") {
+		t.Fatalf("background missing from system prompt:\n%s", prompt)
+	}
+}
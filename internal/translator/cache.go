@@ -0,0 +1,128 @@
+package translator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// ChunkCache persists completed chunk translations keyed by chunk hash, so an
+// interrupted run can reuse them instead of paying for the same chunk again.
+type ChunkCache interface {
+	Load(key string) ([]srt.Segment, bool)
+	Store(key string, segments []srt.Segment) error
+}
+
+// SetChunkCache enables incremental storage and reuse of completed chunks.
+func (t *Translator) SetChunkCache(cache ChunkCache) {
+	t.chunkCache = cache
+}
+
+// chunkCacheKey hashes everything that affects a chunk's translation:
+// languages, prompt options, names mapping, and the target/context segments.
+func (t *Translator) chunkCacheKey(chunk chunker.Chunk) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "chunk_v1\n%s\n%s\n%t\n", t.srcLang.Code, t.tgtLang.Code, t.promptCPL)
+	if t.keepDashes {
+		// Only hashed when enabled so existing cache entries keep their keys.
+		io.WriteString(h, "dialogue_dashes\n")
+	}
+	if t.onEmpty == EmptyPolicyKeepSource {
+		io.WriteString(h, "empty_keep_source\n")
+	}
+	if t.improveDrafts {
+		io.WriteString(h, "improve_drafts\n")
+	}
+	if len(t.draftMemory) > 0 {
+		// Hash the drafts this chunk would be sent, so a different memory
+		// only invalidates the chunks it touches.
+		for _, seg := range chunk.Target {
+			if draft := t.memoryDraft(seg); draft != nil {
+				fmt.Fprintf(h, "memory_draft %d=%q\n", seg.ID, draft)
+			}
+		}
+	}
+	if t.formality != "" && t.formality != FormalityAuto {
+		fmt.Fprintf(h, "formality=%s\n", t.formality)
+	}
+	if t.narrative != "" {
+		fmt.Fprintf(h, "narrative=%s\n", t.narrative)
+	}
+	if t.singleLine {
+		io.WriteString(h, "single_line\n")
+	}
+	if t.autoLinebreak {
+		io.WriteString(h, "auto_linebreak\n")
+	}
+	if t.validateCPL {
+		// The limit covers the metric and tolerance, so a stricter re-run
+		// does not reuse chunks it would reject.
+		fmt.Fprintf(h, "validate_cpl=%g %s\n", t.lineLimit(), t.lengthUnit())
+	}
+	if t.background != "" {
+		fmt.Fprintf(h, "background=%q\n", t.background)
+	}
+	// promptTerms are the entries the system prompt of this run carries.
+	for _, e := range t.promptTerms {
+		fmt.Fprintf(h, "term %q=%q\n", e.Source, e.Target)
+	}
+	keys := make([]string, 0, len(t.namesMapping))
+	for k := range t.namesMapping {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s=%d:%s\n", len(k), k, len(t.namesMapping[k]), t.namesMapping[k])
+	}
+	for _, part := range [][]srt.Segment{chunk.Context.Before, chunk.Target, chunk.Context.After} {
+		io.WriteString(h, srt.SegmentsChecksumHex(part))
+		io.WriteString(h, "\n")
+		for _, seg := range part {
+			fmt.Fprintf(h, "%d\n", seg.ID)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CachedChunks returns the cached translations of the given chunks of
+// segments, split as TranslateChunks splits them, by chunk index. Chunks the
+// cache does not hold are left out; nothing is sent to the model.
+func (t *Translator) CachedChunks(segments []srt.Segment, chunkIndices []int) map[int][]srt.Segment {
+	if t.chunkCache == nil {
+		return nil
+	}
+	t.promptTerms = t.termEntries()
+	chunks := chunker.SplitIntoChunks(segments, t.chunkSize, t.contextSize)
+	out := make(map[int][]srt.Segment)
+	for _, idx := range chunkIndices {
+		if idx < 0 || idx >= len(chunks) {
+			continue
+		}
+		if cached, ok := t.loadCachedChunk(t.chunkCacheKey(chunks[idx]), chunks[idx]); ok {
+			out[idx] = cached
+		}
+	}
+	return out
+}
+
+// loadCachedChunk returns a cached translation only if it matches the chunk's segment IDs.
+func (t *Translator) loadCachedChunk(key string, chunk chunker.Chunk) ([]srt.Segment, bool) {
+	if t.chunkCache == nil {
+		return nil, false
+	}
+	cached, ok := t.chunkCache.Load(key)
+	if !ok || len(cached) != len(chunk.Target) {
+		return nil, false
+	}
+	for i := range cached {
+		if cached[i].ID != chunk.Target[i].ID {
+			return nil, false
+		}
+	}
+	return cached, true
+}
@@ -0,0 +1,153 @@
+package translator
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+type memoryChunkCache struct {
+	mu      sync.Mutex
+	entries map[string][]srt.Segment
+}
+
+func (c *memoryChunkCache) Load(key string) ([]srt.Segment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	segs, ok := c.entries[key]
+	return segs, ok
+}
+
+func (c *memoryChunkCache) Store(key string, segments []srt.Segment) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = segments
+	return nil
+}
+
+func TestTranslator_ChunkCacheReuse(t *testing.T) {
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"a"}},
+		{ID: 2, Lines: []string{"b"}},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	cache := &memoryChunkCache{entries: map[string][]srt.Segment{}}
+
+	first := &recordingClient{failID: 2}
+	tr, err := NewTranslator(first, 1, 1, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetChunkCache(cache)
+	_, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+	if err != nil {
+		t.Fatalf("TranslateSRT failed: %v", err)
+	}
+	if len(failed) != 1 || len(cache.entries) != 1 {
+		t.Fatalf("expected 1 failed chunk and 1 cached chunk, got failed=%v cached=%d", failed, len(cache.entries))
+	}
+
+	second := &recordingClient{}
+	tr, err = NewTranslator(second, 1, 1, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetChunkCache(cache)
+	results, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+	if err != nil {
+		t.Fatalf("TranslateSRT failed: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("expected no failed chunks, got %v", failed)
+	}
+	if len(second.targets) != 1 || second.targets[0][0] != 2 {
+		t.Fatalf("expected only chunk with ID 2 to be requested, got %v", second.targets)
+	}
+	if results[0].Lines[0] != "Ta" || results[1].Lines[0] != "Tb" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestTranslator_ChunkCacheKeyChangesWithSettings(t *testing.T) {
+	segments := []srt.Segment{{ID: 1, Lines: []string{"a"}}}
+	src, _ := language.GetLanguage("en")
+	ko, _ := language.GetLanguage("ko")
+	ja, _ := language.GetLanguage("ja")
+	client := &gemini.MockClient{}
+
+	trKo, _ := NewTranslator(client, 1, 0, 1, false, src, ko)
+	trJa, _ := NewTranslator(client, 1, 0, 1, false, src, ja)
+	chunk := chunker.Chunk{Target: segments}
+	base := trKo.chunkCacheKey(chunk)
+	if base == trJa.chunkCacheKey(chunk) {
+		t.Fatalf("expected different keys for different target languages")
+	}
+	trKo.SetNamesMapping(map[string]string{"Alice": "앨리스"})
+	if base == trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected names mapping to change the key")
+	}
+	trKo.SetNamesMapping(nil)
+	trKo.SetFormality(FormalityAuto)
+	if base != trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected auto formality to keep the key")
+	}
+	trKo.SetFormality(FormalityFormal)
+	if base == trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected formality to change the key")
+	}
+	trKo.SetFormality(FormalityAuto)
+	trKo.SetSingleLine(true)
+	if base == trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected single-line mode to change the key")
+	}
+	trKo.SetSingleLine(false)
+	trKo.SetAutoLinebreak(true)
+	if base == trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected auto line breaking to change the key")
+	}
+	trKo.SetAutoLinebreak(false)
+	trKo.validateCPL = true
+	validated := trKo.chunkCacheKey(chunk)
+	if base == validated {
+		t.Fatalf("expected CPL validation to change the key")
+	}
+	trKo.SetCPLTolerance(1.2)
+	if validated == trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected the CPL tolerance to change the key")
+	}
+	trKo.SetCPLTolerance(0)
+	trKo.SetCPLMetric(CPLMetricWidth)
+	if validated == trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected the CPL metric to change the key")
+	}
+	trKo.SetCPLMetric(CPLMetricGraphemes)
+	trKo.validateCPL = false
+	trKo.SetBackground("A detective story set in Osaka.")
+	if base == trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected background information to change the key")
+	}
+	trKo.SetBackground("")
+	memory := NewTermMemory(0, []TermEntry{{Source: "Captain", Target: "선장"}})
+	trKo.SetTermMemory(memory)
+	trKo.setSystemInstruction()
+	withTerms := trKo.chunkCacheKey(chunk)
+	if base == withTerms {
+		t.Fatalf("expected term memory in the prompt to change the key")
+	}
+	memory.Add("Doctor", "박사")
+	if withTerms != trKo.chunkCacheKey(chunk) {
+		t.Fatalf("expected terms added after the prompt was built to keep the key")
+	}
+	trKo.SetTermMemory(nil)
+	trKo.setSystemInstruction()
+	changed := chunker.Chunk{Target: []srt.Segment{{ID: 1, Lines: []string{"b"}}}}
+	if base == trKo.chunkCacheKey(changed) {
+		t.Fatalf("expected segment text to change the key")
+	}
+}
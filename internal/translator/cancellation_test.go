@@ -2,10 +2,12 @@ package translator
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/oukeidos/focst/internal/apperrors"
 	"github.com/oukeidos/focst/internal/gemini"
 	"github.com/oukeidos/focst/internal/language"
 	"github.com/oukeidos/focst/internal/srt"
@@ -79,3 +81,64 @@ func TestTranslator_Cancellation(t *testing.T) {
 		t.Errorf("TranslateSRT took too long to return after cancellation: %v", duration)
 	}
 }
+
+// authFailClient rejects the first request with an auth error and holds every
+// other request until its context is canceled.
+type authFailClient struct {
+	callCount int32
+}
+
+func (m *authFailClient) SetSystemInstruction(prompt string) {}
+
+func (m *authFailClient) Translate(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	if atomic.AddInt32(&m.callCount, 1) == 1 {
+		return nil, apperrors.Auth(errors.New("invalid key"))
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+	}
+	translations := make([]gemini.TranslatedSegment, len(req.Target))
+	for i, s := range req.Target {
+		translations[i] = gemini.TranslatedSegment{ID: s.ID, Line1: "translated"}
+	}
+	return &gemini.ResponseData{Translations: translations}, nil
+}
+
+func TestTranslator_FailFastStopsOnAuthError(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	mock := &authFailClient{}
+	src, _ := language.GetLanguage("ja")
+	tgt, _ := language.GetLanguage("ko")
+	tr, _ := NewTranslator(mock, 1, 0, 3, false, src, tgt)
+	tr.SetFailFast(true)
+
+	segments := make([]srt.Segment, 20)
+	for i := range segments {
+		segments[i] = srt.Segment{ID: i + 1, Lines: []string{"test"}}
+	}
+
+	start := time.Now()
+	_, _, err := tr.TranslateSRT(context.Background(), segments, nil)
+	duration := time.Since(start)
+
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) || appErr.Kind != apperrors.KindAuth {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+	if duration > time.Second {
+		t.Errorf("TranslateSRT took too long to stop after the auth error: %v", duration)
+	}
+	if calls := atomic.LoadInt32(&mock.callCount); calls > 3 {
+		t.Errorf("expected only the in-flight chunks to be requested, got %d calls", calls)
+	}
+}
@@ -0,0 +1,92 @@
+package translator
+
+import (
+	"fmt"
+
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/rivo/uniseg"
+)
+
+// CPLMetric selects how line length is measured against the CPL limit.
+type CPLMetric string
+
+const (
+	// CPLMetricGraphemes counts user-perceived characters (the default).
+	CPLMetricGraphemes CPLMetric = "graphemes"
+	// CPLMetricWidth counts East Asian display width (full-width = 2 units)
+	// when the target is a CJK language.
+	CPLMetricWidth CPLMetric = "width"
+)
+
+// ParseCPLMetric validates a metric name. An empty string selects graphemes.
+func ParseCPLMetric(s string) (CPLMetric, error) {
+	switch CPLMetric(s) {
+	case "", CPLMetricGraphemes:
+		return CPLMetricGraphemes, nil
+	case CPLMetricWidth:
+		return CPLMetricWidth, nil
+	default:
+		return "", fmt.Errorf("invalid CPL metric %q (want %q or %q)", s, CPLMetricGraphemes, CPLMetricWidth)
+	}
+}
+
+// DefaultCPLTolerance is how far past the target's CPL a line may run before
+// CPL validation rejects it.
+const DefaultCPLTolerance = 1.5
+
+// ValidateCPLTolerance checks that a tolerance multiplier is at least 1.0.
+func ValidateCPLTolerance(tolerance float64) error {
+	if tolerance < 1.0 {
+		return fmt.Errorf("CPL tolerance must be 1.0 or greater, got %.2f", tolerance)
+	}
+	return nil
+}
+
+// SetCPLTolerance sets the multiplier on the target CPL used as the soft line
+// limit when validating responses (see NewTranslator's validateCPL). Zero
+// uses DefaultCPLTolerance.
+func (t *Translator) SetCPLTolerance(tolerance float64) {
+	t.cplTolerance = tolerance
+}
+
+// SetCPLMetric selects the line length metric used by CPL validation.
+func (t *Translator) SetCPLMetric(metric CPLMetric) {
+	t.cplMetric = metric
+}
+
+// usesWidth reports whether lines are measured by display width. The width
+// metric only applies to CJK targets, whose CPL counts full-width characters.
+func (t *Translator) usesWidth() bool {
+	return t.cplMetric == CPLMetricWidth && language.IsCJK(t.tgtLang.Code)
+}
+
+// lineLength measures a translated line under the active metric.
+func (t *Translator) lineLength(line string) int {
+	if t.usesWidth() {
+		return uniseg.StringWidth(line)
+	}
+	return uniseg.GraphemeClusterCount(line)
+}
+
+// lineLimit returns the maximum accepted line length under the active metric.
+// Width limits are doubled so a line of full-width characters keeps the same
+// budget as under the grapheme metric, while half-width text gains room.
+func (t *Translator) lineLimit() float64 {
+	tolerance := t.cplTolerance
+	if tolerance == 0 {
+		tolerance = DefaultCPLTolerance
+	}
+	limit := float64(t.tgtLang.DefaultCPL) * tolerance
+	if t.usesWidth() {
+		limit *= 2
+	}
+	return limit
+}
+
+// lengthUnit names the unit reported in CPL validation errors.
+func (t *Translator) lengthUnit() string {
+	if t.usesWidth() {
+		return "width units"
+	}
+	return "chars"
+}
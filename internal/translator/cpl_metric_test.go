@@ -0,0 +1,111 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+)
+
+func TestCPLMetric_MixedLineLength(t *testing.T) {
+	ja, _ := language.GetLanguage("ja")
+	en, _ := language.GetLanguage("en")
+
+	tests := []struct {
+		name   string
+		lang   language.Language
+		metric CPLMetric
+		want   int
+	}{
+		{name: "graphemes", lang: ja, metric: CPLMetricGraphemes, want: 6},
+		{name: "width", lang: ja, metric: CPLMetricWidth, want: 9},
+		{name: "default", lang: ja, metric: "", want: 6},
+		{name: "width ignored for non-CJK target", lang: en, metric: CPLMetricWidth, want: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &Translator{tgtLang: tt.lang}
+			tr.SetCPLMetric(tt.metric)
+			if got := tr.lineLength("ABCあいう"); got != tt.want {
+				t.Fatalf("lineLength(%q) = %d, want %d", "ABCあいう", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCPLMetric_ValidateResponse(t *testing.T) {
+	ja, _ := language.GetLanguage("ja") // CPL 13: 19.5 graphemes or 39 width units
+	latinHeavy := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{
+		{ID: 1, Line1: "ABCDEFGHIJKLMNOPQRSTあ"}, // 21 graphemes, width 22
+	}}
+	fullWidth := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{
+		{ID: 1, Line1: "あいうえおかきくけこさしすせそたちつてと"}, // 20 graphemes, width 40
+	}}
+
+	tests := []struct {
+		name    string
+		metric  CPLMetric
+		resp    *gemini.ResponseData
+		wantErr bool
+	}{
+		{name: "graphemes rejects latin-heavy", metric: CPLMetricGraphemes, resp: latinHeavy, wantErr: true},
+		{name: "width accepts latin-heavy", metric: CPLMetricWidth, resp: latinHeavy, wantErr: false},
+		{name: "graphemes rejects full-width", metric: CPLMetricGraphemes, resp: fullWidth, wantErr: true},
+		{name: "width rejects full-width", metric: CPLMetricWidth, resp: fullWidth, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &Translator{tgtLang: ja}
+			tr.SetCPLMetric(tt.metric)
+			err := tr.validateResponse(tt.resp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCPLTolerance_ValidateResponseBoundary(t *testing.T) {
+	ja, _ := language.GetLanguage("ja") // CPL 13: 15.6 graphemes at 1.2x
+	atLimit := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{
+		{ID: 1, Line1: "あいうえおかきくけこさしすせそ"}, // 15 graphemes
+	}}
+	overLimit := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{
+		{ID: 1, Line1: "あいうえおかきくけこさしすせそた"}, // 16 graphemes
+	}}
+
+	tr := &Translator{tgtLang: ja}
+	tr.SetCPLTolerance(1.2)
+	if err := tr.validateResponse(atLimit); err != nil {
+		t.Fatalf("15 graphemes rejected at 1.2x: %v", err)
+	}
+	if err := tr.validateResponse(overLimit); err == nil {
+		t.Fatalf("16 graphemes accepted at 1.2x")
+	}
+
+	tr.SetCPLTolerance(0)
+	if err := tr.validateResponse(overLimit); err != nil {
+		t.Fatalf("16 graphemes rejected at default tolerance: %v", err)
+	}
+}
+
+func TestValidateCPLTolerance(t *testing.T) {
+	for tolerance, wantErr := range map[float64]bool{1.0: false, 1.2: false, 2: false, 0.99: true, 0: true} {
+		if err := ValidateCPLTolerance(tolerance); (err != nil) != wantErr {
+			t.Errorf("ValidateCPLTolerance(%v) error = %v, wantErr %v", tolerance, err, wantErr)
+		}
+	}
+}
+
+func TestParseCPLMetric(t *testing.T) {
+	for _, s := range []string{"", "graphemes", "width"} {
+		if _, err := ParseCPLMetric(s); err != nil {
+			t.Fatalf("ParseCPLMetric(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseCPLMetric("bytes"); err == nil {
+		t.Fatalf("expected error for unknown metric")
+	}
+}
@@ -0,0 +1,104 @@
+package translator
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// SetDedupRepeats makes the translator send each repeated source text (e.g. a
+// chant or recap line) to the model once per run and reuse that translation
+// for every identical cue. Each cue keeps its own ID and timing.
+func (t *Translator) SetDedupRepeats(enabled bool) {
+	t.dedupRepeats = enabled
+}
+
+// repeatKey normalizes a segment's text for duplicate detection: lines are
+// joined and runs of whitespace collapsed. Empty text is never deduplicated.
+func repeatKey(seg srt.Segment) string {
+	return strings.Join(strings.Fields(strings.Join(seg.Lines, " ")), " ")
+}
+
+// repeatMemo holds translations of source texts completed earlier in a run.
+// Chunks run concurrently, so reuse across chunks is best-effort; repeats
+// within one chunk are always sent once.
+type repeatMemo struct {
+	mu    sync.Mutex
+	lines map[string][]string
+}
+
+func newRepeatMemo() *repeatMemo {
+	return &repeatMemo{lines: make(map[string][]string)}
+}
+
+// record stores the translation of each target segment not yet remembered.
+func (m *repeatMemo) record(target, translated []srt.Segment) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, seg := range target {
+		key := repeatKey(seg)
+		if key == "" {
+			continue
+		}
+		if _, ok := m.lines[key]; !ok {
+			m.lines[key] = translated[i].Lines
+		}
+	}
+}
+
+// repeatPlan maps a chunk's target segments onto the unique segments sent to
+// the model. from[i] is the index into unique for target i, or -1 when its
+// lines come from the memo (stored in known[i]).
+type repeatPlan struct {
+	unique []srt.Segment
+	from   []int
+	known  [][]string
+}
+
+// plan drops from target every segment whose text is already remembered or
+// appears earlier in the same chunk.
+func (m *repeatMemo) plan(target []srt.Segment) repeatPlan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p := repeatPlan{from: make([]int, len(target)), known: make([][]string, len(target))}
+	first := make(map[string]int)
+	for i, seg := range target {
+		key := repeatKey(seg)
+		if key != "" {
+			if lines, ok := m.lines[key]; ok {
+				p.from[i] = -1
+				p.known[i] = lines
+				continue
+			}
+			if idx, ok := first[key]; ok {
+				p.from[i] = idx
+				continue
+			}
+			first[key] = len(p.unique)
+		}
+		p.from[i] = len(p.unique)
+		p.unique = append(p.unique, seg)
+	}
+	return p
+}
+
+// reduces reports whether the plan sends fewer segments than the chunk holds.
+func (p repeatPlan) reduces() bool {
+	return len(p.unique) < len(p.from)
+}
+
+// expand rebuilds the full target from the translated unique segments,
+// copying lines onto each repeat while keeping its own ID and timing.
+func (p repeatPlan) expand(target, translated []srt.Segment) []srt.Segment {
+	out := make([]srt.Segment, len(target))
+	for i, seg := range target {
+		lines := p.known[i]
+		if p.from[i] >= 0 {
+			lines = translated[p.from[i]].Lines
+		}
+		seg.Lines = append([]string(nil), lines...)
+		out[i] = seg
+	}
+	return out
+}
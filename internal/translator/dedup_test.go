@@ -0,0 +1,88 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// echoClient translates each target as "T:" + its joined lines and records
+// every segment it was sent.
+type echoClient struct {
+	sent []string
+}
+
+func (c *echoClient) Translate(_ context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	resp := &gemini.ResponseData{}
+	for _, seg := range req.Target {
+		text := strings.Join(seg.Lines, " ")
+		c.sent = append(c.sent, text)
+		resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T:" + text})
+	}
+	return resp, nil
+}
+
+func (c *echoClient) SetSystemInstruction(string) {}
+
+func threeIdenticalCues() []srt.Segment {
+	return []srt.Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"No! No! No!"}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"Run"}},
+		{ID: 3, StartTime: "00:00:05,000", EndTime: "00:00:06,000", Lines: []string{"No!  No! No!"}},
+		{ID: 4, StartTime: "00:00:07,000", EndTime: "00:00:08,000", Lines: []string{"No! No!", "No!"}},
+	}
+}
+
+func TestTranslator_DedupRepeats_ThreeIdenticalCues(t *testing.T) {
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+
+	for _, tc := range []struct {
+		name      string
+		chunkSize int
+		dedup     bool
+		wantSent  []string
+	}{
+		{name: "single_chunk", chunkSize: 10, dedup: true, wantSent: []string{"No! No! No!", "Run"}},
+		{name: "across_chunks", chunkSize: 1, dedup: true, wantSent: []string{"No! No! No!", "Run"}},
+		{name: "disabled", chunkSize: 10, dedup: false, wantSent: []string{"No! No! No!", "Run", "No!  No! No!", "No! No! No!"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &echoClient{}
+			tr, err := NewTranslator(client, tc.chunkSize, 0, 1, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator: %v", err)
+			}
+			tr.SetRampUp(0)
+			tr.SetDedupRepeats(tc.dedup)
+
+			segments := threeIdenticalCues()
+			out, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+			if err != nil || len(failed) != 0 {
+				t.Fatalf("TranslateSRT failed=%v err=%v", failed, err)
+			}
+			if strings.Join(client.sent, "|") != strings.Join(tc.wantSent, "|") {
+				t.Fatalf("sent = %q, want %q", client.sent, tc.wantSent)
+			}
+			if len(out) != len(segments) {
+				t.Fatalf("got %d segments, want %d", len(out), len(segments))
+			}
+			for i, seg := range out {
+				if seg.ID != segments[i].ID || seg.StartTime != segments[i].StartTime || seg.EndTime != segments[i].EndTime {
+					t.Fatalf("segment %d timing/ID changed: %+v", i, seg)
+				}
+			}
+			if tc.dedup {
+				for _, i := range []int{0, 2, 3} {
+					if got := strings.Join(out[i].Lines, " "); got != "T:No! No! No!" {
+						t.Fatalf("segment %d = %q, want shared translation", i, got)
+					}
+				}
+			}
+		})
+	}
+}
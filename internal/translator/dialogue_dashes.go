@@ -0,0 +1,70 @@
+package translator
+
+import (
+	"regexp"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// dialogueDashPattern matches a leading speaker dash: a hyphen followed by a
+// space ("- Hi"), or an en/em/horizontal-bar dash with optional space ("—Hi").
+// A bare hyphen is left alone so negative numbers like "-5" are not touched.
+var dialogueDashPattern = regexp.MustCompile(`^\s*(?:-\s+|[‐–—―]\s*)`)
+
+// SetPreserveDialogueDashes strips leading dialogue dashes from target lines
+// before the request and re-applies the source's dashes to the translation, so
+// two-speaker cues keep their formatting regardless of what the model returns.
+func (t *Translator) SetPreserveDialogueDashes(enabled bool) {
+	t.keepDashes = enabled
+}
+
+// splitDialogueDash returns line's leading dialogue dash (including surrounding
+// whitespace) and the remaining text. A line that is only a dash is not split.
+func splitDialogueDash(line string) (string, string) {
+	loc := dialogueDashPattern.FindStringIndex(line)
+	if loc == nil || loc[1] == len(line) {
+		return "", line
+	}
+	return line[:loc[1]], line[loc[1]:]
+}
+
+// stripDialogueDashes returns a copy of segments with leading dialogue dashes removed.
+func stripDialogueDashes(segments []gemini.SegmentData) []gemini.SegmentData {
+	out := make([]gemini.SegmentData, len(segments))
+	for i, seg := range segments {
+		lines := make([]string, len(seg.Lines))
+		for j, line := range seg.Lines {
+			_, lines[j] = splitDialogueDash(line)
+		}
+		out[i] = gemini.SegmentData{ID: seg.ID, Lines: lines}
+	}
+	return out
+}
+
+// applyDialogueDashes puts orig's per-line dashes back on the translated lines.
+// Dashes the model added are removed first so each prefix appears exactly once.
+// Segments without dialogue dashes are returned unchanged.
+func applyDialogueDashes(orig srt.Segment, lines []string) []string {
+	prefixes := make([]string, len(orig.Lines))
+	dashed := false
+	for i, line := range orig.Lines {
+		prefixes[i], _ = splitDialogueDash(line)
+		if prefixes[i] != "" {
+			dashed = true
+		}
+	}
+	if !dashed {
+		return lines
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		_, text := splitDialogueDash(line)
+		prefix := ""
+		if i < len(prefixes) {
+			prefix = prefixes[i]
+		}
+		out[i] = prefix + text
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestSplitDialogueDash(t *testing.T) {
+	cases := []struct {
+		line   string
+		prefix string
+		text   string
+	}{
+		{line: "- Hello", prefix: "- ", text: "Hello"},
+		{line: "-  Hello", prefix: "-  ", text: "Hello"},
+		{line: "—Hello", prefix: "—", text: "Hello"},
+		{line: "– Hello", prefix: "– ", text: "Hello"},
+		{line: "-5 degrees", prefix: "", text: "-5 degrees"},
+		{line: "well-known", prefix: "", text: "well-known"},
+		{line: "- ", prefix: "", text: "- "},
+	}
+	for _, tc := range cases {
+		prefix, text := splitDialogueDash(tc.line)
+		if prefix != tc.prefix || text != tc.text {
+			t.Fatalf("splitDialogueDash(%q) = %q, %q, want %q, %q", tc.line, prefix, text, tc.prefix, tc.text)
+		}
+	}
+}
+
+// dashClient drops the dash on line1 and keeps it on line2, mimicking the
+// inconsistent output the option guards against.
+type dashClient struct {
+	received [][]string
+}
+
+func (c *dashClient) Translate(_ context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	resp := &gemini.ResponseData{}
+	for _, seg := range req.Target {
+		c.received = append(c.received, seg.Lines)
+		resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "안녕", Line2: "- 잘 가"})
+	}
+	return resp, nil
+}
+
+func (c *dashClient) SetSystemInstruction(string) {}
+
+func TestTranslator_PreserveDialogueDashes_TwoSpeakerCue(t *testing.T) {
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+
+	cases := []struct {
+		name      string
+		preserve  bool
+		source    []string
+		wantSent  []string
+		wantLines []string
+	}{
+		{name: "hyphen", preserve: true, source: []string{"- Hi.", "- Bye."}, wantSent: []string{"Hi.", "Bye."}, wantLines: []string{"- 안녕", "- 잘 가"}},
+		{name: "em_dash", preserve: true, source: []string{"—Hi.", "—Bye."}, wantSent: []string{"Hi.", "Bye."}, wantLines: []string{"—안녕", "—잘 가"}},
+		{name: "disabled", preserve: false, source: []string{"- Hi.", "- Bye."}, wantSent: []string{"- Hi.", "- Bye."}, wantLines: []string{"안녕", "- 잘 가"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &dashClient{}
+			tr, err := NewTranslator(client, 10, 0, 1, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			tr.SetPreserveDialogueDashes(tc.preserve)
+			segments := []srt.Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: tc.source}}
+			results, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+			if err != nil || len(failed) != 0 {
+				t.Fatalf("TranslateSRT err %v failed %v", err, failed)
+			}
+			if !reflect.DeepEqual(client.received[0], tc.wantSent) {
+				t.Fatalf("sent lines = %q, want %q", client.received[0], tc.wantSent)
+			}
+			if !reflect.DeepEqual(results[0].Lines, tc.wantLines) {
+				t.Fatalf("translated lines = %q, want %q", results[0].Lines, tc.wantLines)
+			}
+			if !reflect.DeepEqual(segments[0].Lines, tc.source) {
+				t.Fatalf("source segment was modified: %q", segments[0].Lines)
+			}
+		})
+	}
+}
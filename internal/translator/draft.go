@@ -0,0 +1,143 @@
+package translator
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// SetImproveDrafts makes the translator treat target-language lines already
+// present in a segment (e.g. a rough machine pass or alternating bilingual
+// lines) as a draft to improve. Those lines are sent in the segment's draft
+// field instead of as source text. It only applies when the source and target
+// languages use different scripts (see language.DistinctScripts).
+func (t *Translator) SetImproveDrafts(enabled bool) {
+	t.improveDrafts = enabled && language.DistinctScripts(t.srcLang.Code, t.tgtLang.Code)
+}
+
+// SetDraftMemory sends earlier translations from a translation memory (e.g.
+// an imported TMX file) as drafts: a target segment whose text matches a key
+// of memory exactly, lines separated by "\n", gets the value as its draft.
+// Surrounding whitespace is ignored. Segments that already carry a draft
+// (see SetImproveDrafts) keep it.
+func (t *Translator) SetDraftMemory(memory map[string]string) {
+	t.draftMemory = make(map[string][]string, len(memory))
+	for source, target := range memory {
+		key := draftMemoryKey(strings.Split(source, "\n"))
+		lines := strings.Split(strings.TrimSpace(target), "\n")
+		if key != "" && lines[0] != "" {
+			t.draftMemory[key] = lines
+		}
+	}
+}
+
+// draftMemoryKey normalizes segment lines for a draft memory lookup.
+func draftMemoryKey(lines []string) string {
+	trimmed := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			trimmed = append(trimmed, line)
+		}
+	}
+	return strings.Join(trimmed, "\n")
+}
+
+// memoryDraft returns the remembered translation of a segment, if any.
+func (t *Translator) memoryDraft(seg srt.Segment) []string {
+	if len(t.draftMemory) == 0 {
+		return nil
+	}
+	return t.draftMemory[draftMemoryKey(seg.Lines)]
+}
+
+// applyDraftMemory sets the draft of each segment in data, built from
+// segments, that has none yet but a remembered translation.
+func (t *Translator) applyDraftMemory(segments []srt.Segment, data []gemini.SegmentData) []gemini.SegmentData {
+	for i := range data {
+		if data[i].Draft != nil {
+			continue
+		}
+		if draft := t.memoryDraft(segments[i]); draft != nil {
+			data[i].Draft = draft
+		}
+	}
+	return data
+}
+
+// draftPromptSection explains the draft field to the model.
+func (t *Translator) draftPromptSection() string {
+	return "\n\nDRAFTS: Some target segments include a \"draft\" array: an existing " + t.tgtLang.Name +
+		" translation of that segment, of unknown quality. Treat it as a draft to improve, not as source text. " +
+		"Translate from \"lines\"; reuse the draft's wording where it is accurate and natural, and fix mistranslations, " +
+		"omissions, and awkward phrasing. All other rules still apply to your output.\n"
+}
+
+// splitDrafts moves each segment's target-script lines into its Draft field.
+// A segment whose lines are all target-script keeps them as Lines too, so the
+// model still has text to translate.
+func (t *Translator) splitDrafts(segments []gemini.SegmentData) []gemini.SegmentData {
+	src, tgt := language.Scripts(t.srcLang.Code), language.Scripts(t.tgtLang.Code)
+	out := make([]gemini.SegmentData, len(segments))
+	for i, seg := range segments {
+		out[i] = seg
+		var source, draft []string
+		for _, line := range seg.Lines {
+			if isDraftLine(line, src, tgt) {
+				draft = append(draft, line)
+			} else {
+				source = append(source, line)
+			}
+		}
+		if len(draft) == 0 {
+			continue
+		}
+		if len(source) > 0 {
+			out[i].Lines = source
+		}
+		out[i].Draft = draft
+	}
+	return out
+}
+
+// isDraftLine reports whether line is written in the target's script: it has
+// letters in a target script, none in a script only the source uses, and,
+// when the target has scripts of its own (e.g. kana for Japanese), at least
+// one of those.
+func isDraftLine(line string, src, tgt []*unicode.RangeTable) bool {
+	inTarget, inTargetOnly, inSourceOnly := false, false, false
+	for _, r := range line {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		s, t := unicode.IsOneOf(src, r), unicode.IsOneOf(tgt, r)
+		switch {
+		case s && !t:
+			inSourceOnly = true
+		case t && !s:
+			inTarget, inTargetOnly = true, true
+		case t:
+			inTarget = true
+		}
+	}
+	return inTarget && !inSourceOnly && (inTargetOnly || !hasOwnScript(tgt, src))
+}
+
+// hasOwnScript reports whether scripts includes one absent from others.
+func hasOwnScript(scripts, others []*unicode.RangeTable) bool {
+	for _, s := range scripts {
+		found := false
+		for _, o := range others {
+			if s == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package translator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestIsDraftLine(t *testing.T) {
+	cases := []struct {
+		src, tgt string
+		line     string
+		want     bool
+	}{
+		{src: "ja", tgt: "ko", line: "안녕하세요", want: true},
+		{src: "ja", tgt: "ko", line: "こんにちは", want: false},
+		{src: "ja", tgt: "ko", line: "♪～", want: false},
+		{src: "ko", tgt: "en", line: "OK, let's go.", want: true},
+		{src: "ko", tgt: "en", line: "OK 가자", want: false},
+		{src: "ja", tgt: "zh-Hans", line: "我们走吧", want: true},
+		{src: "ja", tgt: "zh-Hans", line: "行こう", want: false},
+		{src: "zh-Hans", tgt: "ja", line: "行こう", want: true},
+		{src: "zh-Hans", tgt: "ja", line: "我们走吧", want: false},
+	}
+	for _, tc := range cases {
+		got := isDraftLine(tc.line, language.Scripts(tc.src), language.Scripts(tc.tgt))
+		if got != tc.want {
+			t.Errorf("isDraftLine(%q, %s->%s) = %v, want %v", tc.line, tc.src, tc.tgt, got, tc.want)
+		}
+	}
+}
+
+func TestTranslator_PrepareRequestSendsDrafts(t *testing.T) {
+	src, _ := language.GetLanguage("ja")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(&dashClient{}, 10, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	chunk := chunker.Chunk{Target: []srt.Segment{
+		{ID: 1, Lines: []string{"行こう", "가자"}},
+		{ID: 2, Lines: []string{"待って"}},
+		{ID: 3, Lines: []string{"잠깐만"}},
+	}}
+
+	if req := tr.prepareRequest(chunk, false, 0); req.Target[0].Draft != nil {
+		t.Fatalf("draft sent without --improve: %+v", req.Target[0])
+	}
+
+	tr.SetImproveDrafts(true)
+	req := tr.prepareRequest(chunk, false, 0)
+	if got := req.Target[0]; !reflect.DeepEqual(got.Lines, []string{"行こう"}) || !reflect.DeepEqual(got.Draft, []string{"가자"}) {
+		t.Fatalf("segment 1 = %+v, want source and draft split", got)
+	}
+	if got := req.Target[1]; got.Draft != nil {
+		t.Fatalf("segment 2 has unexpected draft: %+v", got)
+	}
+	if got := req.Target[2]; !reflect.DeepEqual(got.Lines, []string{"잠깐만"}) || !reflect.DeepEqual(got.Draft, []string{"잠깐만"}) {
+		t.Fatalf("segment 3 = %+v, want lines kept and draft set", got)
+	}
+
+	data, err := json.Marshal(req.Target[0])
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"draft":["가자"]`) {
+		t.Fatalf("draft not labeled in request JSON: %s", data)
+	}
+	if prompt := tr.SystemPrompt(); !strings.Contains(prompt, `"draft"`) || !strings.Contains(prompt, "draft to improve") {
+		t.Fatalf("system prompt does not explain drafts:\n%s", prompt)
+	}
+}
+
+func TestTranslator_SetImproveDraftsNeedsDistinctScripts(t *testing.T) {
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("fr")
+	tr, err := NewTranslator(&dashClient{}, 10, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetImproveDrafts(true)
+	if tr.improveDrafts {
+		t.Fatal("improve mode enabled for languages sharing a script")
+	}
+}
+
+func TestTranslator_DraftMemory(t *testing.T) {
+	src, _ := language.GetLanguage("ja")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(&dashClient{}, 10, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	chunk := chunker.Chunk{Target: []srt.Segment{
+		{ID: 1, Lines: []string{"行こう", " 早く "}},
+		{ID: 2, Lines: []string{"待って"}},
+	}}
+	before := tr.chunkCacheKey(chunk)
+
+	tr.SetDraftMemory(map[string]string{"行こう\n早く": "가자\n빨리", "知らない": "몰라"})
+	req := tr.prepareRequest(chunk, false, 0)
+	if got := req.Target[0]; !reflect.DeepEqual(got.Lines, []string{"行こう", " 早く "}) || !reflect.DeepEqual(got.Draft, []string{"가자", "빨리"}) {
+		t.Fatalf("segment 1 = %+v, want remembered draft", got)
+	}
+	if got := req.Target[1]; got.Draft != nil {
+		t.Fatalf("segment 2 has unexpected draft: %+v", got)
+	}
+	if prompt := tr.SystemPrompt(); !strings.Contains(prompt, "draft to improve") {
+		t.Fatalf("system prompt does not explain drafts:\n%s", prompt)
+	}
+	if tr.chunkCacheKey(chunk) == before {
+		t.Fatal("cache key ignores remembered drafts")
+	}
+
+	// A memory that matches nothing in the chunk keeps its key.
+	tr.SetDraftMemory(map[string]string{"知らない": "몰라"})
+	if tr.chunkCacheKey(chunk) != before {
+		t.Fatal("cache key changed for a chunk without remembered drafts")
+	}
+}
@@ -0,0 +1,32 @@
+package translator
+
+import "fmt"
+
+// EmptyPolicy decides what happens when the model returns an empty translation
+// for a segment whose source has text.
+type EmptyPolicy string
+
+const (
+	// EmptyPolicyFail rejects the whole chunk so it is retried (the default).
+	EmptyPolicyFail EmptyPolicy = "fail"
+	// EmptyPolicyKeepSource fills the empty segment with its source text and
+	// accepts the rest of the chunk.
+	EmptyPolicyKeepSource EmptyPolicy = "keep-source"
+)
+
+// ParseEmptyPolicy validates a policy name. An empty string selects fail.
+func ParseEmptyPolicy(s string) (EmptyPolicy, error) {
+	switch EmptyPolicy(s) {
+	case "", EmptyPolicyFail:
+		return EmptyPolicyFail, nil
+	case EmptyPolicyKeepSource:
+		return EmptyPolicyKeepSource, nil
+	default:
+		return "", fmt.Errorf("invalid empty translation policy %q (want %q or %q)", s, EmptyPolicyFail, EmptyPolicyKeepSource)
+	}
+}
+
+// SetEmptyPolicy selects how empty translations are handled in mergeResults.
+func (t *Translator) SetEmptyPolicy(policy EmptyPolicy) {
+	t.onEmpty = policy
+}
@@ -0,0 +1,66 @@
+package translator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestParseEmptyPolicy(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    EmptyPolicy
+		wantErr bool
+	}{
+		{in: "", want: EmptyPolicyFail},
+		{in: "fail", want: EmptyPolicyFail},
+		{in: "keep-source", want: EmptyPolicyKeepSource},
+		{in: "skip", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := ParseEmptyPolicy(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("ParseEmptyPolicy(%q) err = %v, wantErr %v", tc.in, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Fatalf("ParseEmptyPolicy(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestMergeResults_EmptyPolicyMixedChunk(t *testing.T) {
+	original := []srt.Segment{
+		{ID: 1, Lines: []string{"こんにちは"}},
+		{ID: 2, Lines: []string{"さようなら", "また明日"}},
+		{ID: 3, Lines: []string{"ありがとう"}},
+	}
+	resp := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{
+		{ID: 1, Line1: "안녕"},
+		{ID: 2, Line1: " ", Line2: ""},
+		{ID: 3, Line1: "고마워"},
+	}}
+
+	tr := &Translator{}
+	if _, err := tr.mergeResults(original, resp); err == nil || !strings.Contains(err.Error(), "segment ID 2") {
+		t.Fatalf("default policy: expected empty translation error for ID 2, got %v", err)
+	}
+
+	tr.SetEmptyPolicy(EmptyPolicyKeepSource)
+	results, err := tr.mergeResults(original, resp)
+	if err != nil {
+		t.Fatalf("keep-source: unexpected error: %v", err)
+	}
+	want := [][]string{{"안녕"}, {"さようなら", "また明日"}, {"고마워"}}
+	for i, seg := range results {
+		if !reflect.DeepEqual(seg.Lines, want[i]) {
+			t.Fatalf("segment %d lines = %q, want %q", seg.ID, seg.Lines, want[i])
+		}
+	}
+	results[1].Lines[0] = "changed"
+	if original[1].Lines[0] != "さようなら" {
+		t.Fatalf("kept source lines alias the original segment")
+	}
+}
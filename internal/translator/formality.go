@@ -0,0 +1,74 @@
+package translator
+
+import "fmt"
+
+// Formality selects how the translation addresses people in languages that
+// distinguish formal and informal "you" (du/Sie, tu/vous, 반말/존댓말).
+type Formality string
+
+const (
+	// FormalityAuto leaves the choice to the model (the default).
+	FormalityAuto Formality = "auto"
+	// FormalityFormal uses the polite forms of address throughout.
+	FormalityFormal Formality = "formal"
+	// FormalityInformal uses the familiar forms of address throughout.
+	FormalityInformal Formality = "informal"
+)
+
+// ParseFormality validates a formality name. An empty string selects auto.
+func ParseFormality(s string) (Formality, error) {
+	switch Formality(s) {
+	case "", FormalityAuto:
+		return FormalityAuto, nil
+	case FormalityFormal, FormalityInformal:
+		return Formality(s), nil
+	default:
+		return "", fmt.Errorf("invalid formality %q (want %q, %q, or %q)", s, FormalityFormal, FormalityInformal, FormalityAuto)
+	}
+}
+
+// formalityPhrasing names the forms of address for targets with a T–V
+// distinction; other targets get generic guidance.
+var formalityPhrasing = map[string]map[Formality]string{
+	"de": {
+		FormalityFormal:   `the formal "Sie" (with "Ihnen"/"Ihr"), never "du"`,
+		FormalityInformal: `the informal "du" (with "dich"/"dir"/"dein", and "ihr" for several people), never "Sie"`,
+	},
+	"fr": {
+		FormalityFormal:   `the formal "vous", never "tu"`,
+		FormalityInformal: `the informal "tu" (with "te"/"toi"/"ton"), never "vous" for a single person`,
+	},
+	"es": {
+		FormalityFormal:   `the formal "usted"/"ustedes" with third-person verb forms, never "tú" or "vosotros"`,
+		FormalityInformal: `the informal "tú" (and "vosotros" for several people), never "usted"`,
+	},
+	"ja": {
+		FormalityFormal:   "polite speech (丁寧語, です/ます forms), never plain speech (タメ口)",
+		FormalityInformal: "plain speech (タメ口, dictionary and だ forms), never です/ます forms",
+	},
+	"ko": {
+		FormalityFormal:   "존댓말 (-요/-습니다 endings), never 반말",
+		FormalityInformal: "반말 (plain -아/-어/-야 endings), never 존댓말",
+	},
+}
+
+// FormalityInstruction returns an extra prompt rule that fixes how the target
+// addresses people, or "" for FormalityAuto.
+func FormalityInstruction(code string, f Formality) string {
+	if f == "" || f == FormalityAuto {
+		return ""
+	}
+	if phrasing, ok := formalityPhrasing[code][f]; ok {
+		return fmt.Sprintf("- Address people consistently with %s, in every segment.", phrasing)
+	}
+	if f == FormalityFormal {
+		return "- Address people consistently with the formal, polite forms of \"you\" the target language has, in every segment."
+	}
+	return "- Address people consistently with the informal, familiar forms of \"you\" the target language has, in every segment."
+}
+
+// SetFormality fixes the forms of address in the system prompt; FormalityAuto
+// leaves them to the model.
+func (t *Translator) SetFormality(f Formality) {
+	t.formality = f
+}
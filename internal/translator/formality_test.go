@@ -0,0 +1,74 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+)
+
+func TestFormalityInstruction_PerLanguage(t *testing.T) {
+	tests := []struct {
+		code      string
+		formality Formality
+		want      string
+	}{
+		{code: "de", formality: FormalityFormal, want: `formal "Sie"`},
+		{code: "de", formality: FormalityInformal, want: `informal "du"`},
+		{code: "fr", formality: FormalityFormal, want: `formal "vous"`},
+		{code: "fr", formality: FormalityInformal, want: `informal "tu"`},
+		{code: "es", formality: FormalityFormal, want: `formal "usted"`},
+		{code: "es", formality: FormalityInformal, want: `informal "tú"`},
+		{code: "ja", formality: FormalityFormal, want: "です/ます forms), never plain"},
+		{code: "ja", formality: FormalityInformal, want: "plain speech (タメ口"},
+		{code: "ko", formality: FormalityFormal, want: "존댓말 (-요/-습니다 endings)"},
+		{code: "ko", formality: FormalityInformal, want: "반말 (plain"},
+		{code: "it", formality: FormalityFormal, want: "formal, polite forms"},
+		{code: "it", formality: FormalityInformal, want: "informal, familiar forms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+string(tt.formality), func(t *testing.T) {
+			got := FormalityInstruction(tt.code, tt.formality)
+			if !strings.Contains(got, tt.want) {
+				t.Fatalf("FormalityInstruction = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+	for _, code := range []string{"de", "ko", "it"} {
+		if got := FormalityInstruction(code, FormalityAuto); got != "" {
+			t.Fatalf("FormalityInstruction(%s, auto) = %q, want none", code, got)
+		}
+	}
+}
+
+func TestTranslator_SystemPromptIncludesFormality(t *testing.T) {
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("de")
+	client := &gemini.MockClient{}
+	tr, err := NewTranslator(client, 10, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.setSystemInstruction()
+	if strings.Contains(client.LastSystemInstruction, "Address people") {
+		t.Fatalf("default prompt should leave formality to the model")
+	}
+	tr.SetFormality(FormalityFormal)
+	tr.setSystemInstruction()
+	if !strings.Contains(client.LastSystemInstruction, FormalityInstruction("de", FormalityFormal)) {
+		t.Fatalf("prompt missing formality rule:\n%s", client.LastSystemInstruction)
+	}
+}
+
+func TestParseFormality(t *testing.T) {
+	for in, want := range map[string]Formality{"": FormalityAuto, "auto": FormalityAuto, "formal": FormalityFormal, "informal": FormalityInformal} {
+		got, err := ParseFormality(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseFormality(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormality("polite"); err == nil {
+		t.Fatalf("expected error for unknown formality")
+	}
+}
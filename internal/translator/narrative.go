@@ -0,0 +1,74 @@
+package translator
+
+import (
+	"context"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// narrativeSection is appended to the system prompt for requests that carry
+// only on-screen text.
+const narrativeSection = `
+
+4. On-screen text:
+- Every target segment in this request is on-screen text (a sign, note, caption, or title), not dialogue.
+- Translate it concisely and literally, as it would be printed: do not add words, do not make it conversational, and keep it about as short as the source.`
+
+// SetNarrative routes segments matching tag through a literal-register
+// prompt. Each chunk's tagged and untagged targets are sent as separate
+// requests with their own system instruction (see
+// gemini.RequestData.SystemInstruction); the chunk succeeds or fails as a
+// whole, so chunk numbering is unchanged. The empty tag disables routing.
+func (t *Translator) SetNarrative(tag srt.NarrativeTag) {
+	t.narrative = tag
+}
+
+// translateRouted sends req, whose targets are the segments in targets, in
+// one request per register when narrative routing is on, and merges the
+// responses. A register with no targets is not sent. The first failing request
+// fails the whole chunk.
+func (t *Translator) translateRouted(ctx context.Context, req gemini.RequestData, targets []srt.Segment) (*gemini.ResponseData, error) {
+	if t.narrative == "" {
+		return t.geminiClient.Translate(ctx, req)
+	}
+	tagged := make(map[int]bool, len(targets))
+	for _, seg := range targets {
+		if t.narrative.Matches(seg) {
+			tagged[seg.ID] = true
+		}
+	}
+	if len(tagged) == 0 {
+		return t.geminiClient.Translate(ctx, req)
+	}
+
+	var dialogue, narrative []gemini.SegmentData
+	for _, seg := range req.Target {
+		if tagged[seg.ID] {
+			narrative = append(narrative, seg)
+		} else {
+			dialogue = append(dialogue, seg)
+		}
+	}
+	dialogueReq := req
+	dialogueReq.Target = dialogue
+	narrativeReq := req
+	narrativeReq.Target = narrative
+	narrativeReq.SystemInstruction = t.narrativePrompt
+
+	merged := &gemini.ResponseData{}
+	for _, r := range []gemini.RequestData{dialogueReq, narrativeReq} {
+		if len(r.Target) == 0 {
+			continue
+		}
+		resp, err := t.geminiClient.Translate(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		merged.Translations = append(merged.Translations, resp.Translations...)
+		merged.Usage.PromptTokenCount += resp.Usage.PromptTokenCount
+		merged.Usage.CandidatesTokenCount += resp.Usage.CandidatesTokenCount
+		merged.Usage.TotalTokenCount += resp.Usage.TotalTokenCount
+	}
+	return merged, nil
+}
@@ -0,0 +1,91 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestTranslator_NarrativeTagRoutesToLiteralPrompt(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	var mu sync.Mutex
+	// instructions records, per target ID, the system instruction override
+	// it was sent with ("" means the client's dialogue prompt).
+	instructions := map[int]string{}
+	client := &gemini.MockClient{
+		TranslateFunc: func(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			resp := &gemini.ResponseData{}
+			for _, seg := range req.Target {
+				instructions[seg.ID] = req.SystemInstruction
+				resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return resp, nil
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 4, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetNarrative(srt.NarrativeForced)
+
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"Hello"}},
+		{ID: 2, Lines: []string{"EXIT"}, Forced: true},
+		{ID: 3, Lines: []string{"Come on"}},
+		{ID: 4, Lines: []string{"DAY 3"}, Forced: true},
+	}
+	translated, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("TranslateSRT = failed %v, err %v", failed, err)
+	}
+	for i, seg := range translated {
+		if seg.ID != segments[i].ID || seg.Lines[0] != "T-"+segments[i].Lines[0] {
+			t.Fatalf("segment %d = %+v, want translation of %+v in place", i, seg, segments[i])
+		}
+	}
+	for _, id := range []int{1, 3} {
+		if instructions[id] != "" {
+			t.Fatalf("dialogue segment %d sent with an override prompt", id)
+		}
+	}
+	for _, id := range []int{2, 4} {
+		if !strings.Contains(instructions[id], "On-screen text") || !strings.HasPrefix(instructions[id], client.LastSystemInstruction) {
+			t.Fatalf("narrative segment %d sent with %q, want the literal prompt", id, instructions[id])
+		}
+	}
+}
+
+func TestTranslator_NarrativeTagOffSendsOneRequest(t *testing.T) {
+	var requests []gemini.RequestData
+	tr := &Translator{geminiClient: &gemini.MockClient{
+		TranslateFunc: func(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			requests = append(requests, req)
+			return &gemini.ResponseData{}, nil
+		},
+	}}
+	targets := []srt.Segment{{ID: 1, Lines: []string{"a"}}, {ID: 2, Lines: []string{"b"}, Forced: true}}
+	req := gemini.RequestData{Target: toSegmentData(targets)}
+	if _, err := tr.translateRouted(context.Background(), req, targets); err != nil {
+		t.Fatalf("translateRouted: %v", err)
+	}
+	if len(requests) != 1 || len(requests[0].Target) != 2 || requests[0].SystemInstruction != "" {
+		t.Fatalf("requests = %+v, want one unchanged request", requests)
+	}
+}
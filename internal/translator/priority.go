@@ -0,0 +1,77 @@
+package translator
+
+import (
+	"sync"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// SetPriorityFirst streams results front of file first for near-real-time
+// use. Workers already take chunks in file order; with onFlush set, whenever
+// the run of finished chunks at the start of the file grows, onFlush receives
+// that run's segments, in order and never concurrently. A chunk that fails
+// keeps its source text there so one failure does not hold back the rest.
+// nil disables flushing.
+func (t *Translator) SetPriorityFirst(onFlush func([]srt.Segment)) {
+	t.onFlush = onFlush
+}
+
+// prefixFlusher tracks which chunks have finished and reports the finished
+// prefix of the file each time it grows.
+type prefixFlusher struct {
+	mu      sync.Mutex
+	onFlush func([]srt.Segment)
+	chunks  []chunker.Chunk
+	done    []bool
+	out     [][]srt.Segment
+	next    int
+}
+
+// newPrefixFlusher returns nil when onFlush is nil. Chunks outside
+// toTranslate count as finished with their source text.
+func newPrefixFlusher(onFlush func([]srt.Segment), chunks []chunker.Chunk, toTranslate map[int]bool) *prefixFlusher {
+	if onFlush == nil {
+		return nil
+	}
+	f := &prefixFlusher{
+		onFlush: onFlush,
+		chunks:  chunks,
+		done:    make([]bool, len(chunks)),
+		out:     make([][]srt.Segment, len(chunks)),
+	}
+	for i := range chunks {
+		if !toTranslate[i] {
+			f.done[i] = true
+			f.out[i] = chunks[i].Target
+		}
+	}
+	return f
+}
+
+// finish records chunk i's translation, or its failure when translated is
+// nil, and flushes the prefix if it grew.
+func (f *prefixFlusher) finish(i int, translated []srt.Segment) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if translated == nil {
+		translated = f.chunks[i].Target
+	}
+	f.done[i] = true
+	f.out[i] = translated
+	prev := f.next
+	for f.next < len(f.done) && f.done[f.next] {
+		f.next++
+	}
+	if f.next == prev {
+		return
+	}
+	var segments []srt.Segment
+	for _, part := range f.out[:f.next] {
+		segments = append(segments, part...)
+	}
+	f.onFlush(segments)
+}
@@ -0,0 +1,114 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/chunker"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// delayMockClient delays its answer for each segment ID listed in delays and
+// records the order in which chunks complete.
+type delayMockClient struct {
+	delays map[int]time.Duration
+
+	mu        sync.Mutex
+	completed []int
+}
+
+func (m *delayMockClient) SetSystemInstruction(string) {}
+
+func (m *delayMockClient) Translate(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	id := req.Target[0].ID
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(m.delays[id]):
+	}
+	m.mu.Lock()
+	m.completed = append(m.completed, id)
+	m.mu.Unlock()
+	return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: id, Line1: fmt.Sprintf("번역 %d", id)}}}, nil
+}
+
+func TestTranslator_PriorityFirstFlushesFrontPrefix(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	tests := []struct {
+		name   string
+		delays map[int]time.Duration
+		// wantFlushes lists the segment count of each flush, in order.
+		wantFlushes []int
+	}{
+		{name: "front chunk flushed before later chunks finish", delays: map[int]time.Duration{2: 150 * time.Millisecond, 3: 300 * time.Millisecond}, wantFlushes: []int{1, 2, 3}},
+		{name: "later chunks wait for the front", delays: map[int]time.Duration{1: 200 * time.Millisecond}, wantFlushes: []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &delayMockClient{delays: tt.delays}
+			src, _ := language.GetLanguage("en")
+			tgt, _ := language.GetLanguage("ko")
+			tr, err := NewTranslator(client, 1, 0, 3, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			var flushes [][]srt.Segment
+			var completedAtFlush [][]int
+			tr.SetPriorityFirst(func(done []srt.Segment) {
+				flushes = append(flushes, done)
+				client.mu.Lock()
+				completedAtFlush = append(completedAtFlush, append([]int(nil), client.completed...))
+				client.mu.Unlock()
+			})
+
+			segments := []srt.Segment{
+				{ID: 1, Lines: []string{"one"}},
+				{ID: 2, Lines: []string{"two"}},
+				{ID: 3, Lines: []string{"three"}},
+			}
+			translated, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+			if err != nil || len(failed) != 0 {
+				t.Fatalf("TranslateSRT = failed %v, err %v", failed, err)
+			}
+
+			var sizes []int
+			for _, f := range flushes {
+				sizes = append(sizes, len(f))
+			}
+			if !reflect.DeepEqual(sizes, tt.wantFlushes) {
+				t.Fatalf("flush sizes = %v, want %v", sizes, tt.wantFlushes)
+			}
+			if first := completedAtFlush[0]; len(first) != tt.wantFlushes[0] {
+				t.Fatalf("first flush happened after chunks %v completed, want only the front", first)
+			}
+			if last := flushes[len(flushes)-1]; !reflect.DeepEqual(last, translated) {
+				t.Fatalf("last flush = %+v, want the full translation %+v", last, translated)
+			}
+		})
+	}
+}
+
+func TestPrefixFlusher_UntranslatedAndFailedChunksKeepSource(t *testing.T) {
+	segments := []srt.Segment{{ID: 1, Lines: []string{"one"}}, {ID: 2, Lines: []string{"two"}}}
+	var got []srt.Segment
+	// Chunk 0 is outside the run (as in repair) and chunk 1 fails.
+	f := newPrefixFlusher(func(done []srt.Segment) { got = done }, chunker.SplitIntoChunks(segments, 1, 0), map[int]bool{1: true})
+	f.finish(1, nil)
+	if !reflect.DeepEqual(got, segments) {
+		t.Fatalf("flushed %+v, want source segments %+v", got, segments)
+	}
+}
@@ -124,3 +124,96 @@ func TestTranslator_RampUp(t *testing.T) {
 		t.Fatalf("ramp-up not applied: delta %v", times[2].Sub(times[1]))
 	}
 }
+
+func TestRampDelay_Distribution(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		ramp        time.Duration
+		want        []time.Duration
+	}{
+		{name: "default ramp", concurrency: 5, ramp: 2 * time.Second, want: []time.Duration{0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond, 2 * time.Second}},
+		{name: "stretched ramp", concurrency: 3, ramp: 10 * time.Second, want: []time.Duration{0, 5 * time.Second, 10 * time.Second}},
+		{name: "zero ramp", concurrency: 4, ramp: 0, want: []time.Duration{0, 0, 0, 0}},
+		{name: "single worker", concurrency: 1, ramp: 2 * time.Second, want: []time.Duration{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for worker, want := range tt.want {
+				if got := rampDelay(worker, tt.concurrency, tt.ramp); got != want {
+					t.Fatalf("rampDelay(%d, %d, %s) = %s, want %s", worker, tt.concurrency, tt.ramp, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslator_MaxSegmentsPerMinute(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 0
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	client := &timeMockClient{}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 2, 0, 3, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	// 1200 segments a minute is 2 segments, one chunk, every 100ms.
+	tr.SetMaxSegmentsPerMinute(1200)
+
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"a"}},
+		{ID: 2, Lines: []string{"b"}},
+		{ID: 3, Lines: []string{"c"}},
+		{ID: 4, Lines: []string{"d"}},
+		{ID: 5, Lines: []string{"e"}},
+		{ID: 6, Lines: []string{"f"}},
+	}
+
+	start := time.Now()
+	if _, _, err := tr.TranslateSRT(context.Background(), segments, nil); err != nil {
+		t.Fatalf("TranslateSRT failed: %v", err)
+	}
+
+	client.mu.Lock()
+	times := append([]time.Time(nil), client.times...)
+	client.mu.Unlock()
+	if len(times) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(times))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	if d := times[0].Sub(start); d > 50*time.Millisecond {
+		t.Errorf("first chunk waited %v, want it sent at once", d)
+	}
+	for i := 1; i < len(times); i++ {
+		if d := times[i].Sub(times[i-1]); d < 80*time.Millisecond {
+			t.Errorf("chunk %d sent %v after the previous one, want about 100ms", i+1, d)
+		}
+	}
+}
+
+func TestSegmentBudget_Reserve(t *testing.T) {
+	if newSegmentBudget(0, 5) != nil {
+		t.Fatal("a zero budget should be disabled")
+	}
+	b := newSegmentBudget(60, 5)
+	if d := b.reserve(5); d != 0 {
+		t.Fatalf("burst reserve waited %v", d)
+	}
+	// The bucket is empty, so 3 more segments at 1/s wait about 3s, and the
+	// debt pushes the next reservation back further.
+	if d := b.reserve(3); d < 2900*time.Millisecond || d > 3*time.Second {
+		t.Fatalf("reserve(3) = %v, want about 3s", d)
+	}
+	if d := b.reserve(1); d < 3900*time.Millisecond || d > 4*time.Second {
+		t.Fatalf("reserve(1) after debt = %v, want about 4s", d)
+	}
+}
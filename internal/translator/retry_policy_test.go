@@ -3,8 +3,11 @@ package translator
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/oukeidos/focst/internal/apperrors"
 	"github.com/oukeidos/focst/internal/gemini"
@@ -118,3 +121,196 @@ func TestRetryPolicy_TransientRetries(t *testing.T) {
 		t.Fatalf("expected 3 attempts for transient errors, got %d", client.calls)
 	}
 }
+
+func TestRetryDecision_PrefersRetryAfter(t *testing.T) {
+	err := apperrors.WithRetryAfter(apperrors.RateLimit(errors.New("429")), 42*time.Second)
+	retry, backoff := retryDecision(context.Background(), err, 1, 3, DefaultJitterMax)
+	if !retry {
+		t.Fatalf("expected rate limit error to be retried")
+	}
+	if backoff != 42*time.Second {
+		t.Fatalf("expected retry-after delay 42s, got %v", backoff)
+	}
+
+	huge := apperrors.WithRetryAfter(apperrors.RateLimit(errors.New("429")), 3*time.Hour)
+	if retry, backoff = retryDecision(context.Background(), huge, 1, 3, DefaultJitterMax); !retry || backoff != MaxRetryAfter {
+		t.Fatalf("expected a 3h retry-after to be capped at %v, got %v (retry=%v)", MaxRetryAfter, backoff, retry)
+	}
+
+	retry, backoff = retryDecision(context.Background(), apperrors.RateLimit(errors.New("429")), 1, 3, DefaultJitterMax)
+	if !retry {
+		t.Fatalf("expected rate limit error to be retried")
+	}
+	if backoff < 2*time.Second || backoff >= 3*time.Second {
+		t.Fatalf("expected computed backoff in [2s, 3s), got %v", backoff)
+	}
+}
+
+func TestRetryDecision_JitterMax(t *testing.T) {
+	transient := apperrors.Transient(errors.New("temporary"))
+	rateLimit := apperrors.RateLimit(errors.New("429"))
+
+	for _, tt := range []struct {
+		err     error
+		attempt int
+		want    time.Duration
+	}{
+		{transient, 1, 1 * time.Second},
+		{transient, 2, 2 * time.Second},
+		{rateLimit, 1, 2 * time.Second},
+		{rateLimit, 2, 4 * time.Second},
+	} {
+		for i := 0; i < 20; i++ {
+			if _, backoff := retryDecision(context.Background(), tt.err, tt.attempt, 5, 0); backoff != tt.want {
+				t.Fatalf("attempt %d without jitter: backoff %v, want exactly %v", tt.attempt, backoff, tt.want)
+			}
+		}
+	}
+
+	jitterMax := 10 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		_, backoff := retryDecision(context.Background(), transient, 1, 5, jitterMax)
+		if backoff < time.Second || backoff >= time.Second+jitterMax {
+			t.Fatalf("backoff %v outside [1s, 1s+%v)", backoff, jitterMax)
+		}
+	}
+}
+
+func TestRetryDecision_RequestTimeoutRetriesUnlessCanceled(t *testing.T) {
+	timeoutErr := apperrors.New(apperrors.KindTransient, "request timed out", fmt.Errorf("call: %w", context.DeadlineExceeded))
+	if retry, _ := retryDecision(context.Background(), timeoutErr, 1, 3, DefaultJitterMax); !retry {
+		t.Fatalf("expected per-request timeout to be retried while the run is live")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if retry, _ := retryDecision(ctx, timeoutErr, 1, 3, DefaultJitterMax); retry {
+		t.Fatalf("expected no retry once the run context is canceled")
+	}
+}
+
+func TestRetryPolicy_RateLimitedProgress(t *testing.T) {
+	client := &sequenceClient{
+		responses: []sequenceResponse{
+			{err: apperrors.WithRetryAfter(apperrors.RateLimit(errors.New("429")), 10*time.Millisecond)},
+			{err: apperrors.Transient(errors.New("temporary"))},
+			{
+				resp: &gemini.ResponseData{
+					Translations: []gemini.TranslatedSegment{
+						{ID: 1, Line1: "ok"},
+					},
+				},
+			},
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 1, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	segments := []srt.Segment{{ID: 1, Lines: []string{"hello"}}}
+
+	var retries []bool
+	_, failed, err := tr.TranslateSRT(context.Background(), segments, func(p TranslationProgress) {
+		if p.State == StateInProgress {
+			retries = append(retries, p.RateLimited)
+		} else if p.RateLimited {
+			t.Errorf("RateLimited set on state %v", p.State)
+		}
+	})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("TranslateSRT failed=%v err=%v", failed, err)
+	}
+	if fmt.Sprint(retries) != "[true false]" {
+		t.Fatalf("retry RateLimited flags = %v, want [true false]", retries)
+	}
+}
+
+type temperatureClient struct {
+	sequenceClient
+	temperatures []*float32
+}
+
+func (c *temperatureClient) Translate(ctx context.Context, request gemini.RequestData) (*gemini.ResponseData, error) {
+	c.mu.Lock()
+	c.temperatures = append(c.temperatures, request.Temperature)
+	c.mu.Unlock()
+	return c.sequenceClient.Translate(ctx, request)
+}
+
+func formatTemperatures(temps []*float32) string {
+	parts := make([]string, len(temps))
+	for i, temp := range temps {
+		if temp == nil {
+			parts[i] = "base"
+		} else {
+			parts[i] = fmt.Sprintf("%.1f", *temp)
+		}
+	}
+	return strings.Join(parts, " -> ")
+}
+
+func TestRetryPolicy_TemperatureEscalation(t *testing.T) {
+	tooLong := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: 1, Line1: strings.Repeat("가", 100)}}}
+	ok := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: 1, Line1: "안녕"}}}
+	tests := []struct {
+		name      string
+		responses []sequenceResponse
+		want      string
+	}{
+		{
+			name:      "validation failures escalate",
+			responses: []sequenceResponse{{resp: tooLong}, {resp: tooLong}, {resp: ok}},
+			want:      "base -> 0.4 -> 0.8",
+		},
+		{
+			name: "rate limit retries stay at base",
+			responses: []sequenceResponse{
+				{err: apperrors.RateLimit(errors.New("429"))},
+				{err: apperrors.Transient(errors.New("503"))},
+				{resp: ok},
+			},
+			want: "base -> base -> base",
+		},
+		{
+			name: "transient retry after validation failure resets",
+			responses: []sequenceResponse{
+				{resp: tooLong},
+				{err: apperrors.Transient(errors.New("503"))},
+				{resp: ok},
+			},
+			want: "base -> 0.4 -> base",
+		},
+	}
+
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := &temperatureClient{sequenceClient: sequenceClient{responses: tt.responses}}
+			tr, err := NewTranslator(client, 1, 0, 1, true, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			segments := []srt.Segment{{ID: 1, Lines: []string{"hello"}}}
+			if _, failed, err := tr.TranslateSRT(context.Background(), segments, nil); err != nil || len(failed) != 0 {
+				t.Fatalf("TranslateSRT failed: %v (failed chunks %v)", err, failed)
+			}
+			if got := formatTemperatures(client.temperatures); got != tt.want {
+				t.Errorf("temperatures = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTemperature_HoldsLastStep(t *testing.T) {
+	if got := retryTemperature(0); got != nil {
+		t.Fatalf("retryTemperature(0) = %v, want nil", *got)
+	}
+	last := validationRetryTemperatures[len(validationRetryTemperatures)-1]
+	if got := retryTemperature(len(validationRetryTemperatures) + 3); got == nil || *got != last {
+		t.Fatalf("retryTemperature past the last step = %v, want %v", got, last)
+	}
+}
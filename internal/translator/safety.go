@@ -0,0 +1,77 @@
+package translator
+
+import (
+	"context"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/logger"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// SetRelaxSafety allows resending a chunk the safety filters blocked once
+// with relaxed thresholds (see gemini.RequestData.RelaxSafety) before it is
+// split. Without it, a blocked chunk is split right away.
+func (t *Translator) SetRelaxSafety(enabled bool) {
+	t.relaxSafety = enabled
+}
+
+// recoverSafetyBlock answers a request the safety filters blocked. Resending
+// the same request is futile, so it first resends it with relaxed thresholds
+// when allowed, then translates the target segments in two halves, so that
+// only the half holding the offending lines can stay blocked. A chunk that is
+// still blocked fails with a safety_block error saying what was tried; the
+// tokens a translated first half used are still counted.
+func (t *Translator) recoverSafetyBlock(ctx context.Context, req gemini.RequestData, targets []srt.Segment, blockErr error) (*gemini.ResponseData, error) {
+	if t.relaxSafety {
+		logger.Warn("Chunk blocked by safety filters; retrying with relaxed thresholds", "segments", len(req.Target))
+		req.RelaxSafety = true
+		resp, err := t.translateRouted(ctx, req, targets)
+		if !apperrors.IsSafetyBlock(err) {
+			return resp, err
+		}
+		blockErr = err
+	}
+	split := len(req.Target) > 1
+	if split {
+		logger.Warn("Chunk blocked by safety filters; translating it in two parts", "segments", len(req.Target))
+		mid := len(req.Target) / 2
+		merged := &gemini.ResponseData{}
+		for _, half := range [][2]int{{0, mid}, {mid, len(req.Target)}} {
+			partReq := req
+			partReq.Target = req.Target[half[0]:half[1]]
+			resp, err := t.translateRouted(ctx, partReq, targets[half[0]:half[1]])
+			if err != nil {
+				t.usageMu.Lock()
+				t.usage.PromptTokenCount += merged.Usage.PromptTokenCount
+				t.usage.CandidatesTokenCount += merged.Usage.CandidatesTokenCount
+				t.usage.TotalTokenCount += merged.Usage.TotalTokenCount
+				t.usageMu.Unlock()
+				if !apperrors.IsSafetyBlock(err) {
+					return nil, err
+				}
+				blockErr, merged = err, nil
+				break
+			}
+			merged.Translations = append(merged.Translations, resp.Translations...)
+			merged.Usage.PromptTokenCount += resp.Usage.PromptTokenCount
+			merged.Usage.CandidatesTokenCount += resp.Usage.CandidatesTokenCount
+			merged.Usage.TotalTokenCount += resp.Usage.TotalTokenCount
+		}
+		if merged != nil {
+			return merged, nil
+		}
+	}
+	var msg string
+	switch {
+	case t.relaxSafety && split:
+		msg = "Blocked by Gemini safety filters even with relaxed thresholds and split in two."
+	case t.relaxSafety:
+		msg = "Blocked by Gemini safety filters even with relaxed thresholds."
+	case split:
+		msg = "Blocked by Gemini safety filters even split in two; relaxing the safety thresholds may help."
+	default:
+		msg = "Blocked by Gemini safety filters; relaxing the safety thresholds may help."
+	}
+	return nil, apperrors.New(apperrors.KindSafetyBlock, msg, blockErr)
+}
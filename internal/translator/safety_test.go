@@ -0,0 +1,220 @@
+package translator
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestTranslator_SafetyBlockRecovery(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"one"}},
+		{ID: 2, Lines: []string{"two"}},
+		{ID: 3, Lines: []string{"three"}},
+		{ID: 4, Lines: []string{"four"}},
+	}
+	// blocked answers with a safety block, as the client does for a
+	// response without candidates.
+	blocked := apperrors.SafetyBlock(gemini.ErrNoCandidates)
+
+	cases := []struct {
+		name      string
+		relax     bool
+		block     func(req gemini.RequestData) bool
+		wantCalls int
+		wantFail  bool
+	}{
+		{
+			name:      "SplitUnblocks",
+			block:     func(req gemini.RequestData) bool { return len(req.Target) > 2 },
+			wantCalls: 3,
+		},
+		{
+			name:      "RelaxUnblocks",
+			relax:     true,
+			block:     func(req gemini.RequestData) bool { return !req.RelaxSafety },
+			wantCalls: 2,
+		},
+		{
+			name:      "StillBlocked",
+			relax:     true,
+			block:     func(req gemini.RequestData) bool { return req.Target[0].ID <= 2 },
+			wantCalls: 3,
+			wantFail:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := 0
+			client := &gemini.MockClient{
+				TranslateFunc: func(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+					mu.Lock()
+					calls++
+					mu.Unlock()
+					if tc.block(req) {
+						return nil, blocked
+					}
+					resp := &gemini.ResponseData{}
+					for _, seg := range req.Target {
+						resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+					}
+					return resp, nil
+				},
+			}
+			src, _ := language.GetLanguage("en")
+			tgt, _ := language.GetLanguage("ko")
+			tr, err := NewTranslator(client, 4, 0, 1, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			tr.SetRelaxSafety(tc.relax)
+
+			translated, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+			if err != nil {
+				t.Fatalf("TranslateSRT failed: %v", err)
+			}
+			if calls != tc.wantCalls {
+				t.Fatalf("calls = %d, want %d", calls, tc.wantCalls)
+			}
+			if tc.wantFail {
+				if len(failed) != 1 {
+					t.Fatalf("failed = %v, want the blocked chunk", failed)
+				}
+				stats := tr.Throughput()
+				if stats.FailedKinds[apperrors.KindSafetyBlock] != 1 {
+					t.Fatalf("failed kinds = %v, want one safety_block", stats.FailedKinds)
+				}
+				return
+			}
+			if len(failed) != 0 {
+				t.Fatalf("failed = %v, want none", failed)
+			}
+			for i, seg := range translated {
+				if seg.Lines[0] != "T-"+segments[i].Lines[0] {
+					t.Fatalf("segment %d = %+v, want translation in place", i, seg)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslator_SafetySplitSendsEachHalfOwnTargets(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	var mu sync.Mutex
+	empty := 0
+	client := &gemini.MockClient{
+		TranslateFunc: func(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if len(req.Target) == 0 {
+				empty++
+			}
+			if len(req.Target) > 2 {
+				return nil, apperrors.SafetyBlock(gemini.ErrNoCandidates)
+			}
+			resp := &gemini.ResponseData{}
+			for _, seg := range req.Target {
+				resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return resp, nil
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 4, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetNarrative(srt.NarrativeForced)
+
+	// The dialogue request holds three targets and is blocked, so the chunk
+	// is split; its first half has no on-screen text although the chunk does.
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"one"}},
+		{ID: 2, Lines: []string{"two"}},
+		{ID: 3, Lines: []string{"three"}},
+		{ID: 4, Lines: []string{"EXIT"}, Forced: true},
+	}
+	translated, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("TranslateSRT = failed %v, err %v", failed, err)
+	}
+	if empty != 0 {
+		t.Fatalf("sent %d requests without targets", empty)
+	}
+	for i, seg := range translated {
+		if seg.Lines[0] != "T-"+segments[i].Lines[0] {
+			t.Fatalf("segment %d = %+v, want translation in place", i, seg)
+		}
+	}
+}
+
+func TestTranslator_SafetySplitCountsFirstHalfUsage(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	client := &gemini.MockClient{
+		TranslateFunc: func(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			for _, seg := range req.Target {
+				if seg.ID == 4 {
+					return nil, apperrors.SafetyBlock(gemini.ErrNoCandidates)
+				}
+			}
+			resp := &gemini.ResponseData{Usage: gemini.UsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15}}
+			for _, seg := range req.Target {
+				resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T-" + seg.Lines[0]})
+			}
+			return resp, nil
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 4, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"one"}},
+		{ID: 2, Lines: []string{"two"}},
+		{ID: 3, Lines: []string{"three"}},
+		{ID: 4, Lines: []string{"four"}},
+	}
+	_, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+	if err != nil || len(failed) != 1 {
+		t.Fatalf("TranslateSRT = failed %v, err %v, want the blocked chunk", failed, err)
+	}
+	want := gemini.UsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15}
+	if usage := tr.GetUsage(); usage != want {
+		t.Fatalf("usage = %+v, want the first half's tokens counted", usage)
+	}
+}
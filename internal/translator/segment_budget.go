@@ -0,0 +1,74 @@
+package translator
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SetMaxSegmentsPerMinute paces requests so that at most n segments a minute
+// are submitted, on top of the request rate limit, so a shared API key keeps
+// room for other tools when chunk sizes vary. Retries of a chunk are not
+// counted again. n <= 0 (the default) disables the budget.
+func (t *Translator) SetMaxSegmentsPerMinute(n int) {
+	t.segmentRate = n
+}
+
+// segmentBudget is a token bucket over segment counts. It holds up to burst
+// segments and refills at the per-minute rate. A request may take more than
+// the bucket holds; the debt delays the requests after it, so the long-run
+// rate stays within the budget.
+type segmentBudget struct {
+	mu     sync.Mutex
+	rate   float64 // segments per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newSegmentBudget returns nil when perMinute <= 0.
+func newSegmentBudget(perMinute, burst int) *segmentBudget {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &segmentBudget{
+		rate:   float64(perMinute) / 60,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes n segments from the bucket and returns how long to wait
+// before submitting them.
+func (b *segmentBudget) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// wait blocks until n segments may be submitted or ctx is done.
+func (b *segmentBudget) wait(ctx context.Context, n int) error {
+	delay := b.reserve(n)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
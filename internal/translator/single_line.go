@@ -0,0 +1,36 @@
+package translator
+
+import (
+	"strings"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+)
+
+// SetSingleLine restricts every translated segment to one line, for players
+// and burned-in subtitles that cannot show two. The prompt forbids 'line2',
+// and any second line the model returns anyway is joined onto line1 before
+// the CPL check, so an over-long result is retried like any other.
+func (t *Translator) SetSingleLine(enabled bool) {
+	t.singleLine = enabled
+}
+
+// joinLines joins lines with a space, or directly for targets that do not
+// separate words with spaces (Japanese, Chinese, Thai, ...).
+func (t *Translator) joinLines(lines []string) string {
+	sep := " "
+	if !language.UsesWordSpaces(t.tgtLang.Code) {
+		sep = ""
+	}
+	return strings.Join(lines, sep)
+}
+
+// collapseResponseLines folds line2, and any line breaks in line1, into a
+// single line1.
+func (t *Translator) collapseResponseLines(resp *gemini.ResponseData) {
+	for i, tr := range resp.Translations {
+		lines := normalizeLines(tr.Line1, tr.Line2)
+		resp.Translations[i].Line1 = t.joinLines(lines)
+		resp.Translations[i].Line2 = ""
+	}
+}
@@ -0,0 +1,96 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestGetSystemPrompt_SingleLineForbidsLine2(t *testing.T) {
+	prompt := GetSystemPrompt("Japanese", "Korean", 16, true, true)
+	if !strings.Contains(prompt, "NEVER include 'line2'") || !strings.Contains(prompt, "strictly 16 characters") {
+		t.Fatalf("single-line prompt missing the rule or the limit:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "'line2': ") {
+		t.Fatalf("single-line prompt still describes line2:\n%s", prompt)
+	}
+	if !strings.Contains(GetSystemPrompt("Japanese", "Korean", 16, true, false), "'line2': ") {
+		t.Fatalf("default prompt lost line2")
+	}
+}
+
+func TestTranslator_SingleLineCollapsesAndValidates(t *testing.T) {
+	oldQPS := defaultQPS
+	oldRamp := defaultRampUp
+	defaultQPS = 1000
+	defaultRampUp = 0
+	defer func() {
+		defaultQPS = oldQPS
+		defaultRampUp = oldRamp
+	}()
+
+	client := &gemini.MockClient{
+		TranslateFunc: func(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{
+				{ID: 1, Line1: "안녕하세요", Line2: "반가워요"},
+				{ID: 2, Line1: "가자\n빨리"},
+			}}, nil
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 2, 0, 1, true, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetSingleLine(true)
+
+	segments := []srt.Segment{{ID: 1, Lines: []string{"Hello", "nice to meet you"}}, {ID: 2, Lines: []string{"Let's go"}}}
+	translated, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("TranslateSRT = failed %v, err %v", failed, err)
+	}
+	if !strings.Contains(client.LastSystemInstruction, "NEVER include 'line2'") {
+		t.Fatalf("system prompt does not forbid line2")
+	}
+	want := [][]string{{"안녕하세요 반가워요"}, {"가자 빨리"}}
+	for i, seg := range translated {
+		if !reflect.DeepEqual(seg.Lines, want[i]) {
+			t.Fatalf("segment %d lines = %q, want %q", seg.ID, seg.Lines, want[i])
+		}
+	}
+}
+
+func TestTranslator_SingleLineJoinedLineIsCPLChecked(t *testing.T) {
+	tgt, _ := language.GetLanguage("ko")
+	tr := &Translator{tgtLang: tgt, validateCPL: true, singleLine: true}
+	limit := int(tr.lineLimit())
+	line := strings.Repeat("가", limit/2+1)
+
+	resp := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: 1, Line1: line, Line2: line}}}
+	if err := tr.validateResponse(resp); err != nil {
+		t.Fatalf("each line fits on its own, got %v", err)
+	}
+	tr.collapseResponseLines(resp)
+	if resp.Translations[0].Line2 != "" || resp.Translations[0].Line1 != line+" "+line {
+		t.Fatalf("collapsed to %+v", resp.Translations[0])
+	}
+	if err := tr.validateResponse(resp); err == nil || !strings.Contains(err.Error(), "line 1 too long") {
+		t.Fatalf("expected the joined line to fail the CPL check, got %v", err)
+	}
+}
+
+func TestTranslator_SingleLineJoinsWithoutSpaceForJapanese(t *testing.T) {
+	tgt, _ := language.GetLanguage("ja")
+	tr := &Translator{tgtLang: tgt, singleLine: true}
+	resp := &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: 1, Line1: "行こう", Line2: "早く"}}}
+	tr.collapseResponseLines(resp)
+	if got := resp.Translations[0].Line1; got != "行こう早く" {
+		t.Fatalf("Line1 = %q, want 行こう早く", got)
+	}
+}
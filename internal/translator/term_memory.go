@@ -0,0 +1,127 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/oukeidos/focst/internal/srt"
+	"github.com/rivo/uniseg"
+)
+
+// DefaultTermMemoryLimit bounds how many remembered pairs are injected into the prompt.
+const DefaultTermMemoryLimit = 40
+
+// maxTermGraphemes keeps term memory to short, phrase-like lines; long
+// dialogue lines rarely recur verbatim and would bloat the prompt.
+const maxTermGraphemes = 20
+
+// TermEntry is a remembered source phrase and the translation chosen for it.
+type TermEntry struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// TermMemory carries recent phrase choices between files (e.g. episodes of a
+// series) so later translations stay consistent. It holds at most limit
+// entries, evicting the oldest first. It is safe for concurrent use.
+type TermMemory struct {
+	mu      sync.Mutex
+	limit   int
+	entries []TermEntry
+}
+
+// NewTermMemory returns a memory seeded with entries, keeping the most recent
+// limit of them. A non-positive limit uses DefaultTermMemoryLimit.
+func NewTermMemory(limit int, entries []TermEntry) *TermMemory {
+	if limit <= 0 {
+		limit = DefaultTermMemoryLimit
+	}
+	m := &TermMemory{limit: limit}
+	for _, e := range entries {
+		m.Add(e.Source, e.Target)
+	}
+	return m
+}
+
+// Add records a pair as the most recent choice for source.
+func (m *TermMemory) Add(source, target string) {
+	source = strings.TrimSpace(source)
+	target = strings.TrimSpace(target)
+	if source == "" || target == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.entries {
+		if e.Source == source {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+			break
+		}
+	}
+	m.entries = append(m.entries, TermEntry{Source: source, Target: target})
+	if over := len(m.entries) - m.limit; over > 0 {
+		m.entries = append([]TermEntry(nil), m.entries[over:]...)
+	}
+}
+
+// Entries returns a copy of the remembered pairs, oldest first.
+func (m *TermMemory) Entries() []TermEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TermEntry(nil), m.entries...)
+}
+
+// RecordSegments remembers short single-line source/translation pairs from a
+// finished file. Segments whose text is unchanged (passthrough or failed
+// chunks keeping source text) are skipped.
+func (m *TermMemory) RecordSegments(source, translated []srt.Segment) {
+	for i := 0; i < len(source) && i < len(translated); i++ {
+		if len(source[i].Lines) != 1 || len(translated[i].Lines) != 1 {
+			continue
+		}
+		src := strings.TrimSpace(source[i].Lines[0])
+		tgt := strings.TrimSpace(translated[i].Lines[0])
+		if src == tgt || !hasLetter(src) || uniseg.GraphemeClusterCount(src) > maxTermGraphemes {
+			continue
+		}
+		m.Add(src, tgt)
+	}
+}
+
+// termPromptSection renders remembered pairs as system prompt guidance, or ""
+// when there are none.
+func termPromptSection(entries []TermEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nFor consistency with earlier translations (previous files in this series or other parts of this file), reuse these choices when the same phrase appears:\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "- %s -> %s\n", e.Source, e.Target)
+	}
+	return b.String()
+}
+
+// SetTermMemory injects remembered phrase choices into the system prompt.
+func (t *Translator) SetTermMemory(memory *TermMemory) {
+	t.termMemory = memory
+}
+
+// termEntries returns the entries of the term memory, or nil without one.
+func (t *Translator) termEntries() []TermEntry {
+	if t.termMemory == nil {
+		return nil
+	}
+	return t.termMemory.Entries()
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
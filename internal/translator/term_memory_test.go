@@ -0,0 +1,74 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+func TestTermMemory_BoundedAndDeduplicated(t *testing.T) {
+	m := NewTermMemory(3, nil)
+	m.Add("a", "A1")
+	m.Add("b", "B")
+	m.Add("c", "C")
+	m.Add("a", "A2") // refreshes "a" as most recent
+	m.Add("d", "D")  // evicts "b", the oldest
+
+	got := m.Entries()
+	want := []TermEntry{{"c", "C"}, {"a", "A2"}, {"d", "D"}}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("entries[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTermMemory_RecordSegmentsFilters(t *testing.T) {
+	source := []srt.Segment{
+		{ID: 1, Lines: []string{"魔法学院"}},
+		{ID: 2, Lines: []string{"これはとても長い台詞なので用語として記憶するべきではありません"}},
+		{ID: 3, Lines: []string{"♪"}},
+		{ID: 4, Lines: []string{"未翻訳"}},
+		{ID: 5, Lines: []string{"一行目", "二行目"}},
+	}
+	translated := []srt.Segment{
+		{ID: 1, Lines: []string{"마법 학원"}},
+		{ID: 2, Lines: []string{"긴 대사"}},
+		{ID: 3, Lines: []string{"♫"}},
+		{ID: 4, Lines: []string{"未翻訳"}},
+		{ID: 5, Lines: []string{"첫 줄", "둘째 줄"}},
+	}
+	m := NewTermMemory(0, nil)
+	m.RecordSegments(source, translated)
+
+	got := m.Entries()
+	if len(got) != 1 || got[0] != (TermEntry{Source: "魔法学院", Target: "마법 학원"}) {
+		t.Fatalf("expected only the short translated phrase, got %v", got)
+	}
+}
+
+func TestTranslator_TermMemoryInPrompt(t *testing.T) {
+	mock := &gemini.MockClient{Response: &gemini.ResponseData{
+		Translations: []gemini.TranslatedSegment{{ID: 1, Line1: "안녕"}},
+	}}
+	tr, err := NewTranslator(mock, 1, 0, 1, false, language.Language{Name: "Japanese", Code: "ja"}, language.Language{Name: "Korean", Code: "ko"})
+	if err != nil {
+		t.Fatalf("NewTranslator failed: %v", err)
+	}
+	tr.SetTermMemory(NewTermMemory(0, []TermEntry{{Source: "魔法学院", Target: "마법 학원"}}))
+
+	segments := []srt.Segment{{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"こんにちは"}}}
+	if _, _, err := tr.TranslateSRT(context.Background(), segments, nil); err != nil {
+		t.Fatalf("TranslateSRT failed: %v", err)
+	}
+	if !strings.Contains(mock.LastSystemInstruction, "- 魔法学院 -> 마법 학원") {
+		t.Fatalf("expected term memory in system prompt, got:\n%s", mock.LastSystemInstruction)
+	}
+}
@@ -0,0 +1,150 @@
+package translator
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/gemini"
+)
+
+// throughputNow is swapped in tests to simulate timed chunk completions.
+var throughputNow = time.Now
+
+// Throughput is a snapshot of the current run's progress and rate, used to
+// estimate the time and tokens still needed.
+type Throughput struct {
+	Elapsed   time.Duration
+	Scheduled int // chunks scheduled in this run
+	Completed int // chunks translated, reused from cache, or filled from repeats
+	Failed    int
+	// FailedKinds counts failed chunks by the apperrors kind of their last
+	// error. Unclassified failures (e.g. cancellation) are not counted.
+	FailedKinds map[apperrors.Kind]int
+	// Failures holds the last error of each failed chunk, ordered by chunk
+	// index. Chunks left unfinished by cancellation are not listed.
+	Failures []ChunkFailure
+	// Usage counts tokens spent on completed chunks, including their retries.
+	Usage           gemini.UsageMetadata
+	TokensPerSecond float64
+	// Remaining is the estimated time until the remaining chunks finish at the
+	// observed chunk rate. It is zero until a chunk completes.
+	Remaining time.Duration
+	// ProjectedUsage adds the average usage of chunks that called the API for
+	// each chunk still pending.
+	ProjectedUsage gemini.UsageMetadata
+}
+
+// ChunkFailure describes why a chunk failed: the kind and user-facing
+// message of its last error and how many attempts it used. ChunkIndex uses
+// the numbering of the failed chunk indices the translate call returned.
+type ChunkFailure struct {
+	ChunkIndex int
+	// Kind is empty when the error was not classified.
+	Kind     apperrors.Kind
+	Message  string
+	Attempts int
+}
+
+// throughputTracker accumulates per-chunk usage deltas. Workers report
+// concurrently, so every method locks.
+type throughputTracker struct {
+	mu        sync.Mutex
+	start     time.Time
+	scheduled int
+	completed int
+	failed    int
+	billed    int // completed chunks that used tokens
+	usage     gemini.UsageMetadata
+	kinds     map[apperrors.Kind]int
+	failures  []ChunkFailure
+}
+
+func (tt *throughputTracker) reset(scheduled int) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.start = throughputNow()
+	tt.scheduled = scheduled
+	tt.completed, tt.failed, tt.billed = 0, 0, 0
+	tt.usage = gemini.UsageMetadata{}
+	tt.kinds = nil
+	tt.failures = nil
+}
+
+func (tt *throughputTracker) complete(usage gemini.UsageMetadata) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.completed++
+	if usage.TotalTokenCount > 0 {
+		tt.billed++
+	}
+	tt.usage.PromptTokenCount += usage.PromptTokenCount
+	tt.usage.CandidatesTokenCount += usage.CandidatesTokenCount
+	tt.usage.TotalTokenCount += usage.TotalTokenCount
+}
+
+func (tt *throughputTracker) fail(chunk, attempts int, err error) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.failed++
+	kind, ok := apperrors.KindOf(err)
+	if ok {
+		if tt.kinds == nil {
+			tt.kinds = make(map[apperrors.Kind]int)
+		}
+		tt.kinds[kind]++
+	}
+	tt.failures = append(tt.failures, ChunkFailure{
+		ChunkIndex: chunk,
+		Kind:       kind,
+		Message:    apperrors.PublicMessage(err),
+		Attempts:   attempts,
+	})
+}
+
+func (tt *throughputTracker) snapshot() Throughput {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	s := Throughput{
+		Scheduled:      tt.scheduled,
+		Completed:      tt.completed,
+		Failed:         tt.failed,
+		Usage:          tt.usage,
+		ProjectedUsage: tt.usage,
+	}
+	if len(tt.kinds) > 0 {
+		s.FailedKinds = make(map[apperrors.Kind]int, len(tt.kinds))
+		for kind, n := range tt.kinds {
+			s.FailedKinds[kind] = n
+		}
+	}
+	if len(tt.failures) > 0 {
+		s.Failures = append([]ChunkFailure(nil), tt.failures...)
+		sort.Slice(s.Failures, func(i, j int) bool { return s.Failures[i].ChunkIndex < s.Failures[j].ChunkIndex })
+	}
+	if tt.start.IsZero() {
+		return s
+	}
+	s.Elapsed = throughputNow().Sub(tt.start)
+	if s.Elapsed > 0 {
+		s.TokensPerSecond = float64(tt.usage.TotalTokenCount) / s.Elapsed.Seconds()
+	}
+	pending := tt.scheduled - tt.completed - tt.failed
+	if pending <= 0 || tt.completed == 0 {
+		return s
+	}
+	s.Remaining = s.Elapsed / time.Duration(tt.completed) * time.Duration(pending)
+	if tt.billed > 0 {
+		s.ProjectedUsage.PromptTokenCount += tt.usage.PromptTokenCount * pending / tt.billed
+		s.ProjectedUsage.CandidatesTokenCount += tt.usage.CandidatesTokenCount * pending / tt.billed
+		s.ProjectedUsage.TotalTokenCount += tt.usage.TotalTokenCount * pending / tt.billed
+	}
+	return s
+}
+
+// Throughput returns the progress and rate of the current (or last) run. It
+// is safe to call from progress callbacks and other goroutines.
+func (t *Translator) Throughput() Throughput {
+	return t.throughput.snapshot()
+}
@@ -0,0 +1,187 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oukeidos/focst/internal/apperrors"
+	"github.com/oukeidos/focst/internal/gemini"
+	"github.com/oukeidos/focst/internal/language"
+	"github.com/oukeidos/focst/internal/srt"
+)
+
+// fakeClock advances only when the mock client answers a request.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestTranslator_ThroughputEstimates(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	oldNow := throughputNow
+	throughputNow = clock.Now
+	defer func() { throughputNow = oldNow }()
+
+	client := &gemini.MockClient{
+		TranslateFunc: func(_ context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			clock.Advance(2 * time.Second)
+			resp := &gemini.ResponseData{
+				Usage: gemini.UsageMetadata{PromptTokenCount: 80, CandidatesTokenCount: 20, TotalTokenCount: 100},
+			}
+			for _, seg := range req.Target {
+				resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T"})
+			}
+			return resp, nil
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 1, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator: %v", err)
+	}
+	tr.SetRampUp(0)
+
+	segments := make([]srt.Segment, 4)
+	for i := range segments {
+		segments[i] = srt.Segment{ID: i + 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{fmt.Sprintf("line %d", i+1)}}
+	}
+
+	var snaps []Throughput
+	onProgress := func(p TranslationProgress) {
+		if p.State == StateCompleted {
+			snaps = append(snaps, tr.Throughput())
+		}
+	}
+	if _, failed, err := tr.TranslateSRT(context.Background(), segments, onProgress); err != nil || len(failed) != 0 {
+		t.Fatalf("TranslateSRT failed=%v err=%v", failed, err)
+	}
+	if len(snaps) != 4 {
+		t.Fatalf("got %d completion snapshots, want 4", len(snaps))
+	}
+
+	first := snaps[0]
+	if first.Scheduled != 4 || first.Completed != 1 || first.Elapsed != 2*time.Second {
+		t.Fatalf("first snapshot = %+v", first)
+	}
+	if first.TokensPerSecond != 50 {
+		t.Fatalf("TokensPerSecond = %v, want 50", first.TokensPerSecond)
+	}
+	if first.Remaining != 6*time.Second {
+		t.Fatalf("Remaining = %v, want 6s", first.Remaining)
+	}
+	if first.ProjectedUsage.TotalTokenCount != 400 || first.ProjectedUsage.PromptTokenCount != 320 {
+		t.Fatalf("ProjectedUsage = %+v, want 400 total / 320 prompt", first.ProjectedUsage)
+	}
+
+	final := tr.Throughput()
+	if final.Completed != 4 || final.Remaining != 0 || final.Elapsed != 8*time.Second {
+		t.Fatalf("final snapshot = %+v", final)
+	}
+	if final.Usage != final.ProjectedUsage || final.Usage.TotalTokenCount != 400 {
+		t.Fatalf("final usage = %+v, projected = %+v", final.Usage, final.ProjectedUsage)
+	}
+}
+
+func TestTranslator_ThroughputFailedKinds(t *testing.T) {
+	client := &gemini.MockClient{
+		TranslateFunc: func(_ context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			return nil, apperrors.Auth(fmt.Errorf("401"))
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 1, 0, 1, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator: %v", err)
+	}
+	tr.SetRampUp(0)
+
+	segments := []srt.Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"one"}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"two"}},
+	}
+	if _, failed, err := tr.TranslateSRT(context.Background(), segments, nil); err != nil || len(failed) != 2 {
+		t.Fatalf("TranslateSRT failed=%v err=%v", failed, err)
+	}
+	got := tr.Throughput()
+	if got.Failed != 2 || got.FailedKinds[apperrors.KindAuth] != 2 || len(got.FailedKinds) != 1 {
+		t.Fatalf("snapshot = %+v, want 2 auth failures", got)
+	}
+}
+
+func TestTranslator_ThroughputFailuresPerChunk(t *testing.T) {
+	oldQPS := defaultQPS
+	defaultQPS = 0
+	defer func() { defaultQPS = oldQPS }()
+
+	// Each failing chunk has its own error kind; the rate-limited one is
+	// retried until it runs out of attempts.
+	errs := map[string]error{
+		"one":   apperrors.Auth(fmt.Errorf("401")),
+		"two":   apperrors.WithRetryAfter(apperrors.RateLimit(fmt.Errorf("429")), time.Millisecond),
+		"three": apperrors.BadRequest(fmt.Errorf("400")),
+	}
+	client := &gemini.MockClient{
+		TranslateFunc: func(_ context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+			if err, ok := errs[req.Target[0].Lines[0]]; ok {
+				return nil, err
+			}
+			return &gemini.ResponseData{Translations: []gemini.TranslatedSegment{{ID: req.Target[0].ID, Line1: "ok"}}}, nil
+		},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+	tr, err := NewTranslator(client, 1, 0, 2, false, src, tgt)
+	if err != nil {
+		t.Fatalf("NewTranslator: %v", err)
+	}
+	tr.SetRampUp(0)
+
+	segments := []srt.Segment{
+		{ID: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Lines: []string{"one"}},
+		{ID: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Lines: []string{"fine"}},
+		{ID: 3, StartTime: "00:00:05,000", EndTime: "00:00:06,000", Lines: []string{"two"}},
+		{ID: 4, StartTime: "00:00:07,000", EndTime: "00:00:08,000", Lines: []string{"three"}},
+	}
+	_, failed, err := tr.TranslateSRT(context.Background(), segments, nil)
+	if err != nil || len(failed) != 3 {
+		t.Fatalf("TranslateSRT failed=%v err=%v", failed, err)
+	}
+	got := tr.Throughput().Failures
+	want := []struct {
+		chunk    int
+		kind     apperrors.Kind
+		attempts int
+	}{
+		{0, apperrors.KindAuth, 1},
+		{2, apperrors.KindRateLimit, 3},
+		{3, apperrors.KindBadRequest, 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Failures = %+v, want %d entries", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].ChunkIndex != w.chunk || got[i].Kind != w.kind || got[i].Attempts != w.attempts {
+			t.Errorf("Failures[%d] = %+v, want chunk %d kind %s after %d attempts", i, got[i], w.chunk, w.kind, w.attempts)
+		}
+		if got[i].Message == "" {
+			t.Errorf("Failures[%d] has no message", i)
+		}
+	}
+}
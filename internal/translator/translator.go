@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 
@@ -16,7 +17,6 @@ import (
 	"github.com/oukeidos/focst/internal/language"
 	"github.com/oukeidos/focst/internal/logger"
 	"github.com/oukeidos/focst/internal/srt"
-	"github.com/rivo/uniseg"
 )
 
 // normalizeLines splits text containing newlines into separate lines.
@@ -41,8 +41,18 @@ func normalizeLines(lines ...string) []string {
 	return result
 }
 
-// GetSystemPrompt generates a language-specific system prompt.
-func GetSystemPrompt(sourceName, targetName string, cpl int, enforceCPL bool) string {
+// promoteLine2 moves line2 into line1 when the model returned only a second line,
+// so the translated segment never starts from an empty first line.
+func promoteLine2(line1, line2 string) (string, string) {
+	if strings.TrimSpace(line1) == "" && strings.TrimSpace(line2) != "" {
+		return line2, ""
+	}
+	return line1, line2
+}
+
+// GetSystemPrompt generates a language-specific system prompt. With
+// singleLine, 'line2' is forbidden and line1 carries the whole subtitle.
+func GetSystemPrompt(sourceName, targetName string, cpl int, enforceCPL, singleLine bool) string {
 	lineGuidance := "" +
 		"- The output MUST be a JSON object with a 'translations' field, containing an array of objects.\n" +
 		"- Each object in the array must have:\n" +
@@ -59,6 +69,19 @@ func GetSystemPrompt(sourceName, targetName string, cpl int, enforceCPL bool) st
 			"  - 'line2': Use this if the text exceeds the character limit for a single line or if a natural line break is appropriate. If provided, this line must also be **strictly %d characters or less (including spaces)**.\n"+
 			"- Respond ONLY with the JSON object.\n", cpl, cpl)
 	}
+	if singleLine {
+		limit := ""
+		if enforceCPL {
+			limit = fmt.Sprintf(" Keep it **strictly %d characters or less (including spaces)**, condensing the wording if needed.", cpl)
+		}
+		lineGuidance = fmt.Sprintf(""+
+			"- The output MUST be a JSON object with a 'translations' field, containing an array of objects.\n"+
+			"- Each object in the array must have:\n"+
+			"  - 'id': The ID from the input segment.\n"+
+			"  - 'line1': The whole subtitle on a single line.%s\n"+
+			"- NEVER include 'line2' or a line break: every subtitle must be exactly one line.\n"+
+			"- Respond ONLY with the JSON object.\n", limit)
+	}
 
 	return fmt.Sprintf(`You are a professional %s to %s translator specializing in subtitles.
 Translate the provided %s subtitle segments into %s.
@@ -67,6 +90,7 @@ Translate the provided %s subtitle segments into %s.
 - The input is provided in JSON format with 'context_before', 'target', and 'context_after'.
 - 'target': Contains the segments you must translate. 
 - 'context_before' and 'context_after': Provided for context only. Do NOT translate them or include them in the output.
+- 'note' (only on retries): A correction about your previous response. Follow it; do NOT translate it.
 
 2. Output Structure:
 %s
@@ -80,17 +104,46 @@ Translate the provided %s subtitle segments into %s.
 
 // Translator orchestrates the translation process.
 type Translator struct {
-	geminiClient gemini.Translator
-	chunkSize    int
-	contextSize  int
-	concurrency  int
-	validateCPL  bool
-	promptCPL    bool
-	usage        gemini.UsageMetadata
-	usageMu      sync.Mutex
-	namesMapping map[string]string
-	srcLang      language.Language
-	tgtLang      language.Language
+	geminiClient  gemini.Translator
+	chunkSize     int
+	contextSize   int
+	concurrency   int
+	validateCPL   bool
+	promptCPL     bool
+	usage         gemini.UsageMetadata
+	usageMu       sync.Mutex
+	namesMapping  map[string]string
+	srcLang       language.Language
+	tgtLang       language.Language
+	chunkCache    ChunkCache
+	termMemory    *TermMemory
+	cplMetric     CPLMetric
+	cplTolerance  float64
+	rampUp        time.Duration
+	jitterMax     time.Duration
+	keepDashes    bool
+	onEmpty       EmptyPolicy
+	formality     Formality
+	narrative     srt.NarrativeTag
+	dedupRepeats  bool
+	singleLine    bool
+	autoLinebreak bool
+	failFast      bool
+	improveDrafts bool
+	draftMemory   map[string][]string
+	relaxSafety   bool
+	background    string
+	segmentRate   int
+	onFlush       func([]srt.Segment)
+	throughput    throughputTracker
+	spans         []chunker.Span
+
+	// narrativePrompt is the system instruction for on-screen text requests,
+	// set with the client's instruction at the start of each run.
+	narrativePrompt string
+	// promptTerms are the term memory entries sent in the system prompt of
+	// the current run; the memory itself may grow afterwards.
+	promptTerms []TermEntry
 }
 
 // NewTranslator creates a new Translator instance.
@@ -108,6 +161,8 @@ func NewTranslator(client gemini.Translator, chunkSize, contextSize, concurrency
 		concurrency:  concurrency,
 		validateCPL:  validateCPL,
 		promptCPL:    true,
+		rampUp:       defaultRampUp,
+		jitterMax:    DefaultJitterMax,
 		srcLang:      srcLang,
 		tgtLang:      tgtLang,
 	}, nil
@@ -118,6 +173,24 @@ func (t *Translator) SetPromptCPL(enabled bool) {
 	t.promptCPL = enabled
 }
 
+// SetRampUp sets the window over which worker starts are staggered to avoid an
+// initial request burst. Zero starts all workers at once.
+func (t *Translator) SetRampUp(ramp time.Duration) {
+	t.rampUp = ramp
+}
+
+// SetJitterMax bounds the random delay added to each computed retry backoff.
+// Zero makes backoff deterministic. Server-suggested delays are never jittered.
+func (t *Translator) SetJitterMax(d time.Duration) {
+	t.jitterMax = d
+}
+
+// SetFailFast makes the first chunk failure that cannot be retried (auth,
+// bad request, ...) cancel the remaining chunks and fail the whole run.
+func (t *Translator) SetFailFast(enabled bool) {
+	t.failFast = enabled
+}
+
 // SetNamesMapping sets the character name dictionary.
 func (t *Translator) SetNamesMapping(mapping map[string]string) {
 	t.namesMapping = mapping
@@ -133,8 +206,19 @@ const (
 	StateCanceled
 )
 
+// DefaultRampUp is the default window over which worker starts are staggered.
+const DefaultRampUp = 2 * time.Second
+
+// DefaultJitterMax is the default upper bound of the random delay added to
+// each retry backoff.
+const DefaultJitterMax = 1 * time.Second
+
+// MaxRetryAfter caps a server-suggested retry delay (Retry-After or
+// RetryInfo), so a hint of minutes or hours does not hold a worker that long.
+const MaxRetryAfter = 60 * time.Second
+
 var defaultQPS = 3
-var defaultRampUp = 2 * time.Second
+var defaultRampUp = DefaultRampUp
 
 // TranslationProgress represents the current state of the translation process.
 type TranslationProgress struct {
@@ -143,20 +227,59 @@ type TranslationProgress struct {
 	Attempt     int
 	State       TranslationState
 	Error       error
+	// RateLimited is set on StateInProgress events when the retry follows a
+	// rate-limit (429) error, so front ends can report throttling.
+	RateLimited bool
 }
 
-func (t *Translator) setSystemInstruction() {
-	prompt := GetSystemPrompt(t.srcLang.Name, t.tgtLang.Name, t.tgtLang.DefaultCPL, t.promptCPL)
+// SystemPrompt returns the full system instruction sent with every chunk:
+// the base prompt for the language pair and CPL setting, plus the names
+// mapping and term memory sections when set.
+func (t *Translator) SystemPrompt() string {
+	return t.systemPrompt(t.termEntries())
+}
+
+// systemPrompt builds the system instruction with terms as the term memory
+// section.
+func (t *Translator) systemPrompt(terms []TermEntry) string {
+	prompt := GetSystemPrompt(t.srcLang.Name, t.tgtLang.Name, t.tgtLang.DefaultCPL, t.promptCPL, t.singleLine)
+	if rule := language.ScriptInstruction(t.tgtLang.Code); rule != "" {
+		prompt += "\n" + rule
+	}
+	if rule := FormalityInstruction(t.tgtLang.Code, t.formality); rule != "" {
+		prompt += "\n" + rule
+	}
+	if t.background != "" {
+		prompt += t.backgroundPromptSection()
+	}
 
 	// Inject Names Mapping if present
 	if len(t.namesMapping) > 0 {
+		sources := make([]string, 0, len(t.namesMapping))
+		for src := range t.namesMapping {
+			sources = append(sources, src)
+		}
+		sort.Strings(sources)
 		mappingStr := "\n\nCRITICAL: The following character names MUST be translated as specified:\n"
-		for src, tgt := range t.namesMapping {
-			mappingStr += fmt.Sprintf("- %s -> %s\n", src, tgt)
+		for _, src := range sources {
+			mappingStr += fmt.Sprintf("- %s -> %s\n", src, t.namesMapping[src])
 		}
 		prompt += mappingStr
 	}
 
+	prompt += termPromptSection(terms)
+	if t.improveDrafts || len(t.draftMemory) > 0 {
+		prompt += t.draftPromptSection()
+	}
+	return prompt
+}
+
+func (t *Translator) setSystemInstruction() {
+	t.promptTerms = t.termEntries()
+	prompt := t.systemPrompt(t.promptTerms)
+	if t.narrative != "" {
+		t.narrativePrompt = prompt + narrativeSection
+	}
 	if sc, ok := t.geminiClient.(interface{ SetSystemInstruction(string) }); ok {
 		sc.SetSystemInstruction(prompt)
 	}
@@ -166,6 +289,7 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 	t.setSystemInstruction()
 
 	chunks := chunker.SplitIntoChunks(segments, t.chunkSize, t.contextSize)
+	t.spans = chunker.Spans(chunks)
 	translatedChunks := make([][]srt.Segment, len(chunks))
 	failedMarks := make([]bool, len(chunks))
 	processed := make([]bool, len(chunks))
@@ -183,11 +307,27 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 		}
 	}
 
+	t.throughput.reset(len(toTranslate))
+	flusher := newPrefixFlusher(t.onFlush, chunks, toTranslate)
+
+	var memo *repeatMemo
+	if t.dedupRepeats {
+		memo = newRepeatMemo()
+	}
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
+	var fatalErr error
+	var abort context.CancelFunc
+	if t.failFast {
+		ctx, abort = context.WithCancel(ctx)
+		defer abort()
+	}
+
 	rateCh, stopRate := newRateLimiter(defaultQPS)
 	defer stopRate()
+	budget := newSegmentBudget(t.segmentRate, t.chunkSize)
 
 	jobs := make(chan int, len(chunks))
 	for i := range chunks {
@@ -201,7 +341,7 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 		wg.Add(1)
 		go func(worker int) {
 			defer wg.Done()
-			if delay := rampDelay(worker, t.concurrency, defaultRampUp); delay > 0 {
+			if delay := rampDelay(worker, t.concurrency, t.rampUp); delay > 0 {
 				timer := time.NewTimer(delay)
 				select {
 				case <-ctx.Done():
@@ -216,6 +356,62 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 					return
 				default:
 				}
+				chunk := chunks[i]
+
+				cacheKey := ""
+				if t.chunkCache != nil {
+					cacheKey = t.chunkCacheKey(chunk)
+					if cached, ok := t.loadCachedChunk(cacheKey, chunk); ok {
+						mu.Lock()
+						translatedChunks[i] = cached
+						processed[i] = true
+						mu.Unlock()
+						flusher.finish(i, cached)
+						if memo != nil {
+							memo.record(chunk.Target, cached)
+						}
+						t.throughput.complete(gemini.UsageMetadata{})
+						logger.Info("Reused cached chunk translation", "index", i)
+						if onProgress != nil {
+							onProgress(TranslationProgress{
+								ChunkIndex:  i,
+								TotalChunks: len(chunks),
+								State:       StateCompleted,
+							})
+						}
+						continue
+					}
+				}
+
+				// send is the chunk actually sent to the model; with dedup it
+				// omits repeats, which plan.expand fills back in.
+				send := chunk
+				var plan *repeatPlan
+				if memo != nil {
+					if p := memo.plan(chunk.Target); p.reduces() {
+						plan = &p
+						send.Target = p.unique
+					}
+				}
+				if plan != nil && len(send.Target) == 0 {
+					translated := plan.expand(chunk.Target, nil)
+					mu.Lock()
+					translatedChunks[i] = translated
+					processed[i] = true
+					mu.Unlock()
+					flusher.finish(i, translated)
+					logger.Debug("Chunk filled from repeated lines", "index", i)
+					t.throughput.complete(gemini.UsageMetadata{})
+					if onProgress != nil {
+						onProgress(TranslationProgress{
+							ChunkIndex:  i,
+							TotalChunks: len(chunks),
+							State:       StateCompleted,
+						})
+					}
+					continue
+				}
+
 				if rateCh != nil {
 					select {
 					case <-ctx.Done():
@@ -223,12 +419,19 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 					case <-rateCh:
 					}
 				}
-				chunk := chunks[i]
+				if budget != nil {
+					if err := budget.wait(ctx, len(send.Target)); err != nil {
+						return
+					}
+				}
 
 				var resp *gemini.ResponseData
 				var err error
 				const maxAttempts = 3
 				attemptsUsed := 0
+				malformed := false
+				validationFailures := 0
+				var chunkUsage gemini.UsageMetadata
 
 				for attempt := 1; attempt <= maxAttempts; attempt++ {
 					attemptsUsed = attempt
@@ -243,18 +446,41 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 							Attempt:     attempt,
 							State:       state,
 							Error:       err,
+							RateLimited: apperrors.IsRateLimit(err),
 						})
 					}
 
-					req := t.prepareRequest(chunk)
-					resp, err = t.geminiClient.Translate(ctx, req)
+					if errors.Is(err, gemini.ErrMalformedResponse) {
+						malformed = true
+					}
+					// Only a retry right after a validation failure samples at
+					// a raised temperature; transient and rate-limit retries
+					// resend at the client's setting.
+					escalation := 0
+					if apperrors.IsValidation(err) {
+						validationFailures++
+						escalation = validationFailures
+					}
+					req := t.prepareRequest(send, malformed, escalation)
+					resp, err = t.translateRouted(ctx, req, send.Target)
+					if apperrors.IsSafetyBlock(err) {
+						resp, err = t.recoverSafetyBlock(ctx, req, send.Target, err)
+					}
 					if err == nil {
 						t.usageMu.Lock()
 						t.usage.PromptTokenCount += resp.Usage.PromptTokenCount
 						t.usage.CandidatesTokenCount += resp.Usage.CandidatesTokenCount
 						t.usage.TotalTokenCount += resp.Usage.TotalTokenCount
 						t.usageMu.Unlock()
-
+						chunkUsage.PromptTokenCount += resp.Usage.PromptTokenCount
+						chunkUsage.CandidatesTokenCount += resp.Usage.CandidatesTokenCount
+						chunkUsage.TotalTokenCount += resp.Usage.TotalTokenCount
+
+						if t.singleLine {
+							t.collapseResponseLines(resp)
+						} else if t.autoLinebreak {
+							t.breakResponseLines(resp)
+						}
 						if t.validateCPL {
 							err = t.validateResponse(resp)
 							if err != nil {
@@ -264,20 +490,33 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 
 						if err == nil {
 							var translated []srt.Segment
-							translated, err = t.mergeResults(chunk.Target, resp)
+							translated, err = t.mergeResults(send.Target, resp)
 							if err != nil {
 								err = apperrors.Validation(err)
 							}
 							if err == nil {
+								if plan != nil {
+									translated = plan.expand(chunk.Target, translated)
+								}
+								if memo != nil {
+									memo.record(chunk.Target, translated)
+								}
 								mu.Lock()
 								translatedChunks[i] = translated
 								processed[i] = true
 								mu.Unlock()
+								flusher.finish(i, translated)
+								if t.chunkCache != nil {
+									if storeErr := t.chunkCache.Store(cacheKey, translated); storeErr != nil {
+										logger.Warn("Failed to cache chunk translation", "index", i, "error", storeErr)
+									}
+								}
 							}
 						}
 					}
 
 					if err == nil {
+						t.throughput.complete(chunkUsage)
 						if onProgress != nil {
 							onProgress(TranslationProgress{
 								ChunkIndex:  i,
@@ -289,7 +528,7 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 						break
 					}
 
-					retry, backoff := retryDecision(ctx, err, attempt, maxAttempts)
+					retry, backoff := retryDecision(ctx, err, attempt, maxAttempts, t.jitterMax)
 					if !retry {
 						break
 					}
@@ -301,9 +540,15 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 				}
 
 				if err != nil {
+					t.throughput.fail(i, attemptsUsed, err)
 					mu.Lock()
 					failedMarks[i] = true
+					if abort != nil && fatalErr == nil && ctx.Err() == nil && !apperrors.IsRetryable(err) {
+						fatalErr = fmt.Errorf("chunk %d failed (fail-fast): %w", i, err)
+						abort()
+					}
 					mu.Unlock()
+					flusher.finish(i, nil)
 					if attemptsUsed >= maxAttempts && apperrors.IsRetryable(err) {
 						logger.Error("Chunk failed after maximum retries", "index", i, "attempts", attemptsUsed, "error", err)
 					} else {
@@ -315,6 +560,9 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 	}
 
 	wg.Wait()
+	if fatalErr != nil {
+		return nil, nil, nil, fatalErr
+	}
 	if ctx.Err() != nil && onProgress != nil {
 		onProgress(TranslationProgress{
 			ChunkIndex:  -1,
@@ -332,6 +580,14 @@ func (t *Translator) translateEngine(ctx context.Context, segments []srt.Segment
 	return chunks, translatedChunks, failedMarks, nil
 }
 
+// ChunkSpans returns where each chunk of the last run sat in the segments it
+// was split from (the selected subset for TranslateSubset), by chunk index.
+// Callers map chunk indices to segments with it instead of assuming chunks
+// of a uniform size. Call it after a translation returns.
+func (t *Translator) ChunkSpans() []chunker.Span {
+	return t.spans
+}
+
 // TranslateSRT translates a slice of SRT segments with retries and concurrency.
 func (t *Translator) TranslateSRT(ctx context.Context, segments []srt.Segment, onProgress func(TranslationProgress)) ([]srt.Segment, []int, error) {
 	chunks, translatedChunks, failedMarks, err := t.translateEngine(ctx, segments, nil, onProgress)
@@ -361,7 +617,7 @@ func (t *Translator) TranslateSRT(ctx context.Context, segments []srt.Segment, o
 
 // TranslateChunks translates a list of specific chunks concurrently.
 func (t *Translator) TranslateChunks(ctx context.Context, segments []srt.Segment, chunkIndices []int, onProgress func(TranslationProgress)) ([]srt.Segment, []int, error) {
-	_, translatedChunks, failedMarks, err := t.translateEngine(ctx, segments, chunkIndices, onProgress)
+	chunks, translatedChunks, failedMarks, err := t.translateEngine(ctx, segments, chunkIndices, onProgress)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -372,12 +628,10 @@ func (t *Translator) TranslateChunks(ctx context.Context, segments []srt.Segment
 		if translated == nil {
 			continue
 		}
-		startIdx := i * t.chunkSize
-		for j, seg := range translated {
-			if startIdx+j < len(translatedSegments) {
-				translatedSegments[startIdx+j] = seg
-			}
+		if len(translated) != len(chunks[i].Target) {
+			return nil, nil, fmt.Errorf("chunk %d size mismatch: expected %d segments, got %d", i, len(chunks[i].Target), len(translated))
 		}
+		copy(translatedSegments[chunks[i].Offset:], translated)
 	}
 	var failedChunkIndices []int
 	for i, failed := range failedMarks {
@@ -388,12 +642,95 @@ func (t *Translator) TranslateChunks(ctx context.Context, segments []srt.Segment
 	return translatedSegments, failedChunkIndices, nil
 }
 
-func (t *Translator) prepareRequest(chunk chunker.Chunk) gemini.RequestData {
-	return gemini.RequestData{
+// TranslateSubset translates only the segments at the selected indices and passes
+// the rest through unchanged. selected must be ascending and in range.
+//
+// Chunks are formed over the selected subset in order, so chunk i covers
+// segments[selected[i*chunkSize]] .. segments[selected[min((i+1)*chunkSize, len(selected))-1]],
+// and context is drawn from neighbouring selected segments only. chunkIndices and
+// the returned failed chunk indices use this subset numbering (nil translates all
+// chunks). The returned slice has the same length and order as segments.
+func (t *Translator) TranslateSubset(ctx context.Context, segments []srt.Segment, selected []int, chunkIndices []int, onProgress func(TranslationProgress)) ([]srt.Segment, []int, error) {
+	subset := make([]srt.Segment, len(selected))
+	prev := -1
+	for k, idx := range selected {
+		if idx <= prev || idx >= len(segments) {
+			return nil, nil, fmt.Errorf("invalid selected segment index %d at position %d", idx, k)
+		}
+		subset[k] = segments[idx]
+		prev = idx
+	}
+
+	var translatedSubset []srt.Segment
+	var failed []int
+	var err error
+	if chunkIndices == nil {
+		translatedSubset, failed, err = t.TranslateSRT(ctx, subset, onProgress)
+	} else {
+		translatedSubset, failed, err = t.TranslateChunks(ctx, subset, chunkIndices, onProgress)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(translatedSubset) != len(subset) {
+		return nil, nil, fmt.Errorf("translated subset size mismatch: expected %d, got %d", len(subset), len(translatedSubset))
+	}
+
+	out := make([]srt.Segment, len(segments))
+	copy(out, segments)
+	for k, idx := range selected {
+		out[idx] = translatedSubset[k]
+	}
+	return out, failed, nil
+}
+
+// reformatNote is sent with every retry of a chunk once the model has
+// returned a response that was not valid JSON.
+const reformatNote = "Your previous response was not valid JSON. Return ONLY the JSON object with the 'translations' array, with no extra text or markdown."
+
+// validationRetryTemperatures are the sampling temperatures for retries after
+// a chunk's first, second, ... validation failure (e.g. lines over the CPL
+// limit). A different temperature helps the model out of a repeating answer.
+var validationRetryTemperatures = []float32{0.4, 0.8}
+
+// retryTemperature returns the temperature for a retry after the chunk's n-th
+// validation failure, holding the last step once the list runs out. It
+// returns nil, keeping the client's setting, when n is zero.
+func retryTemperature(n int) *float32 {
+	if n <= 0 || len(validationRetryTemperatures) == 0 {
+		return nil
+	}
+	if n > len(validationRetryTemperatures) {
+		n = len(validationRetryTemperatures)
+	}
+	temperature := validationRetryTemperatures[n-1]
+	return &temperature
+}
+
+// prepareRequest builds the request for chunk. reformat adds reformatNote
+// after an earlier attempt came back malformed; a positive escalation raises
+// the temperature (see retryTemperature).
+func (t *Translator) prepareRequest(chunk chunker.Chunk, reformat bool, escalation int) gemini.RequestData {
+	target := toSegmentData(chunk.Target)
+	if t.keepDashes {
+		target = stripDialogueDashes(target)
+	}
+	if t.improveDrafts {
+		target = t.splitDrafts(target)
+	}
+	if len(t.draftMemory) > 0 {
+		target = t.applyDraftMemory(chunk.Target, target)
+	}
+	req := gemini.RequestData{
 		ContextBefore: toSegmentData(chunk.Context.Before),
-		Target:        toSegmentData(chunk.Target),
+		Target:        target,
 		ContextAfter:  toSegmentData(chunk.Context.After),
 	}
+	if reformat {
+		req.Note = reformatNote
+	}
+	req.Temperature = retryTemperature(escalation)
+	return req
 }
 
 func toSegmentData(segments []srt.Segment) []gemini.SegmentData {
@@ -434,63 +771,92 @@ func (t *Translator) mergeResults(original []srt.Segment, resp *gemini.ResponseD
 	}
 
 	results := make([]srt.Segment, len(original))
+	var keptSource []int
 	for i, orig := range original {
 		tr, ok := transMap[orig.ID]
 		if !ok {
 			return nil, fmt.Errorf("missing translation for segment ID %d", orig.ID)
 		}
 
+		line1, line2 := promoteLine2(tr.Line1, tr.Line2)
+
 		// Validation: Ensure translation is not empty if original was not empty
-		if tr.Line1 == "" && tr.Line2 == "" && len(orig.Lines) > 0 {
-			return nil, fmt.Errorf("hallucination detected: empty translation for segment ID %d", orig.ID)
+		var newLines []string
+		if strings.TrimSpace(line1) == "" && strings.TrimSpace(line2) == "" && len(orig.Lines) > 0 {
+			if t.onEmpty != EmptyPolicyKeepSource {
+				return nil, fmt.Errorf("hallucination detected: empty translation for segment ID %d", orig.ID)
+			}
+			newLines = append([]string(nil), orig.Lines...)
+			keptSource = append(keptSource, orig.ID)
+		} else {
+			newLines = normalizeLines(line1, line2)
+			if t.keepDashes {
+				newLines = applyDialogueDashes(orig, newLines)
+			}
+		}
+		if t.singleLine && len(newLines) > 1 {
+			newLines = []string{t.joinLines(newLines)}
 		}
-
-		newLines := normalizeLines(tr.Line1, tr.Line2)
 
 		results[i] = srt.Segment{
-			ID:        orig.ID,
-			StartTime: orig.StartTime,
-			EndTime:   orig.EndTime,
-			Lines:     newLines,
+			ID:           orig.ID,
+			StartTime:    orig.StartTime,
+			EndTime:      orig.EndTime,
+			Lines:        newLines,
+			Forced:       orig.Forced,
+			CueSettings:  orig.CueSettings,
+			OverrideTags: orig.OverrideTags,
+			Notes:        orig.Notes,
 		}
 	}
+	if len(keptSource) > 0 {
+		logger.Warn("Kept source text for empty translations", "ids", keptSource)
+	}
 
 	return results, nil
 }
 
 func (t *Translator) validateResponse(resp *gemini.ResponseData) error {
-	limit := float64(t.tgtLang.DefaultCPL) * 1.5
+	limit := t.lineLimit()
 	for _, tr := range resp.Translations {
-		c1 := uniseg.GraphemeClusterCount(tr.Line1)
+		c1 := t.lineLength(tr.Line1)
 		if float64(c1) > limit {
-			return fmt.Errorf("line 1 too long: %d chars (max %.0f) for ID %d", c1, limit, tr.ID)
+			return fmt.Errorf("line 1 too long: %d %s (max %.0f) for ID %d", c1, t.lengthUnit(), limit, tr.ID)
 		}
 		if tr.Line2 != "" {
-			c2 := uniseg.GraphemeClusterCount(tr.Line2)
+			c2 := t.lineLength(tr.Line2)
 			if float64(c2) > limit {
-				return fmt.Errorf("line 2 too long: %d chars (max %.0f) for ID %d", c2, limit, tr.ID)
+				return fmt.Errorf("line 2 too long: %d %s (max %.0f) for ID %d", c2, t.lengthUnit(), limit, tr.ID)
 			}
 		}
 	}
 	return nil
 }
 
-func retryDecision(ctx context.Context, err error, attempt, maxAttempts int) (bool, time.Duration) {
+// retryDecision reports whether a failed attempt should be retried and after
+// what delay. A random delay in [0, jitterMax) is added to the computed
+// backoff; jitterMax <= 0 adds none.
+func retryDecision(ctx context.Context, err error, attempt, maxAttempts int, jitterMax time.Duration) (bool, time.Duration) {
 	if err == nil {
 		return false, 0
 	}
 	if attempt >= maxAttempts {
 		return false, 0
 	}
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+	// Stop when the run itself is canceled. A per-request timeout surfaces as a
+	// transient error while ctx is still live, so it is retried below.
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
 		return false, 0
 	}
 	if !apperrors.IsRetryable(err) {
 		return false, 0
 	}
+	if d, ok := apperrors.RetryAfterOf(err); ok {
+		// Server-suggested delay takes precedence over the computed backoff.
+		return true, min(d, MaxRetryAfter)
+	}
 	base := 1 * time.Second
 	maxBackoff := 20 * time.Second
-	jitterMax := 1 * time.Second
 
 	backoff := base << (attempt - 1)
 	if apperrors.IsRateLimit(err) {
@@ -499,6 +865,9 @@ func retryDecision(ctx context.Context, err error, attempt, maxAttempts int) (bo
 	if backoff > maxBackoff {
 		backoff = maxBackoff
 	}
+	if jitterMax <= 0 {
+		return true, backoff
+	}
 	jitter := time.Duration(rand.Int63n(int64(jitterMax)))
 	return true, backoff + jitter
 }
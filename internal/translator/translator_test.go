@@ -2,10 +2,13 @@ package translator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/oukeidos/focst/internal/apperrors"
 	"github.com/oukeidos/focst/internal/gemini"
 	"github.com/oukeidos/focst/internal/language"
 	"github.com/oukeidos/focst/internal/srt"
@@ -193,20 +196,280 @@ func TestTranslator_MergeResultsStrictValidation(t *testing.T) {
 	}
 }
 
+func TestTranslator_MergeResultsPromotesLine2(t *testing.T) {
+	tr := &Translator{}
+	original := []srt.Segment{
+		{ID: 1, Lines: []string{"こんにちは"}},
+		{ID: 2, Lines: []string{"さようなら"}},
+	}
+	resp := &gemini.ResponseData{
+		Translations: []gemini.TranslatedSegment{
+			{ID: 1, Line1: "", Line2: "안녕하세요"},
+			{ID: 2, Line1: "  ", Line2: "잘 가요"},
+		},
+	}
+
+	results, err := tr.mergeResults(original, resp)
+	if err != nil {
+		t.Fatalf("mergeResults() unexpected error: %v", err)
+	}
+	want := [][]string{{"안녕하세요"}, {"잘 가요"}}
+	for i, w := range want {
+		if !reflect.DeepEqual(results[i].Lines, w) {
+			t.Fatalf("segment %d lines = %q, want %q", i+1, results[i].Lines, w)
+		}
+	}
+
+	blank := &gemini.ResponseData{
+		Translations: []gemini.TranslatedSegment{
+			{ID: 1, Line1: " ", Line2: "\t"},
+			{ID: 2, Line1: "잘 가요"},
+		},
+	}
+	if _, err := tr.mergeResults(original, blank); err == nil || !strings.Contains(err.Error(), "empty translation") {
+		t.Fatalf("expected whitespace-only translation to be rejected, got %v", err)
+	}
+}
+
 func TestGetSystemPrompt_IncludesSlashRule(t *testing.T) {
 	rule := "Do NOT use \"/\" as a line-break substitute in subtitle text."
 
 	t.Run("without_cpl_enforcement", func(t *testing.T) {
-		prompt := GetSystemPrompt("Japanese", "Korean", 13, false)
+		prompt := GetSystemPrompt("Japanese", "Korean", 13, false, false)
 		if !strings.Contains(prompt, rule) {
 			t.Fatalf("expected prompt to contain slash rule")
 		}
 	})
 
 	t.Run("with_cpl_enforcement", func(t *testing.T) {
-		prompt := GetSystemPrompt("Japanese", "Korean", 13, true)
+		prompt := GetSystemPrompt("Japanese", "Korean", 13, true, false)
 		if !strings.Contains(prompt, rule) {
 			t.Fatalf("expected prompt to contain slash rule")
 		}
 	})
 }
+
+type recordingClient struct {
+	targets [][]int
+	failID  int
+}
+
+func (c *recordingClient) Translate(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	ids := make([]int, 0, len(req.Target))
+	resp := &gemini.ResponseData{}
+	for _, seg := range req.Target {
+		ids = append(ids, seg.ID)
+		if seg.ID == c.failID {
+			c.targets = append(c.targets, ids)
+			return nil, apperrors.BadRequest(errors.New("rejected"))
+		}
+		resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T" + strings.Join(seg.Lines, "")})
+	}
+	c.targets = append(c.targets, ids)
+	return resp, nil
+}
+
+func (c *recordingClient) SetSystemInstruction(prompt string) {}
+
+func TestTranslator_TranslateSubset_PassthroughOrdering(t *testing.T) {
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"a"}},
+		{ID: 2, Lines: []string{"b"}},
+		{ID: 3, Lines: []string{"c"}},
+		{ID: 4, Lines: []string{"d"}},
+		{ID: 5, Lines: []string{"e"}},
+	}
+	selected := []int{1, 3, 4}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+
+	t.Run("subset chunks and merge order", func(t *testing.T) {
+		client := &recordingClient{}
+		tr, err := NewTranslator(client, 2, 0, 1, false, src, tgt)
+		if err != nil {
+			t.Fatalf("NewTranslator failed: %v", err)
+		}
+		results, failed, err := tr.TranslateSubset(context.Background(), segments, selected, nil, nil)
+		if err != nil {
+			t.Fatalf("TranslateSubset failed: %v", err)
+		}
+		if len(failed) != 0 {
+			t.Fatalf("expected no failed chunks, got %v", failed)
+		}
+		// Chunk 0 = selected[0:2] (IDs 2, 4), chunk 1 = selected[2:3] (ID 5).
+		if want := [][]int{{2, 4}, {5}}; !reflect.DeepEqual(client.targets, want) {
+			t.Fatalf("request targets = %v, want %v", client.targets, want)
+		}
+		var got []string
+		for _, seg := range results {
+			got = append(got, seg.Lines[0])
+		}
+		if want := []string{"a", "Tb", "c", "Td", "Te"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("merged lines = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("failed chunk indices use subset numbering", func(t *testing.T) {
+		client := &recordingClient{failID: 5}
+		tr, err := NewTranslator(client, 2, 0, 1, false, src, tgt)
+		if err != nil {
+			t.Fatalf("NewTranslator failed: %v", err)
+		}
+		results, failed, err := tr.TranslateSubset(context.Background(), segments, selected, nil, nil)
+		if err != nil {
+			t.Fatalf("TranslateSubset failed: %v", err)
+		}
+		if !reflect.DeepEqual(failed, []int{1}) {
+			t.Fatalf("failed = %v, want [1]", failed)
+		}
+		if results[4].Lines[0] != "e" {
+			t.Fatalf("failed chunk should keep source text, got %q", results[4].Lines[0])
+		}
+	})
+
+	t.Run("rejects unordered selection", func(t *testing.T) {
+		tr, err := NewTranslator(&recordingClient{}, 2, 0, 1, false, src, tgt)
+		if err != nil {
+			t.Fatalf("NewTranslator failed: %v", err)
+		}
+		if _, _, err := tr.TranslateSubset(context.Background(), segments, []int{3, 1}, nil, nil); err == nil {
+			t.Fatalf("expected error for unordered selection")
+		}
+	})
+}
+
+func TestTranslator_TranslateChunks_NonUniformFinalChunk(t *testing.T) {
+	// Chunk size 2 over 5 segments leaves a short final chunk: [a b] [c d] [e].
+	segments := []srt.Segment{
+		{ID: 1, Lines: []string{"a"}},
+		{ID: 2, Lines: []string{"b"}},
+		{ID: 3, Lines: []string{"c"}},
+		{ID: 4, Lines: []string{"d"}},
+		{ID: 5, Lines: []string{"e"}},
+	}
+	src, _ := language.GetLanguage("en")
+	tgt, _ := language.GetLanguage("ko")
+
+	cases := []struct {
+		name   string
+		chunks []int
+		want   []string
+	}{
+		{name: "final_short_chunk", chunks: []int{2}, want: []string{"a", "b", "c", "d", "Te"}},
+		{name: "unordered_indices", chunks: []int{2, 0}, want: []string{"Ta", "Tb", "c", "d", "Te"}},
+		{name: "all_chunks", chunks: []int{0, 1, 2}, want: []string{"Ta", "Tb", "Tc", "Td", "Te"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, err := NewTranslator(&recordingClient{}, 2, 1, 1, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			results, failed, err := tr.TranslateChunks(context.Background(), segments, tc.chunks, nil)
+			if err != nil {
+				t.Fatalf("TranslateChunks failed: %v", err)
+			}
+			if len(failed) != 0 {
+				t.Fatalf("expected no failed chunks, got %v", failed)
+			}
+			var got []string
+			for i, seg := range results {
+				if seg.ID != i+1 {
+					t.Fatalf("segment %d has ID %d; assembly reordered segments", i, seg.ID)
+				}
+				got = append(got, seg.Lines[0])
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("assembled lines = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTranslator_SystemPromptCommitsChineseScript(t *testing.T) {
+	src, _ := language.GetLanguage("ja")
+	tests := []struct {
+		target  string
+		want    string
+		notWant string
+	}{
+		{target: "zh", want: "ONLY Simplified Chinese characters", notWant: "ONLY Traditional"},
+		{target: "zh-Hant", want: "ONLY Traditional Chinese characters", notWant: "ONLY Simplified"},
+		{target: "ko", notWant: "Chinese characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			tgt, _ := language.GetLanguage(tt.target)
+			client := &gemini.MockClient{}
+			tr, err := NewTranslator(client, 10, 0, 1, false, src, tgt)
+			if err != nil {
+				t.Fatalf("NewTranslator failed: %v", err)
+			}
+			tr.setSystemInstruction()
+			if tt.want != "" && !strings.Contains(client.LastSystemInstruction, tt.want) {
+				t.Fatalf("expected prompt to contain %q", tt.want)
+			}
+			if strings.Contains(client.LastSystemInstruction, tt.notWant) {
+				t.Fatalf("expected prompt not to contain %q", tt.notWant)
+			}
+		})
+	}
+}
+
+type flakyFormatClient struct {
+	firstErr error
+	notes    []string
+}
+
+func (c *flakyFormatClient) Translate(ctx context.Context, req gemini.RequestData) (*gemini.ResponseData, error) {
+	c.notes = append(c.notes, req.Note)
+	if len(c.notes) == 1 {
+		return nil, c.firstErr
+	}
+	resp := &gemini.ResponseData{}
+	for _, seg := range req.Target {
+		resp.Translations = append(resp.Translations, gemini.TranslatedSegment{ID: seg.ID, Line1: "T" + seg.Lines[0]})
+	}
+	return resp, nil
+}
+
+func (c *flakyFormatClient) SetSystemInstruction(prompt string) {}
+
+func TestTranslator_RetryAddsReformatNoteAfterMalformedJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		firstErr error
+		wantNote string
+	}{
+		{
+			name:     "malformed_json",
+			firstErr: apperrors.Validation(fmt.Errorf("%w: unexpected end of JSON input", gemini.ErrMalformedResponse)),
+			wantNote: reformatNote,
+		},
+		{
+			name:     "other_validation",
+			firstErr: apperrors.Validation(errors.New("translation count mismatch")),
+			wantNote: "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &flakyFormatClient{firstErr: tc.firstErr}
+			tr, err := NewTranslator(client, 10, 0, 1, false, language.Language{Name: "English", Code: "en"}, language.Language{Name: "Korean", Code: "ko"})
+			if err != nil {
+				t.Fatalf("NewTranslator: %v", err)
+			}
+			tr.SetRampUp(0)
+			out, failed, err := tr.TranslateSRT(context.Background(), []srt.Segment{{ID: 1, Lines: []string{"a"}}}, nil)
+			if err != nil || len(failed) != 0 {
+				t.Fatalf("TranslateSRT failed=%v err=%v", failed, err)
+			}
+			if out[0].Lines[0] != "Ta" {
+				t.Fatalf("unexpected output %+v", out)
+			}
+			if len(client.notes) != 2 || client.notes[0] != "" || client.notes[1] != tc.wantNote {
+				t.Fatalf("notes = %q, want [\"\" %q]", client.notes, tc.wantNote)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package version
 
-import "fmt"
+import (
+	"fmt"
+	"runtime"
+)
 
 // Version is the release version embedded in the binary.
 // It can be overridden at build time via:
@@ -17,7 +20,31 @@ var Commit = "unknown"
 // go build -ldflags "-X github.com/oukeidos/focst/internal/version.BuildDate=2026-01-30T12:00:00Z"
 var BuildDate = "unknown"
 
+// BuildInfo is the version metadata plus the Go runtime and platform, as
+// wanted in bug reports.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// Get returns the BuildInfo of the running binary.
+func Get() BuildInfo {
+	return BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+}
+
 // Info returns a multi-line version string for CLI output.
 func Info() string {
-	return fmt.Sprintf("focst %s\ncommit: %s\nbuild: %s", Version, Commit, BuildDate)
+	b := Get()
+	return fmt.Sprintf("focst %s\ncommit: %s\nbuild: %s\ngo: %s %s/%s", b.Version, b.Commit, b.BuildDate, b.GoVersion, b.OS, b.Arch)
 }